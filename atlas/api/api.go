@@ -83,3 +83,33 @@ func (a api) GetPathsWithToken(stream pb.Atlas_GetPathsWithTokenServer) error {
 		}
 	}
 }
+
+func (a api) IngestTraceroutes(stream pb.Atlas_IngestTraceroutesServer) error {
+	ctx := stream.Context()
+	resp := &pb.IngestResponse{}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		stored, err := a.s.IngestTraceroute(req)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if stored {
+			resp.Stored++
+		} else {
+			resp.Rejected++
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+}
@@ -15,6 +15,7 @@ type client struct {
 type Atlas interface {
 	GetIntersectingPath(context.Context) (pb.Atlas_GetIntersectingPathClient, error)
 	GetPathsWithToken(context.Context) (pb.Atlas_GetPathsWithTokenClient, error)
+	IngestTraceroutes(context.Context) (pb.Atlas_IngestTraceroutesClient, error)
 }
 
 // New returns a new atlas
@@ -31,3 +32,8 @@ func (c client) GetIntersectingPath(ctx context.Context) (pb.Atlas_GetIntersecti
 func (c client) GetPathsWithToken(ctx context.Context) (pb.Atlas_GetPathsWithTokenClient, error) {
 	return c.AtlasClient.GetPathsWithToken(ctx)
 }
+
+// IngestTraceroutes streams external traceroutes into the atlas
+func (c client) IngestTraceroutes(ctx context.Context) (pb.Atlas_IngestTraceroutesClient, error) {
+	return c.AtlasClient.IngestTraceroutes(ctx)
+}
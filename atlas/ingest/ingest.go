@@ -0,0 +1,35 @@
+// Package ingest normalizes traceroutes from external data sets so they can be
+// stored in the atlas
+package ingest
+
+import (
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+)
+
+// Scanner reads traceroutes from an external data set
+type Scanner interface {
+	// Scan advances to the next traceroute
+	Scan() bool
+	// Traceroute returns the traceroute from the last call to Scan
+	Traceroute() *dm.Traceroute
+	// Err returns the first error that occurred while scanning
+	Err() error
+}
+
+func makeTime(t time.Time) *dm.TracerouteTime {
+	return &dm.TracerouteTime{
+		Sec:   t.Unix(),
+		Usec:  int64(t.Nanosecond() / 1000),
+		Ftime: t.Format(`"2006-01-_2 15:04:05"`),
+	}
+}
+
+func makeRTT(ms float64) *dm.RTT {
+	usec := int64(ms * 1000)
+	return &dm.RTT{
+		Sec:  usec / 1000000,
+		Usec: usec % 1000000,
+	}
+}
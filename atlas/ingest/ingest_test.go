@@ -0,0 +1,166 @@
+package ingest_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/atlas/ingest"
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+)
+
+const ripeTrace = `{"af": 4, "type": "traceroute", "dst_addr": "5.6.7.8", "src_addr": "192.168.1.2",
+"from": "1.2.3.4", "proto": "ICMP", "paris_id": 3, "size": 48, "timestamp": 1470000000,
+"result": [
+	{"hop": 1, "result": [{"from": "10.0.0.1", "ttl": 64, "size": 76, "rtt": 1.5}, {"x": "*"}]},
+	{"hop": 2, "result": [{"x": "*"}, {"x": "*"}]},
+	{"hop": 3, "result": [{"from": "5.6.7.8", "ttl": 55, "size": 48, "rtt": 20.25}]}
+]}`
+
+const ripeV6 = `{"af": 6, "type": "traceroute", "dst_addr": "2001:db8::1", "from": "2001:db8::2", "result": []}`
+
+func scanAll(t *testing.T, s ingest.Scanner) []*dm.Traceroute {
+	var ret []*dm.Traceroute
+	for s.Scan() {
+		ret = append(ret, s.Traceroute())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	return ret
+}
+
+func TestRIPEScanner(t *testing.T) {
+	for _, test := range []struct {
+		desc  string
+		input string
+		count int
+	}{
+		{desc: "array", input: "[" + ripeTrace + "," + ripeV6 + "," + ripeTrace + "]", count: 2},
+		{desc: "lines", input: ripeTrace + "\n" + ripeV6 + "\n" + ripeTrace + "\n", count: 2},
+		{desc: "empty", input: "\n", count: 0},
+	} {
+		trs := scanAll(t, ingest.NewRIPEScanner(strings.NewReader(test.input)))
+		if len(trs) != test.count {
+			t.Fatalf("%s: expected %d traceroutes, got %d", test.desc, test.count, len(trs))
+		}
+		for _, tr := range trs {
+			if tr.Src != 0x01020304 || tr.Dst != 0x05060708 {
+				t.Fatalf("%s: wrong src/dst %v", test.desc, tr)
+			}
+			if tr.Method != "icmp-paris" || tr.GetStart().Sec != 1470000000 {
+				t.Fatalf("%s: wrong method or start %v", test.desc, tr)
+			}
+			hops := tr.GetHops()
+			if len(hops) != 2 {
+				t.Fatalf("%s: expected 2 hops, got %d", test.desc, len(hops))
+			}
+			if hops[1].Addr != tr.Dst || hops[1].ProbeTtl != 3 || hops[1].Rtt.Usec != 20250 {
+				t.Fatalf("%s: wrong last hop %v", test.desc, hops[1])
+			}
+		}
+	}
+}
+
+const ripeTriple = `{"af": 4, "type": "traceroute", "dst_addr": "5.6.7.8", "from": "1.2.3.4",
+"proto": "UDP", "timestamp": 1470000000,
+"result": [
+	{"hop": 1, "result": [{"from": "10.0.0.1", "ttl": 64, "rtt": 1.5}, {"from": "10.0.0.1", "ttl": 64, "rtt": 0.75},
+		{"from": "10.0.0.1", "ttl": 64, "rtt": 2.0}]},
+	{"hop": 2, "result": [{"from": "10.0.0.2", "ttl": 63, "rtt": 5.0}, {"x": "*"},
+		{"from": "10.0.0.3", "ttl": 63, "rtt": 4.0}]},
+	{"hop": 3, "result": [{"from": "5.6.7.8", "ttl": 55, "rtt": 20.0}, {"from": "5.6.7.8", "ttl": 55, "rtt": 30.0},
+		{"from": "5.6.7.8", "ttl": 55, "rtt": 10.0}]}
+]}`
+
+func TestRIPEScannerCollapsesReplies(t *testing.T) {
+	trs := scanAll(t, ingest.NewRIPEScanner(strings.NewReader(ripeTriple)))
+	if len(trs) != 1 {
+		t.Fatalf("expected 1 traceroute, got %d", len(trs))
+	}
+	expected := []struct {
+		addr uint32
+		ttl  uint32
+		usec int64
+	}{
+		{addr: 0x0a000001, ttl: 1, usec: 750},
+		{addr: 0x0a000002, ttl: 2, usec: 5000},
+		{addr: 0x0a000003, ttl: 2, usec: 4000},
+		{addr: 0x05060708, ttl: 3, usec: 10000},
+	}
+	hops := trs[0].GetHops()
+	if len(hops) != len(expected) || trs[0].HopCount != uint32(len(expected)) {
+		t.Fatalf("expected %d hops, got %v", len(expected), hops)
+	}
+	for i, e := range expected {
+		if hops[i].Addr != e.addr || hops[i].ProbeTtl != e.ttl || hops[i].Rtt.Usec != e.usec {
+			t.Fatalf("hop %d: expected %v, got %v", i, e, hops[i])
+		}
+	}
+}
+
+func TestRIPEScannerBadJSON(t *testing.T) {
+	s := ingest.NewRIPEScanner(strings.NewReader(`{"af": 4,`))
+	if s.Scan() {
+		t.Fatal("Scan succeeded on invalid JSON")
+	}
+	if s.Err() == nil {
+		t.Fatal("Expected an error for invalid JSON")
+	}
+}
+
+func TestWartsScanner(t *testing.T) {
+	f, err := os.Open("../../doc/trace_test.warts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	trs := scanAll(t, ingest.NewWartsScanner(f))
+	if len(trs) == 0 {
+		t.Fatal("No traceroutes scanned from warts")
+	}
+	for _, tr := range trs {
+		if tr.Src == 0 || tr.Dst == 0 {
+			t.Fatalf("Traceroute missing src or dst: %v", tr)
+		}
+	}
+}
+
+func TestIPlaneScanner(t *testing.T) {
+	var buf bytes.Buffer
+	w := func(v interface{}) {
+		if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// clientID, uniqueID, number of traceroutes, length
+	w([]int32{1, 1, 1, 0})
+	// destination 5.6.7.8 with 3 hops, one unresponsive
+	w(uint32(0x08070605))
+	w(int32(3))
+	w(uint32(0x0100000a))
+	w(float32(1.5))
+	w(int32(1))
+	w(uint32(0))
+	w(float32(0))
+	w(int32(2))
+	w(uint32(0x08070605))
+	w(float32(20.25))
+	w(int32(3))
+	start := time.Unix(1470000000, 0)
+	trs := scanAll(t, ingest.NewIPlaneScanner(&buf, 0x01020304, start))
+	if len(trs) != 1 {
+		t.Fatalf("Expected 1 traceroute, got %d", len(trs))
+	}
+	tr := trs[0]
+	if tr.Src != 0x01020304 || tr.Dst != 0x05060708 || tr.GetStart().Sec != start.Unix() {
+		t.Fatalf("Wrong traceroute %v", tr)
+	}
+	hops := tr.GetHops()
+	if len(hops) != 2 || hops[0].Addr != 0x0a000001 || hops[1].Addr != tr.Dst {
+		t.Fatalf("Wrong hops %v", hops)
+	}
+}
@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"io"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	iplane "github.com/NEU-SNS/ReverseTraceroute/iplanetraceroute"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+)
+
+// IPlaneScanner scans an iPlane traceroute file. iPlane files contain
+// the traceroutes of a single vantage point and do not record the source
+// or the time of the measurements, so they must be supplied.
+type IPlaneScanner struct {
+	s     *iplane.TracerouteScanner
+	src   uint32
+	start time.Time
+	tr    *dm.Traceroute
+	err   error
+}
+
+// NewIPlaneScanner creates an IPlaneScanner reading from r. The traceroutes
+// are given the source src and, if start is not zero, the start time start
+func NewIPlaneScanner(r io.Reader, src uint32, start time.Time) *IPlaneScanner {
+	return &IPlaneScanner{
+		s:     iplane.NewTracerouteScanner(r),
+		src:   src,
+		start: start,
+	}
+}
+
+// Scan advances to the next traceroute
+func (is *IPlaneScanner) Scan() bool {
+	if is.err != nil {
+		return false
+	}
+	if !is.s.Scan() {
+		is.err = is.s.Err()
+		return false
+	}
+	t := is.s.Traceroute()
+	tr := &dm.Traceroute{
+		Type: "trace",
+		Src:  is.src,
+	}
+	dst, err := util.IPtoInt32(t.Dest)
+	if err != nil {
+		is.err = err
+		return false
+	}
+	tr.Dst = dst
+	if !is.start.IsZero() {
+		tr.Start = makeTime(is.start)
+	}
+	for _, hop := range t.Hops {
+		addr, err := util.IPtoInt32(hop.IP)
+		if err != nil {
+			is.err = err
+			return false
+		}
+		// iPlane records unresponsive hops as 0.0.0.0
+		if addr == 0 {
+			continue
+		}
+		tr.Hops = append(tr.Hops, &dm.TracerouteHop{
+			Addr:     addr,
+			ProbeTtl: uint32(hop.TTL),
+			Rtt:      makeRTT(float64(hop.Lat)),
+		})
+	}
+	tr.HopCount = uint32(len(tr.Hops))
+	is.tr = tr
+	return true
+}
+
+// Traceroute returns the traceroute from the last scan
+func (is *IPlaneScanner) Traceroute() *dm.Traceroute {
+	return is.tr
+}
+
+// Err returns any error that occured while scanning
+func (is *IPlaneScanner) Err() error {
+	return is.err
+}
@@ -0,0 +1,194 @@
+package ingest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+)
+
+type ripeReply struct {
+	From string  `json:"from"`
+	TTL  uint32  `json:"ttl"`
+	Size uint32  `json:"size"`
+	RTT  float64 `json:"rtt"`
+	X    string  `json:"x"`
+}
+
+type ripeHop struct {
+	Hop    uint32      `json:"hop"`
+	Result []ripeReply `json:"result"`
+}
+
+type ripeTraceroute struct {
+	AF        int       `json:"af"`
+	Type      string    `json:"type"`
+	DstAddr   string    `json:"dst_addr"`
+	SrcAddr   string    `json:"src_addr"`
+	From      string    `json:"from"`
+	Proto     string    `json:"proto"`
+	ParisID   int       `json:"paris_id"`
+	Size      uint32    `json:"size"`
+	Timestamp int64     `json:"timestamp"`
+	Result    []ripeHop `json:"result"`
+}
+
+// RIPEScanner scans a RIPE Atlas traceroute result dump. Both the JSON array
+// returned by the results API and newline delimited dumps are supported.
+// Results that are not IPv4 traceroutes are skipped.
+type RIPEScanner struct {
+	r           *bufio.Reader
+	dec         *json.Decoder
+	array       bool
+	initialized bool
+	tr          *dm.Traceroute
+	err         error
+}
+
+// NewRIPEScanner creates a RIPEScanner reading from r
+func NewRIPEScanner(r io.Reader) *RIPEScanner {
+	return &RIPEScanner{
+		r: bufio.NewReader(r),
+	}
+}
+
+func (rs *RIPEScanner) initialize() error {
+	rs.initialized = true
+	for {
+		b, err := rs.r.Peek(1)
+		if err != nil {
+			return err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			if _, err := rs.r.ReadByte(); err != nil {
+				return err
+			}
+			continue
+		}
+		rs.dec = json.NewDecoder(rs.r)
+		if b[0] != '[' {
+			return nil
+		}
+		rs.array = true
+		_, err = rs.dec.Token()
+		return err
+	}
+}
+
+// Scan advances to the next traceroute
+func (rs *RIPEScanner) Scan() bool {
+	if rs.err != nil {
+		return false
+	}
+	if !rs.initialized {
+		if err := rs.initialize(); err != nil {
+			if err != io.EOF {
+				rs.err = err
+			}
+			return false
+		}
+	}
+	for {
+		if rs.array && !rs.dec.More() {
+			return false
+		}
+		var rt ripeTraceroute
+		err := rs.dec.Decode(&rt)
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			rs.err = err
+			return false
+		}
+		if rt.AF != 4 || rt.Type != "traceroute" {
+			continue
+		}
+		tr, err := convertRIPE(rt)
+		if err != nil {
+			rs.err = err
+			return false
+		}
+		rs.tr = tr
+		return true
+	}
+}
+
+// Traceroute returns the traceroute from the last scan
+func (rs *RIPEScanner) Traceroute() *dm.Traceroute {
+	return rs.tr
+}
+
+// Err returns any error that occured while scanning
+func (rs *RIPEScanner) Err() error {
+	return rs.err
+}
+
+func convertRIPE(rt ripeTraceroute) (*dm.Traceroute, error) {
+	// from is the public address of the probe, src_addr may be behind a NAT
+	src := rt.From
+	if src == "" {
+		src = rt.SrcAddr
+	}
+	srcIP, err := util.IPStringToInt32(src)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid RIPE source %q: %v", src, err)
+	}
+	dstIP, err := util.IPStringToInt32(rt.DstAddr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid RIPE destination %q: %v", rt.DstAddr, err)
+	}
+	method := strings.ToLower(rt.Proto)
+	if rt.ParisID > 0 {
+		method += "-paris"
+	}
+	tr := &dm.Traceroute{
+		Type:      "trace",
+		Method:    method,
+		Src:       srcIP,
+		Dst:       dstIP,
+		Start:     makeTime(time.Unix(rt.Timestamp, 0)),
+		ProbeSize: rt.Size,
+	}
+	for _, hop := range rt.Result {
+		// RIPE sends several packets per hop, each address that replied
+		// is one hop with the fastest of its replies
+		var hops []*dm.TracerouteHop
+		var rtts []float64
+		seen := make(map[uint32]int)
+		for _, rep := range hop.Result {
+			if rep.From == "" {
+				continue
+			}
+			addr, err := util.IPStringToInt32(rep.From)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid RIPE hop %q: %v", rep.From, err)
+			}
+			th := &dm.TracerouteHop{
+				Addr:      addr,
+				ProbeTtl:  hop.Hop,
+				ReplyTtl:  rep.TTL,
+				ReplySize: rep.Size,
+				Rtt:       makeRTT(rep.RTT),
+			}
+			if i, ok := seen[addr]; ok {
+				if rep.RTT < rtts[i] {
+					hops[i], rtts[i] = th, rep.RTT
+				}
+				continue
+			}
+			seen[addr] = len(hops)
+			hops = append(hops, th)
+			rtts = append(rtts, rep.RTT)
+		}
+		tr.Hops = append(tr.Hops, hops...)
+	}
+	tr.HopCount = uint32(len(tr.Hops))
+	return tr, nil
+}
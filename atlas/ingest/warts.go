@@ -0,0 +1,56 @@
+package ingest
+
+import (
+	"io"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+// WartsScanner scans the traceroutes of a warts file such as
+// those published by CAIDA's Ark
+type WartsScanner struct {
+	r   io.Reader
+	wr  *warts.Reader
+	tr  *dm.Traceroute
+	err error
+}
+
+// NewWartsScanner creates a WartsScanner reading from r
+func NewWartsScanner(r io.Reader) *WartsScanner {
+	return &WartsScanner{
+		r: r,
+	}
+}
+
+// Scan advances to the next traceroute
+func (ws *WartsScanner) Scan() bool {
+	if ws.err != nil {
+		return false
+	}
+	if ws.wr == nil {
+		ws.wr, ws.err = warts.NewReader(ws.r)
+		if ws.err != nil {
+			return false
+		}
+	}
+	for ws.wr.Next() {
+		if t, ok := ws.wr.Traceroute(); ok {
+			tr := dm.ConvertTraceroute(t)
+			ws.tr = &tr
+			return true
+		}
+	}
+	ws.err = ws.wr.Err()
+	return false
+}
+
+// Traceroute returns the traceroute from the last scan
+func (ws *WartsScanner) Traceroute() *dm.Traceroute {
+	return ws.tr
+}
+
+// Err returns any error that occured while scanning
+func (ws *WartsScanner) Err() error {
+	return ws.err
+}
@@ -50,3 +50,24 @@ func (_m *Atlas) GetPathsWithToken(_a0 context.Context) (pb.Atlas_GetPathsWithTo
 
 	return r0, r1
 }
+
+// IngestTraceroutes provides a mock function with given fields: _a0
+func (_m *Atlas) IngestTraceroutes(_a0 context.Context) (pb.Atlas_IngestTraceroutesClient, error) {
+	ret := _m.Called(_a0)
+
+	var r0 pb.Atlas_IngestTraceroutesClient
+	if rf, ok := ret.Get(0).(func(context.Context) pb.Atlas_IngestTraceroutesClient); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(pb.Atlas_IngestTraceroutesClient)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
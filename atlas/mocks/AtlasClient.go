@@ -51,3 +51,24 @@ func (_m *AtlasClient) GetPathsWithToken(ctx context.Context, opts ...grpc.CallO
 
 	return r0, r1
 }
+
+// IngestTraceroutes provides a mock function with given fields: ctx, opts
+func (_m *AtlasClient) IngestTraceroutes(ctx context.Context, opts ...grpc.CallOption) (pb.Atlas_IngestTraceroutesClient, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 pb.Atlas_IngestTraceroutesClient
+	if rf, ok := ret.Get(0).(func(context.Context, ...grpc.CallOption) pb.Atlas_IngestTraceroutesClient); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		r0 = ret.Get(0).(pb.Atlas_IngestTraceroutesClient)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -53,3 +53,24 @@ func (_m *AtlasServer) GetPathsWithToken(_a0 *pb.TokenRequest) (*pb.TokenRespons
 
 	return r0, r1
 }
+
+// IngestTraceroute provides a mock function with given fields: _a0
+func (_m *AtlasServer) IngestTraceroute(_a0 *pb.IngestRequest) (bool, error) {
+	ret := _m.Called(_a0)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*pb.IngestRequest) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pb.IngestRequest) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
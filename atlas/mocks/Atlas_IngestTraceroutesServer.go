@@ -0,0 +1,45 @@
+package mocks
+
+import "github.com/NEU-SNS/ReverseTraceroute/atlas/pb"
+import "github.com/stretchr/testify/mock"
+
+type Atlas_IngestTraceroutesServer struct {
+	mock.Mock
+}
+
+// SendAndClose provides a mock function with given fields: _a0
+func (_m *Atlas_IngestTraceroutesServer) SendAndClose(_a0 *pb.IngestResponse) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*pb.IngestResponse) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Recv provides a mock function with given fields:
+func (_m *Atlas_IngestTraceroutesServer) Recv() (*pb.IngestRequest, error) {
+	ret := _m.Called()
+
+	var r0 *pb.IngestRequest
+	if rf, ok := ret.Get(0).(func() *pb.IngestRequest); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pb.IngestRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -34,13 +34,13 @@ func (_m *TRStore) FindIntersectingTraceroute(_a0 types.IntersectionQuery) (*pb.
 	return r0, r1
 }
 
-// StoreAtlasTraceroute provides a mock function with given fields: _a0
-func (_m *TRStore) StoreAtlasTraceroute(_a0 *datamodel.Traceroute) error {
-	ret := _m.Called(_a0)
+// StoreAtlasTraceroute provides a mock function with given fields: _a0, _a1
+func (_m *TRStore) StoreAtlasTraceroute(_a0 *datamodel.Traceroute, _a1 pb.TraceSource) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*datamodel.Traceroute) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(*datamodel.Traceroute, pb.TraceSource) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
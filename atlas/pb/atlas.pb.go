@@ -15,12 +15,15 @@ It has these top-level messages:
 	IntersectionResponse
 	TokenRequest
 	TokenResponse
+	IngestRequest
+	IngestResponse
 */
 package pb
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import datamodel1 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 
 import (
 	context "golang.org/x/net/context"
@@ -65,6 +68,33 @@ func (x IResponseType) String() string {
 }
 func (IResponseType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+type TraceSource int32
+
+const (
+	TraceSource_INTERNAL   TraceSource = 0
+	TraceSource_RIPE_ATLAS TraceSource = 1
+	TraceSource_CAIDA_ARK  TraceSource = 2
+	TraceSource_IPLANE     TraceSource = 3
+)
+
+var TraceSource_name = map[int32]string{
+	0: "INTERNAL",
+	1: "RIPE_ATLAS",
+	2: "CAIDA_ARK",
+	3: "IPLANE",
+}
+var TraceSource_value = map[string]int32{
+	"INTERNAL":   0,
+	"RIPE_ATLAS": 1,
+	"CAIDA_ARK":  2,
+	"IPLANE":     3,
+}
+
+func (x TraceSource) String() string {
+	return proto.EnumName(TraceSource_name, int32(x))
+}
+func (TraceSource) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type Hop struct {
 	Ip  uint32 `protobuf:"varint,1,opt,name=Ip" json:"Ip,omitempty"`
 	Ttl uint32 `protobuf:"varint,2,opt,name=ttl" json:"ttl,omitempty"`
@@ -94,12 +124,13 @@ func (m *Path) GetHops() []*Hop {
 }
 
 type IntersectionRequest struct {
-	Address      uint32 `protobuf:"varint,1,opt,name=address" json:"address,omitempty"`
-	Dest         uint32 `protobuf:"varint,2,opt,name=dest" json:"dest,omitempty"`
-	Staleness    int64  `protobuf:"varint,3,opt,name=staleness" json:"staleness,omitempty"`
-	UseAliases   bool   `protobuf:"varint,4,opt,name=use_aliases" json:"use_aliases,omitempty"`
-	IgnoreSource bool   `protobuf:"varint,5,opt,name=ignore_source" json:"ignore_source,omitempty"`
-	Src          uint32 `protobuf:"varint,6,opt,name=src" json:"src,omitempty"`
+	Address         uint32 `protobuf:"varint,1,opt,name=address" json:"address,omitempty"`
+	Dest            uint32 `protobuf:"varint,2,opt,name=dest" json:"dest,omitempty"`
+	Staleness       int64  `protobuf:"varint,3,opt,name=staleness" json:"staleness,omitempty"`
+	UseAliases      bool   `protobuf:"varint,4,opt,name=use_aliases" json:"use_aliases,omitempty"`
+	IgnoreSource    bool   `protobuf:"varint,5,opt,name=ignore_source" json:"ignore_source,omitempty"`
+	Src             uint32 `protobuf:"varint,6,opt,name=src" json:"src,omitempty"`
+	ExcludeExternal bool   `protobuf:"varint,7,opt,name=exclude_external" json:"exclude_external,omitempty"`
 }
 
 func (m *IntersectionRequest) Reset()                    { *m = IntersectionRequest{} }
@@ -154,6 +185,33 @@ func (m *TokenResponse) GetPath() *Path {
 	return nil
 }
 
+type IngestRequest struct {
+	Source     TraceSource            `protobuf:"varint,1,opt,name=source,enum=atlas.pb.TraceSource" json:"source,omitempty"`
+	Traceroute *datamodel1.Traceroute `protobuf:"bytes,2,opt,name=traceroute" json:"traceroute,omitempty"`
+}
+
+func (m *IngestRequest) Reset()                    { *m = IngestRequest{} }
+func (m *IngestRequest) String() string            { return proto.CompactTextString(m) }
+func (*IngestRequest) ProtoMessage()               {}
+func (*IngestRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *IngestRequest) GetTraceroute() *datamodel1.Traceroute {
+	if m != nil {
+		return m.Traceroute
+	}
+	return nil
+}
+
+type IngestResponse struct {
+	Stored   uint32 `protobuf:"varint,1,opt,name=stored" json:"stored,omitempty"`
+	Rejected uint32 `protobuf:"varint,2,opt,name=rejected" json:"rejected,omitempty"`
+}
+
+func (m *IngestResponse) Reset()                    { *m = IngestResponse{} }
+func (m *IngestResponse) String() string            { return proto.CompactTextString(m) }
+func (*IngestResponse) ProtoMessage()               {}
+func (*IngestResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
 func init() {
 	proto.RegisterType((*Hop)(nil), "atlas.pb.Hop")
 	proto.RegisterType((*Path)(nil), "atlas.pb.Path")
@@ -161,7 +219,10 @@ func init() {
 	proto.RegisterType((*IntersectionResponse)(nil), "atlas.pb.IntersectionResponse")
 	proto.RegisterType((*TokenRequest)(nil), "atlas.pb.TokenRequest")
 	proto.RegisterType((*TokenResponse)(nil), "atlas.pb.TokenResponse")
+	proto.RegisterType((*IngestRequest)(nil), "atlas.pb.IngestRequest")
+	proto.RegisterType((*IngestResponse)(nil), "atlas.pb.IngestResponse")
 	proto.RegisterEnum("atlas.pb.IResponseType", IResponseType_name, IResponseType_value)
+	proto.RegisterEnum("atlas.pb.TraceSource", TraceSource_name, TraceSource_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -177,6 +238,7 @@ const _ = grpc.SupportPackageIsVersion3
 type AtlasClient interface {
 	GetIntersectingPath(ctx context.Context, opts ...grpc.CallOption) (Atlas_GetIntersectingPathClient, error)
 	GetPathsWithToken(ctx context.Context, opts ...grpc.CallOption) (Atlas_GetPathsWithTokenClient, error)
+	IngestTraceroutes(ctx context.Context, opts ...grpc.CallOption) (Atlas_IngestTraceroutesClient, error)
 }
 
 type atlasClient struct {
@@ -249,11 +311,46 @@ func (x *atlasGetPathsWithTokenClient) Recv() (*TokenResponse, error) {
 	return m, nil
 }
 
+func (c *atlasClient) IngestTraceroutes(ctx context.Context, opts ...grpc.CallOption) (Atlas_IngestTraceroutesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Atlas_serviceDesc.Streams[2], c.cc, "/atlas.pb.Atlas/IngestTraceroutes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &atlasIngestTraceroutesClient{stream}
+	return x, nil
+}
+
+type Atlas_IngestTraceroutesClient interface {
+	Send(*IngestRequest) error
+	CloseAndRecv() (*IngestResponse, error)
+	grpc.ClientStream
+}
+
+type atlasIngestTraceroutesClient struct {
+	grpc.ClientStream
+}
+
+func (x *atlasIngestTraceroutesClient) Send(m *IngestRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *atlasIngestTraceroutesClient) CloseAndRecv() (*IngestResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(IngestResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Atlas service
 
 type AtlasServer interface {
 	GetIntersectingPath(Atlas_GetIntersectingPathServer) error
 	GetPathsWithToken(Atlas_GetPathsWithTokenServer) error
+	IngestTraceroutes(Atlas_IngestTraceroutesServer) error
 }
 
 func RegisterAtlasServer(s *grpc.Server, srv AtlasServer) {
@@ -312,6 +409,32 @@ func (x *atlasGetPathsWithTokenServer) Recv() (*TokenRequest, error) {
 	return m, nil
 }
 
+func _Atlas_IngestTraceroutes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AtlasServer).IngestTraceroutes(&atlasIngestTraceroutesServer{stream})
+}
+
+type Atlas_IngestTraceroutesServer interface {
+	SendAndClose(*IngestResponse) error
+	Recv() (*IngestRequest, error)
+	grpc.ServerStream
+}
+
+type atlasIngestTraceroutesServer struct {
+	grpc.ServerStream
+}
+
+func (x *atlasIngestTraceroutesServer) SendAndClose(m *IngestResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *atlasIngestTraceroutesServer) Recv() (*IngestRequest, error) {
+	m := new(IngestRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Atlas_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "atlas.pb.Atlas",
 	HandlerType: (*AtlasServer)(nil),
@@ -329,6 +452,11 @@ var _Atlas_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "IngestTraceroutes",
+			Handler:       _Atlas_IngestTraceroutes_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: fileDescriptor0,
 }
@@ -338,34 +466,45 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 458 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x92, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x86, 0xeb, 0x8f, 0x94, 0x64, 0x52, 0x87, 0x74, 0x03, 0xd4, 0x2a, 0xb4, 0xaa, 0x2c, 0x21,
-	0x45, 0x48, 0x24, 0x28, 0x9c, 0x38, 0xa1, 0x56, 0x04, 0x1a, 0x90, 0x1c, 0xe4, 0xba, 0x42, 0xea,
-	0x25, 0x5a, 0x3b, 0xa3, 0x24, 0xc2, 0x78, 0x97, 0xdd, 0x35, 0x52, 0x6f, 0xfc, 0x23, 0xfe, 0x22,
-	0xeb, 0x8d, 0x53, 0x27, 0xaa, 0x00, 0x71, 0xf3, 0xce, 0xc7, 0x3b, 0xcf, 0xbc, 0x1e, 0x78, 0xb3,
-	0x58, 0xa9, 0x65, 0x91, 0x0c, 0x52, 0xf6, 0x6d, 0x18, 0x8e, 0xaf, 0x5f, 0x5e, 0x85, 0x57, 0xc3,
-	0x08, 0x7f, 0xa0, 0x90, 0x18, 0x0b, 0x9a, 0xa2, 0x60, 0x85, 0xc2, 0x21, 0x55, 0x19, 0x95, 0x43,
-	0x9e, 0xac, 0x3f, 0x06, 0x5c, 0x30, 0xc5, 0x48, 0xb3, 0x7a, 0x24, 0xc1, 0x29, 0x38, 0x97, 0x8c,
-	0x13, 0x00, 0x7b, 0xc2, 0x7d, 0xeb, 0xcc, 0xea, 0x7b, 0xa4, 0x0d, 0x8e, 0x52, 0x99, 0x6f, 0x97,
-	0x8f, 0xe0, 0x02, 0xdc, 0xcf, 0x54, 0x2d, 0xc9, 0x43, 0x78, 0x40, 0xe7, 0x73, 0x81, 0x52, 0x56,
-	0x55, 0x07, 0xe0, 0xce, 0x51, 0xaa, 0x75, 0x19, 0x79, 0x0a, 0xee, 0x92, 0x71, 0xe9, 0x3b, 0x67,
-	0x4e, 0xbf, 0x3d, 0xf2, 0x06, 0x1b, 0xfd, 0x81, 0x16, 0x0f, 0x7e, 0x5a, 0xd0, 0x9b, 0xe4, 0xaa,
-	0x04, 0x4b, 0xd5, 0x8a, 0xe5, 0x11, 0x7e, 0x2f, 0x74, 0xeb, 0xbf, 0x34, 0x0f, 0xa1, 0x25, 0x15,
-	0xcd, 0x30, 0x2f, 0x0b, 0x1c, 0x1d, 0x72, 0x48, 0x0f, 0xda, 0x85, 0xc4, 0x19, 0xcd, 0x56, 0x54,
-	0xa2, 0xf4, 0x5d, 0x1d, 0x6c, 0x92, 0xc7, 0xe0, 0xad, 0x16, 0x39, 0x13, 0x38, 0x93, 0xac, 0x10,
-	0x29, 0xfa, 0x0d, 0x13, 0xd6, 0x6b, 0x48, 0x91, 0xfa, 0xfb, 0x66, 0x8d, 0x5b, 0x78, 0xb4, 0x4b,
-	0x20, 0x39, 0xcb, 0x25, 0x92, 0xe7, 0xe0, 0xaa, 0x5b, 0x8e, 0x66, 0x7e, 0x67, 0x74, 0x54, 0x73,
-	0x4f, 0x36, 0x25, 0xb1, 0x4e, 0x13, 0x0f, 0x1a, 0x8a, 0x7d, 0xc5, 0xbc, 0x22, 0x7b, 0x06, 0x2e,
-	0xd7, 0xa6, 0x18, 0xa8, 0xf6, 0xa8, 0x53, 0x77, 0x19, 0xab, 0x74, 0x31, 0x0a, 0xc1, 0x84, 0xc1,
-	0x6b, 0x05, 0x27, 0x70, 0x10, 0x97, 0xbd, 0x9b, 0xad, 0xef, 0xb4, 0xcc, 0xce, 0x81, 0x00, 0xaf,
-	0x4a, 0x57, 0x48, 0xbb, 0xf9, 0x3b, 0x42, 0xfb, 0xef, 0x84, 0xff, 0x83, 0xf4, 0xe2, 0x2d, 0x78,
-	0xbb, 0xdd, 0x1d, 0x80, 0x70, 0x1a, 0x8e, 0x67, 0xef, 0xa7, 0xd7, 0xe1, 0xbb, 0xee, 0x1e, 0x69,
-	0x41, 0x23, 0x9e, 0x7e, 0x1a, 0x87, 0x5d, 0x8b, 0x34, 0xf5, 0x01, 0x9c, 0xc7, 0x97, 0x5d, 0xbb,
-	0x0c, 0x8e, 0xa3, 0x68, 0x1a, 0x75, 0x9d, 0xd1, 0x2f, 0x0b, 0x1a, 0xe7, 0xe5, 0x04, 0x72, 0x03,
-	0xbd, 0x0f, 0xa8, 0x6a, 0x6f, 0xf3, 0x85, 0x19, 0x78, 0xb2, 0xc5, 0x79, 0xff, 0xcf, 0x1f, 0x9f,
-	0xfe, 0x29, 0xbd, 0x66, 0x0a, 0xf6, 0xfa, 0xd6, 0x2b, 0x8b, 0x7c, 0x84, 0x43, 0xad, 0x5d, 0xea,
-	0xc9, 0x2f, 0xfa, 0xd4, 0x8d, 0x4d, 0xe4, 0x49, 0xdd, 0xba, 0x6d, 0xeb, 0xf1, 0xd1, 0xbd, 0xf8,
-	0xb6, 0xd6, 0x85, 0x7b, 0x63, 0xf3, 0x24, 0xd9, 0x37, 0xe7, 0xff, 0xfa, 0x77, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x31, 0xd5, 0xba, 0x93, 0x3b, 0x03, 0x00, 0x00,
+	// 626 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0x5f, 0x6f, 0xda, 0x4a,
+	0x10, 0xc5, 0x63, 0x6c, 0x08, 0x0c, 0x31, 0xd7, 0xd9, 0xdc, 0xdc, 0x58, 0xdc, 0x26, 0x42, 0x96,
+	0x22, 0xd1, 0x48, 0x85, 0x8a, 0x3e, 0xf5, 0x29, 0x72, 0x1a, 0x27, 0x71, 0x13, 0x19, 0x64, 0x1c,
+	0x55, 0xca, 0x0b, 0x32, 0xf6, 0x08, 0x68, 0x1d, 0xaf, 0xbb, 0xbb, 0x54, 0xc9, 0x57, 0xe9, 0x57,
+	0xed, 0x4b, 0xe5, 0x3f, 0x60, 0xd2, 0xa8, 0xff, 0xde, 0xd8, 0x99, 0xd9, 0xc3, 0x6f, 0xce, 0x1e,
+	0x19, 0xde, 0xce, 0x16, 0x62, 0xbe, 0x9c, 0xf6, 0x02, 0x7a, 0xdf, 0x77, 0xac, 0xdb, 0x57, 0x63,
+	0x67, 0xdc, 0x77, 0xf1, 0x0b, 0x32, 0x8e, 0x1e, 0xf3, 0x03, 0x64, 0x74, 0x29, 0xb0, 0xef, 0x8b,
+	0xc8, 0xe7, 0xfd, 0x64, 0x9a, 0xff, 0xe8, 0x25, 0x8c, 0x0a, 0x4a, 0xea, 0xc5, 0x61, 0xda, 0x3e,
+	0xfd, 0x23, 0x91, 0xd0, 0x17, 0xfe, 0x3d, 0x0d, 0x31, 0xea, 0x8b, 0x75, 0x31, 0x97, 0x32, 0x8e,
+	0x40, 0xbe, 0xa2, 0x09, 0x01, 0xa8, 0xd8, 0x89, 0x2e, 0x75, 0xa4, 0xae, 0x4a, 0x9a, 0x20, 0x0b,
+	0x11, 0xe9, 0x95, 0xf4, 0x60, 0x9c, 0x81, 0x32, 0xf2, 0xc5, 0x9c, 0xfc, 0x03, 0xdb, 0x7e, 0x18,
+	0x32, 0xe4, 0xbc, 0x98, 0xda, 0x01, 0x25, 0x44, 0x2e, 0xf2, 0x31, 0xf2, 0x3f, 0x28, 0x73, 0x9a,
+	0x70, 0x5d, 0xee, 0xc8, 0xdd, 0xe6, 0x40, 0xed, 0xad, 0x00, 0x7b, 0x57, 0x34, 0x31, 0xbe, 0x4a,
+	0xb0, 0x67, 0xc7, 0x22, 0x85, 0x0a, 0xc4, 0x82, 0xc6, 0x2e, 0x7e, 0x5e, 0x22, 0x17, 0xbf, 0xd3,
+	0xdc, 0x85, 0x06, 0x17, 0x7e, 0x84, 0x71, 0x3a, 0x20, 0x77, 0xa4, 0xae, 0x4c, 0xf6, 0xa0, 0xb9,
+	0xe4, 0x38, 0xf1, 0xa3, 0x85, 0xcf, 0x91, 0xeb, 0x4a, 0x47, 0xea, 0xd6, 0xc9, 0x3e, 0xa8, 0x8b,
+	0x59, 0x4c, 0x19, 0x4e, 0x38, 0x5d, 0xb2, 0x00, 0xf5, 0x6a, 0x56, 0x6e, 0x82, 0xcc, 0x59, 0xa0,
+	0xd7, 0x32, 0x2d, 0x1d, 0x34, 0x7c, 0x08, 0xa2, 0x65, 0x88, 0x13, 0x7c, 0x10, 0xc8, 0x62, 0x3f,
+	0xd2, 0xb7, 0xd3, 0x31, 0xe3, 0x11, 0xfe, 0x7d, 0xca, 0xc6, 0x13, 0x1a, 0x73, 0x24, 0xc7, 0xa0,
+	0x88, 0xc7, 0x04, 0x33, 0xb2, 0xd6, 0xe0, 0xa0, 0xdc, 0xc8, 0x5e, 0x8d, 0x78, 0x8f, 0x09, 0x12,
+	0x15, 0xaa, 0x82, 0x7e, 0xc2, 0xb8, 0x60, 0x7e, 0x01, 0x4a, 0xe2, 0x8b, 0x79, 0x86, 0xdb, 0x1c,
+	0xb4, 0xca, 0x5b, 0x99, 0x89, 0x2a, 0x54, 0x91, 0x31, 0xca, 0x32, 0xf0, 0x86, 0x71, 0x08, 0x3b,
+	0x5e, 0x7a, 0x77, 0xe5, 0xc7, 0x5a, 0x2b, 0x73, 0xc3, 0x60, 0xa0, 0x16, 0xed, 0x02, 0xe9, 0x69,
+	0x7f, 0x4d, 0x58, 0xf9, 0x35, 0xe1, 0x5f, 0x21, 0xf9, 0xa0, 0xda, 0xf1, 0x0c, 0xb9, 0x58, 0x31,
+	0x1d, 0x43, 0xad, 0x70, 0x35, 0x37, 0x62, 0xbf, 0xbc, 0x9f, 0x05, 0x6c, 0x9c, 0x35, 0xc9, 0x4b,
+	0x80, 0x32, 0x5a, 0x19, 0x51, 0x73, 0xb0, 0xdf, 0x5b, 0xe7, 0xae, 0x57, 0x86, 0xd1, 0x18, 0x40,
+	0x6b, 0xf5, 0x17, 0xc5, 0x5e, 0x2d, 0xa8, 0x71, 0x41, 0x19, 0x86, 0xc5, 0x62, 0x1a, 0xd4, 0x19,
+	0x7e, 0xc4, 0x40, 0x60, 0x98, 0xdb, 0x7a, 0x72, 0x0a, 0xea, 0xd3, 0xa5, 0x5a, 0x00, 0xce, 0xd0,
+	0xb1, 0x26, 0x17, 0xc3, 0x5b, 0xe7, 0x5c, 0xdb, 0x22, 0x0d, 0xa8, 0x7a, 0xc3, 0x6b, 0xcb, 0xd1,
+	0x24, 0x52, 0x07, 0x65, 0x64, 0x7a, 0x57, 0x5a, 0x25, 0x2d, 0x5a, 0xae, 0x3b, 0x74, 0x35, 0xf9,
+	0xe4, 0x02, 0x9a, 0x9b, 0xb8, 0x3b, 0x50, 0xb7, 0x1d, 0xcf, 0x72, 0x1d, 0xf3, 0x46, 0xdb, 0x4a,
+	0xc5, 0x5c, 0x7b, 0x64, 0x4d, 0x4c, 0xef, 0xc6, 0x1c, 0x6b, 0x12, 0x51, 0xa1, 0xf1, 0xce, 0xb4,
+	0xcf, 0xcd, 0x89, 0xe9, 0x5e, 0x6b, 0x15, 0x02, 0x50, 0xb3, 0x47, 0x37, 0xa6, 0x63, 0x69, 0xf2,
+	0xe0, 0x9b, 0x04, 0x55, 0x33, 0x35, 0x80, 0xdc, 0xc1, 0xde, 0x25, 0x8a, 0x32, 0x3a, 0xf1, 0x2c,
+	0xf3, 0xf3, 0x70, 0xe3, 0x19, 0x9e, 0x47, 0xbe, 0x7d, 0xf4, 0xb3, 0x76, 0xbe, 0x9b, 0xb1, 0xd5,
+	0x95, 0x5e, 0x4b, 0xe4, 0x3d, 0xec, 0x5e, 0xa2, 0x48, 0xf5, 0xf8, 0x87, 0x85, 0x98, 0x67, 0x29,
+	0x20, 0xff, 0x6d, 0x38, 0xbf, 0x91, 0x9a, 0xf6, 0xc1, 0xb3, 0xfa, 0x8f, 0x5a, 0xb9, 0xdd, 0xe5,
+	0x13, 0x70, 0xb2, 0x19, 0x96, 0xcd, 0xe7, 0x6e, 0xeb, 0xcf, 0x1b, 0xa5, 0xda, 0x99, 0x72, 0x57,
+	0x49, 0xa6, 0xd3, 0x5a, 0xf6, 0xe5, 0x78, 0xf3, 0x7d, 0x00, 0x62, 0x4e, 0x93, 0x9d, 0xc1, 0x04,
+	0x00, 0x00,
 }
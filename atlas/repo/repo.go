@@ -102,7 +102,7 @@ FROM
 (
 (SELECT atr.Id, atr.date, atr.dest FROM
 atlas_traceroutes atr 
-WHERE atr.dest = ? AND atr.date >= DATE_SUB(NOW(), interval ?  minute) 
+WHERE atr.dest = ? AND atr.date >= DATE_SUB(NOW(), interval ?  minute) AND (? = 0 OR atr.source = 0)
 ORDER BY atr.date desc)
 ) X 
 INNER JOIN atlas_traceroute_hops ath on ath.trace_id = X.Id
@@ -134,7 +134,7 @@ FROM
 (
 (SELECT atr.Id, atr.date, atr.dest FROM
 atlas_traceroutes atr 
-WHERE  atr.src != ? AND atr.dest = ? AND atr.date >= DATE_SUB(NOW(), interval ?  minute) 
+WHERE  atr.src != ? AND atr.dest = ? AND atr.date >= DATE_SUB(NOW(), interval ?  minute) AND (? = 0 OR atr.source = 0)
 ORDER BY atr.date desc)
 ) X 
 INNER JOIN atlas_traceroute_hops ath on ath.trace_id = X.Id
@@ -161,7 +161,7 @@ SELECT
 FROM
     atlas_traceroutes
 WHERE
-    dest = ? AND date >= DATE_SUB(NOW(), interval ? minute) 
+    dest = ? AND date >= DATE_SUB(NOW(), interval ? minute) AND source = 0
 GROUP BY
     src;
 `
@@ -180,7 +180,8 @@ var (
 )
 
 // GetAtlasSources gets all sources that were used for existing atlas traceroutes
-// the set of vps - this would be the sources to use to run traces
+// the set of vps - this would be the sources to use to run traces.
+// Traceroutes ingested from external sources are not included
 func (r *Repo) GetAtlasSources(dst uint32, stale time.Duration) ([]uint32, error) {
 	rows, err := r.repo.GetReader().Query(getSources, dst, int64(stale.Minutes()))
 	var srcs []uint32
@@ -211,13 +212,13 @@ func (r *Repo) FindIntersectingTraceroute(iq types.IntersectionQuery) (*pb.Path,
 	var rows *sql.Rows
 	var err error
 	if iq.IgnoreSource {
-		rows, err = r.repo.GetReader().Query(findIntersectingIgnoreSource, iq.Addr, iq.Src, iq.Dst, int64(iq.Stale.Minutes()), iq.Addr, iq.Addr)
+		rows, err = r.repo.GetReader().Query(findIntersectingIgnoreSource, iq.Addr, iq.Src, iq.Dst, int64(iq.Stale.Minutes()), iq.ExcludeExternal, iq.Addr, iq.Addr)
 		if err != nil {
 			log.Error(err)
 			return nil, err
 		}
 	} else {
-		rows, err = r.repo.GetReader().Query(findIntersecting, iq.Addr, iq.Dst, int64(iq.Stale.Minutes()), iq.Addr, iq.Addr)
+		rows, err = r.repo.GetReader().Query(findIntersecting, iq.Addr, iq.Dst, int64(iq.Stale.Minutes()), iq.ExcludeExternal, iq.Addr, iq.Addr)
 		if err != nil {
 			log.Error(err)
 			return nil, err
@@ -248,20 +249,30 @@ func (r *Repo) FindIntersectingTraceroute(iq types.IntersectionQuery) (*pb.Path,
 }
 
 const (
-	insertAtlasTrace = `INSERT INTO atlas_traceroutes(dest, src) VALUES(?, ?)`
-	insertAtlasHop   = `
+	insertAtlasTrace         = `INSERT INTO atlas_traceroutes(dest, src, source) VALUES(?, ?, ?)`
+	insertExternalAtlasTrace = `
+	INSERT INTO atlas_traceroutes(dest, src, source, date) 
+	VALUES(?, ?, ?, FROM_UNIXTIME(?))`
+	insertAtlasHop = `
 	INSERT INTO atlas_traceroute_hops(trace_id, hop, ttl) 
 	VALUES (?, ?, ?)`
 )
 
-// StoreAtlasTraceroute stores a traceroute in a form that the Atlas requires
-func (r *Repo) StoreAtlasTraceroute(trace *datamodel.Traceroute) error {
+// StoreAtlasTraceroute stores a traceroute in a form that the Atlas requires.
+// Traceroutes from an external source keep the time they were measured so
+// that staleness checks apply to them as well
+func (r *Repo) StoreAtlasTraceroute(trace *datamodel.Traceroute, source pb.TraceSource) error {
 	conn := r.repo.GetWriter()
 	tx, err := conn.Begin()
 	if err != nil {
 		return err
 	}
-	res, err := tx.Exec(insertAtlasTrace, trace.Dst, trace.Src)
+	var res sql.Result
+	if source == pb.TraceSource_INTERNAL || trace.GetStart() == nil {
+		res, err = tx.Exec(insertAtlasTrace, trace.Dst, trace.Src, int32(source))
+	} else {
+		res, err = tx.Exec(insertExternalAtlasTrace, trace.Dst, trace.Src, int32(source), trace.GetStart().Sec)
+	}
 	if err != nil {
 		logError(tx.Rollback)
 		return err
@@ -42,6 +42,7 @@ import (
 	cclient "github.com/NEU-SNS/ReverseTraceroute/controller/client"
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/trvalidate"
 	"github.com/NEU-SNS/ReverseTraceroute/vpservice/client"
 	vppb "github.com/NEU-SNS/ReverseTraceroute/vpservice/pb"
 	"github.com/prometheus/client_golang/prometheus"
@@ -60,17 +61,25 @@ var (
 		Name:      "traceroutes",
 		Help:      "The current number of running traceroutes",
 	})
+	ingestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: nameSpace,
+		Subsystem: "ingest",
+		Name:      "traceroutes",
+		Help:      "The number of external traceroutes ingested by source and result",
+	}, []string{"source", "result"})
 )
 
 func init() {
 	prometheus.MustRegister(procCollector)
 	prometheus.MustRegister(tracerouteGauge)
+	prometheus.MustRegister(ingestCounter)
 }
 
 // AtlasServer is the interface for the atlas
 type AtlasServer interface {
 	GetIntersectingPath(*pb.IntersectionRequest) (*pb.IntersectionResponse, error)
 	GetPathsWithToken(*pb.TokenRequest) (*pb.TokenResponse, error)
+	IngestTraceroute(*pb.IngestRequest) (bool, error)
 }
 
 type server struct {
@@ -86,6 +95,7 @@ type serverOptions struct {
 	vps client.VPSource
 	trs types.TRStore
 	ca  Cache
+	v   *trvalidate.Validator
 }
 
 // Cache is the cache used for the atlas
@@ -126,6 +136,14 @@ func WithCache(ca Cache) Option {
 	}
 }
 
+// WithValidator configures the server to only store traceroutes
+// that pass validation by v
+func WithValidator(v *trvalidate.Validator) Option {
+	return func(opts *serverOptions) {
+		opts.v = v
+	}
+}
+
 // NewServer creates a server
 func NewServer(opts ...Option) AtlasServer {
 	atlas := &server{
@@ -153,12 +171,13 @@ func (a *server) GetPathsWithToken(tr *pb.TokenRequest) (*pb.TokenResponse, erro
 	}
 	a.tc.Remove(tr.Token)
 	ir := types.IntersectionQuery{
-		Addr:         req.Address,
-		Dst:          req.Dest,
-		Src:          req.Src,
-		Stale:        time.Duration(req.Staleness) * time.Minute,
-		IgnoreSource: req.IgnoreSource,
-		Alias:        req.UseAliases,
+		Addr:            req.Address,
+		Dst:             req.Dest,
+		Src:             req.Src,
+		Stale:           time.Duration(req.Staleness) * time.Minute,
+		IgnoreSource:    req.IgnoreSource,
+		Alias:           req.UseAliases,
+		ExcludeExternal: req.ExcludeExternal,
 	}
 	log.Debug("Looking for intesection for: ", req)
 	path, err := a.opts.trs.FindIntersectingTraceroute(ir)
@@ -190,12 +209,13 @@ func (a *server) GetIntersectingPath(ir *pb.IntersectionRequest) (*pb.Intersecti
 		ir.Staleness = 60
 	}
 	iq := types.IntersectionQuery{
-		Addr:         ir.Address,
-		Dst:          ir.Dest,
-		Src:          ir.Src,
-		Stale:        time.Duration(ir.Staleness) * time.Minute,
-		Alias:        ir.UseAliases,
-		IgnoreSource: ir.IgnoreSource,
+		Addr:            ir.Address,
+		Dst:             ir.Dest,
+		Src:             ir.Src,
+		Stale:           time.Duration(ir.Staleness) * time.Minute,
+		Alias:           ir.UseAliases,
+		IgnoreSource:    ir.IgnoreSource,
+		ExcludeExternal: ir.ExcludeExternal,
 	}
 	res, err := a.opts.trs.FindIntersectingTraceroute(iq)
 	log.Debug("FindIntersectingTraceroute resp ", res)
@@ -229,6 +249,52 @@ func (a *server) GetIntersectingPath(ir *pb.IntersectionRequest) (*pb.Intersecti
 	return intr, nil
 }
 
+// IngestTraceroute satisfies the server interface
+// It returns false if the traceroute was rejected and an error
+// if the traceroute could not be stored
+func (a *server) IngestTraceroute(ir *pb.IngestRequest) (bool, error) {
+	source := ir.Source.String()
+	if reason := checkIngest(ir); reason != "" {
+		log.Debug("Rejecting ingested traceroute: ", reason, " ", ir)
+		ingestCounter.WithLabelValues(source, "rejected").Inc()
+		return false, nil
+	}
+	if err := a.opts.v.Validate(ir.Traceroute); err != nil {
+		log.Debug(err)
+		ingestCounter.WithLabelValues(source, "rejected").Inc()
+		return false, nil
+	}
+	err := a.opts.trs.StoreAtlasTraceroute(ir.Traceroute, ir.Source)
+	if err != nil {
+		log.Error(err)
+		ingestCounter.WithLabelValues(source, "error").Inc()
+		return false, err
+	}
+	ingestCounter.WithLabelValues(source, "stored").Inc()
+	return true, nil
+}
+
+func checkIngest(ir *pb.IngestRequest) string {
+	if ir.Source == pb.TraceSource_INTERNAL {
+		return "source must be external"
+	}
+	t := ir.GetTraceroute()
+	if t == nil {
+		return "missing traceroute"
+	}
+	if t.Src == 0 || t.Dst == 0 {
+		return "missing src or dst"
+	}
+	hops := t.GetHops()
+	if len(hops) == 0 {
+		return "no hops"
+	}
+	if hops[len(hops)-1].Addr != t.Dst {
+		return "did not reach destination"
+	}
+	return ""
+}
+
 func (a *server) fillAtlas(hop, dest uint32, stale int64) {
 	srcs := a.getSrcs(hop, dest, stale)
 	log.Debug("Sources to fill atlas for ", dest, " ", srcs, " ", len(srcs), " new sources.")
@@ -291,7 +357,11 @@ func (a *server) fillAtlas(hop, dest uint32, stale int64) {
 			log.Error("Traceroute did not reach destination")
 			continue
 		}
-		err = a.opts.trs.StoreAtlasTraceroute(t)
+		if err := a.opts.v.Validate(t); err != nil {
+			log.Debug(err)
+			continue
+		}
+		err = a.opts.trs.StoreAtlasTraceroute(t, pb.TraceSource_INTERNAL)
 		if err != nil {
 			log.Error(err)
 		}
@@ -36,6 +36,7 @@ import (
 	"github.com/NEU-SNS/ReverseTraceroute/atlas/repo"
 	"github.com/NEU-SNS/ReverseTraceroute/atlas/server"
 	cmocks "github.com/NEU-SNS/ReverseTraceroute/controller/mocks"
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	vpmocks "github.com/NEU-SNS/ReverseTraceroute/vpservice/mocks"
 	vppb "github.com/NEU-SNS/ReverseTraceroute/vpservice/pb"
 	"github.com/stretchr/testify/mock"
@@ -156,3 +157,42 @@ func TestGetPathsWithTokenInvalidToken(t *testing.T) {
 		t.Fatalf("Unexpected response resp[%v], err[%v]", r, err)
 	}
 }
+
+func TestIngestTraceroute(t *testing.T) {
+	complete := &dm.Traceroute{
+		Src:  1,
+		Dst:  3,
+		Hops: []*dm.TracerouteHop{{Addr: 2}, {Addr: 3}},
+	}
+	incomplete := &dm.Traceroute{
+		Src:  1,
+		Dst:  3,
+		Hops: []*dm.TracerouteHop{{Addr: 2}},
+	}
+	for _, test := range []struct {
+		desc   string
+		req    *pb.IngestRequest
+		stored bool
+	}{
+		{desc: "stored", req: &pb.IngestRequest{Source: pb.TraceSource_RIPE_ATLAS, Traceroute: complete}, stored: true},
+		{desc: "internal source", req: &pb.IngestRequest{Source: pb.TraceSource_INTERNAL, Traceroute: complete}},
+		{desc: "no traceroute", req: &pb.IngestRequest{Source: pb.TraceSource_IPLANE}},
+		{desc: "incomplete", req: &pb.IngestRequest{Source: pb.TraceSource_CAIDA_ARK, Traceroute: incomplete}},
+	} {
+		trsm := &mocks.TRStore{}
+		trsm.On("StoreAtlasTraceroute", test.req.Traceroute, test.req.Source).Return(nil)
+		serv := server.NewServer(server.WithTRS(trsm), server.WithCache(&mockCache{}))
+		stored, err := serv.IngestTraceroute(test.req)
+		if err != nil {
+			t.Fatalf("%s: IngestTraceroute failed: %v", test.desc, err)
+		}
+		if stored != test.stored {
+			t.Fatalf("%s: expected stored %v, got %v", test.desc, test.stored, stored)
+		}
+		if test.stored {
+			trsm.AssertExpectations(t)
+		} else {
+			trsm.AssertNotCalled(t, "StoreAtlasTraceroute", test.req.Traceroute, test.req.Source)
+		}
+	}
+}
@@ -9,15 +9,16 @@ import (
 
 // IntersectionQuery represents a request to the TRStore for an intersecting traceroute
 type IntersectionQuery struct {
-	Addr, Dst, Src uint32
-	Alias          bool
-	Stale          time.Duration
-	IgnoreSource   bool
+	Addr, Dst, Src  uint32
+	Alias           bool
+	Stale           time.Duration
+	IgnoreSource    bool
+	ExcludeExternal bool
 }
 
 // TRStore is the interface required by the
 type TRStore interface {
 	FindIntersectingTraceroute(IntersectionQuery) (*pb.Path, error)
-	StoreAtlasTraceroute(*datamodel.Traceroute) error
+	StoreAtlasTraceroute(*datamodel.Traceroute, pb.TraceSource) error
 	GetAtlasSources(uint32, time.Duration) ([]uint32, error)
 }
@@ -21,6 +21,7 @@ import (
 	cclient "github.com/NEU-SNS/ReverseTraceroute/controller/client"
 	"github.com/NEU-SNS/ReverseTraceroute/httputils"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/trvalidate"
 	vpsclient "github.com/NEU-SNS/ReverseTraceroute/vpservice/client"
 	"github.com/hashicorp/golang-lru"
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,15 +29,29 @@ import (
 
 // Config is the config for the atlas
 type Config struct {
-	DB       repo.Configs
-	RootCA   string `flag:"root-ca"`
-	CertFile string `flag:"cert-file"`
-	KeyFile  string `flag:"key-file"`
+	DB         repo.Configs
+	RootCA     string `flag:"root-ca"`
+	CertFile   string `flag:"cert-file"`
+	KeyFile    string `flag:"key-file"`
+	Validation trvalidate.Config
+}
+
+var conf = Config{
+	Validation: trvalidate.NewConfig(),
 }
 
 func init() {
 	config.SetEnvPrefix("ATLAS")
 	config.AddConfigPath("./atlas.config")
+	// Validation is on by default with the rules of trvalidate.NewConfig
+	flag.BoolVar(conf.Validation.Loops, "validate-loops", *conf.Validation.Loops,
+		"Do not store traceroutes that contain loops or repeated hops, on by default, false stores them")
+	flag.IntVar(conf.Validation.MaxGaps, "validate-max-gaps", *conf.Validation.MaxGaps,
+		"Do not store traceroutes with more unresponsive hops than this, 3 by default, negative disables the check")
+	flag.BoolVar(conf.Validation.PrivateHops, "validate-private-hops", *conf.Validation.PrivateHops,
+		"Do not store traceroutes that contain private or bogon hops, on by default, false stores them")
+	flag.BoolVar(conf.Validation.MonotonicTTL, "validate-ttl-order", *conf.Validation.MonotonicTTL,
+		"Do not store traceroutes whose hop ttls are not increasing, on by default, false stores them")
 	trace.AuthRequest = func(req *http.Request) (any, sensitive bool) {
 		host, _, err := net.SplitHostPort(req.RemoteAddr)
 		switch {
@@ -60,7 +75,6 @@ func logError(f errorf) {
 }
 
 func main() {
-	conf := Config{}
 	err := config.Parse(flag.CommandLine, &conf)
 	if err != nil {
 		log.Fatal(err)
@@ -97,7 +111,8 @@ func main() {
 	serv := server.NewServer(server.WithVPS(vps),
 		server.WithTRS(r),
 		server.WithClient(cc),
-		server.WithCache(cache))
+		server.WithCache(cache),
+		server.WithValidator(trvalidate.New(conf.Validation)))
 	ln, err := net.Listen("tcp", ":55000")
 	if err != nil {
 		log.Fatal(err)
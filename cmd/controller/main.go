@@ -35,6 +35,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc/grpclog"
 
@@ -46,6 +47,7 @@ import (
 	da "github.com/NEU-SNS/ReverseTraceroute/dataaccess"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
 	"github.com/NEU-SNS/ReverseTraceroute/router"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
 )
 
@@ -69,8 +71,47 @@ func init() {
 		"The path to the private key for the file")
 	flag.Int64Var(conf.Local.ConnTimeout, "conn-timeout", 60,
 		"How long to wait for an rpc connection to timeout")
+	flag.Int64Var(conf.Local.SpoofTimeout, "spoof-timeout", 60,
+		"How long to wait for the response to a spoofed probe that has no timeout of its own")
+	flag.Int64Var(conf.Local.RouteRefresh, "route-refresh", 60,
+		"Seconds between reloading which plcontroller each vantage point uses, 0 sends everything to the default plcontroller")
+	flag.StringVar(conf.Local.PLCPort, "plc-port", "4380",
+		"The port plcontrollers listen on")
+	flag.StringVar(conf.Local.AdminAddr, "admin-addr", "127.0.0.1:55556",
+		"The address operators drain plcontrollers on, it has no authentication so keep it private")
+	flag.StringVar(conf.Local.LocalScamper, "local-scamper", "",
+		"Run every measurement on a scamper on this host, either the path of its unix control socket or host:port")
+	flag.StringVar(conf.Local.LocalFormat, "local-scamper-format", "warts",
+		"The format results are asked for from the local scamper, warts or json. A scamper without JSON output uses warts")
+	flag.Int64Var(conf.Local.JobTimeout, "job-timeout", 30,
+		"Seconds a batch of measurements submitted through the HTTP api may run")
+	flag.IntVar(conf.Local.MaxJobs, "max-jobs", 10,
+		"The number of HTTP api batches that run at once, others are queued")
+	flag.Int64Var(conf.Alias.Interval, "alias-interval", 0,
+		"Seconds between rounds of alias resolution on new atlas and reverse traceroute paths, 0 disables it")
+	flag.IntVar(conf.Alias.Batch, "alias-batch", 100,
+		"The most alias candidate pairs measured in a round")
+	flag.StringVar(conf.Alias.VP, "alias-vp", "",
+		"The vantage point alias resolution probes from, any vantage point when empty")
 	flag.Var(conf.Cache.Addrs, "cache-list",
 		"The list of cache servers.")
+	// Validation is on by default with the rules of trvalidate.NewConfig
+	flag.BoolVar(conf.Validation.Loops, "validate-loops", *conf.Validation.Loops,
+		"Do not store traceroutes that contain loops or repeated hops, on by default, false stores them")
+	flag.IntVar(conf.Validation.MaxGaps, "validate-max-gaps", *conf.Validation.MaxGaps,
+		"Do not store traceroutes with more unresponsive hops than this, 3 by default, negative disables the check")
+	flag.BoolVar(conf.Validation.PrivateHops, "validate-private-hops", *conf.Validation.PrivateHops,
+		"Do not store traceroutes that contain private or bogon hops, on by default, false stores them")
+	flag.BoolVar(conf.Validation.MonotonicTTL, "validate-ttl-order", *conf.Validation.MonotonicTTL,
+		"Do not store traceroutes whose hop ttls are not increasing, on by default, false stores them")
+	flag.Float64Var(conf.Limits.VPRate, "vp-rate", 50,
+		"Measurements per second sent from a single vantage point, 0 disables the limit")
+	flag.IntVar(conf.Limits.VPBurst, "vp-burst", 100,
+		"Measurements a vantage point may send in a burst")
+	flag.Float64Var(conf.Limits.DstRate, "dst-rate", 10,
+		"Measurements per second sent to a single destination, 0 disables the limit")
+	flag.IntVar(conf.Limits.DstBurst, "dst-burst", 20,
+		"Measurements a destination may receive in a burst")
 	trace.AuthRequest = func(req *http.Request) (any, sensitive bool) {
 		host, _, err := net.SplitHostPort(req.RemoteAddr)
 		switch {
@@ -98,7 +139,38 @@ func main() {
 		log.Errorf("Failed to create db: %v", err)
 		exit(1)
 	}
-	err = <-controller.Start(conf, db, cache.New(*conf.Cache.Addrs), router.New(*conf.Local.RootCA))
+	r := router.New(*conf.Local.RootCA)
+	switch {
+	case *conf.Local.LocalScamper != "":
+		addr, port := *conf.Local.LocalScamper, ""
+		if host, p, err := net.SplitHostPort(addr); err == nil {
+			addr, port = host, p
+		}
+		f, err := scamper.ParseFormat(*conf.Local.LocalFormat)
+		if err != nil {
+			log.Errorf("Failed to parse config: %v", err)
+			exit(1)
+		}
+		r.SetSource(router.NewLocalSource(addr, port, f))
+	case *conf.Local.RouteRefresh > 0:
+		refresh := time.Duration(*conf.Local.RouteRefresh) * time.Second
+		src := router.NewDBSource(db, *conf.Local.PLCPort, refresh, refresh)
+		if err := src.Refresh(); err != nil {
+			log.Errorf("Failed to load vantage point routes: %v", err)
+		}
+		go src.Run(nil)
+		http.Handle("/plcontrollers", src)
+		admin := http.NewServeMux()
+		admin.Handle("/plcontrollers", src.DrainHandler())
+		go func() {
+			for {
+				log.Error(http.ListenAndServe(*conf.Local.AdminAddr, admin))
+				time.Sleep(time.Second)
+			}
+		}()
+		r.SetSource(src)
+	}
+	err = <-controller.Start(conf, db, cache.New(*conf.Cache.Addrs), r)
 
 	if err != nil {
 		log.Errorf("Controller Start returned with error: %v", err)
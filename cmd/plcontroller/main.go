@@ -35,6 +35,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc/grpclog"
 
@@ -74,6 +75,12 @@ func init() {
 
 	flag.Int64Var(conf.Local.Timeout, "t", 60,
 		"The default timeout used for measurement requests.")
+	flag.Int64Var(conf.Local.SpoofExpire, "spoof-expire", 60,
+		"Seconds a spoof waits for its probe and a received probe is retried before it is dropped.")
+	flag.BoolVar(conf.Local.Handover, "handover", false,
+		"Take over the scamper and vps of a plcontroller that was sent SIGUSR2 instead of starting fresh.")
+	flag.Int64Var(conf.Local.DrainTimeout, "drain-timeout", 60,
+		"Seconds running measurements get to finish when handing over on SIGUSR2.")
 	flag.StringVar(conf.Local.Addr, "a", "0.0.0.0",
 		"The address that the controller will bind to.")
 	flag.IntVar(conf.Local.Port, "p", 4380,
@@ -88,6 +95,8 @@ func init() {
 		"Path to the scamper binary")
 	flag.StringVar(conf.Scamper.ConverterPath, "converter-path", "/usr/local/bin/sc_warts2json",
 		"Path for warts parser")
+	flag.StringVar(conf.Scamper.Format, "scamper-format", "warts",
+		"The format vantage point results are asked for, warts or json. Vantage points whose scamper has no JSON output use warts")
 	flag.StringVar(conf.Local.PProfAddr, "pprof", ":55556",
 		"The port for pprof")
 	flag.StringVar(conf.Local.CertFile, "cert-file", "cert.pem",
@@ -146,11 +155,17 @@ func main() {
 	if err != nil {
 		log.Fatalf("Invalid scamper configuration: %v\n", err)
 	}
-	proc := scamper.GetProc(sc.Path, sc.Port, sc.ScPath)
-	mp := mproc.New()
-	_, err = mp.ManageProcess(proc, true, 1000)
-	if err != nil {
-		log.Fatalf("Could not start scamper: %v\n", err)
+	scamp := newScamperProc(mproc.New(), sc)
+	if *conf.Local.Handover && scamperRunning(sc.Port) {
+		// The previous plcontroller left scamper running with the vps connected
+		log.Infof("Taking over running scamper on port %s", sc.Port)
+		scamp.adopt()
+		go scamp.watch(time.Second * 5)
+	} else {
+		err = scamp.start()
+		if err != nil {
+			log.Fatalf("Could not start scamper: %v\n", err)
+		}
 	}
 	db, err := da.New(da.DbConfig{
 		WriteConfigs: []da.Config{
@@ -185,16 +200,33 @@ func main() {
 	}
 
 	http.Handle("/metrics", prometheus.Handler())
+	http.HandleFunc("/vpstatus", plc.VPStatusHandler)
 	go func() {
 		log.Error(http.ListenAndServe(*conf.Local.PProfAddr, nil))
 	}()
+	// Closed once a handover has started, Start returns as soon as the
+	// listener is closed so main waits for it to finish
+	handingOver := make(chan struct{})
+	handedOver := make(chan struct{})
 	var sigHandle = func() {
 		c := make(chan os.Signal, 1)
 		signal.Notify(c, syscall.SIGKILL, syscall.SIGINT, syscall.SIGTERM,
-			syscall.SIGQUIT, syscall.SIGSTOP)
+			syscall.SIGQUIT, syscall.SIGSTOP, syscall.SIGUSR2)
 		for sig := range c {
 			log.Infof("Got signal: %v", sig)
-			mp.IntAll()
+			if sig == syscall.SIGUSR2 {
+				// Leave scamper running for the plcontroller taking over
+				close(handingOver)
+				if err := scamp.handover(); err != nil {
+					log.Errorf("Failed to hand over scamper: %v", err)
+				}
+				fw.Close()
+				plc.Handover(time.Duration(*conf.Local.DrainTimeout) * time.Second)
+				db.Close()
+				close(handedOver)
+				return
+			}
+			scamp.stop()
 			fw.Close()
 			plc.Stop()
 			db.Close()
@@ -205,4 +237,19 @@ func main() {
 	if err != nil {
 		log.Error(err)
 	}
+	select {
+	case <-handingOver:
+		<-handedOver
+	default:
+	}
+}
+
+// scamperRunning reports if a scamper is already listening on port
+func scamperRunning(port string) bool {
+	c, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", port), time.Second)
+	if err != nil {
+		return false
+	}
+	c.Close()
+	return true
 }
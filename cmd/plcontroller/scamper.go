@@ -0,0 +1,179 @@
+/*
+Copyright (c) 2015, Northeastern University
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+    * Redistributions of source code must retain the above copyright
+      notice, this list of conditions and the following disclaimer.
+    * Redistributions in binary form must reproduce the above copyright
+      notice, this list of conditions and the following disclaimer in the
+      documentation and/or other materials provided with the distribution.
+    * Neither the name of the Northeastern University nor the
+      names of its contributors may be used to endorse or promote products
+      derived from this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+(INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/mproc"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
+)
+
+// scamperProc is the sc_remoted the plcontroller runs measurements through.
+// It's either started and kept alive by mproc or, after a handover, adopted
+// from the previous plcontroller. An adopted scamper isn't a child so it
+// can't be waited on, its port is polled instead and a managed scamper is
+// started when it goes away
+type scamperProc struct {
+	mu      sync.Mutex
+	mp      mproc.MProc
+	sc      scamper.Config
+	id      uint32
+	managed bool
+	adopted *os.Process
+	stopped bool
+}
+
+func newScamperProc(mp mproc.MProc, sc scamper.Config) *scamperProc {
+	return &scamperProc{mp: mp, sc: sc}
+}
+
+// pidFile is where a plcontroller handing over leaves the pid of scamper
+func (s *scamperProc) pidFile() string {
+	return strings.TrimSuffix(s.sc.Path, "/") + ".pid"
+}
+
+// start runs a scamper managed by mproc
+func (s *scamperProc) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.startLocked()
+}
+
+func (s *scamperProc) startLocked() error {
+	p := scamper.GetProc(s.sc.Path, s.sc.Port, s.sc.ScPath)
+	id, err := s.mp.ManageProcess(p, true, 1000)
+	if err != nil {
+		return err
+	}
+	s.id = id
+	s.managed = true
+	s.adopted = nil
+	return nil
+}
+
+// adopt takes over the scamper a plcontroller handing over left running.
+// Without a pid file the scamper is still used and replaced when it dies,
+// but it can't be stopped with the plcontroller
+func (s *scamperProc) adopt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid, err := readPid(s.pidFile())
+	if err != nil {
+		log.Errorf("Adopted scamper won't be stopped on exit: %v", err)
+		return
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		log.Errorf("Adopted scamper won't be stopped on exit: %v", err)
+		return
+	}
+	s.adopted = p
+	log.Infof("Adopted scamper with pid %d", pid)
+}
+
+// watch polls an adopted scamper and starts a managed one if it stops
+// listening. Call in a goroutine
+func (s *scamperProc) watch(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for range t.C {
+		if scamperRunning(s.sc.Port) {
+			continue
+		}
+		s.mu.Lock()
+		if s.stopped {
+			s.mu.Unlock()
+			return
+		}
+		log.Infof("Adopted scamper exited, starting a new one")
+		if err := s.startLocked(); err != nil {
+			log.Errorf("Could not start scamper: %v", err)
+			s.mu.Unlock()
+			continue
+		}
+		s.mu.Unlock()
+		return
+	}
+}
+
+// handover writes the pid of scamper for the plcontroller taking over.
+// A managed scamper isn't restarted by this process after this, so the pid
+// stays good, the new plcontroller replaces it if it dies
+func (s *scamperProc) handover() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	var pid int
+	switch {
+	case s.adopted != nil:
+		pid = s.adopted.Pid
+	case s.managed:
+		if err := s.mp.EndKeepAlive(s.id); err != nil {
+			return err
+		}
+		p, err := s.mp.GetProc(s.id).Pid()
+		if err != nil {
+			return err
+		}
+		pid = p
+	default:
+		return fmt.Errorf("No scamper pid to hand over")
+	}
+	return ioutil.WriteFile(s.pidFile(), []byte(strconv.Itoa(pid)), 0644)
+}
+
+// stop interrupts scamper, managed or adopted
+func (s *scamperProc) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	s.mp.IntAll()
+	if s.adopted != nil {
+		if err := s.adopted.Signal(syscall.SIGINT); err != nil {
+			log.Error(err)
+		}
+	}
+	os.Remove(s.pidFile())
+}
+
+func readPid(path string) (int, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(buf)))
+}
@@ -8,13 +8,16 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"golang.org/x/net/trace"
 
 	"google.golang.org/grpc/grpclog"
 
 	"github.com/NEU-SNS/ReverseTraceroute/config"
+	"github.com/NEU-SNS/ReverseTraceroute/httpupdate"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
 	"github.com/NEU-SNS/ReverseTraceroute/plvp"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
@@ -24,9 +27,13 @@ var (
 	defaultConfig = "./plvp.config"
 	configPath    string
 	versionNo     string
-	vFlag         bool
-	pidFile       string
-	lockFile      string
+	// updateKey is the base64 PKIX public key new binaries must be signed
+	// with, self-update is disabled without one
+	updateKey string
+	vFlag     bool
+	selfTest  bool
+	pidFile   string
+	lockFile  string
 )
 
 var conf = plvp.NewConfig()
@@ -40,6 +47,10 @@ func init() {
 	}
 	flag.BoolVar(&vFlag, "version", false,
 		"Prints the current version")
+	flag.BoolVar(&selfTest, "self-test", false,
+		"Test raw sockets, sending ip options and receiving ICMP, report the results and exit")
+	flag.StringVar(conf.Local.SelfTestTarget, "self-test-target", "",
+		"The host pinged to check ICMP reaches the vp, defaults to the plcontroller host")
 	flag.StringVar(conf.Local.Addr, "a", ":65000",
 		"The address to run the local service on")
 	flag.BoolVar(conf.Local.CloseStdDesc, "d", false,
@@ -54,6 +65,17 @@ func init() {
 		"The port the controller service is listening on")
 	flag.BoolVar(conf.Local.StartScamp, "start-scamper", true,
 		"Determines if scamper starts or not.")
+	flag.StringVar(conf.Local.SpoofPcap, "spoof-pcap", "",
+		"Record every spoofed probe received to this pcap file, appending if it exists")
+	flag.StringVar(conf.Local.OutboxPath, "outbox-path", "./plvp.outbox",
+		"The file spoofed probes are saved in until the plcontroller accepts them")
+	flag.IntVar(conf.Local.OutboxSize, "outbox-size", 100000,
+		"The maximum number of spoofed probes held for the plcontroller")
+	flag.StringVar(conf.Local.UpdateURL, "update-url",
+		"http://www.ccs.neu.edu/home/rhansen2/plvp.json",
+		"The path for the version info used to update the plvp, empty disables updates")
+	flag.Int64Var(conf.Local.UpdateEvery, "update-interval", 3600,
+		"Seconds between checks for a new plvp version")
 	flag.StringVar(conf.Scamper.BinPath, "b", "/usr/local/bin/scamper",
 		"The path to the scamper binary")
 	flag.StringVar(conf.Scamper.Port, "scamper-port", "4381",
@@ -82,8 +104,22 @@ func main() {
 		exit(1)
 	}
 	if vFlag {
+		// Don't exit through exit, this is run while another plvp owns the pid file
 		fmt.Println(versionNo)
-		exit(0)
+		os.Exit(0)
+	}
+	if selfTest {
+		st, err := plvp.RunSelfTest(conf, &plvp.PLControllerSender{RootCA: *conf.Local.RootCA})
+		fmt.Printf("raw socket: %v\nsend record route: %v\nsend timestamp: %v\nreceive icmp: %v\n",
+			st.RawSocket, st.SendRecordRoute, st.SendTimestamp, st.ReceiveIcmp)
+		for _, e := range st.Errors {
+			fmt.Println(e)
+		}
+		if err != nil {
+			fmt.Printf("Failed to report: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
 	_, err = os.Stat(lockFile)
 	if err == nil {
@@ -97,13 +133,55 @@ func main() {
 		}
 	}
 	util.CloseStdFiles(*conf.Local.CloseStdDesc)
-	err = <-plvp.Start(conf, &plvp.PLControllerSender{RootCA: *conf.Local.RootCA})
+	up := updater()
+	ec := plvp.Start(conf, &plvp.PLControllerSender{RootCA: *conf.Local.RootCA})
+	if up != nil {
+		if from := os.Getenv(plvp.UpdatedEnv); from != "" {
+			if err := up.Confirm(from, ec); err != nil {
+				log.Errorf("Failed to roll back update: %v", err)
+				exit(1)
+			}
+		}
+		go up.Run(time.Duration(*conf.Local.UpdateEvery) * time.Second)
+	}
+	err = <-ec
 	if err != nil {
 		log.Errorf("PLVP Start returned with error: %v", err)
 		exit(1)
 	}
 }
 
+func updater() *plvp.Updater {
+	if *conf.Local.UpdateURL == "" {
+		return nil
+	}
+	if updateKey == "" {
+		log.Info("Built without an update key, not updating")
+		return nil
+	}
+	key, err := httpupdate.ParseKey(updateKey)
+	if err != nil {
+		log.Errorf("Invalid update key: %v", err)
+		return nil
+	}
+	path, err := os.Executable()
+	if err == nil {
+		path, err = filepath.EvalSymlinks(path)
+	}
+	if err != nil {
+		log.Errorf("Failed to find the plvp binary: %v", err)
+		return nil
+	}
+	up := plvp.NewUpdater(*conf.Local.UpdateURL, versionNo, path, key)
+	up.Health = plvp.Health
+	up.Stop = func() {
+		// The pid stays the same across the exec, only the lock is released
+		os.Remove(lockFile)
+		plvp.Stop()
+	}
+	return up
+}
+
 func exit(status int) {
 	os.Remove(pidFile)
 	os.Exit(status)
@@ -0,0 +1,358 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// Package controller is the library for creating a central controller
+package controller
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	da "github.com/NEU-SNS/ReverseTraceroute/dataaccess"
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+	con "golang.org/x/net/context"
+)
+
+// AliasStore is a database with an ip_aliases table the alias
+// resolution job keeps up to date
+type AliasStore interface {
+	// GetAliasCluster returns the cluster ip is in and its members,
+	// the id is -1 if ip is in no cluster
+	GetAliasCluster(ip uint32) (int, []uint32, error)
+	// NextAliasClusterID returns an unused cluster id
+	NextAliasClusterID() (int, error)
+	// MergeAliases replaces cluster id with ips and removes the merged
+	// clusters in one transaction
+	MergeAliases(id int, merged []int, ips []net.IP) error
+}
+
+// aliasPaths returns the paths measured since since keyed by the
+// destination they lead to
+type aliasPaths func(since time.Time, limit int) (map[uint32][][]uint32, error)
+
+const (
+	aliasPathLimit = 10000
+	// Alias resolution competes with user measurements, it goes last
+	aliasPriority = Priority(0)
+)
+
+// aliasCandidates returns pairs of addresses that sit between the same
+// previous and next hop on paths toward the same destination. Those are
+// often different interfaces of one router. At most max pairs are
+// returned, each with the lower address first
+func aliasCandidates(paths map[uint32][][]uint32, max int) [][2]uint32 {
+	type around struct {
+		prev, next uint32
+	}
+	var dsts []uint32
+	for dst := range paths {
+		dsts = append(dsts, dst)
+	}
+	sort.Sort(uint32s(dsts))
+	seen := make(map[[2]uint32]bool)
+	var ret [][2]uint32
+	for _, dst := range dsts {
+		var order []around
+		between := make(map[around][]uint32)
+		for _, path := range paths[dst] {
+			for i := 1; i+1 < len(path); i++ {
+				a := around{prev: path[i-1], next: path[i+1]}
+				if a.prev == 0 || a.next == 0 || path[i] == 0 {
+					continue
+				}
+				if _, ok := between[a]; !ok {
+					order = append(order, a)
+				}
+				between[a] = appendUnique(between[a], path[i])
+			}
+		}
+		for _, a := range order {
+			addrs := between[a]
+			for i := 0; i < len(addrs); i++ {
+				for j := i + 1; j < len(addrs); j++ {
+					pair := [2]uint32{addrs[i], addrs[j]}
+					if pair[0] > pair[1] {
+						pair[0], pair[1] = pair[1], pair[0]
+					}
+					if seen[pair] {
+						continue
+					}
+					seen[pair] = true
+					ret = append(ret, pair)
+					if len(ret) == max {
+						return ret
+					}
+				}
+			}
+		}
+	}
+	return ret
+}
+
+func appendUnique(addrs []uint32, addr uint32) []uint32 {
+	for _, a := range addrs {
+		if a == addr {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
+
+type uint32s []uint32
+
+func (u uint32s) Len() int           { return len(u) }
+func (u uint32s) Less(i, j int) bool { return u[i] < u[j] }
+func (u uint32s) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+
+// aliasResolver periodically runs ally on the alias candidates found in
+// new paths and merges the clusters of the addresses that are aliases
+// in every store
+type aliasResolver struct {
+	paths   []aliasPaths
+	stores  []AliasStore
+	dealias func(con.Context, []*dm.DealiasMeasurement, Priority) <-chan *dm.Dealias
+	source  func(con.Context) (uint32, error)
+	batch   int
+	last    time.Time
+}
+
+func (ar *aliasResolver) run(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			ctx, cancel := con.WithTimeout(con.Background(), interval)
+			go func() {
+				select {
+				case <-stop:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			if err := ar.resolve(ctx); err != nil {
+				log.Error(err)
+			}
+			cancel()
+		}
+	}
+}
+
+// resolve measures the candidates seen since the last round. The next
+// round starts from this one only once its aliases are stored, a failed
+// round is retried
+func (ar *aliasResolver) resolve(ctx con.Context) error {
+	start := time.Now()
+	paths := make(map[uint32][][]uint32)
+	for _, get := range ar.paths {
+		ps, err := get(ar.last, aliasPathLimit)
+		if err != nil {
+			return err
+		}
+		for dst, p := range ps {
+			paths[dst] = append(paths[dst], p...)
+		}
+	}
+	var dms []*dm.DealiasMeasurement
+	var src uint32
+	for _, pair := range aliasCandidates(paths, ar.batch) {
+		same, err := ar.sameCluster(pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+		if same {
+			continue
+		}
+		if src == 0 {
+			if src, err = ar.source(ctx); err != nil {
+				return err
+			}
+		}
+		dms = append(dms, &dm.DealiasMeasurement{
+			Src:    src,
+			Method: "ally",
+			Addrs:  pair[:],
+		})
+	}
+	if len(dms) > 0 {
+		log.Debugf("Resolving %d alias candidates", len(dms))
+		for d := range ar.dealias(ctx, dms, aliasPriority) {
+			if d.Error != "" {
+				log.Debugf("Dealias of %v failed: %s", d.Addrs, d.Error)
+				continue
+			}
+			if d.Result != dm.DealiasAliases {
+				continue
+			}
+			for _, store := range ar.stores {
+				if err := merge(store, d.Aliases()); err != nil {
+					return err
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	ar.last = start
+	return nil
+}
+
+// sameCluster is whether a and b are clustered together in every store
+func (ar *aliasResolver) sameCluster(a, b uint32) (bool, error) {
+	for _, store := range ar.stores {
+		ida, _, err := store.GetAliasCluster(a)
+		if err != nil {
+			return false, err
+		}
+		if ida < 0 {
+			return false, nil
+		}
+		idb, _, err := store.GetAliasCluster(b)
+		if err != nil {
+			return false, err
+		}
+		if ida != idb {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// merge puts ips and every address already clustered with one of them
+// into a single cluster of store. It keeps the lowest of their cluster
+// ids, or takes a new one when none of them were clustered
+func merge(store AliasStore, ips []uint32) error {
+	if len(ips) < 2 {
+		return nil
+	}
+	members := make(map[uint32]bool)
+	clusters := make(map[int]bool)
+	for _, ip := range ips {
+		members[ip] = true
+		id, cl, err := store.GetAliasCluster(ip)
+		if err != nil {
+			return err
+		}
+		if id < 0 {
+			continue
+		}
+		clusters[id] = true
+		for _, m := range cl {
+			members[m] = true
+		}
+	}
+	target := -1
+	for id := range clusters {
+		if target < 0 || id < target {
+			target = id
+		}
+	}
+	if target < 0 {
+		id, err := store.NextAliasClusterID()
+		if err != nil {
+			return err
+		}
+		target = id
+	}
+	var merged []int
+	for id := range clusters {
+		if id != target {
+			merged = append(merged, id)
+		}
+	}
+	sort.Ints(merged)
+	var addrs []uint32
+	for m := range members {
+		addrs = append(addrs, m)
+	}
+	sort.Sort(uint32s(addrs))
+	var nips []net.IP
+	for _, a := range addrs {
+		ip, err := util.Int32ToIPString(a)
+		if err != nil {
+			return err
+		}
+		nips = append(nips, net.ParseIP(ip))
+	}
+	log.Debugf("Storing alias cluster %d: %v", target, nips)
+	return store.MergeAliases(target, merged, nips)
+}
+
+// newAliasResolver connects to the atlas and reverse traceroute
+// databases the alias resolution job reads paths from and keeps the
+// ip_aliases of
+func (c *controllerT) newAliasResolver(conf AliasConfig) (*aliasResolver, error) {
+	atlas, err := da.New(conf.AtlasDb)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to the atlas db: %v", err)
+	}
+	revtr, err := da.New(conf.RevtrDb)
+	if err != nil {
+		atlas.Close()
+		return nil, fmt.Errorf("Failed to connect to the revtr db: %v", err)
+	}
+	c.aliasDbs = []*da.DataAccess{atlas, revtr}
+	ar := &aliasResolver{
+		paths:   []aliasPaths{atlas.GetAtlasAliasPaths, revtr.GetRevtrAliasPaths},
+		stores:  []AliasStore{atlas, revtr},
+		dealias: c.doDealias,
+		source:  c.aliasSource,
+		batch:   100,
+		last:    time.Now(),
+	}
+	if conf.Batch != nil && *conf.Batch > 0 {
+		ar.batch = *conf.Batch
+	}
+	return ar, nil
+}
+
+// aliasSource is the vantage point alias resolution probes from, the
+// configured one or else the least loaded vantage point the controller knows
+func (c *controllerT) aliasSource(ctx con.Context) (uint32, error) {
+	if c.config.Alias.VP != nil && *c.config.Alias.VP != "" {
+		return util.IPStringToInt32(*c.config.Alias.VP)
+	}
+	vps, err := c.fetchVPs(ctx, &dm.VPRequest{})
+	if err != nil {
+		return 0, err
+	}
+	if len(vps.Vps) == 0 {
+		return 0, fmt.Errorf("No vantage points to resolve aliases from")
+	}
+	ips := make([]uint32, 0, len(vps.Vps))
+	for _, vp := range vps.Vps {
+		ips = append(ips, vp.Ip)
+	}
+	return leastLoaded(ips, c.fetchVPStatus(ctx, ips)), nil
+}
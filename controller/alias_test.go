@@ -0,0 +1,264 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+	con "golang.org/x/net/context"
+)
+
+func TestAliasCandidates(t *testing.T) {
+	for _, test := range []struct {
+		desc     string
+		paths    map[uint32][][]uint32
+		max      int
+		expected [][2]uint32
+	}{
+		{
+			desc: "same neighbours",
+			paths: map[uint32][][]uint32{
+				100: {{1, 5, 9, 100}, {1, 4, 9, 100}},
+			},
+			max:      10,
+			expected: [][2]uint32{{4, 5}},
+		},
+		{
+			desc: "different destinations are not compared",
+			paths: map[uint32][][]uint32{
+				100: {{1, 5, 9, 100}},
+				200: {{1, 4, 9, 200}},
+			},
+			max: 10,
+		},
+		{
+			desc: "unresponsive hops are skipped",
+			paths: map[uint32][][]uint32{
+				100: {{1, 0, 9, 100}, {1, 4, 9, 100}, {0, 6, 9, 100}, {0, 7, 9, 100}},
+			},
+			max: 10,
+		},
+		{
+			desc: "pairs are only returned once",
+			paths: map[uint32][][]uint32{
+				100: {{1, 5, 9, 100}, {1, 4, 9, 100}},
+				200: {{1, 4, 9, 200}, {1, 5, 9, 200}},
+			},
+			max:      10,
+			expected: [][2]uint32{{4, 5}},
+		},
+		{
+			desc: "limited to max",
+			paths: map[uint32][][]uint32{
+				100: {{1, 5, 9, 100}, {1, 4, 9, 100}, {1, 3, 9, 100}},
+			},
+			max:      2,
+			expected: [][2]uint32{{4, 5}, {3, 5}},
+		},
+	} {
+		got := aliasCandidates(test.paths, test.max)
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: got %v, expected %v", test.desc, got, test.expected)
+		}
+	}
+}
+
+type fakeAliasStore struct {
+	clusters map[int][]uint32
+	merges   int
+}
+
+func (f *fakeAliasStore) GetAliasCluster(ip uint32) (int, []uint32, error) {
+	for id, ips := range f.clusters {
+		for _, i := range ips {
+			if i == ip {
+				return id, ips, nil
+			}
+		}
+	}
+	return -1, nil, nil
+}
+
+func (f *fakeAliasStore) NextAliasClusterID() (int, error) {
+	next := 1
+	for id := range f.clusters {
+		if id >= next {
+			next = id + 1
+		}
+	}
+	return next, nil
+}
+
+func (f *fakeAliasStore) MergeAliases(id int, merged []int, ips []net.IP) error {
+	f.merges++
+	for _, m := range merged {
+		delete(f.clusters, m)
+	}
+	delete(f.clusters, id)
+	for _, ip := range ips {
+		i, _ := util.IPtoInt32(ip)
+		f.clusters[id] = append(f.clusters[id], i)
+	}
+	return nil
+}
+
+func TestAliasResolverMerge(t *testing.T) {
+	for _, test := range []struct {
+		desc     string
+		clusters map[int][]uint32
+		ips      []uint32
+		expected map[int][]uint32
+	}{
+		{
+			desc:     "new cluster",
+			clusters: map[int][]uint32{3: {7, 8}},
+			ips:      []uint32{1, 2},
+			expected: map[int][]uint32{3: {7, 8}, 4: {1, 2}},
+		},
+		{
+			desc:     "joins a cluster",
+			clusters: map[int][]uint32{3: {7, 8}},
+			ips:      []uint32{1, 8},
+			expected: map[int][]uint32{3: {1, 7, 8}},
+		},
+		{
+			desc:     "merges clusters into the lowest id",
+			clusters: map[int][]uint32{3: {7, 8}, 5: {1, 2}, 6: {10}},
+			ips:      []uint32{2, 7},
+			expected: map[int][]uint32{3: {1, 2, 7, 8}, 6: {10}},
+		},
+	} {
+		store := &fakeAliasStore{clusters: test.clusters}
+		if err := merge(store, test.ips); err != nil {
+			t.Fatalf("%s: merge failed: %v", test.desc, err)
+		}
+		if !reflect.DeepEqual(store.clusters, test.expected) {
+			t.Errorf("%s: got %v, expected %v", test.desc, store.clusters, test.expected)
+		}
+		if store.merges != 1 {
+			t.Errorf("%s: stored in %d transactions, expected 1", test.desc, store.merges)
+		}
+	}
+}
+
+func pathsOf(paths map[uint32][][]uint32) aliasPaths {
+	return func(time.Time, int) (map[uint32][][]uint32, error) {
+		return paths, nil
+	}
+}
+
+func TestAliasResolverResolve(t *testing.T) {
+	// 5 and 6 are already known aliases
+	atlas := &fakeAliasStore{clusters: map[int][]uint32{1: {5, 6}}}
+	revtr := &fakeAliasStore{clusters: map[int][]uint32{1: {5, 6}}}
+	var measured [][]uint32
+	ar := &aliasResolver{
+		paths: []aliasPaths{
+			pathsOf(map[uint32][][]uint32{100: {{1, 5, 9, 100}, {1, 4, 9, 100}}}),
+			pathsOf(map[uint32][][]uint32{100: {{1, 6, 9, 100}}}),
+		},
+		stores: []AliasStore{atlas, revtr},
+		batch:  10,
+		source: func(con.Context) (uint32, error) {
+			return 42, nil
+		},
+		dealias: func(ctx con.Context, dms []*dm.DealiasMeasurement, p Priority) <-chan *dm.Dealias {
+			ret := make(chan *dm.Dealias, len(dms))
+			for _, d := range dms {
+				measured = append(measured, d.Addrs)
+				if d.Src != 42 || d.Method != "ally" {
+					t.Errorf("Unexpected dealias: %+v", d)
+				}
+				res := &dm.Dealias{Method: d.Method, Result: dm.DealiasNotAliases}
+				if reflect.DeepEqual(d.Addrs, []uint32{4, 6}) {
+					res.Result = dm.DealiasAliases
+					res.Addrs = d.Addrs
+				}
+				ret <- res
+			}
+			close(ret)
+			return ret
+		},
+	}
+	if err := ar.resolve(con.Background()); err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if expected := [][]uint32{{4, 5}, {4, 6}}; !reflect.DeepEqual(measured, expected) {
+		t.Fatalf("Measured %v, expected %v", measured, expected)
+	}
+	for _, store := range []*fakeAliasStore{atlas, revtr} {
+		if expected := map[int][]uint32{1: {4, 5, 6}}; !reflect.DeepEqual(store.clusters, expected) {
+			t.Fatalf("Clusters %v, expected %v", store.clusters, expected)
+		}
+	}
+	if ar.last.IsZero() {
+		t.Fatalf("Stored round did not advance the cursor")
+	}
+}
+
+func TestAliasResolverKeepsCursorOnFailure(t *testing.T) {
+	ctx, cancel := con.WithCancel(con.Background())
+	ar := &aliasResolver{
+		paths: []aliasPaths{
+			pathsOf(map[uint32][][]uint32{100: {{1, 5, 9, 100}, {1, 4, 9, 100}}}),
+		},
+		stores: []AliasStore{&fakeAliasStore{clusters: map[int][]uint32{}}},
+		batch:  10,
+		source: func(con.Context) (uint32, error) {
+			return 42, nil
+		},
+		dealias: func(ctx con.Context, dms []*dm.DealiasMeasurement, p Priority) <-chan *dm.Dealias {
+			// The round is cut short before any result
+			cancel()
+			ret := make(chan *dm.Dealias)
+			close(ret)
+			return ret
+		},
+	}
+	if err := ar.resolve(ctx); err == nil {
+		t.Fatalf("Cancelled round succeeded")
+	}
+	if !ar.last.IsZero() {
+		t.Fatalf("Cancelled round advanced the cursor to %v", ar.last)
+	}
+	ar.paths = append(ar.paths, func(time.Time, int) (map[uint32][][]uint32, error) {
+		return nil, fmt.Errorf("no db")
+	})
+	if err := ar.resolve(con.Background()); err == nil {
+		t.Fatalf("Round with a failed path read succeeded")
+	}
+	if !ar.last.IsZero() {
+		t.Fatalf("Failed round advanced the cursor to %v", ar.last)
+	}
+}
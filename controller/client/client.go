@@ -16,7 +16,10 @@ type client struct {
 type Client interface {
 	Ping(context.Context, *datamodel.PingArg) (controllerapi.Controller_PingClient, error)
 	Traceroute(context.Context, *datamodel.TracerouteArg) (controllerapi.Controller_TracerouteClient, error)
+	Tracelb(context.Context, *datamodel.TracelbArg) (controllerapi.Controller_TracelbClient, error)
+	Dealias(context.Context, *datamodel.DealiasArg) (controllerapi.Controller_DealiasClient, error)
 	GetVps(context.Context, *datamodel.VPRequest) (*datamodel.VPReturn, error)
+	GetVPStatus(context.Context, *datamodel.VPStatusRequest) (*datamodel.VPStatusReturn, error)
 	ReceiveSpoofedProbes(context.Context) (controllerapi.Controller_ReceiveSpoofedProbesClient, error)
 }
 
@@ -33,10 +36,22 @@ func (c client) Traceroute(ctx context.Context, ta *datamodel.TracerouteArg) (co
 	return c.ControllerClient.Traceroute(ctx, ta)
 }
 
+func (c client) Tracelb(ctx context.Context, ta *datamodel.TracelbArg) (controllerapi.Controller_TracelbClient, error) {
+	return c.ControllerClient.Tracelb(ctx, ta)
+}
+
+func (c client) Dealias(ctx context.Context, da *datamodel.DealiasArg) (controllerapi.Controller_DealiasClient, error) {
+	return c.ControllerClient.Dealias(ctx, da)
+}
+
 func (c client) GetVps(ctx context.Context, vpr *datamodel.VPRequest) (*datamodel.VPReturn, error) {
 	return c.ControllerClient.GetVPs(ctx, vpr)
 }
 
+func (c client) GetVPStatus(ctx context.Context, vsr *datamodel.VPStatusRequest) (*datamodel.VPStatusReturn, error) {
+	return c.ControllerClient.GetVPStatus(ctx, vsr)
+}
+
 func (c client) ReceiveSpoofedProbes(ctx context.Context) (controllerapi.Controller_ReceiveSpoofedProbesClient, error) {
 	return c.ControllerClient.ReceiveSpoofedProbes(ctx)
 }
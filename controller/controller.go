@@ -31,18 +31,20 @@ package controller
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"net"
 	"net/http"
 	"os"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	ca "github.com/NEU-SNS/ReverseTraceroute/cache"
 	"github.com/NEU-SNS/ReverseTraceroute/controller/pb"
+	da "github.com/NEU-SNS/ReverseTraceroute/dataaccess"
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
 	"github.com/NEU-SNS/ReverseTraceroute/router"
+	"github.com/NEU-SNS/ReverseTraceroute/trvalidate"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
 	"github.com/prometheus/client_golang/prometheus"
 	con "golang.org/x/net/context"
@@ -97,6 +99,18 @@ var (
 		Name:      "pings_from_db",
 		Help:      "The number of pings retrieved from the db.",
 	})
+	tracelbResponseTimes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: nameSpace,
+		Subsystem: "measurements",
+		Name:      "tracelb_response_times",
+		Help:      "The time it takes for tracelbs to respond",
+	})
+	dealiasResponseTimes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: nameSpace,
+		Subsystem: "measurements",
+		Name:      "dealias_response_times",
+		Help:      "The time it takes for dealiases to respond",
+	})
 	tracesFromCache = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: nameSpace,
 		Subsystem: "cache",
@@ -118,6 +132,8 @@ func init() {
 	prometheus.MustRegister(errorCounter)
 	prometheus.MustRegister(pingResponseTimes)
 	prometheus.MustRegister(tracerouteResponseTimes)
+	prometheus.MustRegister(tracelbResponseTimes)
+	prometheus.MustRegister(dealiasResponseTimes)
 	prometheus.MustRegister(pingsFromCache)
 	prometheus.MustRegister(pingsFromDB)
 	prometheus.MustRegister(tracesFromCache)
@@ -132,6 +148,7 @@ type DataAccess interface {
 	GetTRBySrcDst(uint32, uint32) ([]*dm.Traceroute, error)
 	GetTraceMulti([]*dm.TracerouteMeasurement) ([]*dm.Traceroute, error)
 	StoreTraceroute(*dm.Traceroute) (int64, error)
+	StoreTracelb(*dm.Tracelb) (int64, error)
 	Close() error
 	GetUser(string) (dm.User, error)
 	AddPingBatch(dm.User) (int64, error)
@@ -143,21 +160,25 @@ type DataAccess interface {
 }
 
 type controllerT struct {
-	config  Config
-	db      DataAccess
-	cache   ca.Cache
-	router  router.Router
-	server  *grpc.Server
-	spoofID uint32
-	sm      *spoofMap
+	config Config
+	db     DataAccess
+	cache  ca.Cache
+	router router.Router
+	server *grpc.Server
+	st     *spoofTracker
+	v      *trvalidate.Validator
+	pings  *flightGroup
+	traces *flightGroup
+	limits *limits
+	jobs   *jobStore
+	// done is closed when the controller stops
+	done     chan struct{}
+	stopOnce sync.Once
+	aliasDbs []*da.DataAccess
 }
 
 var controller controllerT
 
-func (c *controllerT) nextSpoofID() uint32 {
-	return atomic.AddUint32(&(c.spoofID), 1)
-}
-
 // HandleSig handles and signals received from the OS
 func HandleSig(sig os.Signal) {
 	controller.handleSig(sig)
@@ -214,6 +235,8 @@ func errorAllTrace(ctx con.Context, err error, out chan<- *dm.Traceroute, ts []*
 var (
 	// ErrTimeout is used when the done channel on a context is received from
 	ErrTimeout = fmt.Errorf("Request timeout")
+	// ErrSpoofTimeout is used when no response to a spoofed probe arrived before its deadline
+	ErrSpoofTimeout = fmt.Errorf("Spoofed probe timed out")
 )
 
 func checkPingCache(ctx con.Context, keys []string, c ca.Cache) (map[string]*dm.Ping, error) {
@@ -290,7 +313,17 @@ func checkPingDb(ctx con.Context, check []*dm.PingMeasurement, db DataAccess) (m
 	}
 }
 
-func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *dm.Ping {
+// spoofReceiver is the vp that receives the replies to a spoofed ping.
+// Spoofed record route pings only set the address they spoof, SAddr
+func spoofReceiver(pm *dm.PingMeasurement) string {
+	if pm.SpooferAddr == 0 {
+		return pm.SAddr
+	}
+	ip, _ := util.Int32ToIPString(pm.SpooferAddr)
+	return ip
+}
+
+func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement, prio Priority) <-chan *dm.Ping {
 	ret := make(chan *dm.Ping, len(pm))
 	go func() {
 		var checkCache = make(map[string]*dm.PingMeasurement)
@@ -367,7 +400,9 @@ func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *
 				remaining = append(remaining, pm)
 			}
 		}
-		mts := make(map[router.ServiceDef][]*dm.PingMeasurement)
+		var wg sync.WaitGroup
+		sds := make(map[*dm.PingMeasurement]router.ServiceDef)
+		var toRun []*dm.PingMeasurement
 		for _, pm := range remaining {
 			ip, _ := util.Int32ToIPString(pm.Src)
 			sd, err := c.router.GetService(ip)
@@ -380,61 +415,112 @@ func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *
 				}
 				continue
 			}
-			mts[sd] = append(mts[sd], pm)
+			// If the same ping is already running share its result
+			if f, leader := c.pings.join(pingFlightKey(pm)); !leader {
+				probesSaved.WithLabelValues("ping").Inc()
+				wg.Add(1)
+				go func(pm *dm.PingMeasurement) {
+					defer wg.Done()
+					if !waitPing(ctx, f, ret) {
+						c.reissuePing(ctx, pm, prio, ret)
+					}
+				}(pm)
+				continue
+			}
+			sds[pm] = sd
+			toRun = append(toRun, pm)
 		}
-		var wg sync.WaitGroup
-		for sd, pms := range mts {
-			wg.Add(1)
-			go func(s router.ServiceDef, meas []*dm.PingMeasurement) {
-				defer wg.Done()
-				mt, err := c.router.GetMT(s)
-				if err != nil {
-					log.Error(err)
-					errorAllPing(ctx, err, ret, meas)
-					return
+		runPings := func(s router.ServiceDef, meas []*dm.PingMeasurement) {
+			defer wg.Done()
+			pending := make([]*dm.PingMeasurement, len(meas))
+			copy(pending, meas)
+			// Anything left pending finished without a result
+			defer func() {
+				for _, pm := range pending {
+					c.pings.finish(pingFlightKey(pm), nil)
 				}
-				defer mt.Close()
-				pc, err := mt.Ping(ctx, &dm.PingArg{
-					Pings: meas,
-				})
-				if err != nil {
-					log.Error(err)
-					errorAllPing(ctx, err, ret, meas)
+			}()
+			mt, err := c.router.GetMT(s)
+			if err != nil {
+				log.Error(err)
+				c.limits.refundPings(meas)
+				errorAllPing(ctx, err, ret, meas)
+				return
+			}
+			defer mt.Close()
+			pc, err := mt.Ping(ctx, &dm.PingArg{
+				Pings:    meas,
+				Priority: uint32(prio),
+			})
+			if err != nil {
+				log.Error(err)
+				c.limits.refundPings(meas)
+				errorAllPing(ctx, err, ret, meas)
+				return
+			}
+			for {
+				select {
+				case <-ctx.Done():
 					return
-				}
-				for {
+				case pp, ok := <-pc:
+					if !ok {
+						return
+					}
+					if pp == nil {
+						return
+					}
+					id, err := c.db.StorePing(pp)
+					if err != nil {
+						log.Error(err)
+					}
+					err = c.cache.SetWithExpire(pp.Key(), pp.CMarshal(), 5*60)
+					if err != nil {
+						log.Error(err)
+					}
+					pp.Id = id
+					if i := matchPing(pending, pp); i != -1 {
+						c.pings.finish(pingFlightKey(pending[i]), pp)
+						pending = append(pending[:i], pending[i+1:]...)
+					}
+					log.Debug("Sending: ", pp)
 					select {
+					case ret <- pp:
 					case <-ctx.Done():
 						return
-					case pp, ok := <-pc:
-						if !ok {
-							return
-						}
-						if pp == nil {
-							return
-						}
-						id, err := c.db.StorePing(pp)
-						if err != nil {
-							log.Error(err)
-						}
-						err = c.cache.SetWithExpire(pp.Key(), pp.CMarshal(), 5*60)
-						if err != nil {
-							log.Error(err)
-						}
-						pp.Id = id
-						log.Debug("Sending: ", pp)
-						select {
-						case ret <- pp:
-						case <-ctx.Done():
-							return
-						}
 					}
 				}
-			}(sd, pms)
+			}
+		}
+		// Send the pings as the limits admit them, batched by service
+		admitted := c.limits.admitPings(ctx, toRun, prio)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := nextPings(admitted); batch != nil; batch = nextPings(admitted) {
+				mts := make(map[router.ServiceDef][]*dm.PingMeasurement)
+				for _, pm := range batch {
+					mts[sds[pm]] = append(mts[sds[pm]], pm)
+					delete(sds, pm)
+				}
+				for sd, pms := range mts {
+					wg.Add(1)
+					go runPings(sd, pms)
+				}
+			}
+			// Pings that were never admitted finish without a result
+			for pm := range sds {
+				c.pings.finish(pingFlightKey(pm), nil)
+			}
+		}()
+		type spoofReq struct {
+			pm     *dm.PingMeasurement
+			sd     router.ServiceDef
+			sds    router.ServiceDef
+			saddr  uint32
+			flight string
 		}
-		sdForSpoof := make(map[router.ServiceDef][]*dm.Spoof)
-		sdForSpoofP := make(map[router.ServiceDef][]*dm.PingMeasurement)
-		var spoofIds []uint32
+		reqs := make(map[*dm.PingMeasurement]spoofReq)
+		var toSpoof []*dm.PingMeasurement
 		for _, sp := range spoofs {
 			ip, _ := util.Int32ToIPString(sp.Src)
 			sd, err := c.router.GetService(ip)
@@ -447,8 +533,7 @@ func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *
 				}
 				continue
 			}
-			ips, _ := util.Int32ToIPString(sp.SpooferAddr)
-			sds, err := c.router.GetService(ips)
+			sds, err := c.router.GetService(spoofReceiver(sp))
 			if err != nil {
 				log.Error(err)
 				ret <- &dm.Ping{
@@ -458,93 +543,128 @@ func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *
 				}
 				continue
 			}
-			sdForSpoofP[sd] = append(sdForSpoofP[sd], sp)
-			id := c.nextSpoofID()
-			sp.Payload = fmt.Sprintf("%08x", id)
-			spoofIds = append(spoofIds, id)
-			log.Debug("Creating dm.Spoof for: ", *sp)
 			sa, err := util.IPStringToInt32(sp.SAddr)
 			if err != nil {
 				log.Error(err)
 				continue
 			}
-			sdForSpoof[sds] = append(sdForSpoof[sds], &dm.Spoof{
-				Ip:  sp.Src,
-				Id:  id,
-				Sip: sa,
-				Dst: sp.Dst,
-			})
-
-		}
-		rChan := make(chan *dm.Probe, len(spoofIds))
-		kill := make(chan struct{})
-		if len(spoofIds) != 0 {
-			c.sm.Add(rChan, kill, spoofIds)
-		} else {
-			// This is ugly but prevent waiting for no reason
-			close(rChan)
-		}
-		for sd, spoofs := range sdForSpoof {
-			wg.Add(1)
-			go func(s router.ServiceDef, sps []*dm.Spoof) {
-				defer wg.Done()
-				mt, err := c.router.GetMT(s)
-				if err != nil {
-					log.Error(err)
-					return
-				}
-				defer mt.Close()
-				resp, err := mt.ReceiveSpoof(ctx, &dm.RecSpoof{
-					Spoofs: sps,
-				})
-				if err != nil {
-					log.Error(err)
-					return
-				}
-				for _ = range resp {
-				}
-			}(sd, spoofs)
+			key := pingFlightKey(sp)
+			if f, leader := c.pings.join(key); !leader {
+				probesSaved.WithLabelValues("ping").Inc()
+				wg.Add(1)
+				go func(sp *dm.PingMeasurement) {
+					defer wg.Done()
+					if !waitPing(ctx, f, ret) {
+						c.reissuePing(ctx, sp, prio, ret)
+					}
+				}(sp)
+				continue
+			}
+			reqs[sp] = spoofReq{pm: sp, sd: sd, sds: sds, saddr: sa, flight: key}
+			toSpoof = append(toSpoof, sp)
 		}
-		for sd, spoofs := range sdForSpoofP {
-			wg.Add(1)
-			go func(s router.ServiceDef, sps []*dm.PingMeasurement) {
-				defer wg.Done()
-				mt, err := c.router.GetMT(s)
-				if err != nil {
-					log.Error(err)
-					return
-				}
-				defer mt.Close()
-				resp, err := mt.Ping(ctx, &dm.PingArg{
-					Pings: sps,
+		// runSpoofs registers a batch of admitted spoofed pings, sends them
+		// and waits for their results or timeouts
+		runSpoofs := func(reqs []spoofReq) {
+			defer wg.Done()
+			timeouts := make([]time.Duration, len(reqs))
+			for i, r := range reqs {
+				timeouts[i] = time.Duration(r.pm.Timeout) * time.Second
+			}
+			group := c.st.register(timeouts)
+			sdForSpoof := make(map[router.ServiceDef][]*dm.Spoof)
+			sdForSpoofP := make(map[router.ServiceDef][]*dm.PingMeasurement)
+			// The spoof ids identify which request a spoofed result belongs to
+			spoofReqs := make(map[uint32]spoofReq)
+			for i, r := range reqs {
+				id := group.IDs[i]
+				r.pm.Payload = fmt.Sprintf("%08x", id)
+				spoofReqs[id] = r
+				sdForSpoofP[r.sd] = append(sdForSpoofP[r.sd], r.pm)
+				log.Debug("Creating dm.Spoof for: ", *r.pm)
+				sdForSpoof[r.sds] = append(sdForSpoof[r.sds], &dm.Spoof{
+					Ip:  r.pm.Src,
+					Id:  id,
+					Sip: r.saddr,
+					Dst: r.pm.Dst,
 				})
-				if err != nil {
-					log.Error(err)
-					return
-				}
-				for _ = range resp {
+			}
+			for sd, spoofs := range sdForSpoof {
+				wg.Add(1)
+				go func(s router.ServiceDef, sps []*dm.Spoof) {
+					defer wg.Done()
+					mt, err := c.router.GetMT(s)
+					if err != nil {
+						log.Error(err)
+						return
+					}
+					defer mt.Close()
+					resp, err := mt.ReceiveSpoof(ctx, &dm.RecSpoof{
+						Spoofs: sps,
+					})
+					if err != nil {
+						log.Error(err)
+						return
+					}
+					for _ = range resp {
+					}
+				}(sd, spoofs)
+			}
+			for sd, spoofs := range sdForSpoofP {
+				wg.Add(1)
+				go func(s router.ServiceDef, sps []*dm.PingMeasurement) {
+					defer wg.Done()
+					mt, err := c.router.GetMT(s)
+					if err != nil {
+						log.Error(err)
+						c.limits.refundPings(sps)
+						return
+					}
+					defer mt.Close()
+					resp, err := mt.Ping(ctx, &dm.PingArg{
+						Pings:    sps,
+						Priority: uint32(prio),
+					})
+					if err != nil {
+						log.Error(err)
+						c.limits.refundPings(sps)
+						return
+					}
+					for _ = range resp {
 
+					}
+				}(sd, spoofs)
+			}
+			// Anything left finished without a result
+			defer func() {
+				c.st.cancel(group)
+				for _, r := range spoofReqs {
+					c.pings.finish(r.flight, nil)
 				}
-			}(sd, spoofs)
-		}
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+			}()
 			for {
+				var res spoofResult
+				var ok bool
 				select {
 				case <-ctx.Done():
-					close(kill)
 					return
-				case probe, ok := <-rChan:
+				case res, ok = <-group.Results:
 					if !ok {
-						close(kill)
 						return
 					}
-					if probe == nil {
-						close(kill)
-						return
+				}
+				r := spoofReqs[res.ID]
+				delete(spoofReqs, res.ID)
+				var px *dm.Ping
+				if res.TimedOut {
+					px = &dm.Ping{
+						Src:         r.pm.Src,
+						Dst:         r.pm.Dst,
+						SpoofedFrom: r.pm.SpooferAddr,
+						Error:       ErrSpoofTimeout.Error(),
 					}
-					px := toPing(probe)
+				} else {
+					px = toPing(res.Probe)
 					log.Debug("Caching spoofed result with key: ", px.Key())
 					err := c.cache.SetWithExpire(px.Key(), px.CMarshal(), 5*60)
 					if err != nil {
@@ -555,13 +675,33 @@ func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *
 						log.Error(err)
 					}
 					px.Id = pid
-					select {
-					case <-ctx.Done():
-						close(kill)
-						return
-					case ret <- px:
-					}
 				}
+				c.pings.finish(r.flight, px)
+				select {
+				case <-ctx.Done():
+					return
+				case ret <- px:
+				}
+			}
+		}
+		// Spoofed pings are sent as the limits admit them, so a slow
+		// vantage point or destination only holds up its own pings
+		admittedSpoofs := c.limits.admitPings(ctx, toSpoof, prio)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := nextPings(admittedSpoofs); batch != nil; batch = nextPings(admittedSpoofs) {
+				rs := make([]spoofReq, 0, len(batch))
+				for _, sp := range batch {
+					rs = append(rs, reqs[sp])
+					delete(reqs, sp)
+				}
+				wg.Add(1)
+				go runSpoofs(rs)
+			}
+			// Spoofed pings that were never admitted finish without a result
+			for _, r := range reqs {
+				c.pings.finish(r.flight, nil)
 			}
 		}()
 		wg.Wait()
@@ -571,6 +711,18 @@ func (c *controllerT) doPing(ctx con.Context, pm []*dm.PingMeasurement) <-chan *
 	return ret
 }
 
+// reissuePing runs pm for a request that was sharing a measurement
+// whose leader finished without a result, such as when the leader's
+// request was cancelled
+func (c *controllerT) reissuePing(ctx con.Context, pm *dm.PingMeasurement, prio Priority, out chan<- *dm.Ping) {
+	for p := range c.doPing(ctx, []*dm.PingMeasurement{pm}, prio) {
+		select {
+		case out <- p:
+		case <-ctx.Done():
+		}
+	}
+}
+
 func toPing(probe *dm.Probe) *dm.Ping {
 	var ping dm.Ping
 	// Ping src and dst are reversed from the probe
@@ -599,6 +751,8 @@ func toPing(probe *dm.Probe) *dm.Ping {
 	}
 	ts := probe.GetTs()
 	if ts != nil {
+		pr.TsOverflow = ts.Overflow
+		pr.TsPointer = ts.Pointer
 		switch ts.Type {
 		case dm.TSType_TSOnly:
 			ping.Flags = append(ping.Flags, "tsonly")
@@ -616,8 +770,9 @@ func toPing(probe *dm.Probe) *dm.Ping {
 				ping.Flags = append(ping.Flags, "tsandaddr")
 				for _, stamp := range stamps {
 					ts = append(ts, &dm.TsAndAddr{
-						Ip: stamp.Ip,
-						Ts: stamp.Time,
+						Ip:      stamp.Ip,
+						Ts:      stamp.Time,
+						Stamped: stamp.Stamped,
 					})
 				}
 				pr.Tsandaddr = ts
@@ -629,8 +784,10 @@ func toPing(probe *dm.Probe) *dm.Ping {
 	return &ping
 }
 
-func (c *controllerT) doRecSpoof(ctx con.Context, pr *dm.Probe) {
-	c.sm.Notify(pr)
+// doRecSpoof hands a received spoofed probe to the measurement waiting
+// for it. It returns false if no measurement is or was waiting for it
+func (c *controllerT) doRecSpoof(ctx con.Context, pr *dm.Probe) bool {
+	return c.st.receive(pr) != spoofUnknown
 }
 
 func checkTraceCache(ctx con.Context, keys []string, ca ca.Cache) (map[string]*dm.Traceroute, error) {
@@ -710,7 +867,7 @@ func checkTraceDb(ctx con.Context, check []*dm.TracerouteMeasurement, db DataAcc
 	}
 }
 
-func (c *controllerT) doTraceroute(ctx con.Context, tms []*dm.TracerouteMeasurement) <-chan *dm.Traceroute {
+func (c *controllerT) doTraceroute(ctx con.Context, tms []*dm.TracerouteMeasurement, prio Priority) <-chan *dm.Traceroute {
 	ret := make(chan *dm.Traceroute)
 	log.Debug("Running traceroutes: ", tms)
 	go func() {
@@ -779,7 +936,9 @@ func (c *controllerT) doTraceroute(ctx con.Context, tms []*dm.TracerouteMeasurem
 				remaining = append(remaining, val)
 			}
 		}
-		mts := make(map[router.ServiceDef][]*dm.TracerouteMeasurement)
+		var wg sync.WaitGroup
+		sds := make(map[*dm.TracerouteMeasurement]router.ServiceDef)
+		var toRun []*dm.TracerouteMeasurement
 		for _, tm := range remaining {
 			ip, _ := util.Int32ToIPString(tm.Src)
 			sd, err := c.router.GetService(ip)
@@ -792,67 +951,276 @@ func (c *controllerT) doTraceroute(ctx con.Context, tms []*dm.TracerouteMeasurem
 				}
 				continue
 			}
-			mts[sd] = append(mts[sd], tm)
+			// If the same traceroute is already running share its result
+			if f, leader := c.traces.join(tm.Key()); !leader {
+				probesSaved.WithLabelValues("traceroute").Inc()
+				wg.Add(1)
+				go func(tm *dm.TracerouteMeasurement) {
+					defer wg.Done()
+					if !waitTrace(ctx, f, ret) {
+						c.reissueTrace(ctx, tm, prio, ret)
+					}
+				}(tm)
+				continue
+			}
+			sds[tm] = sd
+			toRun = append(toRun, tm)
+		}
+		runTraces := func(s router.ServiceDef, meas []*dm.TracerouteMeasurement) {
+			defer wg.Done()
+			// Anything not finished by a result finished without one
+			defer func() {
+				for _, tm := range meas {
+					c.traces.finish(tm.Key(), nil)
+				}
+			}()
+			mt, err := c.router.GetMT(s)
+			if err != nil {
+				log.Error(err)
+				errorAllTrace(ctx, err, ret, meas)
+				return
+			}
+			defer mt.Close()
+			pc, err := mt.Traceroute(ctx, &dm.TracerouteArg{
+				Traceroutes: meas,
+				Priority:    uint32(prio),
+			})
+			if err != nil {
+				log.Error(err)
+				errorAllTrace(ctx, err, ret, meas)
+				return
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case pp, ok := <-pc:
+					if !ok {
+						return
+					}
+					log.Debug("Got TR ", pp)
+					if err := c.v.Validate(pp); err != nil {
+						// Invalid traceroutes are still returned
+						// but they are not stored or cached
+						log.Debug(err)
+						c.traces.finish(pp.Key(), pp)
+						select {
+						case ret <- pp:
+						case <-ctx.Done():
+							return
+						}
+						continue
+					}
+					id, err := c.db.StoreTraceroute(pp)
+					if err != nil {
+						log.Error(err)
+					}
+					pp.Id = id
+					c.traces.finish(pp.Key(), pp)
+					if pp.Error == "" {
+						err = c.cache.SetWithExpire(pp.Key(), pp.CMarshal(), 5*60)
+						if err != nil {
+							log.Error(err)
+						}
+					}
+					select {
+					case ret <- pp:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
 		}
+		// Send the traceroutes as the limits admit them, batched by service
+		admitted := c.limits.admitTraces(ctx, toRun, prio)
+		for batch := nextTraces(admitted); batch != nil; batch = nextTraces(admitted) {
+			mts := make(map[router.ServiceDef][]*dm.TracerouteMeasurement)
+			for _, tm := range batch {
+				mts[sds[tm]] = append(mts[sds[tm]], tm)
+				delete(sds, tm)
+			}
+			for sd, tms := range mts {
+				wg.Add(1)
+				go runTraces(sd, tms)
+			}
+		}
+		// Traceroutes that were never admitted finish without a result
+		for tm := range sds {
+			c.traces.finish(tm.Key(), nil)
+		}
+		wg.Wait()
+		close(ret)
+	}()
+
+	return ret
+}
+
+func errorAllTracelb(ctx con.Context, err error, out chan<- *dm.Tracelb, ts []*dm.TracelbMeasurement) {
+	for _, t := range ts {
+		select {
+		case out <- &dm.Tracelb{
+			Src:   t.Src,
+			Dst:   t.Dst,
+			Error: err.Error(),
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// doTracelb runs MDA traceroutes. They enumerate every path through load
+// balancers so they are never shared or served from the cache, but they
+// are rate limited like traceroutes and stored once they finish
+// reissueTrace runs tm for a request that was sharing a measurement
+// whose leader finished without a result
+func (c *controllerT) reissueTrace(ctx con.Context, tm *dm.TracerouteMeasurement, prio Priority, out chan<- *dm.Traceroute) {
+	for t := range c.doTraceroute(ctx, []*dm.TracerouteMeasurement{tm}, prio) {
+		select {
+		case out <- t:
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (c *controllerT) doTracelb(ctx con.Context, tms []*dm.TracelbMeasurement, prio Priority) <-chan *dm.Tracelb {
+	ret := make(chan *dm.Tracelb)
+	log.Debug("Running tracelbs: ", tms)
+	go func() {
 		var wg sync.WaitGroup
-		for sd, tms := range mts {
+		for _, tm := range tms {
+			ip, _ := util.Int32ToIPString(tm.Src)
+			sd, err := c.router.GetService(ip)
+			if err != nil {
+				log.Error(err)
+				errorAllTracelb(ctx, err, ret, []*dm.TracelbMeasurement{tm})
+				continue
+			}
 			wg.Add(1)
-			go func(s router.ServiceDef, meas []*dm.TracerouteMeasurement) {
+			go func(sd router.ServiceDef, tm *dm.TracelbMeasurement) {
 				defer wg.Done()
-				mt, err := c.router.GetMT(s)
+				if err := c.limits.wait(ctx, tm.Src, tm.Dst, prio); err != nil {
+					errorAllTracelb(ctx, err, ret, []*dm.TracelbMeasurement{tm})
+					return
+				}
+				mt, err := c.router.GetMT(sd)
 				if err != nil {
 					log.Error(err)
-					errorAllTrace(ctx, err, ret, meas)
+					errorAllTracelb(ctx, err, ret, []*dm.TracelbMeasurement{tm})
 					return
 				}
 				defer mt.Close()
-				pc, err := mt.Traceroute(ctx, &dm.TracerouteArg{
-					Traceroutes: meas,
+				tc, err := mt.Tracelb(ctx, &dm.TracelbArg{
+					Tracelbs: []*dm.TracelbMeasurement{tm},
+					Priority: uint32(prio),
 				})
 				if err != nil {
 					log.Error(err)
-					errorAllTrace(ctx, err, ret, meas)
+					errorAllTracelb(ctx, err, ret, []*dm.TracelbMeasurement{tm})
 					return
 				}
-				for {
-					select {
-					case <-ctx.Done():
-						return
-					case pp, ok := <-pc:
-						if !ok {
-							return
-						}
-						log.Debug("Got TR ", pp)
-						id, err := c.db.StoreTraceroute(pp)
+				for tl := range tc {
+					if tl.Error == "" {
+						id, err := c.db.StoreTracelb(tl)
 						if err != nil {
 							log.Error(err)
 						}
-						pp.Id = id
-						if pp.Error == "" {
-							err = c.cache.SetWithExpire(pp.Key(), pp.CMarshal(), 5*60)
-							if err != nil {
-								log.Error(err)
-							}
-						}
-						select {
-						case ret <- pp:
-						case <-ctx.Done():
-							return
-						}
+						tl.Id = id
+					}
+					select {
+					case ret <- tl:
+					case <-ctx.Done():
+						return
 					}
 				}
-			}(sd, tms)
+			}(sd, tm)
 		}
 		wg.Wait()
 		close(ret)
 	}()
+	return ret
+}
 
+func errorAllDealias(ctx con.Context, err error, out chan<- *dm.Dealias, ds []*dm.DealiasMeasurement) {
+	for _, d := range ds {
+		select {
+		case out <- &dm.Dealias{
+			Src:    d.Src,
+			Method: d.Method,
+			Addrs:  d.Addrs,
+			Error:  err.Error(),
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// doDealias runs alias resolution measurements. Like tracelbs they are
+// never cached, the results are only useful to whoever merges the clusters
+func (c *controllerT) doDealias(ctx con.Context, dms []*dm.DealiasMeasurement, prio Priority) <-chan *dm.Dealias {
+	ret := make(chan *dm.Dealias)
+	log.Debug("Running dealiases: ", dms)
+	go func() {
+		var wg sync.WaitGroup
+		for _, d := range dms {
+			if len(d.Addrs) == 0 {
+				errorAllDealias(ctx, fmt.Errorf("Dealias without addresses"), ret, []*dm.DealiasMeasurement{d})
+				continue
+			}
+			ip, _ := util.Int32ToIPString(d.Src)
+			sd, err := c.router.GetService(ip)
+			if err != nil {
+				log.Error(err)
+				errorAllDealias(ctx, err, ret, []*dm.DealiasMeasurement{d})
+				continue
+			}
+			wg.Add(1)
+			go func(sd router.ServiceDef, d *dm.DealiasMeasurement) {
+				defer wg.Done()
+				if err := c.limits.wait(ctx, d.Src, d.Addrs[0], prio); err != nil {
+					errorAllDealias(ctx, err, ret, []*dm.DealiasMeasurement{d})
+					return
+				}
+				mt, err := c.router.GetMT(sd)
+				if err != nil {
+					log.Error(err)
+					errorAllDealias(ctx, err, ret, []*dm.DealiasMeasurement{d})
+					return
+				}
+				defer mt.Close()
+				dc, err := mt.Dealias(ctx, &dm.DealiasArg{
+					Dealiases: []*dm.DealiasMeasurement{d},
+					Priority:  uint32(prio),
+				})
+				if err != nil {
+					log.Error(err)
+					errorAllDealias(ctx, err, ret, []*dm.DealiasMeasurement{d})
+					return
+				}
+				for res := range dc {
+					select {
+					case ret <- res:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(sd, d)
+		}
+		wg.Wait()
+		close(ret)
+	}()
 	return ret
 }
 
 func (c *controllerT) fetchVPs(ctx con.Context, gvp *dm.VPRequest) (*dm.VPReturn, error) {
 	mts := c.router.All()
 	var ret dm.VPReturn
+	defer func() {
+		for _, mt := range mts {
+			mt.Close()
+		}
+	}()
 	for _, mt := range mts {
 		vpc, err := mt.GetVPs(ctx, gvp)
 		if err != nil {
@@ -861,11 +1229,55 @@ func (c *controllerT) fetchVPs(ctx con.Context, gvp *dm.VPRequest) (*dm.VPReturn
 		for vp := range vpc {
 			ret.Vps = append(ret.Vps, vp.Vps...)
 		}
-		mt.Close()
 	}
 	return &ret, nil
 }
 
+// fetchVPStatus collects the load the measurement tools report for the vps
+// in vps, keyed by vp. Tools that can't report are skipped
+func (c *controllerT) fetchVPStatus(ctx con.Context, vps []uint32) map[uint32]*dm.VPStatus {
+	mts := c.router.All()
+	ret := make(map[uint32]*dm.VPStatus)
+	defer func() {
+		for _, mt := range mts {
+			mt.Close()
+		}
+	}()
+	for _, mt := range mts {
+		st, err := mt.GetVPStatus(ctx, &dm.VPStatusRequest{Vps: vps})
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		for _, s := range st.GetStatuses() {
+			ret[s.Ip] = s
+		}
+	}
+	return ret
+}
+
+// leastLoaded returns the vp in vps with the least load, picking randomly
+// between vps that are equally loaded. vps without a status are only
+// picked when none has one
+func leastLoaded(vps []uint32, status map[uint32]*dm.VPStatus) uint32 {
+	var best []uint32
+	var min float64
+	for _, vp := range vps {
+		l := status[vp].Load()
+		switch {
+		case len(best) == 0 || l < min:
+			best = []uint32{vp}
+			min = l
+		case l == min:
+			best = append(best, vp)
+		}
+	}
+	if len(best) == 0 {
+		return 0
+	}
+	return best[rand.Intn(len(best))]
+}
+
 func (c *controllerT) doGetVPs(ctx con.Context, gvp *dm.VPRequest) (*dm.VPReturn, error) {
 	return c.fetchVPs(ctx, gvp)
 }
@@ -877,14 +1289,23 @@ func startHTTP(addr string) {
 }
 
 func (c *controllerT) stop() {
+	c.stopOnce.Do(func() {
+		if c.done != nil {
+			close(c.done)
+		}
+	})
 	if c.db != nil {
 		c.db.Close()
 	}
+	for _, db := range c.aliasDbs {
+		db.Close()
+	}
 }
 
 func (c *controllerT) run(ec chan error, con Config, db DataAccess, cache ca.Cache, r router.Router) {
 	controller.config = con
 	controller.db = db
+	controller.done = make(chan struct{})
 	controller.cache = cache
 	controller.router = r
 	if db == nil {
@@ -915,8 +1336,24 @@ func (c *controllerT) run(ec chan error, con Config, db DataAccess, cache ca.Cac
 	}
 	controller.server = grpc.NewServer(grpc.Creds(certs))
 	controllerapi.RegisterControllerServer(controller.server, c)
-	controller.sm = &spoofMap{
-		sm: make(map[uint32]*channel),
+	controller.st = newSpoofTracker(time.Duration(*con.Local.SpoofTimeout)*time.Second, 10*time.Minute)
+	go controller.st.run(time.Second, nil)
+	controller.v = trvalidate.New(con.Validation)
+	controller.pings = newFlightGroup()
+	controller.traces = newFlightGroup()
+	controller.limits = newLimits(con.Limits)
+	maxJobs := 1
+	if con.Local.MaxJobs != nil {
+		maxJobs = *con.Local.MaxJobs
+	}
+	controller.jobs = newJobStore(maxJobs, jobKeep)
+	if con.Alias.Interval != nil && *con.Alias.Interval > 0 {
+		ar, err := c.newAliasResolver(con.Alias)
+		if err != nil {
+			log.Errorf("Not resolving aliases: %v", err)
+		} else {
+			go ar.run(time.Duration(*con.Alias.Interval)*time.Second, c.done)
+		}
 	}
 	go controller.startRPC(ec)
 }
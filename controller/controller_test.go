@@ -26,3 +26,200 @@
 */
 
 package controller
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ca "github.com/NEU-SNS/ReverseTraceroute/cache"
+	"github.com/NEU-SNS/ReverseTraceroute/controller/mocks"
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/router"
+	"github.com/stretchr/testify/mock"
+	con "golang.org/x/net/context"
+)
+
+func TestLeastLoaded(t *testing.T) {
+	status := map[uint32]*dm.VPStatus{
+		1: &dm.VPStatus{Ip: 1, Outstanding: 40, Queued: 10, Issued: 100},
+		2: &dm.VPStatus{Ip: 2, Outstanding: 2, Issued: 100, Latency: 200000},
+		3: &dm.VPStatus{Ip: 3, Outstanding: 2, Issued: 100, Errors: 50},
+		4: &dm.VPStatus{Ip: 4, Outstanding: 2, Issued: 100, Latency: 100000},
+	}
+	for _, test := range []struct {
+		vps  []uint32
+		want uint32
+	}{
+		{vps: []uint32{1, 2, 3, 4}, want: 4},
+		{vps: []uint32{1, 2, 3}, want: 2},
+		{vps: []uint32{1, 3}, want: 3},
+		// A vp without a status may be down
+		{vps: []uint32{1, 2, 5}, want: 2},
+		{vps: []uint32{5}, want: 5},
+		{vps: nil, want: 0},
+	} {
+		if got := leastLoaded(test.vps, status); got != test.want {
+			t.Errorf("leastLoaded(%v) = %d, want %d", test.vps, got, test.want)
+		}
+	}
+}
+
+type fakeVPs []*dm.VantagePoint
+
+func (f fakeVPs) GetVPs() ([]*dm.VantagePoint, error) {
+	return f, nil
+}
+
+// fakeRouter routes with a real Source and records what is sent to
+// each service instead of dialing it
+type fakeRouter struct {
+	router.Source
+	mu     sync.Mutex
+	pings  map[string][]*dm.PingMeasurement
+	spoofs map[string][]*dm.Spoof
+	// reply answers plain pings
+	reply bool
+}
+
+func (r *fakeRouter) GetMT(sd router.ServiceDef) (router.MeasurementTool, error) {
+	return fakeMT{r: r, addr: sd.Addr}, nil
+}
+
+func (r *fakeRouter) GetService(addr string) (router.ServiceDef, error) {
+	return r.Source.Get(addr)
+}
+
+func (r *fakeRouter) All() []router.MeasurementTool { return nil }
+
+func (r *fakeRouter) SetSource(s router.Source) { r.Source = s }
+
+type fakeMT struct {
+	router.MeasurementTool
+	r    *fakeRouter
+	addr string
+}
+
+func (m fakeMT) Ping(ctx con.Context, pa *dm.PingArg) (<-chan *dm.Ping, error) {
+	m.r.mu.Lock()
+	m.r.pings[m.addr] = append(m.r.pings[m.addr], pa.Pings...)
+	m.r.mu.Unlock()
+	ret := make(chan *dm.Ping, len(pa.Pings))
+	for _, pm := range pa.Pings {
+		// Spoofed results come back through the receiver
+		if m.r.reply && !pm.Spoof {
+			ret <- &dm.Ping{Src: pm.Src, Dst: pm.Dst}
+		}
+	}
+	close(ret)
+	return ret, nil
+}
+
+func (m fakeMT) ReceiveSpoof(ctx con.Context, rs *dm.RecSpoof) (<-chan *dm.NotifyRecSpoofResponse, error) {
+	m.r.mu.Lock()
+	m.r.spoofs[m.addr] = append(m.r.spoofs[m.addr], rs.Spoofs...)
+	m.r.mu.Unlock()
+	ret := make(chan *dm.NotifyRecSpoofResponse)
+	close(ret)
+	return ret, nil
+}
+
+func (m fakeMT) Close() error { return nil }
+
+type fakeCache struct{}
+
+func (fakeCache) Get(string) (ca.Item, error) { return nil, ca.ErrorCacheMiss }
+
+func (fakeCache) GetMulti([]string) (map[string]ca.Item, error) { return nil, nil }
+
+func (fakeCache) Set(string, []byte) error { return nil }
+
+func (fakeCache) SetWithExpire(string, []byte, int32) error { return nil }
+
+// newTestController routes 10.0.0.1 to plcontroller 10.0.1.1 and
+// 10.0.0.2 to plcontroller 10.0.1.2 through a DBSource
+func newTestController(t *testing.T) (*controllerT, *fakeRouter) {
+	src := router.NewDBSource(fakeVPs{
+		{Ip: 167772161, Controller: 167772417},
+		{Ip: 167772162, Controller: 167772418},
+	}, "4380", time.Minute, time.Minute)
+	if err := src.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeRouter{
+		Source: src,
+		pings:  make(map[string][]*dm.PingMeasurement),
+		spoofs: make(map[string][]*dm.Spoof),
+	}
+	db := &mocks.DataAccess{}
+	db.On("GetPingsMulti", mock.Anything).Return([]*dm.Ping(nil), nil)
+	db.On("StorePing", mock.Anything).Return(int64(1), nil)
+	off, zero := 0.0, 0
+	return &controllerT{
+		db:     db,
+		cache:  fakeCache{},
+		router: r,
+		st:     newSpoofTracker(time.Minute, time.Minute),
+		pings:  newFlightGroup(),
+		limits: newLimits(LimitConfig{
+			VPRate:   &off,
+			VPBurst:  &zero,
+			DstRate:  &off,
+			DstBurst: &zero,
+		}),
+	}, r
+}
+
+func TestDoPingSpoofedDoesNotShareWithPlain(t *testing.T) {
+	c, r := newTestController(t)
+	r.reply = true
+	plain := &dm.PingMeasurement{Src: 167772161, Dst: 167772173, SAddr: "10.0.0.2", Count: "1", Timeout: 1}
+	spoofed := &dm.PingMeasurement{Src: 167772161, Dst: 167772173, SAddr: "10.0.0.2", Count: "1", Timeout: 1,
+		Spoof: true, SpooferAddr: 167772162}
+	ctx, cancel := con.WithTimeout(con.Background(), 100*time.Millisecond)
+	defer cancel()
+	var got []*dm.Ping
+	for p := range c.doPing(ctx, []*dm.PingMeasurement{plain, spoofed}, 0) {
+		got = append(got, p)
+	}
+	// The spoofed ping gets no reply before ctx is done, it must not
+	// be handed the result of the plain ping
+	if len(got) != 1 {
+		t.Fatalf("Expected only the plain result, got %v", got)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pings["10.0.1.1"]) != 2 || len(r.spoofs["10.0.1.2"]) != 1 {
+		t.Fatalf("Expected both pings sent, got %v %v", r.pings, r.spoofs)
+	}
+}
+
+func TestDoPingSpoofedRRThroughDBSource(t *testing.T) {
+	c, r := newTestController(t)
+	// Like the runner's spoofed record route pings SpooferAddr is unset
+	pm := &dm.PingMeasurement{
+		Src:     167772161,
+		Dst:     167772173,
+		SAddr:   "10.0.0.2",
+		Spoof:   true,
+		RR:      true,
+		Count:   "1",
+		Timeout: 1,
+	}
+	ctx, cancel := con.WithTimeout(con.Background(), 100*time.Millisecond)
+	defer cancel()
+	for p := range c.doPing(ctx, []*dm.PingMeasurement{pm}, 0) {
+		if p.Error != "" {
+			t.Fatalf("Spoofed RR ping failed: %s", p.Error)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pings["10.0.1.1"]) != 1 {
+		t.Fatalf("Expected the ping sent through the sender's plcontroller, got %v", r.pings)
+	}
+	sps := r.spoofs["10.0.1.2"]
+	if len(sps) != 1 || sps[0].Sip != 167772162 || sps[0].Ip != pm.Src {
+		t.Fatalf("Expected the spoof registered with the receiver's plcontroller, got %v", r.spoofs)
+	}
+}
@@ -52,7 +52,7 @@ func (c *controllerT) Ping(pa *dm.PingArg, stream cont.Controller_PingServer) er
 	start := time.Now()
 	ctx, cancel := con.WithCancel(stream.Context())
 	defer cancel()
-	res := c.doPing(ctx, pms)
+	res := c.doPing(ctx, pms, Priority(pa.Priority))
 	for {
 		select {
 		case p, ok := <-res:
@@ -83,7 +83,7 @@ func (c *controllerT) Traceroute(ta *dm.TracerouteArg, stream cont.Controller_Tr
 	start := time.Now()
 	ctx, cancel := con.WithCancel(stream.Context())
 	defer cancel()
-	res := c.doTraceroute(ctx, tms)
+	res := c.doTraceroute(ctx, tms, Priority(ta.Priority))
 	for {
 		select {
 		case t, ok := <-res:
@@ -106,7 +106,64 @@ func (c *controllerT) Traceroute(ta *dm.TracerouteArg, stream cont.Controller_Tr
 	}
 }
 
-//Priority is the priority for ping request
+func (c *controllerT) Tracelb(ta *dm.TracelbArg, stream cont.Controller_TracelbServer) error {
+	tms := ta.GetTracelbs()
+	if tms == nil {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		tracelbResponseTimes.Observe(time.Since(start).Seconds())
+	}()
+	ctx, cancel := con.WithCancel(stream.Context())
+	defer cancel()
+	res := c.doTracelb(ctx, tms, Priority(ta.Priority))
+	for {
+		select {
+		case t, ok := <-res:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(t); err != nil {
+				log.Error(err)
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *controllerT) Dealias(da *dm.DealiasArg, stream cont.Controller_DealiasServer) error {
+	dms := da.GetDealiases()
+	if dms == nil {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		dealiasResponseTimes.Observe(time.Since(start).Seconds())
+	}()
+	ctx, cancel := con.WithCancel(stream.Context())
+	defer cancel()
+	res := c.doDealias(ctx, dms, Priority(da.Priority))
+	for {
+		select {
+		case t, ok := <-res:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(t); err != nil {
+				log.Error(err)
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//Priority is the priority for ping request. When measurements are
+//queued by the rate limits higher priorities are sent first
 type Priority uint32
 
 //PingReq is a request for pings
@@ -153,106 +210,351 @@ func (c *controllerT) verifyKey(key string) (dm.User, bool) {
 	return u, true
 }
 
-func (c *controllerT) GetPingsHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodGet {
-		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return
+// apiError is the body of an error response from the HTTP api
+type apiError struct {
+	Error apiErrorBody `json:"error"`
+}
+
+type apiErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeError(rw http.ResponseWriter, code int, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	err := json.NewEncoder(rw).Encode(apiError{Error: apiErrorBody{Code: code, Message: msg}})
+	if err != nil {
+		log.Error(err)
 	}
-	key := req.Header.Get(apiKey)
-	u, ok := c.verifyKey(key)
+}
+
+func writeJSON(rw http.ResponseWriter, code int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		log.Error(err)
+	}
+}
+
+// authorize checks the method and api key of req, writing an error if either is wrong
+func (c *controllerT) authorize(rw http.ResponseWriter, req *http.Request, method string) (dm.User, bool) {
+	if req.Method != method {
+		writeError(rw, http.StatusMethodNotAllowed, fmt.Sprintf("Method must be %s", method))
+		return dm.User{}, false
+	}
+	u, ok := c.verifyKey(req.Header.Get(apiKey))
+	if !ok {
+		writeError(rw, http.StatusUnauthorized, "Invalid or missing "+apiKey)
+		return dm.User{}, false
+	}
+	return u, true
+}
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+	// jobKeep is how long finished jobs are kept for status requests
+	jobKeep = 24 * time.Hour
+)
+
+// page is the window of a batch's results that was requested
+type page struct {
+	id     int64
+	offset int
+	limit  int
+}
+
+func parsePage(req *http.Request) (page, error) {
+	var p page
+	var err error
+	p.id, err = strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		return p, fmt.Errorf("id must be a batch id")
+	}
+	p.limit = defaultPageSize
+	if l := req.FormValue("limit"); l != "" {
+		p.limit, err = strconv.Atoi(l)
+		if err != nil || p.limit <= 0 || p.limit > maxPageSize {
+			return p, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+	}
+	if o := req.FormValue("offset"); o != "" {
+		p.offset, err = strconv.Atoi(o)
+		if err != nil || p.offset < 0 {
+			return p, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+	return p, nil
+}
+
+// window returns the start and end of the page in a result set of length total
+// and the url of the next page if there is one
+func (p page) window(total int, path string) (int, int, string) {
+	start, end := p.offset, p.offset+p.limit
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	var next string
+	if end < total {
+		next = fmt.Sprintf("%s?id=%d&offset=%d&limit=%d", path, p.id, end, p.limit)
+	}
+	return start, end, next
+}
+
+type pingsPage struct {
+	Job   *jobStatus        `json:"job,omitempty"`
+	Total int               `json:"total"`
+	Next  string            `json:"next,omitempty"`
+	Pings []json.RawMessage `json:"pings"`
+}
+
+type tracesPage struct {
+	Job         *jobStatus        `json:"job,omitempty"`
+	Total       int               `json:"total"`
+	Next        string            `json:"next,omitempty"`
+	Traceroutes []json.RawMessage `json:"traceroutes"`
+}
+
+// jobResponse is returned when a batch is submitted
+type jobResponse struct {
+	Results string    `json:"results,omitempty"`
+	Job     jobStatus `json:"job"`
+}
+
+func (c *controllerT) GetPingsHandler(rw http.ResponseWriter, req *http.Request) {
+	u, ok := c.authorize(rw, req, http.MethodGet)
 	if !ok {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
-	id := req.FormValue("id")
-	idi, err := strconv.ParseInt(id, 10, 64)
+	p, err := parsePage(req)
 	if err != nil {
-		log.Error(err)
-		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		writeError(rw, http.StatusBadRequest, err.Error())
 		return
 	}
-	log.Debug("Getting pings for batch ", idi)
-	pings, err := c.db.GetPingBatch(u, idi)
+	log.Debug("Getting pings for batch ", p.id)
+	pings, err := c.db.GetPingBatch(u, p.id)
 	if err != nil {
 		log.Error(err)
-		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeError(rw, http.StatusInternalServerError, "Failed to get the batch")
 		return
 	}
-	rw.Header().Set("Content-Type", "application/json")
+	ret := pingsPage{Total: len(pings)}
+	if j, ok := c.jobs.get(pingJob, p.id, u.ID); ok {
+		s := j.snapshot()
+		ret.Job = &s
+	} else if len(pings) == 0 {
+		writeError(rw, http.StatusNotFound, "No batch found with the given id")
+		return
+	}
+	start, end, next := p.window(len(pings), v1Prefix+"pings")
+	ret.Next = next
+	ret.Pings = make([]json.RawMessage, 0, end-start)
 	var marsh jsonpb.Marshaler
-	if err := marsh.Marshal(rw, &dm.PingArgResp{Pings: pings}); err != nil {
-		panic(err)
+	for _, ping := range pings[start:end] {
+		js, err := marsh.MarshalToString(ping)
+		if err != nil {
+			log.Error(err)
+			writeError(rw, http.StatusInternalServerError, "Failed to encode the results")
+			return
+		}
+		ret.Pings = append(ret.Pings, json.RawMessage(js))
 	}
+	writeJSON(rw, http.StatusOK, ret)
 }
 
 func (c *controllerT) GetTracesHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodGet {
-		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return
-	}
-	key := req.Header.Get(apiKey)
-	u, ok := c.verifyKey(key)
+	u, ok := c.authorize(rw, req, http.MethodGet)
 	if !ok {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
-	id := req.FormValue("id")
-	idi, err := strconv.ParseInt(id, 10, 64)
+	p, err := parsePage(req)
 	if err != nil {
-		log.Error(err)
-		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		writeError(rw, http.StatusBadRequest, err.Error())
 		return
 	}
-	log.Debug("Getting traces for batch ", idi)
-	traces, err := c.db.GetTraceBatch(u, idi)
+	log.Debug("Getting traces for batch ", p.id)
+	traces, err := c.db.GetTraceBatch(u, p.id)
 	if err != nil {
 		log.Error(err)
-		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeError(rw, http.StatusInternalServerError, "Failed to get the batch")
 		return
 	}
-	rw.Header().Set("Content-Type", "application/json")
+	ret := tracesPage{Total: len(traces)}
+	if j, ok := c.jobs.get(traceJob, p.id, u.ID); ok {
+		s := j.snapshot()
+		ret.Job = &s
+	} else if len(traces) == 0 {
+		writeError(rw, http.StatusNotFound, "No batch found with the given id")
+		return
+	}
+	start, end, next := p.window(len(traces), v1Prefix+"traceroutes")
+	ret.Next = next
+	ret.Traceroutes = make([]json.RawMessage, 0, end-start)
 	var marsh jsonpb.Marshaler
-	if err := marsh.Marshal(rw, &dm.TracerouteArgResp{Traceroutes: traces}); err != nil {
-		panic(err)
+	for _, trace := range traces[start:end] {
+		js, err := marsh.MarshalToString(trace)
+		if err != nil {
+			log.Error(err)
+			writeError(rw, http.StatusInternalServerError, "Failed to encode the results")
+			return
+		}
+		ret.Traceroutes = append(ret.Traceroutes, json.RawMessage(js))
 	}
+	writeJSON(rw, http.StatusOK, ret)
 }
 
-func (c *controllerT) TracerouteHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodPost {
-		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return
+// ErrNoResults is used when a job finishes without a single result
+var ErrNoResults = fmt.Errorf("No measurements in the job returned a result")
+
+func pairKey(src, dst uint32) string {
+	return fmt.Sprintf("%d_%d", src, dst)
+}
+
+// recordMissing adds an error to j for each measurement left in missing
+func recordMissing(ctx con.Context, j *job, missing map[string]int) {
+	reason := "No result returned"
+	if ctx.Err() != nil {
+		reason = "No result before the job deadline"
+	}
+	for key, count := range missing {
+		var src, dst uint32
+		fmt.Sscanf(key, "%d_%d", &src, &dst)
+		srcs, _ := util.Int32ToIPString(src)
+		dsts, _ := util.Int32ToIPString(dst)
+		for i := 0; i < count; i++ {
+			j.failed(srcs, dsts, reason)
+		}
 	}
-	key := req.Header.Get(apiKey)
-	u, ok := c.verifyKey(key)
+}
+
+func (c *controllerT) jobTimeout() time.Duration {
+	if c.config.Local.JobTimeout == nil || *c.config.Local.JobTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(*c.config.Local.JobTimeout) * time.Second
+}
+
+// startPingJob runs pings as the job for batch bid, adding results to the batch as they arrive
+func (c *controllerT) startPingJob(u dm.User, bid int64, pings []*dm.PingMeasurement, prio Priority) *job {
+	j := c.jobs.add(pingJob, bid, u.ID, len(pings))
+	c.jobs.run(j, c.jobTimeout(), func(ctx con.Context, j *job) error {
+		missing := make(map[string]int)
+		for _, p := range pings {
+			missing[pairKey(p.Src, p.Dst)]++
+		}
+		var stored int
+		for p := range c.doPing(ctx, pings, prio) {
+			key := pairKey(p.Src, p.Dst)
+			if missing[key] > 0 {
+				missing[key]--
+			}
+			if missing[key] == 0 {
+				delete(missing, key)
+			}
+			srcs, _ := util.Int32ToIPString(p.Src)
+			dsts, _ := util.Int32ToIPString(p.Dst)
+			if p.Error != "" {
+				j.failed(srcs, dsts, p.Error)
+				continue
+			}
+			if p.Id == 0 {
+				j.failed(srcs, dsts, "The result could not be stored")
+				continue
+			}
+			if err := c.db.AddPingsToBatch(bid, []int64{p.Id}); err != nil {
+				return err
+			}
+			stored++
+			j.completed()
+		}
+		recordMissing(ctx, j, missing)
+		if stored == 0 {
+			return ErrNoResults
+		}
+		return nil
+	})
+	return j
+}
+
+// startTraceJob runs traces as the job for batch bid, adding results to the batch as they arrive
+func (c *controllerT) startTraceJob(u dm.User, bid int64, traces []*dm.TracerouteMeasurement, prio Priority) *job {
+	j := c.jobs.add(traceJob, bid, u.ID, len(traces))
+	c.jobs.run(j, c.jobTimeout(), func(ctx con.Context, j *job) error {
+		missing := make(map[string]int)
+		for _, t := range traces {
+			missing[pairKey(t.Src, t.Dst)]++
+		}
+		var stored int
+		for t := range c.doTraceroute(ctx, traces, prio) {
+			key := pairKey(t.Src, t.Dst)
+			if missing[key] > 0 {
+				missing[key]--
+			}
+			if missing[key] == 0 {
+				delete(missing, key)
+			}
+			srcs, _ := util.Int32ToIPString(t.Src)
+			dsts, _ := util.Int32ToIPString(t.Dst)
+			if t.Error != "" {
+				j.failed(srcs, dsts, t.Error)
+				continue
+			}
+			if t.Id == 0 {
+				j.failed(srcs, dsts, "The result could not be stored")
+				continue
+			}
+			if err := c.db.AddTraceToBatch(bid, []int64{t.Id}); err != nil {
+				return err
+			}
+			stored++
+			j.completed()
+		}
+		recordMissing(ctx, j, missing)
+		if stored == 0 {
+			return ErrNoResults
+		}
+		return nil
+	})
+	return j
+}
+
+// parseSrcDst parses the src and dst of a measurement in a request
+func parseSrcDst(i int, src, dst string) (uint32, uint32, error) {
+	srci, err := util.IPStringToInt32(src)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Measurement %d: invalid src %q", i, src)
+	}
+	dsti, err := util.IPStringToInt32(dst)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Measurement %d: invalid dst %q", i, dst)
+	}
+	return srci, dsti, nil
+}
+
+func (c *controllerT) TracerouteHandler(rw http.ResponseWriter, req *http.Request) {
+	u, ok := c.authorize(rw, req, http.MethodPost)
 	if !ok {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
 	var treq TraceReq
 	if err := json.NewDecoder(req.Body).Decode(&treq); err != nil {
-		panic(err)
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
 	}
 	log.Debug("Running traces treq")
 	var traces []*dm.TracerouteMeasurement
-	for _, t := range treq.Traceroutes {
+	for i, t := range treq.Traceroutes {
 		if t.Attempts > maxTracerouteAttempts || t.Attempts < 0 {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
-		}
-		srci, err := util.IPStringToInt32(t.Src)
-		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf("Measurement %d: attempts must be between 0 and %d", i, maxTracerouteAttempts))
 			return
 		}
-		dsti, err := util.IPStringToInt32(t.Dst)
+		srci, dsti, err := parseSrcDst(i, t.Src, t.Dst)
 		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, err.Error())
 			return
 		}
 		if t.Attempts == 0 {
@@ -266,72 +568,60 @@ func (c *controllerT) TracerouteHandler(rw http.ResponseWriter, req *http.Reques
 		})
 	}
 	if len(traces) == 0 {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		writeError(rw, http.StatusBadRequest, "No traceroutes in the request")
 		return
 	}
 	bid, err := c.db.AddTraceBatch(u)
 	if err != nil {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		log.Error(err)
+		writeError(rw, http.StatusInternalServerError, "Failed to create the batch")
 		return
 	}
-	go func() {
-		ctx := con.Background()
-		ctx, cancel := con.WithTimeout(ctx, time.Second*30)
-		defer cancel()
-		results := c.doTraceroute(ctx, traces)
-		var ids []int64
-		for t := range results {
-			ids = append(ids, t.Id)
-		}
-		err := c.db.AddTraceToBatch(bid, ids)
-		if err != nil {
-			log.Error(err)
-		}
-	}()
-	rw.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(rw).Encode(struct {
-		Results string `json:"results,omitempty"`
-	}{Results: fmt.Sprintf("https://%s%s?id=%d", "revtr.ccs.neu.edu", v1Prefix+"traceroutes", bid)})
+	j := c.startTraceJob(u, bid, traces, treq.Priority)
+	writeJSON(rw, http.StatusAccepted, jobResponse{
+		Results: fmt.Sprintf("https://%s%s?id=%d", "revtr.ccs.neu.edu", v1Prefix+"traceroutes", bid),
+		Job:     j.snapshot(),
+	})
+}
+
+// submitPings creates a batch for pings and starts the job that runs them
+func (c *controllerT) submitPings(rw http.ResponseWriter, u dm.User, pings []*dm.PingMeasurement, prio Priority) {
+	if len(pings) == 0 {
+		writeError(rw, http.StatusBadRequest, "No pings in the request")
+		return
+	}
+	bid, err := c.db.AddPingBatch(u)
 	if err != nil {
-		panic(err)
+		log.Error(err)
+		writeError(rw, http.StatusInternalServerError, "Failed to create the batch")
+		return
 	}
+	j := c.startPingJob(u, bid, pings, prio)
+	writeJSON(rw, http.StatusAccepted, jobResponse{
+		Results: fmt.Sprintf("https://%s%s?id=%d", "revtr.ccs.neu.edu", v1Prefix+"pings", bid),
+		Job:     j.snapshot(),
+	})
 }
 
 func (c *controllerT) PingHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodPut {
-		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return
-	}
-	key := req.Header.Get(apiKey)
-	u, ok := c.verifyKey(key)
+	u, ok := c.authorize(rw, req, http.MethodPut)
 	if !ok {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
 	var preq PingReq
 	if err := json.NewDecoder(req.Body).Decode(&preq); err != nil {
-		panic(err)
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
 	}
 	var pings []*dm.PingMeasurement
-	for _, p := range preq.Pings {
+	for i, p := range preq.Pings {
 		if p.Count > maxProbeCount || p.Count <= 0 {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
-		}
-		srci, err := util.IPStringToInt32(p.Src)
-		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf("Measurement %d: count must be between 1 and %d", i, maxProbeCount))
 			return
 		}
-		dsti, err := util.IPStringToInt32(p.Dst)
+		srci, dsti, err := parseSrcDst(i, p.Src, p.Dst)
 		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, err.Error())
 			return
 		}
 		pings = append(pings, &dm.PingMeasurement{
@@ -340,66 +630,26 @@ func (c *controllerT) PingHandler(rw http.ResponseWriter, req *http.Request) {
 			Timeout: 10,
 			Count:   fmt.Sprintf("%d", p.Count),
 		})
-
-	}
-	bid, err := c.db.AddPingBatch(u)
-	if err != nil {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return
-	}
-	go func() {
-		ctx := con.Background()
-		ctx, cancel := con.WithTimeout(ctx, time.Second*30)
-		defer cancel()
-		results := c.doPing(ctx, pings)
-		var ids []int64
-		for p := range results {
-			ids = append(ids, p.Id)
-		}
-		err := c.db.AddPingsToBatch(bid, ids)
-		if err != nil {
-			log.Error(err)
-		}
-	}()
-	rw.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(rw).Encode(struct {
-		Results string `json:"results,omitempty"`
-	}{Results: fmt.Sprintf("https://%s%s?id=%d", "revtr.ccs.neu.edu", v1Prefix+"pings", bid)})
-	if err != nil {
-		panic(err)
 	}
+	c.submitPings(rw, u, pings, preq.Priority)
 }
 
 func (c *controllerT) RecordRouteHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodPost {
-		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return
-	}
-	key := req.Header.Get(apiKey)
-	u, ok := c.verifyKey(key)
+	u, ok := c.authorize(rw, req, http.MethodPost)
 	if !ok {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
 	var preq PingReq
 	if err := json.NewDecoder(req.Body).Decode(&preq); err != nil {
-		panic(err)
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
 	}
 	log.Debug("Running record routes ", preq)
 	var pings []*dm.PingMeasurement
-	for _, p := range preq.Pings {
-		srci, err := util.IPStringToInt32(p.Src)
+	for i, p := range preq.Pings {
+		srci, dsti, err := parseSrcDst(i, p.Src, p.Dst)
 		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
-		}
-		dsti, err := util.IPStringToInt32(p.Dst)
-		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, err.Error())
 			return
 		}
 		pings = append(pings, &dm.PingMeasurement{
@@ -410,73 +660,28 @@ func (c *controllerT) RecordRouteHandler(rw http.ResponseWriter, req *http.Reque
 			RR:      true,
 		})
 	}
-	if len(pings) == 0 {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
-	}
-	bid, err := c.db.AddPingBatch(u)
-	if err != nil {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return
-	}
-	go func() {
-		ctx := con.Background()
-		ctx, cancel := con.WithTimeout(ctx, time.Second*30)
-		defer cancel()
-		results := c.doPing(ctx, pings)
-		var ids []int64
-		for p := range results {
-			ids = append(ids, p.Id)
-		}
-		err := c.db.AddPingsToBatch(bid, ids)
-		if err != nil {
-			log.Error(err)
-		}
-	}()
-	rw.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(rw).Encode(struct {
-		Results string `json:"results,omitempty"`
-	}{Results: fmt.Sprintf("https://%s%s?id=%d", "revtr.ccs.neu.edu", v1Prefix+"pings", bid)})
-	if err != nil {
-		panic(err)
-	}
+	c.submitPings(rw, u, pings, preq.Priority)
 }
 
 func (c *controllerT) TimeStampHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodPost {
-		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return
-	}
-	key := req.Header.Get(apiKey)
-	u, ok := c.verifyKey(key)
+	u, ok := c.authorize(rw, req, http.MethodPost)
 	if !ok {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
 	var preq PingReq
 	if err := json.NewDecoder(req.Body).Decode(&preq); err != nil {
-		panic(err)
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
 	}
 	var pings []*dm.PingMeasurement
-	for _, p := range preq.Pings {
+	for i, p := range preq.Pings {
 		if p.Timestamp == "" {
-			rw.Header().Set("Content-Type", "text/plain")
-			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
-		}
-		srci, err := util.IPStringToInt32(p.Src)
-		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			rw.WriteHeader(http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf("Measurement %d: timestamp is required", i))
 			return
 		}
-		dsti, err := util.IPStringToInt32(p.Dst)
+		srci, dsti, err := parseSrcDst(i, p.Src, p.Dst)
 		if err != nil {
-			rw.Header().Set("Content-Type", "text/plain")
-			rw.WriteHeader(http.StatusBadRequest)
+			writeError(rw, http.StatusBadRequest, err.Error())
 			return
 		}
 		pings = append(pings, &dm.PingMeasurement{
@@ -487,39 +692,7 @@ func (c *controllerT) TimeStampHandler(rw http.ResponseWriter, req *http.Request
 			TimeStamp: p.Timestamp,
 		})
 	}
-	if len(pings) == 0 {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
-	}
-	bid, err := c.db.AddPingBatch(u)
-	if err != nil {
-		rw.Header().Set("Content-Type", "text/plain")
-		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return
-	}
-	go func() {
-		ctx := con.Background()
-		ctx, cancel := con.WithTimeout(ctx, time.Second*30)
-		defer cancel()
-		results := c.doPing(ctx, pings)
-		var ids []int64
-		for p := range results {
-			log.Debug("Got timestamp ", p)
-			ids = append(ids, p.Id)
-		}
-		err := c.db.AddPingsToBatch(bid, ids)
-		if err != nil {
-			log.Error(err)
-		}
-	}()
-	rw.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(rw).Encode(struct {
-		Results string `json:"results,omitempty"`
-	}{Results: fmt.Sprintf("https://%s%s?id=%d", "revtr.ccs.neu.edu", v1Prefix+"pings", bid)})
-	if err != nil {
-		panic(err)
-	}
+	c.submitPings(rw, u, pings, preq.Priority)
 }
 
 type vps struct {
@@ -554,9 +727,8 @@ func (c *controllerT) VPSHandler(rw http.ResponseWriter, req *http.Request) {
 	rw.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(rw).Encode(ret)
 	if err != nil {
-		panic(err)
+		log.Error(err)
 	}
-	return
 }
 
 func (c *controllerT) GetVPs(ctx con.Context, gvp *dm.VPRequest) (vpr *dm.VPReturn, err error) {
@@ -564,17 +736,33 @@ func (c *controllerT) GetVPs(ctx con.Context, gvp *dm.VPRequest) (vpr *dm.VPRetu
 	return
 }
 
+func (c *controllerT) GetVPStatus(ctx con.Context, req *dm.VPStatusRequest) (*dm.VPStatusReturn, error) {
+	ret := &dm.VPStatusReturn{}
+	for _, st := range c.fetchVPStatus(ctx, req.Vps) {
+		ret.Statuses = append(ret.Statuses, st)
+	}
+	return ret, nil
+}
+
 func (c *controllerT) ReceiveSpoofedProbes(probes cont.Controller_ReceiveSpoofedProbesServer) error {
 	log.Debug("ReceiveSpoofedProbes")
+	// Probes the controller knows are acknowledged, the plcontroller
+	// retries the rest in case they arrived before their measurement
+	var acked []uint32
 	for {
 		pr, err := probes.Recv()
 		if err == io.EOF {
-			return probes.SendAndClose(&dm.ReceiveSpoofedProbesResponse{})
+			return probes.SendAndClose(&dm.ReceiveSpoofedProbesResponse{
+				Acked:    acked,
+				PerProbe: true,
+			})
 		}
 		if err != nil {
 			log.Error(err)
 			return err
 		}
-		c.doRecSpoof(probes.Context(), pr)
+		if c.doRecSpoof(probes.Context(), pr) {
+			acked = append(acked, pr.ProbeId)
+		}
 	}
 }
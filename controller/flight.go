@@ -0,0 +1,188 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// Package controller is the library for creating a central controller
+package controller
+
+import (
+	"fmt"
+	"sync"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/prometheus/client_golang/prometheus"
+	con "golang.org/x/net/context"
+)
+
+var (
+	probesSaved = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: nameSpace,
+		Subsystem: "singleflight",
+		Name:      "probes_saved",
+		Help:      "The number of measurements that shared the result of an identical in-flight measurement.",
+	}, []string{"type"})
+)
+
+func init() {
+	prometheus.MustRegister(probesSaved)
+}
+
+// flight is a measurement that is currently running.
+// result is only valid after done is closed and is nil if
+// the measurement produced no result
+type flight struct {
+	done   chan struct{}
+	result interface{}
+}
+
+// flightGroup tracks the measurements that are in flight so that
+// concurrent requests for the same measurement only run it once
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		flights: make(map[string]*flight),
+	}
+}
+
+// join returns the flight for key, creating it if there is none.
+// If the flight was created the caller is its leader and must
+// call finish when the measurement is done
+func (fg *flightGroup) join(key string) (*flight, bool) {
+	fg.mu.Lock()
+	defer fg.mu.Unlock()
+	if f, ok := fg.flights[key]; ok {
+		return f, false
+	}
+	f := &flight{done: make(chan struct{})}
+	fg.flights[key] = f
+	return f, true
+}
+
+// finish sets the result of the flight for key and releases all
+// the callers waiting on it. Finishing a key that is not in flight
+// does nothing
+func (fg *flightGroup) finish(key string, result interface{}) {
+	fg.mu.Lock()
+	defer fg.mu.Unlock()
+	f, ok := fg.flights[key]
+	if !ok {
+		return
+	}
+	delete(fg.flights, key)
+	f.result = result
+	close(f.done)
+}
+
+// pingFlightKey is the key for a ping in a flightGroup.
+// The measurement key only has the src, dst, spoofed address and
+// record route so the other options that change the result are
+// added to keep, for example, spoofed pings from sharing with plain ones
+func pingFlightKey(pm *dm.PingMeasurement) string {
+	return fmt.Sprintf("%s_%t_%t_%d_%s_%s_%s_%s_%s_%s_%s_%s_%s_%s_%s_%s_%s_%s_%d",
+		pm.Key(), pm.Spoof, pm.RR, pm.SpooferAddr, pm.TimeStamp,
+		pm.Count, pm.ReplyCount, pm.Wait, pm.Ttl, pm.Tos, pm.Size,
+		pm.Mtu, pm.Method, pm.Pattern, pm.Dport, pm.Sport, pm.IcmpSum,
+		pm.UserId, pm.Timeout)
+}
+
+func hasFlag(p *dm.Ping, flag string) bool {
+	for _, f := range p.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPing finds the index of the measurement in pending that produced p.
+// Results from a measurement tool carry no id, so they are matched on
+// src, dst and the options that were used. Spoofed pings are matched by
+// their spoof id and never here. Error results may not have any flags set
+// so an error is matched to the only measurement with the same src and dst.
+// -1 is returned if there is no match.
+func matchPing(pending []*dm.PingMeasurement, p *dm.Ping) int {
+	rr := hasFlag(p, "v4rr")
+	ts := hasFlag(p, "tsonly") || hasFlag(p, "tsandaddr")
+	fallback, candidates := -1, 0
+	for i, pm := range pending {
+		if pm.Spoof || pm.Src != p.Src || pm.Dst != p.Dst {
+			continue
+		}
+		if pm.RR == rr && (pm.TimeStamp != "") == ts {
+			return i
+		}
+		fallback = i
+		candidates++
+	}
+	if p.Error == "" || len(p.Flags) != 0 || candidates != 1 {
+		return -1
+	}
+	return fallback
+}
+
+// waitPing sends the result of f to out once it is done.
+// It returns false if f finished without a result while ctx is still
+// live, the leader gave up on the measurement so the caller has to run it
+func waitPing(ctx con.Context, f *flight, out chan<- *dm.Ping) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-f.done:
+	}
+	p, ok := f.result.(*dm.Ping)
+	if !ok || p == nil {
+		return ctx.Err() != nil
+	}
+	select {
+	case out <- p:
+	case <-ctx.Done():
+	}
+	return true
+}
+
+// waitTrace sends the result of f to out once it is done.
+// It returns false if f finished without a result while ctx is still
+// live, the leader gave up on the measurement so the caller has to run it
+func waitTrace(ctx con.Context, f *flight, out chan<- *dm.Traceroute) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-f.done:
+	}
+	t, ok := f.result.(*dm.Traceroute)
+	if !ok || t == nil {
+		return ctx.Err() != nil
+	}
+	select {
+	case out <- t:
+	case <-ctx.Done():
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	con "golang.org/x/net/context"
+)
+
+func TestFlightGroupShare(t *testing.T) {
+	fg := newFlightGroup()
+	lf, leader := fg.join("key")
+	if !leader {
+		t.Fatal("First join was not the leader")
+	}
+	ff, leader := fg.join("key")
+	if leader {
+		t.Fatal("Second join was the leader")
+	}
+	if lf != ff {
+		t.Fatal("Joins of the same key got different flights")
+	}
+	out := make(chan *dm.Ping, 1)
+	go waitPing(con.Background(), ff, out)
+	p := &dm.Ping{Src: 1, Dst: 2}
+	fg.finish("key", p)
+	select {
+	case res := <-out:
+		if res != p {
+			t.Fatalf("Expected %v, got %v", p, res)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Waiting flight did not get the result")
+	}
+	if _, leader := fg.join("key"); !leader {
+		t.Fatal("Join after finish was not the leader")
+	}
+}
+
+func TestFlightGroupNoResult(t *testing.T) {
+	fg := newFlightGroup()
+	f, _ := fg.join("key")
+	out := make(chan *dm.Traceroute, 1)
+	done := make(chan bool, 1)
+	go func() {
+		done <- waitTrace(con.Background(), f, out)
+	}()
+	fg.finish("key", nil)
+	select {
+	case handled := <-done:
+		if handled {
+			t.Fatal("Follower of a flight without a result was not told to reissue")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Waiting flight was not released")
+	}
+	if len(out) != 0 {
+		t.Fatal("Flight without a result sent a result")
+	}
+}
+
+func TestFlightGroupCancelledFollower(t *testing.T) {
+	fg := newFlightGroup()
+	f, _ := fg.join("key")
+	ctx, cancel := con.WithCancel(con.Background())
+	cancel()
+	fg.finish("key", nil)
+	if !waitPing(ctx, f, make(chan *dm.Ping, 1)) {
+		t.Fatal("Cancelled follower was told to reissue")
+	}
+}
+
+func TestMatchPing(t *testing.T) {
+	pending := []*dm.PingMeasurement{
+		{Src: 1, Dst: 2},
+		{Src: 1, Dst: 2, RR: true},
+		{Src: 1, Dst: 2, TimeStamp: "tsonly"},
+		{Src: 1, Dst: 3},
+		{Src: 1, Dst: 5, Spoof: true, SpooferAddr: 2},
+		{Src: 1, Dst: 4, RR: true},
+		{Src: 1, Dst: 6, RR: true},
+		{Src: 1, Dst: 6, TimeStamp: "tsonly"},
+	}
+	for _, test := range []struct {
+		desc  string
+		ping  *dm.Ping
+		index int
+	}{
+		{desc: "plain", ping: &dm.Ping{Src: 1, Dst: 2}, index: 0},
+		{desc: "rr", ping: &dm.Ping{Src: 1, Dst: 2, Flags: []string{"v4rr"}}, index: 1},
+		{desc: "ts", ping: &dm.Ping{Src: 1, Dst: 2, Flags: []string{"tsonly"}}, index: 2},
+		{desc: "other dst", ping: &dm.Ping{Src: 1, Dst: 3}, index: 3},
+		{desc: "error with other flags", ping: &dm.Ping{Src: 1, Dst: 3, Flags: []string{"v4rr"}, Error: "failed"}, index: -1},
+		{desc: "error fallback", ping: &dm.Ping{Src: 1, Dst: 4, Error: "failed"}, index: 5},
+		{desc: "ambiguous error", ping: &dm.Ping{Src: 1, Dst: 6, Error: "failed"}, index: -1},
+		{desc: "result of another option", ping: &dm.Ping{Src: 1, Dst: 3, Flags: []string{"v4rr"}}, index: -1},
+		{desc: "spoofed never matched", ping: &dm.Ping{Src: 1, Dst: 5}, index: -1},
+		{desc: "no match", ping: &dm.Ping{Src: 2, Dst: 3}, index: -1},
+	} {
+		if i := matchPing(pending, test.ping); i != test.index {
+			t.Fatalf("%s: expected %d, got %d", test.desc, test.index, i)
+		}
+	}
+}
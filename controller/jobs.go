@@ -0,0 +1,232 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// Package controller is the library for creating a central controller
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/prometheus/client_golang/prometheus"
+	con "golang.org/x/net/context"
+)
+
+var (
+	jobsByState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: nameSpace,
+		Subsystem: "jobs",
+		Name:      "current",
+		Help:      "The number of HTTP measurement jobs in each state.",
+	}, []string{"kind", "state"})
+)
+
+func init() {
+	prometheus.MustRegister(jobsByState)
+}
+
+type jobState string
+
+const (
+	jobQueued  jobState = "queued"
+	jobRunning jobState = "running"
+	jobDone    jobState = "done"
+	jobFailed  jobState = "failed"
+)
+
+const (
+	pingJob  = "ping"
+	traceJob = "traceroute"
+)
+
+// measurementError records why a measurement in a job has no result
+type measurementError struct {
+	Src   string `json:"src"`
+	Dst   string `json:"dst"`
+	Error string `json:"error"`
+}
+
+// jobStatus is the state of a job as returned to the user
+type jobStatus struct {
+	ID        int64              `json:"id"`
+	State     jobState           `json:"state"`
+	Total     int                `json:"total"`
+	Completed int                `json:"completed"`
+	Errors    []measurementError `json:"errors,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	Created   time.Time          `json:"created"`
+	Started   *time.Time         `json:"started,omitempty"`
+	Finished  *time.Time         `json:"finished,omitempty"`
+}
+
+// job is a batch of measurements requested through the HTTP api
+type job struct {
+	mu     sync.Mutex
+	kind   string
+	user   uint32
+	status jobStatus
+}
+
+func (j *job) setState(s jobState) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	jobsByState.WithLabelValues(j.kind, string(j.status.State)).Dec()
+	jobsByState.WithLabelValues(j.kind, string(s)).Inc()
+	j.status.State = s
+	now := time.Now()
+	switch s {
+	case jobRunning:
+		j.status.Started = &now
+	case jobDone, jobFailed:
+		j.status.Finished = &now
+	}
+}
+
+// completed records a measurement that finished
+func (j *job) completed() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Completed++
+}
+
+// failed records a measurement that finished without a result
+func (j *job) failed(src, dst, err string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Completed++
+	j.status.Errors = append(j.status.Errors, measurementError{
+		Src:   src,
+		Dst:   dst,
+		Error: err,
+	})
+}
+
+// snapshot returns a copy of the job's status
+func (j *job) snapshot() jobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	s := j.status
+	s.Errors = append([]measurementError(nil), j.status.Errors...)
+	return s
+}
+
+func (j *job) finished() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status.State == jobDone || j.status.State == jobFailed
+}
+
+// jobStore keeps the jobs that are running or finished recently
+// and limits how many run at once
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*job
+	sem  chan struct{}
+	keep time.Duration
+}
+
+// newJobStore creates a jobStore that runs at most maxRunning jobs at
+// once and forgets finished jobs after keep
+func newJobStore(maxRunning int, keep time.Duration) *jobStore {
+	if maxRunning < 1 {
+		maxRunning = 1
+	}
+	return &jobStore{
+		jobs: make(map[string]*job),
+		sem:  make(chan struct{}, maxRunning),
+		keep: keep,
+	}
+}
+
+func jobKey(kind string, id int64) string {
+	return fmt.Sprintf("%s_%d", kind, id)
+}
+
+// sweep must be called with the lock held
+func (js *jobStore) sweep(now time.Time) {
+	for key, j := range js.jobs {
+		s := j.snapshot()
+		if s.Finished != nil && now.Sub(*s.Finished) > js.keep {
+			jobsByState.WithLabelValues(j.kind, string(s.State)).Dec()
+			delete(js.jobs, key)
+		}
+	}
+}
+
+// add creates a queued job for the batch id
+func (js *jobStore) add(kind string, id int64, user uint32, total int) *job {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.sweep(time.Now())
+	j := &job{
+		kind: kind,
+		user: user,
+		status: jobStatus{
+			ID:      id,
+			State:   jobQueued,
+			Total:   total,
+			Created: time.Now(),
+		},
+	}
+	jobsByState.WithLabelValues(kind, string(jobQueued)).Inc()
+	js.jobs[jobKey(kind, id)] = j
+	return j
+}
+
+// get gets the job for the batch id if it belongs to user
+func (js *jobStore) get(kind string, id int64, user uint32) (*job, bool) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	j, ok := js.jobs[jobKey(kind, id)]
+	if !ok || j.user != user {
+		return nil, false
+	}
+	return j, true
+}
+
+// run runs f for j once there is room, giving it timeout to finish.
+// The job fails if f returns an error
+func (js *jobStore) run(j *job, timeout time.Duration, f func(con.Context, *job) error) {
+	go func() {
+		js.sem <- struct{}{}
+		defer func() { <-js.sem }()
+		j.setState(jobRunning)
+		ctx, cancel := con.WithTimeout(con.Background(), timeout)
+		defer cancel()
+		if err := f(ctx, j); err != nil {
+			log.Error(err)
+			j.mu.Lock()
+			j.status.Error = err.Error()
+			j.mu.Unlock()
+			j.setState(jobFailed)
+			return
+		}
+		j.setState(jobDone)
+	}()
+}
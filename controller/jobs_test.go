@@ -0,0 +1,231 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/controller/mocks"
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	con "golang.org/x/net/context"
+)
+
+func waitFinished(t *testing.T, j *job) jobStatus {
+	deadline := time.Now().Add(5 * time.Second)
+	for !j.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", j.snapshot())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return j.snapshot()
+}
+
+func TestJobStoreRun(t *testing.T) {
+	for _, test := range []struct {
+		desc  string
+		err   error
+		state jobState
+	}{
+		{desc: "Job succeeds", state: jobDone},
+		{desc: "Job fails", err: fmt.Errorf("failed"), state: jobFailed},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			js := newJobStore(1, time.Hour)
+			j := js.add(pingJob, 1, 7, 2)
+			js.run(j, time.Second, func(ctx con.Context, j *job) error {
+				j.completed()
+				j.failed("1.1.1.1", "2.2.2.2", "no result")
+				return test.err
+			})
+			s := waitFinished(t, j)
+			if s.State != test.state {
+				t.Fatalf("state: got %s, expected %s", s.State, test.state)
+			}
+			if s.Completed != 2 || len(s.Errors) != 1 {
+				t.Fatalf("got %d completed with %d errors, expected 2 and 1", s.Completed, len(s.Errors))
+			}
+			if test.err != nil && s.Error != test.err.Error() {
+				t.Fatalf("error: got %q, expected %q", s.Error, test.err.Error())
+			}
+			if s.Started == nil || s.Finished == nil {
+				t.Fatalf("start and finish times not set: %+v", s)
+			}
+		})
+	}
+}
+
+func TestJobStoreGet(t *testing.T) {
+	js := newJobStore(1, time.Hour)
+	js.add(pingJob, 1, 7, 1)
+	if _, ok := js.get(pingJob, 1, 7); !ok {
+		t.Fatal("job not found for its owner")
+	}
+	if _, ok := js.get(pingJob, 1, 8); ok {
+		t.Fatal("job found for another user")
+	}
+	if _, ok := js.get(traceJob, 1, 7); ok {
+		t.Fatal("job found for another kind")
+	}
+}
+
+func TestJobStoreLimitsRunning(t *testing.T) {
+	js := newJobStore(1, time.Hour)
+	block := make(chan struct{})
+	first := js.add(pingJob, 1, 7, 1)
+	js.run(first, time.Second, func(ctx con.Context, j *job) error {
+		<-block
+		return nil
+	})
+	for first.snapshot().State != jobRunning {
+		time.Sleep(time.Millisecond)
+	}
+	second := js.add(pingJob, 2, 7, 1)
+	js.run(second, time.Second, func(ctx con.Context, j *job) error {
+		return nil
+	})
+	time.Sleep(20 * time.Millisecond)
+	if s := second.snapshot(); s.State != jobQueued {
+		t.Fatalf("second job state: got %s, expected %s", s.State, jobQueued)
+	}
+	close(block)
+	waitFinished(t, first)
+	waitFinished(t, second)
+}
+
+func TestJobStoreTimeout(t *testing.T) {
+	js := newJobStore(1, time.Hour)
+	j := js.add(traceJob, 1, 7, 1)
+	js.run(j, 10*time.Millisecond, func(ctx con.Context, j *job) error {
+		<-ctx.Done()
+		recordMissing(ctx, j, map[string]int{pairKey(1, 2): 1})
+		return ErrNoResults
+	})
+	s := waitFinished(t, j)
+	if s.State != jobFailed {
+		t.Fatalf("state: got %s, expected %s", s.State, jobFailed)
+	}
+	if len(s.Errors) != 1 || s.Errors[0].Error != "No result before the job deadline" {
+		t.Fatalf("errors: got %+v", s.Errors)
+	}
+}
+
+func TestJobStoreSweep(t *testing.T) {
+	js := newJobStore(1, time.Minute)
+	j := js.add(pingJob, 1, 7, 0)
+	j.setState(jobDone)
+	js.mu.Lock()
+	js.sweep(time.Now().Add(2 * time.Minute))
+	js.mu.Unlock()
+	if _, ok := js.get(pingJob, 1, 7); ok {
+		t.Fatal("finished job was not swept")
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	for _, test := range []struct {
+		desc  string
+		query string
+		page  page
+		err   bool
+	}{
+		{desc: "Defaults", query: "id=3", page: page{id: 3, limit: defaultPageSize}},
+		{desc: "Offset and limit", query: "id=3&offset=10&limit=5", page: page{id: 3, offset: 10, limit: 5}},
+		{desc: "Missing id", query: "", err: true},
+		{desc: "Limit too large", query: "id=3&limit=1001", err: true},
+		{desc: "Negative offset", query: "id=3&offset=-1", err: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pings?"+test.query, nil)
+			p, err := parsePage(req)
+			if test.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p != test.page {
+				t.Fatalf("got %+v, expected %+v", p, test.page)
+			}
+		})
+	}
+}
+
+func TestPageWindow(t *testing.T) {
+	p := page{id: 3, offset: 2, limit: 2}
+	start, end, next := p.window(5, "/pings")
+	if start != 2 || end != 4 || next != "/pings?id=3&offset=4&limit=2" {
+		t.Fatalf("got %d %d %q", start, end, next)
+	}
+	start, end, next = p.window(3, "/pings")
+	if start != 2 || end != 3 || next != "" {
+		t.Fatalf("got %d %d %q", start, end, next)
+	}
+}
+
+func TestPingHandlerErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		body string
+		code int
+	}{
+		{desc: "Bad body", body: "{", code: http.StatusBadRequest},
+		{desc: "Bad src", body: `{"pings":[{"src":"x","dst":"1.1.1.1","count":1}]}`, code: http.StatusBadRequest},
+		{desc: "Bad count", body: `{"pings":[{"src":"1.1.1.1","dst":"1.1.1.1","count":0}]}`, code: http.StatusBadRequest},
+		{desc: "No pings", body: `{"pings":[]}`, code: http.StatusBadRequest},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			da := new(mocks.DataAccess)
+			da.On("GetUser", "key").Return(dm.User{ID: 1}, nil)
+			c := &controllerT{db: da, jobs: newJobStore(1, time.Hour)}
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/pings", strings.NewReader(test.body))
+			req.Header.Set(apiKey, "key")
+			rw := httptest.NewRecorder()
+			c.PingHandler(rw, req)
+			if rw.Code != test.code {
+				t.Fatalf("code: got %d, expected %d", rw.Code, test.code)
+			}
+			var ae apiError
+			if err := json.NewDecoder(rw.Body).Decode(&ae); err != nil {
+				t.Fatal(err)
+			}
+			if ae.Error.Code != test.code || ae.Error.Message == "" {
+				t.Fatalf("got error %+v", ae)
+			}
+		})
+	}
+}
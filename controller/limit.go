@@ -0,0 +1,394 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// Package controller is the library for creating a central controller
+package controller
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/prometheus/client_golang/prometheus"
+	con "golang.org/x/net/context"
+)
+
+var (
+	limitWaitTimes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: nameSpace,
+		Subsystem: "limit",
+		Name:      "wait_seconds",
+		Help:      "The time measurements spent queued by the rate limits.",
+	}, []string{"limit"})
+	limitQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: nameSpace,
+		Subsystem: "limit",
+		Name:      "queued",
+		Help:      "The number of measurements currently queued by the rate limits.",
+	}, []string{"limit"})
+)
+
+func init() {
+	prometheus.MustRegister(limitWaitTimes)
+	prometheus.MustRegister(limitQueued)
+}
+
+// waiter is a measurement waiting for a token
+type waiter struct {
+	prio  Priority
+	seq   uint64
+	ready chan struct{}
+	index int
+}
+
+// waitQueue orders waiters by priority, highest first,
+// and then by the order they arrived
+type waitQueue []*waiter
+
+func (wq waitQueue) Len() int { return len(wq) }
+func (wq waitQueue) Less(i, j int) bool {
+	if wq[i].prio != wq[j].prio {
+		return wq[i].prio > wq[j].prio
+	}
+	return wq[i].seq < wq[j].seq
+}
+func (wq waitQueue) Swap(i, j int) {
+	wq[i], wq[j] = wq[j], wq[i]
+	wq[i].index = i
+	wq[j].index = j
+}
+func (wq *waitQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*wq)
+	*wq = append(*wq, w)
+}
+func (wq *waitQueue) Pop() interface{} {
+	old := *wq
+	n := len(old)
+	w := old[n-1]
+	w.index = -1
+	*wq = old[:n-1]
+	return w
+}
+
+type bucket struct {
+	tokens  float64
+	last    time.Time
+	waiters waitQueue
+	timer   *time.Timer
+}
+
+// keyedLimiter is a set of token buckets, one for each key.
+// Callers that find their bucket empty are queued by priority
+// rather than rejected
+type keyedLimiter struct {
+	mu        sync.Mutex
+	name      string
+	rate      float64
+	burst     float64
+	seq       uint64
+	buckets   map[uint32]*bucket
+	lastSweep time.Time
+}
+
+// newKeyedLimiter creates a keyedLimiter which allows rate events per
+// second with bursts of up to burst for each key. A rate <= 0 means
+// there is no limit
+func newKeyedLimiter(name string, rate float64, burst int) *keyedLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &keyedLimiter{
+		name:    name,
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[uint32]*bucket),
+	}
+}
+
+func (kl *keyedLimiter) refill(b *bucket, now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * kl.rate
+	if b.tokens > kl.burst {
+		b.tokens = kl.burst
+	}
+	b.last = now
+}
+
+// sweep removes buckets that are full and have nobody waiting on them.
+// Must be called with the lock held
+func (kl *keyedLimiter) sweep(now time.Time) {
+	if now.Sub(kl.lastSweep) < time.Minute {
+		return
+	}
+	kl.lastSweep = now
+	for key, b := range kl.buckets {
+		if len(b.waiters) != 0 {
+			continue
+		}
+		kl.refill(b, now)
+		if b.tokens >= kl.burst {
+			delete(kl.buckets, key)
+		}
+	}
+}
+
+// release hands out tokens to waiters and schedules the next release
+// if there are waiters left. Must be called with the lock held
+func (kl *keyedLimiter) release(b *bucket, now time.Time) {
+	kl.refill(b, now)
+	for len(b.waiters) > 0 && b.tokens >= 1 {
+		w := heap.Pop(&b.waiters).(*waiter)
+		b.tokens--
+		close(w.ready)
+	}
+	if len(b.waiters) == 0 || b.timer != nil {
+		return
+	}
+	wait := time.Duration((1 - b.tokens) / kl.rate * float64(time.Second))
+	b.timer = time.AfterFunc(wait, func() {
+		kl.mu.Lock()
+		defer kl.mu.Unlock()
+		b.timer = nil
+		kl.release(b, time.Now())
+	})
+}
+
+// wait blocks until a token for key is available or ctx is done
+func (kl *keyedLimiter) wait(ctx con.Context, key uint32, prio Priority) error {
+	if kl.rate <= 0 {
+		return nil
+	}
+	now := time.Now()
+	kl.mu.Lock()
+	kl.sweep(now)
+	b, ok := kl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: kl.burst, last: now}
+		kl.buckets[key] = b
+	}
+	kl.refill(b, now)
+	if len(b.waiters) == 0 && b.tokens >= 1 {
+		b.tokens--
+		kl.mu.Unlock()
+		return nil
+	}
+	kl.seq++
+	w := &waiter{prio: prio, seq: kl.seq, ready: make(chan struct{})}
+	heap.Push(&b.waiters, w)
+	kl.release(b, now)
+	kl.mu.Unlock()
+	limitQueued.WithLabelValues(kl.name).Inc()
+	defer limitQueued.WithLabelValues(kl.name).Dec()
+	select {
+	case <-w.ready:
+		limitWaitTimes.WithLabelValues(kl.name).Observe(time.Since(now).Seconds())
+		return nil
+	case <-ctx.Done():
+		kl.mu.Lock()
+		defer kl.mu.Unlock()
+		if w.index >= 0 {
+			heap.Remove(&b.waiters, w.index)
+			return ctx.Err()
+		}
+		// The token was handed out as ctx finished, give it back
+		kl.give(b, time.Now())
+		return ctx.Err()
+	}
+}
+
+// give returns a token to b. Must be called with the lock held
+func (kl *keyedLimiter) give(b *bucket, now time.Time) {
+	kl.refill(b, now)
+	b.tokens++
+	if b.tokens > kl.burst {
+		b.tokens = kl.burst
+	}
+	kl.release(b, now)
+}
+
+// refund gives back a token taken by wait for something that was never sent
+func (kl *keyedLimiter) refund(key uint32) {
+	if kl.rate <= 0 {
+		return
+	}
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+	// A bucket that was swept was full so there is nothing to give back
+	if b, ok := kl.buckets[key]; ok {
+		kl.give(b, time.Now())
+	}
+}
+
+// limits are the rate limits applied to measurements before they are sent
+// to a vantage point
+type limits struct {
+	vp  *keyedLimiter
+	dst *keyedLimiter
+}
+
+func newLimits(lc LimitConfig) *limits {
+	var vpRate, dstRate float64
+	var vpBurst, dstBurst int
+	if lc.VPRate != nil {
+		vpRate = *lc.VPRate
+	}
+	if lc.VPBurst != nil {
+		vpBurst = *lc.VPBurst
+	}
+	if lc.DstRate != nil {
+		dstRate = *lc.DstRate
+	}
+	if lc.DstBurst != nil {
+		dstBurst = *lc.DstBurst
+	}
+	return &limits{
+		vp:  newKeyedLimiter("vp", vpRate, vpBurst),
+		dst: newKeyedLimiter("dst", dstRate, dstBurst),
+	}
+}
+
+func (l *limits) wait(ctx con.Context, vp, dst uint32, prio Priority) error {
+	if l == nil {
+		return nil
+	}
+	if err := l.vp.wait(ctx, vp, prio); err != nil {
+		return err
+	}
+	if err := l.dst.wait(ctx, dst, prio); err != nil {
+		// The measurement isn't sent so the vp keeps its token
+		l.vp.refund(vp)
+		return err
+	}
+	return nil
+}
+
+// refund gives back the tokens of a measurement that was admitted
+// but could not be sent
+func (l *limits) refund(vp, dst uint32) {
+	if l == nil {
+		return
+	}
+	l.vp.refund(vp)
+	l.dst.refund(dst)
+}
+
+// refundPings refunds the tokens of the pings pms
+func (l *limits) refundPings(pms []*dm.PingMeasurement) {
+	for _, pm := range pms {
+		l.refund(pm.Src, pm.Dst)
+	}
+}
+
+// admitPings passes each ping through the limits and sends the ones that
+// are admitted on the returned channel in the order they are admitted.
+// The channel is closed once every ping is admitted or ctx is done
+func (l *limits) admitPings(ctx con.Context, pms []*dm.PingMeasurement, prio Priority) <-chan *dm.PingMeasurement {
+	out := make(chan *dm.PingMeasurement, len(pms))
+	var wg sync.WaitGroup
+	for _, pm := range pms {
+		wg.Add(1)
+		go func(pm *dm.PingMeasurement) {
+			defer wg.Done()
+			// Src sends the probes, spoofed or not
+			if err := l.wait(ctx, pm.Src, pm.Dst, prio); err != nil {
+				return
+			}
+			out <- pm
+		}(pm)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// admitTraces passes each traceroute through the limits and sends the ones
+// that are admitted on the returned channel in the order they are admitted.
+// The channel is closed once every traceroute is admitted or ctx is done
+func (l *limits) admitTraces(ctx con.Context, tms []*dm.TracerouteMeasurement, prio Priority) <-chan *dm.TracerouteMeasurement {
+	out := make(chan *dm.TracerouteMeasurement, len(tms))
+	var wg sync.WaitGroup
+	for _, tm := range tms {
+		wg.Add(1)
+		go func(tm *dm.TracerouteMeasurement) {
+			defer wg.Done()
+			if err := l.wait(ctx, tm.Src, tm.Dst, prio); err != nil {
+				return
+			}
+			out <- tm
+		}(tm)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// nextPings blocks for the next admitted ping and then takes any others
+// that are already admitted so they can be sent together
+func nextPings(admitted <-chan *dm.PingMeasurement) []*dm.PingMeasurement {
+	pm, ok := <-admitted
+	if !ok {
+		return nil
+	}
+	ret := []*dm.PingMeasurement{pm}
+	for {
+		select {
+		case pm, ok := <-admitted:
+			if !ok {
+				return ret
+			}
+			ret = append(ret, pm)
+		default:
+			return ret
+		}
+	}
+}
+
+// nextTraces blocks for the next admitted traceroute and then takes any
+// others that are already admitted so they can be sent together
+func nextTraces(admitted <-chan *dm.TracerouteMeasurement) []*dm.TracerouteMeasurement {
+	tm, ok := <-admitted
+	if !ok {
+		return nil
+	}
+	ret := []*dm.TracerouteMeasurement{tm}
+	for {
+		select {
+		case tm, ok := <-admitted:
+			if !ok {
+				return ret
+			}
+			ret = append(ret, tm)
+		default:
+			return ret
+		}
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	con "golang.org/x/net/context"
+)
+
+func TestKeyedLimiterBurst(t *testing.T) {
+	kl := newKeyedLimiter("test", 1, 3)
+	for i := 0; i < 3; i++ {
+		if err := kl.wait(con.Background(), 1, 0); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	ctx, cancel := con.WithTimeout(con.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := kl.wait(ctx, 1, 0); err == nil {
+		t.Fatal("wait after burst did not queue")
+	}
+	// Other keys have their own bucket
+	if err := kl.wait(con.Background(), 2, 0); err != nil {
+		t.Fatalf("wait other key: %v", err)
+	}
+}
+
+func TestKeyedLimiterUnlimited(t *testing.T) {
+	kl := newKeyedLimiter("test", 0, 1)
+	for i := 0; i < 100; i++ {
+		if err := kl.wait(con.Background(), 1, 0); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+}
+
+func TestKeyedLimiterQueues(t *testing.T) {
+	kl := newKeyedLimiter("test", 50, 1)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := kl.wait(con.Background(), 1, 0); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Fatalf("three waits at rate 50 took %v, expected at least 30ms", d)
+	}
+}
+
+func TestKeyedLimiterPriority(t *testing.T) {
+	kl := newKeyedLimiter("test", 10, 1)
+	if err := kl.wait(con.Background(), 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	order := make(chan Priority, 2)
+	waitFor := func(p Priority) {
+		kl.wait(con.Background(), 1, p)
+		order <- p
+	}
+	go waitFor(0)
+	time.Sleep(10 * time.Millisecond)
+	go waitFor(5)
+	time.Sleep(10 * time.Millisecond)
+	if first := <-order; first != 5 {
+		t.Fatalf("first admitted priority %d, expected 5", first)
+	}
+	if second := <-order; second != 0 {
+		t.Fatalf("second admitted priority %d, expected 0", second)
+	}
+}
+
+func TestKeyedLimiterCancelReleases(t *testing.T) {
+	kl := newKeyedLimiter("test", 10, 1)
+	if err := kl.wait(con.Background(), 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := con.WithCancel(con.Background())
+	done := make(chan error)
+	go func() { done <- kl.wait(ctx, 1, 10) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("canceled wait returned nil")
+	}
+	// The canceled waiter must not hold up the next one
+	wctx, wcancel := con.WithTimeout(con.Background(), time.Second)
+	defer wcancel()
+	if err := kl.wait(wctx, 1, 0); err != nil {
+		t.Fatalf("wait after cancel: %v", err)
+	}
+}
+
+func TestAdmitPings(t *testing.T) {
+	rate, burst := 1.0, 2
+	off, zero := 0.0, 0
+	l := newLimits(LimitConfig{
+		VPRate:   &rate,
+		VPBurst:  &burst,
+		DstRate:  &off,
+		DstBurst: &zero,
+	})
+	pms := []*dm.PingMeasurement{
+		{Src: 1, Dst: 10},
+		{Src: 2, Dst: 13},
+		// Src sends a spoofed ping, SpooferAddr receives it
+		{Spoof: true, SpooferAddr: 2, Src: 1, Dst: 14},
+		// Spoofed record route pings leave SpooferAddr unset
+		{Spoof: true, RR: true, Src: 3, Dst: 15},
+	}
+	ctx, cancel := con.WithTimeout(con.Background(), 50*time.Millisecond)
+	defer cancel()
+	admitted := l.admitPings(ctx, pms, 0)
+	var got []*dm.PingMeasurement
+	for batch := nextPings(admitted); batch != nil; batch = nextPings(admitted) {
+		got = append(got, batch...)
+	}
+	if len(got) != 4 {
+		t.Fatalf("admitted %d pings, expected 4", len(got))
+	}
+	for _, test := range []struct {
+		desc string
+		vp   uint32
+		left int
+	}{
+		{desc: "Spoofing sender", vp: 1, left: 0},
+		{desc: "Spoofed receiver", vp: 2, left: 1},
+		{desc: "Spoofed RR sender", vp: 3, left: 1},
+		{desc: "Unset SpooferAddr", vp: 0, left: 2},
+	} {
+		for i := 0; i <= test.left; i++ {
+			ctx, cancel := con.WithTimeout(con.Background(), 10*time.Millisecond)
+			err := l.vp.wait(ctx, test.vp, 0)
+			cancel()
+			if i < test.left && err != nil {
+				t.Fatalf("%s: vp %d had %d tokens, expected %d", test.desc, test.vp, i, test.left)
+			}
+			if i == test.left && err == nil {
+				t.Fatalf("%s: vp %d had more than %d tokens", test.desc, test.vp, test.left)
+			}
+		}
+	}
+}
+
+func TestLimitsRefundVPOnDstFailure(t *testing.T) {
+	vpRate, dstRate := 0.001, 0.001
+	vpBurst, dstBurst := 1, 1
+	l := newLimits(LimitConfig{
+		VPRate:   &vpRate,
+		VPBurst:  &vpBurst,
+		DstRate:  &dstRate,
+		DstBurst: &dstBurst,
+	})
+	if err := l.wait(con.Background(), 1, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	// vp 2 gets a token but destination 1 is empty
+	ctx, cancel := con.WithTimeout(con.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, 2, 1, 0); err == nil {
+		t.Fatal("wait on an empty destination did not fail")
+	}
+	ctx2, cancel2 := con.WithTimeout(con.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if err := l.wait(ctx2, 2, 2, 0); err != nil {
+		t.Fatalf("vp token was not refunded: %v", err)
+	}
+}
@@ -52,6 +52,48 @@ func (_m *Client) Traceroute(_a0 context.Context, _a1 *datamodel.TracerouteArg)
 	return r0, r1
 }
 
+// Tracelb provides a mock function with given fields: _a0, _a1
+func (_m *Client) Tracelb(_a0 context.Context, _a1 *datamodel.TracelbArg) (controllerapi.Controller_TracelbClient, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 controllerapi.Controller_TracelbClient
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.TracelbArg) controllerapi.Controller_TracelbClient); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(controllerapi.Controller_TracelbClient)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.TracelbArg) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Dealias provides a mock function with given fields: _a0, _a1
+func (_m *Client) Dealias(_a0 context.Context, _a1 *datamodel.DealiasArg) (controllerapi.Controller_DealiasClient, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 controllerapi.Controller_DealiasClient
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.DealiasArg) controllerapi.Controller_DealiasClient); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(controllerapi.Controller_DealiasClient)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.DealiasArg) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVps provides a mock function with given fields: _a0, _a1
 func (_m *Client) GetVps(_a0 context.Context, _a1 *datamodel.VPRequest) (*datamodel.VPReturn, error) {
 	ret := _m.Called(_a0, _a1)
@@ -75,6 +117,29 @@ func (_m *Client) GetVps(_a0 context.Context, _a1 *datamodel.VPRequest) (*datamo
 	return r0, r1
 }
 
+// GetVPStatus provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetVPStatus(_a0 context.Context, _a1 *datamodel.VPStatusRequest) (*datamodel.VPStatusReturn, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datamodel.VPStatusReturn
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.VPStatusRequest) *datamodel.VPStatusReturn); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datamodel.VPStatusReturn)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.VPStatusRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReceiveSpoofedProbes provides a mock function with given fields: _a0
 func (_m *Client) ReceiveSpoofedProbes(_a0 context.Context) (controllerapi.Controller_ReceiveSpoofedProbesClient, error) {
 	ret := _m.Called(_a0)
@@ -56,6 +56,48 @@ func (_m *ControllerClient) Traceroute(ctx context.Context, in *datamodel.Tracer
 	return r0, r1
 }
 
+// Tracelb provides a mock function with given fields: ctx, in, opts
+func (_m *ControllerClient) Tracelb(ctx context.Context, in *datamodel.TracelbArg, opts ...grpc.CallOption) (controllerapi.Controller_TracelbClient, error) {
+	ret := _m.Called(ctx, in, opts)
+
+	var r0 controllerapi.Controller_TracelbClient
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.TracelbArg, ...grpc.CallOption) controllerapi.Controller_TracelbClient); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		r0 = ret.Get(0).(controllerapi.Controller_TracelbClient)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.TracelbArg, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Dealias provides a mock function with given fields: ctx, in, opts
+func (_m *ControllerClient) Dealias(ctx context.Context, in *datamodel.DealiasArg, opts ...grpc.CallOption) (controllerapi.Controller_DealiasClient, error) {
+	ret := _m.Called(ctx, in, opts)
+
+	var r0 controllerapi.Controller_DealiasClient
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.DealiasArg, ...grpc.CallOption) controllerapi.Controller_DealiasClient); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		r0 = ret.Get(0).(controllerapi.Controller_DealiasClient)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.DealiasArg, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVPs provides a mock function with given fields: ctx, in, opts
 func (_m *ControllerClient) GetVPs(ctx context.Context, in *datamodel.VPRequest, opts ...grpc.CallOption) (*datamodel.VPReturn, error) {
 	ret := _m.Called(ctx, in, opts)
@@ -79,6 +121,29 @@ func (_m *ControllerClient) GetVPs(ctx context.Context, in *datamodel.VPRequest,
 	return r0, r1
 }
 
+// GetVPStatus provides a mock function with given fields: ctx, in, opts
+func (_m *ControllerClient) GetVPStatus(ctx context.Context, in *datamodel.VPStatusRequest, opts ...grpc.CallOption) (*datamodel.VPStatusReturn, error) {
+	ret := _m.Called(ctx, in, opts)
+
+	var r0 *datamodel.VPStatusReturn
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.VPStatusRequest, ...grpc.CallOption) *datamodel.VPStatusReturn); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datamodel.VPStatusReturn)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.VPStatusRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReceiveSpoofedProbes provides a mock function with given fields: ctx, opts
 func (_m *ControllerClient) ReceiveSpoofedProbes(ctx context.Context, opts ...grpc.CallOption) (controllerapi.Controller_ReceiveSpoofedProbesClient, error) {
 	ret := _m.Called(ctx, opts)
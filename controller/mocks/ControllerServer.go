@@ -40,6 +40,34 @@ func (_m *ControllerServer) Traceroute(_a0 *datamodel.TracerouteArg, _a1 control
 	return r0
 }
 
+// Tracelb provides a mock function with given fields: _a0, _a1
+func (_m *ControllerServer) Tracelb(_a0 *datamodel.TracelbArg, _a1 controllerapi.Controller_TracelbServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*datamodel.TracelbArg, controllerapi.Controller_TracelbServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Dealias provides a mock function with given fields: _a0, _a1
+func (_m *ControllerServer) Dealias(_a0 *datamodel.DealiasArg, _a1 controllerapi.Controller_DealiasServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*datamodel.DealiasArg, controllerapi.Controller_DealiasServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetVPs provides a mock function with given fields: _a0, _a1
 func (_m *ControllerServer) GetVPs(_a0 context.Context, _a1 *datamodel.VPRequest) (*datamodel.VPReturn, error) {
 	ret := _m.Called(_a0, _a1)
@@ -63,6 +91,29 @@ func (_m *ControllerServer) GetVPs(_a0 context.Context, _a1 *datamodel.VPRequest
 	return r0, r1
 }
 
+// GetVPStatus provides a mock function with given fields: _a0, _a1
+func (_m *ControllerServer) GetVPStatus(_a0 context.Context, _a1 *datamodel.VPStatusRequest) (*datamodel.VPStatusReturn, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *datamodel.VPStatusReturn
+	if rf, ok := ret.Get(0).(func(context.Context, *datamodel.VPStatusRequest) *datamodel.VPStatusReturn); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datamodel.VPStatusReturn)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *datamodel.VPStatusRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReceiveSpoofedProbes provides a mock function with given fields: _a0
 func (_m *ControllerServer) ReceiveSpoofedProbes(_a0 controllerapi.Controller_ReceiveSpoofedProbesServer) error {
 	ret := _m.Called(_a0)
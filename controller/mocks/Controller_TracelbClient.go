@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/stretchr/testify/mock"
+)
+
+type Controller_TracelbClient struct {
+	mock.Mock
+}
+
+// Recv provides a mock function with given fields:
+func (_m *Controller_TracelbClient) Recv() (*datamodel.Tracelb, error) {
+	ret := _m.Called()
+
+	var r0 *datamodel.Tracelb
+	if rf, ok := ret.Get(0).(func() *datamodel.Tracelb); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datamodel.Tracelb)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -55,17 +55,24 @@ func (_m *DataAccess) GetPingsMulti(_a0 []*dm.PingMeasurement) ([]*dm.Ping, erro
 }
 
 // StorePing provides a mock function with given fields: _a0
-func (_m *DataAccess) StorePing(_a0 *dm.Ping) error {
+func (_m *DataAccess) StorePing(_a0 *dm.Ping) (int64, error) {
 	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*dm.Ping) error); ok {
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*dm.Ping) int64); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dm.Ping) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetTRBySrcDst provides a mock function with given fields: _a0, _a1
@@ -115,17 +122,45 @@ func (_m *DataAccess) GetTraceMulti(_a0 []*dm.TracerouteMeasurement) ([]*dm.Trac
 }
 
 // StoreTraceroute provides a mock function with given fields: _a0
-func (_m *DataAccess) StoreTraceroute(_a0 *dm.Traceroute) error {
+func (_m *DataAccess) StoreTraceroute(_a0 *dm.Traceroute) (int64, error) {
 	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*dm.Traceroute) error); ok {
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*dm.Traceroute) int64); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dm.Traceroute) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreTracelb provides a mock function with given fields: _a0
+func (_m *DataAccess) StoreTracelb(_a0 *dm.Tracelb) (int64, error) {
+	ret := _m.Called(_a0)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(*dm.Tracelb) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dm.Tracelb) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Close provides a mock function with given fields:
@@ -141,3 +176,140 @@ func (_m *DataAccess) Close() error {
 
 	return r0
 }
+
+// GetUser provides a mock function with given fields: _a0
+func (_m *DataAccess) GetUser(_a0 string) (dm.User, error) {
+	ret := _m.Called(_a0)
+
+	var r0 dm.User
+	if rf, ok := ret.Get(0).(func(string) dm.User); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(dm.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddPingBatch provides a mock function with given fields: _a0
+func (_m *DataAccess) AddPingBatch(_a0 dm.User) (int64, error) {
+	ret := _m.Called(_a0)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(dm.User) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dm.User) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddPingsToBatch provides a mock function with given fields: _a0, _a1
+func (_m *DataAccess) AddPingsToBatch(_a0 int64, _a1 []int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, []int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPingBatch provides a mock function with given fields: _a0, _a1
+func (_m *DataAccess) GetPingBatch(_a0 dm.User, _a1 int64) ([]*dm.Ping, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*dm.Ping
+	if rf, ok := ret.Get(0).(func(dm.User, int64) []*dm.Ping); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dm.Ping)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dm.User, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddTraceBatch provides a mock function with given fields: _a0
+func (_m *DataAccess) AddTraceBatch(_a0 dm.User) (int64, error) {
+	ret := _m.Called(_a0)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(dm.User) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dm.User) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddTraceToBatch provides a mock function with given fields: _a0, _a1
+func (_m *DataAccess) AddTraceToBatch(_a0 int64, _a1 []int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, []int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTraceBatch provides a mock function with given fields: _a0, _a1
+func (_m *DataAccess) GetTraceBatch(_a0 dm.User, _a1 int64) ([]*dm.Traceroute, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*dm.Traceroute
+	if rf, ok := ret.Get(0).(func(dm.User, int64) []*dm.Traceroute); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dm.Traceroute)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dm.User, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -19,6 +19,8 @@ import datamodel1 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 import datamodel2 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 import datamodel3 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 import datamodel4 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+import datamodel5 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+import datamodel6 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 
 import (
 	context "golang.org/x/net/context"
@@ -49,7 +51,10 @@ const _ = grpc.SupportPackageIsVersion3
 type ControllerClient interface {
 	Ping(ctx context.Context, in *datamodel1.PingArg, opts ...grpc.CallOption) (Controller_PingClient, error)
 	Traceroute(ctx context.Context, in *datamodel2.TracerouteArg, opts ...grpc.CallOption) (Controller_TracerouteClient, error)
-	GetVPs(ctx context.Context, in *datamodel3.VPRequest, opts ...grpc.CallOption) (*datamodel3.VPReturn, error)
+	Tracelb(ctx context.Context, in *datamodel3.TracelbArg, opts ...grpc.CallOption) (Controller_TracelbClient, error)
+	Dealias(ctx context.Context, in *datamodel4.DealiasArg, opts ...grpc.CallOption) (Controller_DealiasClient, error)
+	GetVPs(ctx context.Context, in *datamodel5.VPRequest, opts ...grpc.CallOption) (*datamodel5.VPReturn, error)
+	GetVPStatus(ctx context.Context, in *datamodel5.VPStatusRequest, opts ...grpc.CallOption) (*datamodel5.VPStatusReturn, error)
 	ReceiveSpoofedProbes(ctx context.Context, opts ...grpc.CallOption) (Controller_ReceiveSpoofedProbesClient, error)
 }
 
@@ -125,8 +130,72 @@ func (x *controllerTracerouteClient) Recv() (*datamodel2.Traceroute, error) {
 	return m, nil
 }
 
-func (c *controllerClient) GetVPs(ctx context.Context, in *datamodel3.VPRequest, opts ...grpc.CallOption) (*datamodel3.VPReturn, error) {
-	out := new(datamodel3.VPReturn)
+func (c *controllerClient) Tracelb(ctx context.Context, in *datamodel3.TracelbArg, opts ...grpc.CallOption) (Controller_TracelbClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Controller_serviceDesc.Streams[2], c.cc, "/controllerapi.Controller/Tracelb", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controllerTracelbClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Controller_TracelbClient interface {
+	Recv() (*datamodel3.Tracelb, error)
+	grpc.ClientStream
+}
+
+type controllerTracelbClient struct {
+	grpc.ClientStream
+}
+
+func (x *controllerTracelbClient) Recv() (*datamodel3.Tracelb, error) {
+	m := new(datamodel3.Tracelb)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controllerClient) Dealias(ctx context.Context, in *datamodel4.DealiasArg, opts ...grpc.CallOption) (Controller_DealiasClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Controller_serviceDesc.Streams[3], c.cc, "/controllerapi.Controller/Dealias", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controllerDealiasClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Controller_DealiasClient interface {
+	Recv() (*datamodel4.Dealias, error)
+	grpc.ClientStream
+}
+
+type controllerDealiasClient struct {
+	grpc.ClientStream
+}
+
+func (x *controllerDealiasClient) Recv() (*datamodel4.Dealias, error) {
+	m := new(datamodel4.Dealias)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controllerClient) GetVPs(ctx context.Context, in *datamodel5.VPRequest, opts ...grpc.CallOption) (*datamodel5.VPReturn, error) {
+	out := new(datamodel5.VPReturn)
 	err := grpc.Invoke(ctx, "/controllerapi.Controller/GetVPs", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
@@ -134,8 +203,17 @@ func (c *controllerClient) GetVPs(ctx context.Context, in *datamodel3.VPRequest,
 	return out, nil
 }
 
+func (c *controllerClient) GetVPStatus(ctx context.Context, in *datamodel5.VPStatusRequest, opts ...grpc.CallOption) (*datamodel5.VPStatusReturn, error) {
+	out := new(datamodel5.VPStatusReturn)
+	err := grpc.Invoke(ctx, "/controllerapi.Controller/GetVPStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controllerClient) ReceiveSpoofedProbes(ctx context.Context, opts ...grpc.CallOption) (Controller_ReceiveSpoofedProbesClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Controller_serviceDesc.Streams[2], c.cc, "/controllerapi.Controller/ReceiveSpoofedProbes", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_Controller_serviceDesc.Streams[4], c.cc, "/controllerapi.Controller/ReceiveSpoofedProbes", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +222,8 @@ func (c *controllerClient) ReceiveSpoofedProbes(ctx context.Context, opts ...grp
 }
 
 type Controller_ReceiveSpoofedProbesClient interface {
-	Send(*datamodel4.Probe) error
-	CloseAndRecv() (*datamodel4.ReceiveSpoofedProbesResponse, error)
+	Send(*datamodel6.Probe) error
+	CloseAndRecv() (*datamodel6.ReceiveSpoofedProbesResponse, error)
 	grpc.ClientStream
 }
 
@@ -153,15 +231,15 @@ type controllerReceiveSpoofedProbesClient struct {
 	grpc.ClientStream
 }
 
-func (x *controllerReceiveSpoofedProbesClient) Send(m *datamodel4.Probe) error {
+func (x *controllerReceiveSpoofedProbesClient) Send(m *datamodel6.Probe) error {
 	return x.ClientStream.SendMsg(m)
 }
 
-func (x *controllerReceiveSpoofedProbesClient) CloseAndRecv() (*datamodel4.ReceiveSpoofedProbesResponse, error) {
+func (x *controllerReceiveSpoofedProbesClient) CloseAndRecv() (*datamodel6.ReceiveSpoofedProbesResponse, error) {
 	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	m := new(datamodel4.ReceiveSpoofedProbesResponse)
+	m := new(datamodel6.ReceiveSpoofedProbesResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
@@ -173,7 +251,10 @@ func (x *controllerReceiveSpoofedProbesClient) CloseAndRecv() (*datamodel4.Recei
 type ControllerServer interface {
 	Ping(*datamodel1.PingArg, Controller_PingServer) error
 	Traceroute(*datamodel2.TracerouteArg, Controller_TracerouteServer) error
-	GetVPs(context.Context, *datamodel3.VPRequest) (*datamodel3.VPReturn, error)
+	Tracelb(*datamodel3.TracelbArg, Controller_TracelbServer) error
+	Dealias(*datamodel4.DealiasArg, Controller_DealiasServer) error
+	GetVPs(context.Context, *datamodel5.VPRequest) (*datamodel5.VPReturn, error)
+	GetVPStatus(context.Context, *datamodel5.VPStatusRequest) (*datamodel5.VPStatusReturn, error)
 	ReceiveSpoofedProbes(Controller_ReceiveSpoofedProbesServer) error
 }
 
@@ -223,8 +304,50 @@ func (x *controllerTracerouteServer) Send(m *datamodel2.Traceroute) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Controller_Tracelb_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(datamodel3.TracelbArg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControllerServer).Tracelb(m, &controllerTracelbServer{stream})
+}
+
+type Controller_TracelbServer interface {
+	Send(*datamodel3.Tracelb) error
+	grpc.ServerStream
+}
+
+type controllerTracelbServer struct {
+	grpc.ServerStream
+}
+
+func (x *controllerTracelbServer) Send(m *datamodel3.Tracelb) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Controller_Dealias_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(datamodel4.DealiasArg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControllerServer).Dealias(m, &controllerDealiasServer{stream})
+}
+
+type Controller_DealiasServer interface {
+	Send(*datamodel4.Dealias) error
+	grpc.ServerStream
+}
+
+type controllerDealiasServer struct {
+	grpc.ServerStream
+}
+
+func (x *controllerDealiasServer) Send(m *datamodel4.Dealias) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Controller_GetVPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(datamodel3.VPRequest)
+	in := new(datamodel5.VPRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -236,7 +359,25 @@ func _Controller_GetVPs_Handler(srv interface{}, ctx context.Context, dec func(i
 		FullMethod: "/controllerapi.Controller/GetVPs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControllerServer).GetVPs(ctx, req.(*datamodel3.VPRequest))
+		return srv.(ControllerServer).GetVPs(ctx, req.(*datamodel5.VPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GetVPStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(datamodel5.VPStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GetVPStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controllerapi.Controller/GetVPStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GetVPStatus(ctx, req.(*datamodel5.VPStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -246,8 +387,8 @@ func _Controller_ReceiveSpoofedProbes_Handler(srv interface{}, stream grpc.Serve
 }
 
 type Controller_ReceiveSpoofedProbesServer interface {
-	SendAndClose(*datamodel4.ReceiveSpoofedProbesResponse) error
-	Recv() (*datamodel4.Probe, error)
+	SendAndClose(*datamodel6.ReceiveSpoofedProbesResponse) error
+	Recv() (*datamodel6.Probe, error)
 	grpc.ServerStream
 }
 
@@ -255,12 +396,12 @@ type controllerReceiveSpoofedProbesServer struct {
 	grpc.ServerStream
 }
 
-func (x *controllerReceiveSpoofedProbesServer) SendAndClose(m *datamodel4.ReceiveSpoofedProbesResponse) error {
+func (x *controllerReceiveSpoofedProbesServer) SendAndClose(m *datamodel6.ReceiveSpoofedProbesResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func (x *controllerReceiveSpoofedProbesServer) Recv() (*datamodel4.Probe, error) {
-	m := new(datamodel4.Probe)
+func (x *controllerReceiveSpoofedProbesServer) Recv() (*datamodel6.Probe, error) {
+	m := new(datamodel6.Probe)
 	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
@@ -275,6 +416,10 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetVPs",
 			Handler:    _Controller_GetVPs_Handler,
 		},
+		{
+			MethodName: "GetVPStatus",
+			Handler:    _Controller_GetVPStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -287,6 +432,16 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Controller_Traceroute_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Tracelb",
+			Handler:       _Controller_Tracelb_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Dealias",
+			Handler:       _Controller_Dealias_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ReceiveSpoofedProbes",
 			Handler:       _Controller_ReceiveSpoofedProbes_Handler,
@@ -301,23 +456,27 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 285 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x92, 0xb1, 0x4e, 0xc3, 0x30,
-	0x10, 0x86, 0x5b, 0x84, 0x3a, 0x58, 0x42, 0x45, 0xa6, 0x48, 0x28, 0x63, 0x17, 0x58, 0x88, 0x11,
-	0x88, 0x81, 0x01, 0xa1, 0x80, 0x80, 0xad, 0x8a, 0x12, 0xda, 0x81, 0xcd, 0x49, 0x8e, 0x10, 0x29,
-	0xf5, 0x19, 0xfb, 0x92, 0xc7, 0xe0, 0x99, 0x49, 0xda, 0x2a, 0x75, 0xab, 0x0e, 0x64, 0xf4, 0xe7,
-	0xfb, 0x3f, 0xdf, 0x9d, 0xcc, 0xde, 0xf2, 0x82, 0xbe, 0xab, 0xc4, 0x4f, 0x71, 0x29, 0x66, 0xaf,
-	0xf3, 0xeb, 0x78, 0x16, 0x8b, 0x08, 0x6a, 0x30, 0x16, 0x3e, 0x8c, 0x4c, 0xc1, 0x60, 0x45, 0x20,
-	0x52, 0x54, 0x64, 0xb0, 0x2c, 0xc1, 0x08, 0x9d, 0x38, 0x27, 0xa9, 0x0b, 0x5f, 0x1b, 0x24, 0xe4,
-	0x27, 0x3b, 0xd0, 0x7b, 0xf8, 0x97, 0x36, 0x93, 0x24, 0x97, 0x98, 0x41, 0x29, 0x74, 0xa1, 0xf2,
-	0xb5, 0xc9, 0x7b, 0xea, 0x19, 0xa5, 0x0e, 0x6e, 0x04, 0x41, 0x4f, 0x41, 0x2d, 0x15, 0xc9, 0x1c,
-	0x34, 0x16, 0x8a, 0x36, 0x8a, 0xc7, 0x9e, 0x0a, 0x03, 0xa9, 0xd5, 0x88, 0x5f, 0xeb, 0xf8, 0xed,
-	0xef, 0x11, 0x63, 0x2f, 0xdd, 0x3e, 0xb8, 0x60, 0xc7, 0x61, 0x33, 0x1f, 0xe7, 0x7e, 0x97, 0xf0,
-	0x5b, 0x10, 0x98, 0xdc, 0x1b, 0xef, 0xb1, 0xe9, 0xe0, 0x66, 0xc8, 0x03, 0xc6, 0xb6, 0xcf, 0xf0,
-	0x0b, 0xa7, 0x64, 0x8b, 0xdb, 0xf0, 0xf9, 0xc1, 0x9b, 0x95, 0xe2, 0x9e, 0x8d, 0xde, 0x81, 0x16,
-	0xa1, 0xe5, 0x13, 0xa7, 0x68, 0x11, 0x46, 0xf0, 0x53, 0x81, 0x25, 0xef, 0x6c, 0x8f, 0x52, 0x65,
-	0xd4, 0x74, 0xc0, 0xe7, 0x6c, 0x12, 0x41, 0x0a, 0x45, 0x0d, 0x71, 0x3b, 0x0f, 0x64, 0xa1, 0xc1,
-	0x04, 0x2c, 0x3f, 0x75, 0xdb, 0x6c, 0x91, 0x77, 0xe9, 0x90, 0x43, 0x91, 0x08, 0x9a, 0x8d, 0x28,
-	0xdb, 0x74, 0x73, 0x35, 0x7c, 0x1e, 0x7f, 0xee, 0xfe, 0x8f, 0x64, 0xb4, 0x5a, 0xd4, 0xdd, 0x5f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xa8, 0xae, 0x8f, 0xe6, 0x7f, 0x02, 0x00, 0x00,
+	// 341 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x4d, 0x4b, 0xc3, 0x40,
+	0x10, 0x40, 0x5b, 0x94, 0x0a, 0x2b, 0x52, 0x59, 0x2b, 0x68, 0x8e, 0xbd, 0xe8, 0xc5, 0x44, 0x14,
+	0x41, 0x41, 0x91, 0xfa, 0x51, 0x6f, 0x25, 0x24, 0x6d, 0x0f, 0xde, 0x36, 0xc9, 0x18, 0x17, 0xd2,
+	0xdd, 0x75, 0x77, 0xd2, 0x1f, 0xe9, 0xaf, 0x92, 0x7c, 0xb4, 0x4d, 0xda, 0x1e, 0x4c, 0x8f, 0x7d,
+	0x3b, 0xef, 0x15, 0x66, 0x08, 0x19, 0xc6, 0x1c, 0xbf, 0xd3, 0xc0, 0x0e, 0xe5, 0xcc, 0x19, 0xbd,
+	0x4f, 0xae, 0xfc, 0x91, 0xef, 0x78, 0x30, 0x07, 0x6d, 0x60, 0xac, 0x59, 0x08, 0x5a, 0xa6, 0x08,
+	0x4e, 0x28, 0x05, 0x6a, 0x99, 0x24, 0xa0, 0x1d, 0x15, 0x54, 0x7e, 0x31, 0xc5, 0x6d, 0xa5, 0x25,
+	0x4a, 0x7a, 0x54, 0x83, 0xd6, 0xc3, 0xbf, 0xb2, 0x11, 0x43, 0x36, 0x93, 0x11, 0x24, 0x8e, 0xe2,
+	0x22, 0x2e, 0x4a, 0xd6, 0x73, 0x43, 0x15, 0x97, 0xb0, 0x0c, 0x3c, 0xee, 0x12, 0x48, 0x82, 0x1d,
+	0xed, 0x08, 0x58, 0xc2, 0x99, 0x29, 0xed, 0x41, 0x43, 0x7b, 0xce, 0x04, 0xb2, 0x18, 0x94, 0xe4,
+	0x02, 0xcb, 0xc4, 0x53, 0xc3, 0x84, 0x86, 0xd0, 0x28, 0x29, 0xbf, 0x0a, 0xfd, 0xe6, 0x77, 0x8f,
+	0x90, 0xd7, 0xe5, 0x2d, 0xa8, 0x43, 0xf6, 0x5d, 0x2e, 0x62, 0x4a, 0xed, 0xa5, 0x61, 0x67, 0x60,
+	0xa0, 0x63, 0xab, 0xbb, 0xc6, 0xfa, 0xad, 0xeb, 0x36, 0x1d, 0x10, 0xb2, 0xfa, 0x1b, 0x7a, 0x56,
+	0x19, 0x59, 0xe1, 0x4c, 0x3e, 0xdd, 0xfa, 0x92, 0x27, 0xee, 0xc9, 0xc1, 0xb8, 0xd8, 0x29, 0xdd,
+	0x98, 0x4a, 0x82, 0x4c, 0xa6, 0x9b, 0x78, 0x61, 0xbe, 0x15, 0xfb, 0xac, 0x99, 0x25, 0x5b, 0x37,
+	0x4b, 0x9c, 0x9b, 0x77, 0xa4, 0xf3, 0x01, 0x38, 0x75, 0x0d, 0xed, 0x55, 0x26, 0xa6, 0xae, 0x07,
+	0x3f, 0x29, 0x18, 0xb4, 0x4e, 0xd6, 0x28, 0xa6, 0x5a, 0xf4, 0x5b, 0x74, 0x48, 0x0e, 0x73, 0xcd,
+	0x47, 0x86, 0xa9, 0xa1, 0x56, 0x6d, 0xaa, 0x80, 0x8b, 0xc2, 0xf9, 0xd6, 0xb7, 0xb2, 0x33, 0x21,
+	0x3d, 0x0f, 0x42, 0xe0, 0x73, 0xf0, 0xb3, 0x5b, 0x40, 0xe4, 0x6a, 0x19, 0x80, 0xa1, 0xc7, 0xd5,
+	0x15, 0x67, 0xc8, 0xba, 0xa8, 0x90, 0x6d, 0x8a, 0x07, 0x46, 0x49, 0x61, 0xa0, 0xdf, 0xba, 0x6c,
+	0xbf, 0x74, 0x3f, 0xeb, 0xdf, 0x55, 0xd0, 0xc9, 0x8f, 0x7c, 0xfb, 0x37, 0x00, 0x12, 0x0f, 0x4b,
+	0x2b, 0xb7, 0x03, 0x00, 0x00,
 }
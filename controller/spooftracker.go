@@ -0,0 +1,288 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// Package controller is the library for creating a central controller
+package controller
+
+import (
+	"sync"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	spoofOutstanding = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: nameSpace,
+		Subsystem: "spoof",
+		Name:      "outstanding",
+		Help:      "The number of spoofed probes waiting for a response.",
+	})
+	spoofResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: nameSpace,
+		Subsystem: "spoof",
+		Name:      "probes",
+		Help:      "Count of spoofed probes by how they were resolved.",
+	}, []string{"result"})
+	spoofResponseTimes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: nameSpace,
+		Subsystem: "spoof",
+		Name:      "response_seconds",
+		Help:      "The time between registering a spoofed probe and receiving its response.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(spoofOutstanding)
+	prometheus.MustRegister(spoofResults)
+	prometheus.MustRegister(spoofResponseTimes)
+}
+
+// spoofMatch is the outcome of matching a received probe
+type spoofMatch int
+
+const (
+	// spoofMatched means the probe was delivered to the waiting measurement
+	spoofMatched spoofMatch = iota
+	// spoofDuplicate means a response for the probe was already delivered
+	spoofDuplicate
+	// spoofLate means the probe had already timed out or been canceled
+	spoofLate
+	// spoofUnknown means the probe id was never registered or has been forgotten
+	spoofUnknown
+)
+
+func (sm spoofMatch) String() string {
+	switch sm {
+	case spoofMatched:
+		return "matched"
+	case spoofDuplicate:
+		return "duplicate"
+	case spoofLate:
+		return "late"
+	default:
+		return "unknown"
+	}
+}
+
+// spoofResult is the result of a single spoofed probe. Probe is nil
+// when the probe timed out
+type spoofResult struct {
+	ID       uint32
+	Probe    *dm.Probe
+	TimedOut bool
+}
+
+// spoofGroup is a set of spoofed probes registered by one measurement request.
+// Results are sent on Results, which is closed once every probe has
+// either matched or timed out
+type spoofGroup struct {
+	IDs     []uint32
+	Results chan spoofResult
+	pending int
+}
+
+type spoofEntry struct {
+	group    *spoofGroup
+	added    time.Time
+	deadline time.Time
+}
+
+// finishedSpoof remembers how a probe was resolved so responses
+// that arrive afterwards can be classified
+type finishedSpoof struct {
+	matched bool
+	at      time.Time
+}
+
+// spoofTracker assigns ids to spoofed probes and routes the responses
+// back to the measurement that registered them
+type spoofTracker struct {
+	mu       sync.Mutex
+	nextID   uint32
+	timeout  time.Duration
+	remember time.Duration
+	pending  map[uint32]*spoofEntry
+	finished map[uint32]finishedSpoof
+	now      func() time.Time
+}
+
+// newSpoofTracker creates a spoofTracker. Probes registered without a deadline
+// time out after timeout and finished probes are remembered for remember
+// to account for duplicate and late responses
+func newSpoofTracker(timeout, remember time.Duration) *spoofTracker {
+	return &spoofTracker{
+		timeout:  timeout,
+		remember: remember,
+		pending:  make(map[uint32]*spoofEntry),
+		finished: make(map[uint32]finishedSpoof),
+		now:      time.Now,
+	}
+}
+
+// nextFreeID must be called with the lock held
+func (st *spoofTracker) nextFreeID() uint32 {
+	for {
+		st.nextID++
+		if st.nextID == 0 {
+			continue
+		}
+		if _, ok := st.pending[st.nextID]; ok {
+			continue
+		}
+		if _, ok := st.finished[st.nextID]; ok {
+			continue
+		}
+		return st.nextID
+	}
+}
+
+// register allocates ids for probes with the given timeouts.
+// A timeout <= 0 uses the tracker default
+func (st *spoofTracker) register(timeouts []time.Duration) *spoofGroup {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := st.now()
+	g := &spoofGroup{
+		Results: make(chan spoofResult, len(timeouts)),
+		pending: len(timeouts),
+	}
+	for _, to := range timeouts {
+		if to <= 0 {
+			to = st.timeout
+		}
+		id := st.nextFreeID()
+		st.pending[id] = &spoofEntry{
+			group:    g,
+			added:    now,
+			deadline: now.Add(to),
+		}
+		g.IDs = append(g.IDs, id)
+	}
+	spoofOutstanding.Add(float64(len(timeouts)))
+	log.Debugf("Registered spoof IDs: %v", g.IDs)
+	if g.pending == 0 {
+		close(g.Results)
+	}
+	return g
+}
+
+// resolve must be called with the lock held
+func (st *spoofTracker) resolve(id uint32, e *spoofEntry, res spoofResult, now time.Time) {
+	delete(st.pending, id)
+	st.finished[id] = finishedSpoof{matched: res.Probe != nil, at: now}
+	spoofOutstanding.Dec()
+	e.group.Results <- res
+	e.group.pending--
+	if e.group.pending == 0 {
+		close(e.group.Results)
+	}
+}
+
+// receive matches a probe against the registered ids
+func (st *spoofTracker) receive(probe *dm.Probe) spoofMatch {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := st.match(probe)
+	spoofResults.WithLabelValues(m.String()).Inc()
+	if m != spoofMatched {
+		log.Debugf("Spoofed probe %d was %v", probe.ProbeId, m)
+	}
+	return m
+}
+
+// match must be called with the lock held
+func (st *spoofTracker) match(probe *dm.Probe) spoofMatch {
+	now := st.now()
+	if e, ok := st.pending[probe.ProbeId]; ok {
+		spoofResponseTimes.Observe(now.Sub(e.added).Seconds())
+		st.resolve(probe.ProbeId, e, spoofResult{ID: probe.ProbeId, Probe: probe}, now)
+		return spoofMatched
+	}
+	f, ok := st.finished[probe.ProbeId]
+	if !ok {
+		return spoofUnknown
+	}
+	if f.matched {
+		return spoofDuplicate
+	}
+	return spoofLate
+}
+
+// expire times out every probe whose deadline is before now and
+// forgets finished probes that are older than the remember period
+func (st *spoofTracker) expire(now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for id, e := range st.pending {
+		if now.Before(e.deadline) {
+			continue
+		}
+		spoofResults.WithLabelValues("timeout").Inc()
+		st.resolve(id, e, spoofResult{ID: id, TimedOut: true}, now)
+	}
+	for id, f := range st.finished {
+		if now.Sub(f.at) > st.remember {
+			delete(st.finished, id)
+		}
+	}
+}
+
+// cancel drops the probes of g that are still pending. Responses
+// for them that arrive later are counted as late
+func (st *spoofTracker) cancel(g *spoofGroup) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := st.now()
+	for _, id := range g.IDs {
+		e, ok := st.pending[id]
+		if !ok || e.group != g {
+			continue
+		}
+		spoofResults.WithLabelValues("canceled").Inc()
+		delete(st.pending, id)
+		st.finished[id] = finishedSpoof{at: now}
+		spoofOutstanding.Dec()
+	}
+}
+
+// run expires probes every interval until stop is closed.
+// A nil stop runs for the life of the process
+func (st *spoofTracker) run(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-t.C:
+			st.expire(now)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package controller
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	con "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func newTestTracker(now *time.Time) *spoofTracker {
+	st := newSpoofTracker(time.Minute, 10*time.Minute)
+	st.now = func() time.Time { return *now }
+	return st
+}
+
+func TestSpoofTrackerMatch(t *testing.T) {
+	now := time.Unix(1000, 0)
+	st := newTestTracker(&now)
+	g := st.register([]time.Duration{0, 0})
+	if len(g.IDs) != 2 || g.IDs[0] == g.IDs[1] {
+		t.Fatalf("register gave ids %v", g.IDs)
+	}
+	for _, test := range []struct {
+		desc string
+		id   uint32
+		want spoofMatch
+	}{
+		{desc: "First response", id: g.IDs[0], want: spoofMatched},
+		{desc: "Duplicate response", id: g.IDs[0], want: spoofDuplicate},
+		{desc: "Unregistered id", id: g.IDs[1] + 100, want: spoofUnknown},
+		{desc: "Second response", id: g.IDs[1], want: spoofMatched},
+	} {
+		if got := st.receive(&dm.Probe{ProbeId: test.id}); got != test.want {
+			t.Errorf("%s: receive(%d) = %v, want %v", test.desc, test.id, got, test.want)
+		}
+	}
+	var got []uint32
+	for res := range g.Results {
+		if res.TimedOut || res.Probe == nil {
+			t.Errorf("unexpected result %+v", res)
+		}
+		got = append(got, res.ID)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d results, want 2", len(got))
+	}
+}
+
+func TestSpoofTrackerTimeout(t *testing.T) {
+	now := time.Unix(1000, 0)
+	st := newTestTracker(&now)
+	g := st.register([]time.Duration{5 * time.Second, 0})
+	st.expire(now.Add(10 * time.Second))
+	res := <-g.Results
+	if !res.TimedOut || res.ID != g.IDs[0] {
+		t.Fatalf("expected a timeout for %d, got %+v", g.IDs[0], res)
+	}
+	if m := st.receive(&dm.Probe{ProbeId: g.IDs[0]}); m != spoofLate {
+		t.Fatalf("response after timeout was %v, want late", m)
+	}
+	// The second probe uses the default timeout
+	st.expire(now.Add(2 * time.Minute))
+	res, ok := <-g.Results
+	if !ok || !res.TimedOut || res.ID != g.IDs[1] {
+		t.Fatalf("expected a timeout for %d, got %+v", g.IDs[1], res)
+	}
+	if _, ok := <-g.Results; ok {
+		t.Fatal("results not closed after every probe finished")
+	}
+	// Finished probes are forgotten after the remember period
+	st.expire(now.Add(time.Hour))
+	if m := st.receive(&dm.Probe{ProbeId: g.IDs[0]}); m != spoofUnknown {
+		t.Fatalf("response after forgetting was %v, want unknown", m)
+	}
+}
+
+func TestSpoofTrackerCancel(t *testing.T) {
+	now := time.Unix(1000, 0)
+	st := newTestTracker(&now)
+	g := st.register([]time.Duration{0})
+	st.cancel(g)
+	if m := st.receive(&dm.Probe{ProbeId: g.IDs[0]}); m != spoofLate {
+		t.Fatalf("response after cancel was %v, want late", m)
+	}
+	if len(st.pending) != 0 {
+		t.Fatalf("pending has %d entries after cancel", len(st.pending))
+	}
+}
+
+func TestSpoofTrackerIDsSkipInUse(t *testing.T) {
+	now := time.Unix(1000, 0)
+	st := newTestTracker(&now)
+	st.nextID = ^uint32(0) - 1
+	g := st.register([]time.Duration{0, 0})
+	if g.IDs[0] != ^uint32(0) || g.IDs[1] != 1 {
+		t.Fatalf("ids did not wrap past zero: %v", g.IDs)
+	}
+	st.nextID = 0
+	g2 := st.register([]time.Duration{0})
+	if g2.IDs[0] != 2 {
+		t.Fatalf("id in use was reused: %v", g2.IDs)
+	}
+}
+
+func TestSpoofTrackerEmpty(t *testing.T) {
+	now := time.Unix(1000, 0)
+	st := newTestTracker(&now)
+	g := st.register(nil)
+	if _, ok := <-g.Results; ok {
+		t.Fatal("empty group results not closed")
+	}
+}
+
+type fakeSpoofStream struct {
+	grpc.ServerStream
+	probes []*dm.Probe
+	resp   *dm.ReceiveSpoofedProbesResponse
+}
+
+func (f *fakeSpoofStream) Context() con.Context { return con.Background() }
+
+func (f *fakeSpoofStream) Recv() (*dm.Probe, error) {
+	if len(f.probes) == 0 {
+		return nil, io.EOF
+	}
+	p := f.probes[0]
+	f.probes = f.probes[1:]
+	return p, nil
+}
+
+func (f *fakeSpoofStream) SendAndClose(resp *dm.ReceiveSpoofedProbesResponse) error {
+	f.resp = resp
+	return nil
+}
+
+func TestReceiveSpoofedProbesAcksKnown(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := &controllerT{st: newTestTracker(&now)}
+	g := c.st.register([]time.Duration{0})
+	stream := &fakeSpoofStream{probes: []*dm.Probe{
+		{ProbeId: g.IDs[0]},
+		{ProbeId: g.IDs[0] + 100},
+	}}
+	if err := c.ReceiveSpoofedProbes(stream); err != nil {
+		t.Fatalf("ReceiveSpoofedProbes failed: %v", err)
+	}
+	if !stream.resp.PerProbe || !reflect.DeepEqual(stream.resp.Acked, []uint32{g.IDs[0]}) {
+		t.Fatalf("Got response %+v, expected only %d acked", stream.resp, g.IDs[0])
+	}
+}
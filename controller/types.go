@@ -31,13 +31,37 @@ package controller
 import (
 	"github.com/NEU-SNS/ReverseTraceroute/cache"
 	da "github.com/NEU-SNS/ReverseTraceroute/dataaccess"
+	"github.com/NEU-SNS/ReverseTraceroute/trvalidate"
 )
 
 // Config is the config struct for the controller
 type Config struct {
-	Local LocalConfig
-	Db    da.DbConfig
-	Cache cache.Config
+	Local      LocalConfig
+	Db         da.DbConfig
+	Cache      cache.Config
+	Validation trvalidate.Config
+	Limits     LimitConfig
+	Alias      AliasConfig
+}
+
+// LimitConfig is the configuration of the measurement rate limits.
+// Rates are in measurements per second, a rate <= 0 disables the limit
+type LimitConfig struct {
+	VPRate   *float64 `flag:"vp-rate"`
+	VPBurst  *int     `flag:"vp-burst"`
+	DstRate  *float64 `flag:"dst-rate"`
+	DstBurst *int     `flag:"dst-burst"`
+}
+
+// AliasConfig is the configuration of the alias resolution job.
+// An interval <= 0 disables it. Paths are read from the atlas and
+// reverse traceroute databases and both of their ip_aliases are updated
+type AliasConfig struct {
+	Interval *int64  `flag:"alias-interval"`
+	Batch    *int    `flag:"alias-batch"`
+	VP       *string `flag:"alias-vp"`
+	AtlasDb  da.DbConfig
+	RevtrDb  da.DbConfig
 }
 
 // LocalConfig is the configuration options for the controller
@@ -51,6 +75,14 @@ type LocalConfig struct {
 	KeyFile      *string `flag:"key-file"`
 	ConnTimeout  *int64  `flag:"conn-timeout"`
 	RootCA       *string `flag:"root-ca"`
+	SpoofTimeout *int64  `flag:"spoof-timeout"`
+	RouteRefresh *int64  `flag:"route-refresh"`
+	PLCPort      *string `flag:"plc-port"`
+	LocalScamper *string `flag:"local-scamper"`
+	LocalFormat  *string `flag:"local-scamper-format"`
+	JobTimeout   *int64  `flag:"job-timeout"`
+	MaxJobs      *int    `flag:"max-jobs"`
+	AdminAddr    *string `flag:"admin-addr"`
 }
 
 // NewConfig returns a new blank Config
@@ -65,11 +97,31 @@ func NewConfig() Config {
 		ConnTimeout:  new(int64),
 		Port:         new(int),
 		RootCA:       new(string),
+		SpoofTimeout: new(int64),
+		RouteRefresh: new(int64),
+		PLCPort:      new(string),
+		LocalScamper: new(string),
+		LocalFormat:  new(string),
+		AdminAddr:    new(string),
+		JobTimeout:   new(int64),
+		MaxJobs:      new(int),
 	}
 	c := Config{
-		Local: lc,
-		Db:    da.DbConfig{},
-		Cache: cache.NewConfig(),
+		Local:      lc,
+		Db:         da.DbConfig{},
+		Cache:      cache.NewConfig(),
+		Validation: trvalidate.NewConfig(),
+		Limits: LimitConfig{
+			VPRate:   new(float64),
+			VPBurst:  new(int),
+			DstRate:  new(float64),
+			DstBurst: new(int),
+		},
+		Alias: AliasConfig{
+			Interval: new(int64),
+			Batch:    new(int),
+			VP:       new(string),
+		},
 	}
 	return c
 }
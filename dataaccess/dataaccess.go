@@ -67,6 +67,11 @@ func (d *DataAccess) StoreTraceroute(t *dm.Traceroute) (int64, error) {
 	return d.db.StoreTraceroute(t)
 }
 
+// StoreTracelb stores a tracelb
+func (d *DataAccess) StoreTracelb(t *dm.Tracelb) (int64, error) {
+	return d.db.StoreTracelb(t)
+}
+
 // GetTRBySrcDst gets a trace by src and dst
 func (d *DataAccess) GetTRBySrcDst(src, dst uint32) ([]*dm.Traceroute, error) {
 	return d.db.GetTRBySrcDst(src, dst)
@@ -147,6 +152,31 @@ func (d *DataAccess) StoreAlias(id int, ips []net.IP) error {
 	return d.db.StoreAlias(id, ips)
 }
 
+// MergeAliases replaces the alias cluster id with ips and removes the merged clusters
+func (d *DataAccess) MergeAliases(id int, merged []int, ips []net.IP) error {
+	return d.db.MergeAliases(id, merged, ips)
+}
+
+// GetAtlasAliasPaths gets the atlas traceroute paths since since
+func (d *DataAccess) GetAtlasAliasPaths(since time.Time, limit int) (map[uint32][][]uint32, error) {
+	return d.db.GetAtlasAliasPaths(since, limit)
+}
+
+// GetRevtrAliasPaths gets the reverse traceroute paths since since
+func (d *DataAccess) GetRevtrAliasPaths(since time.Time, limit int) (map[uint32][][]uint32, error) {
+	return d.db.GetRevtrAliasPaths(since, limit)
+}
+
+// GetAliasCluster gets the alias cluster ip is in
+func (d *DataAccess) GetAliasCluster(ip uint32) (int, []uint32, error) {
+	return d.db.GetAliasCluster(ip)
+}
+
+// NextAliasClusterID gets an unused alias cluster id
+func (d *DataAccess) NextAliasClusterID() (int, error) {
+	return d.db.NextAliasClusterID()
+}
+
 // GetUser gets a user for the given key
 func (d *DataAccess) GetUser(key string) (dm.User, error) {
 	return d.db.GetUser(key)
@@ -381,6 +381,110 @@ func (db *DB) StoreTraceroute(in *dm.Traceroute) (int64, error) {
 	return id, tx.Commit()
 }
 
+const (
+	insertTracelb = `
+INSERT INTO
+tracelbs(src, dst, type, user_id, method, sport, dport,
+		 start, probe_size, first_hop, wait_timeout, wait_probe,
+		 attempts, confidence, tos, gap_limit, probec, probec_max)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	insertTracelbNode = `
+INSERT INTO
+tracelb_nodes(tracelb_id, node, addr, q_ttl, name)
+VALUES(?, ?, ?, ?, ?)
+`
+	insertTracelbLink = `
+INSERT INTO
+tracelb_links(tracelb_id, link, addr_from, addr_to)
+VALUES(?, ?, ?, ?)
+`
+	insertTracelbReply = `
+INSERT INTO
+tracelb_replies(tracelb_link_id, hop, flowid, ttl, attempt, addr, rtt,
+				reply_ttl, reply_ipid, icmp_type, icmp_code, icmp_q_ttl,
+				icmp_q_tos, tcp_flags)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+)
+
+func storeTracelbLink(tx *sql.Tx, id int64, link uint32, in *dm.TracelbLink) error {
+	res, err := tx.Exec(insertTracelbLink, id, link, in.From, in.To)
+	if err != nil {
+		return err
+	}
+	lid, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	for hop, set := range in.GetProbes() {
+		for _, probe := range set.GetProbes() {
+			replies := probe.GetReplies()
+			if len(replies) == 0 {
+				// A probe without a reply is kept with no address
+				// so the flow ids that were tried are known
+				replies = []*dm.TracelbReply{&dm.TracelbReply{}}
+			}
+			for _, r := range replies {
+				var rtt uint32
+				if r.Rtt != nil {
+					rtt = uint32(r.Rtt.Sec)*1000000 + uint32(r.Rtt.Usec)
+				}
+				_, err := tx.Exec(insertTracelbReply, lid, hop, probe.Flowid,
+					probe.Ttl, probe.Attempt, r.From, rtt, r.ReplyTtl,
+					r.ReplyIpid, r.IcmpType, r.IcmpCode, r.IcmpQTtl,
+					r.IcmpQTos, r.TcpFlags)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// StoreTracelb saves a tracelb to the DB
+func (db *DB) StoreTracelb(in *dm.Tracelb) (int64, error) {
+	var start time.Time
+	if in.Start != nil {
+		start = time.Unix(in.Start.Sec, in.Start.Usec*1000)
+	}
+	conn := db.GetWriter()
+	tx, err := conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(insertTracelb, in.Src, in.Dst, in.Type,
+		in.UserId, in.Method, in.Sport, in.Dport, start,
+		in.ProbeSize, in.Firsthop, in.WaitTimeout, in.WaitProbe,
+		in.Attempts, in.Confidence, in.Tos, in.GapLimit,
+		in.Probec, in.ProbecMax)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	for i, node := range in.GetNodes() {
+		_, err = tx.Exec(insertTracelbNode, id, i, node.Addr, node.QTtl, node.Name)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	for i, link := range in.GetLinks() {
+		err = storeTracelbLink(tx, id, uint32(i), link)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
 const (
 	getTraceBySrcDst = `
 	SELECT 
@@ -1206,15 +1310,23 @@ const (
 
 // StoreAlias stores an IP alias
 func (db *DB) StoreAlias(id int, ips []net.IP) error {
+	return db.MergeAliases(id, nil, ips)
+}
+
+// MergeAliases replaces the cluster id with ips and removes the merged
+// clusters in a single transaction
+func (db *DB) MergeAliases(id int, merged []int, ips []net.IP) error {
 	con := db.GetWriter()
 	tx, err := con.Begin()
 	if err != nil {
 		return err
 	}
-	_, err = tx.Exec(removeAliasCluster, id)
-	if err != nil {
-		tx.Rollback()
-		return err
+	for _, cid := range append([]int{id}, merged...) {
+		_, err = tx.Exec(removeAliasCluster, cid)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 	for _, ip := range ips {
 		ipint, _ := util.IPtoInt32(ip)
@@ -1227,6 +1339,98 @@ func (db *DB) StoreAlias(id int, ips []net.IP) error {
 	return tx.Commit()
 }
 
+const (
+	getAtlasAliasPaths = `
+SELECT
+	atr.Id, atr.dest, ath.hop
+FROM
+	(SELECT Id, dest FROM atlas_traceroutes WHERE date >= ? ORDER BY date desc LIMIT ?) atr
+	INNER JOIN atlas_traceroute_hops ath on ath.trace_id = atr.Id
+ORDER BY atr.Id, ath.ttl`
+	getRevtrAliasPaths = "SELECT rt.id, rt.src, rth.hop FROM " +
+		"(SELECT id, src FROM reverse_traceroutes WHERE date >= ? AND status = 'COMPLETED' " +
+		"ORDER BY date desc LIMIT ?) rt " +
+		"INNER JOIN reverse_traceroute_hops rth on rth.reverse_traceroute_id = rt.id " +
+		"ORDER BY rt.id, rth.`order`"
+	getAliasCluster    = `SELECT b.cluster_id, b.ip_address FROM ip_aliases a INNER JOIN ip_aliases b on a.cluster_id = b.cluster_id WHERE a.ip_address = ?`
+	nextAliasClusterID = `SELECT COALESCE(MAX(cluster_id), 0) + 1 FROM ip_aliases`
+)
+
+// GetAtlasAliasPaths gets the hops of at most limit atlas traceroutes
+// since since keyed by their destination
+func (db *DB) GetAtlasAliasPaths(since time.Time, limit int) (map[uint32][][]uint32, error) {
+	return db.getAliasPaths(getAtlasAliasPaths, since, limit)
+}
+
+// GetRevtrAliasPaths gets the hops of at most limit completed reverse
+// traceroutes since since keyed by their source
+func (db *DB) GetRevtrAliasPaths(since time.Time, limit int) (map[uint32][][]uint32, error) {
+	return db.getAliasPaths(getRevtrAliasPaths, since, limit)
+}
+
+func (db *DB) getAliasPaths(query string, since time.Time, limit int) (map[uint32][][]uint32, error) {
+	rows, err := db.GetReader().Query(query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[uint32][][]uint32)
+	var path []uint32
+	var last, lastDst uint32
+	for rows.Next() {
+		var id, dst, hop uint32
+		if err := rows.Scan(&id, &dst, &hop); err != nil {
+			return nil, err
+		}
+		if id != last && path != nil {
+			ret[lastDst] = append(ret[lastDst], path)
+			path = nil
+		}
+		last, lastDst = id, dst
+		path = append(path, hop)
+	}
+	if path != nil {
+		ret[lastDst] = append(ret[lastDst], path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// GetAliasCluster gets the cluster ip is in and the addresses in it.
+// The id is -1 if ip is not in a cluster
+func (db *DB) GetAliasCluster(ip uint32) (int, []uint32, error) {
+	rows, err := db.GetReader().Query(getAliasCluster, ip)
+	if err != nil {
+		return -1, nil, err
+	}
+	defer rows.Close()
+	id := -1
+	var ips []uint32
+	for rows.Next() {
+		var addr uint32
+		if err := rows.Scan(&id, &addr); err != nil {
+			return -1, nil, err
+		}
+		ips = append(ips, addr)
+	}
+	if err := rows.Err(); err != nil {
+		return -1, nil, err
+	}
+	return id, ips, nil
+}
+
+// NextAliasClusterID gets a cluster id that is not in use
+func (db *DB) NextAliasClusterID() (int, error) {
+	var id int
+	err := db.GetReader().QueryRow(nextAliasClusterID).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
 const (
 	getUser          = "select * from users where `key` = ?;"
 	addPingBatch     = `insert into ping_batch(user_id) VALUES(?)`
@@ -0,0 +1,122 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package datamodel
+
+import (
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+var dealiasProbeMethods = []string{
+	"NULL",
+	"udp",
+	"icmp-echo",
+	"tcp-ack",
+	"tcp-ack-sport",
+	"udp-dport",
+	"tcp-syn-sport",
+}
+
+func dealiasProbeMethod(m uint8) string {
+	if int(m) >= len(dealiasProbeMethods) {
+		return "NULL"
+	}
+	return dealiasProbeMethods[m]
+}
+
+// ConvertDealias converts a warts Dealias to a datamodel Dealias
+func ConvertDealias(in warts.Dealias) Dealias {
+	d := Dealias{}
+	d.Type = "dealias"
+	d.UserId = in.Flags.UserID
+	d.Method = in.Flags.Method.String()
+	d.Result = in.Flags.Result.String()
+	d.Start = tracelbTime(in.Flags.StartTime)
+	seen := make(map[uint32]bool)
+	defs := make(map[uint32]DealiasProbeDef)
+	for _, pd := range in.ProbeDefs {
+		def := DealiasProbeDef{
+			Id:     pd.ID,
+			Src:    uint32(pd.Src.Address),
+			Dst:    uint32(pd.Dst.Address),
+			Method: dealiasProbeMethod(pd.Method),
+			Ttl:    uint32(pd.TTL),
+		}
+		defs[pd.ID] = def
+		d.ProbeDefs = append(d.ProbeDefs, &def)
+		if d.Src == 0 {
+			d.Src = def.Src
+		}
+		if !seen[def.Dst] {
+			seen[def.Dst] = true
+			d.Addrs = append(d.Addrs, def.Dst)
+		}
+	}
+	if in.Flags.Method == warts.DealiasPrefixscan {
+		// The alias found by a prefixscan is AB, A was probed
+		d.Addrs = nil
+		for _, a := range []warts.Address{in.Options.A, in.Options.AB} {
+			if a.Address != 0 {
+				d.Addrs = append(d.Addrs, uint32(a.Address))
+			}
+		}
+	}
+	for _, p := range in.Probes {
+		probe := &DealiasProbe{
+			Def:  p.Def,
+			Tx:   tracelbTime(p.Tx),
+			Ipid: uint32(p.IPID),
+		}
+		for _, r := range p.Replies {
+			ipid := uint32(r.IPID)
+			if r.IPID32 != 0 {
+				ipid = r.IPID32
+			}
+			probe.Replies = append(probe.Replies, &DealiasReply{
+				Src:      uint32(r.Src.Address),
+				Rtt:      tracelbRTT(p.Tx, r.Rx),
+				Ipid:     ipid,
+				Ttl:      uint32(r.TTL),
+				IcmpType: uint32((r.ICMPTypeCode & 0xFF00) >> 8),
+				IcmpCode: uint32(r.ICMPTypeCode & 0x00FF),
+			})
+		}
+		d.Probes = append(d.Probes, probe)
+	}
+	if in.Flags.Method == warts.DealiasMercator && in.Flags.Result == warts.DealiasResultAliases {
+		// Mercator finds the alias as the source of the reply
+		for _, p := range d.Probes {
+			for _, r := range p.Replies {
+				if !seen[r.Src] {
+					seen[r.Src] = true
+					d.Addrs = append(d.Addrs, r.Src)
+				}
+			}
+		}
+	}
+	return d
+}
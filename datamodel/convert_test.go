@@ -0,0 +1,103 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package datamodel_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+func parseFixture(t *testing.T, name string, filter warts.WartsT) []interface{} {
+	content, err := ioutil.ReadFile("../doc/" + name)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", name, err)
+	}
+	res, err := warts.Parse(content, []warts.WartsT{filter})
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", name, err)
+	}
+	return res
+}
+
+func TestConvertPing(t *testing.T) {
+	tests := []struct {
+		file      string
+		src, dst  uint32
+		sent      uint32
+		responses int
+		rr        int
+		tsonly    int
+		tsandaddr int
+	}{
+		{file: "test_warts.warts", src: 2164945357, dst: 134744072, sent: 4, responses: 4},
+		{file: "rr_test.warts", src: 2502643734, dst: 16778241, sent: 4, responses: 4, rr: 9},
+		{file: "test_tsonly.warts", src: 1023933455, dst: 1023933481, sent: 1, responses: 1, tsonly: 3},
+		{file: "test_tsprespec2.warts", src: 71827085, dst: 3478654309, sent: 4, responses: 4, tsandaddr: 1},
+	}
+	for _, test := range tests {
+		res := parseFixture(t, test.file, warts.PingT)
+		if len(res) != 1 {
+			t.Fatalf("%s: expected 1 ping, got %d", test.file, len(res))
+		}
+		p := datamodel.ConvertPing(res[0].(warts.Ping))
+		if p.Type != "ping" || p.Src != test.src || p.Dst != test.dst || p.PingSent != test.sent {
+			t.Fatalf("%s: unexpected ping %s", test.file, p.String())
+		}
+		if len(p.Responses) != test.responses {
+			t.Fatalf("%s: expected %d responses, got %d", test.file, test.responses, len(p.Responses))
+		}
+		for _, r := range p.Responses {
+			if r.Rx == nil || r.Tx == nil {
+				t.Fatalf("%s: response missing times %s", test.file, r.String())
+			}
+			if len(r.RR) != test.rr || len(r.Tsonly) != test.tsonly || len(r.Tsandaddr) != test.tsandaddr {
+				t.Fatalf("%s: unexpected options in response %s", test.file, r.String())
+			}
+		}
+	}
+}
+
+func TestConvertTraceroute(t *testing.T) {
+	res := parseFixture(t, "trace_test.warts", warts.TracerouteT)
+	if len(res) != 1 {
+		t.Fatalf("Expected 1 traceroute, got %d", len(res))
+	}
+	tr := datamodel.ConvertTraceroute(res[0].(warts.Traceroute))
+	if tr.Type != "trace" || tr.Src != 2164945341 || tr.Dst != 134744072 || tr.StopReason != "COMPLETED" {
+		t.Fatalf("Unexpected traceroute %s", tr.String())
+	}
+	if len(tr.Hops) != 14 {
+		t.Fatalf("Expected 14 hops, got %d", len(tr.Hops))
+	}
+	if last := tr.Hops[len(tr.Hops)-1]; last.Addr != tr.Dst || last.Rtt == nil {
+		t.Fatalf("Expected the last hop to be the destination, got %s", last.String())
+	}
+}
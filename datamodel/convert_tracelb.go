@@ -0,0 +1,127 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package datamodel
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+func tracelbTime(tv syscall.Timeval) *TracerouteTime {
+	tt := &TracerouteTime{
+		Sec:  int64(tv.Sec),
+		Usec: int64(tv.Usec),
+	}
+	tt.Ftime = time.Unix(tt.Sec, tt.Usec*1000).Format(`"` + traceTime + `"`)
+	return tt
+}
+
+func tracelbRTT(tx, rx syscall.Timeval) *RTT {
+	d := time.Unix(int64(rx.Sec), int64(rx.Usec)*1000).Sub(time.Unix(int64(tx.Sec), int64(tx.Usec)*1000))
+	return &RTT{
+		Sec:  int64(d / time.Second),
+		Usec: int64((d % time.Second) / time.Microsecond),
+	}
+}
+
+// ConvertTracelb converts a warts Tracelb to a datamodel Tracelb
+func ConvertTracelb(in warts.Tracelb) Tracelb {
+	t := Tracelb{}
+	t.Type = "tracelb"
+	t.UserId = in.Flags.UserID
+	t.Method = in.Flags.TraceType.String()
+	t.Src = uint32(in.Flags.Src.Address)
+	t.Dst = uint32(in.Flags.Dst.Address)
+	t.Sport = uint32(in.Flags.SourcePort)
+	t.Dport = uint32(in.Flags.DestPort)
+	t.Start = tracelbTime(in.Flags.StartTime)
+	t.ProbeSize = uint32(in.Flags.ProbeSize)
+	t.Firsthop = uint32(in.Flags.FirstHop)
+	t.WaitTimeout = uint32(in.Flags.WaitTimeout)
+	t.WaitProbe = uint32(in.Flags.WaitProbe)
+	t.Attempts = uint32(in.Flags.Attempts)
+	t.Confidence = uint32(in.Flags.Confidence)
+	t.Tos = uint32(in.Flags.ToS)
+	t.GapLimit = uint32(in.Flags.GapLimit)
+	t.Probec = in.Flags.ProbeCount
+	t.ProbecMax = in.Flags.ProbeCMax
+	t.Nodes = make([]*TracelbNode, len(in.Nodes))
+	for i, n := range in.Nodes {
+		t.Nodes[i] = &TracelbNode{
+			Addr: uint32(n.Addr.Address),
+			QTtl: uint32(n.QTTL),
+			Name: n.Name,
+		}
+	}
+	t.Links = make([]*TracelbLink, len(in.Links))
+	for i, l := range in.Links {
+		link := &TracelbLink{}
+		if int(l.From) < len(in.Nodes) {
+			link.From = uint32(in.Nodes[l.From].Addr.Address)
+		}
+		if l.HasTo && int(l.To) < len(in.Nodes) {
+			link.To = uint32(in.Nodes[l.To].Addr.Address)
+		}
+		link.Probes = convertTracelbProbeSets(l.ProbeSets)
+		t.Links[i] = link
+	}
+	return t
+}
+
+func convertTracelbProbeSets(in []warts.TracelbProbeSet) []*TracelbProbeSet {
+	ret := make([]*TracelbProbeSet, len(in))
+	for i, ps := range in {
+		set := &TracelbProbeSet{}
+		for _, p := range ps.Probes {
+			probe := &TracelbProbe{
+				Tx:      tracelbTime(p.Tx),
+				Flowid:  uint32(p.FlowID),
+				Ttl:     uint32(p.TTL),
+				Attempt: uint32(p.Attempt),
+			}
+			for _, r := range p.Replies {
+				probe.Replies = append(probe.Replies, &TracelbReply{
+					From:      uint32(r.From.Address),
+					Rtt:       tracelbRTT(p.Tx, r.Rx),
+					ReplyTtl:  uint32(r.TTL),
+					ReplyIpid: uint32(r.IPID),
+					IcmpType:  uint32((r.ICMPTypeCode & 0xFF00) >> 8),
+					IcmpCode:  uint32(r.ICMPTypeCode & 0x00FF),
+					IcmpQTtl:  uint32(r.QuotedTTL),
+					IcmpQTos:  uint32(r.QuotedToS),
+					TcpFlags:  uint32(r.TCPFlags),
+				})
+			}
+			set.Probes = append(set.Probes, probe)
+		}
+		ret[i] = set
+	}
+	return ret
+}
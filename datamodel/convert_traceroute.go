@@ -87,6 +87,7 @@ func convertHops(in warts.Traceroute) []*TracerouteHop {
 		h.IcmpQTtl = uint32(hop.QuotedTTL)
 		h.IcmpQIpl = uint32(hop.QuotedIPLength)
 		h.IcmpQTos = uint32(hop.QuotesToS)
+		h.IcmpExt = convertICMPExt(hop.ICMPExt)
 		retHops[i] = h
 	}
 	return retHops
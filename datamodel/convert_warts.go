@@ -0,0 +1,229 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package datamodel
+
+import (
+	"syscall"
+
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+// The inverse of the conversions from warts, so that measurements can be
+// written with a warts.Writer
+
+// PingToWarts converts a datamodel Ping to a warts Ping
+func PingToWarts(p *Ping) warts.Ping {
+	var wp warts.Ping
+	wp.Type = "ping"
+	wp.Version = "0.4"
+	f := &wp.Flags
+	f.Src = wartsAddr(p.Src)
+	f.Dst = wartsAddr(p.Dst)
+	f.PingMethod = warts.PingMethod(pingMethod(p.Method))
+	if p.Start != nil {
+		f.StartTime = timeval(p.Start.Sec, p.Start.Usec)
+	}
+	f.ProbeCount = uint16(p.PingSent)
+	f.ProbeSize = uint16(p.ProbeSize)
+	f.UserID = p.UserId
+	f.ProbeTTL = uint8(p.Ttl)
+	f.ProbeWaitS = uint8(p.Wait)
+	f.ProbeTimeout = uint8(p.Timeout)
+	f.PingsSent = uint16(p.PingSent)
+	if p.Statistics != nil {
+		f.PingsSent = uint16(p.Statistics.Replies + int32(p.Statistics.Loss))
+	}
+	// The addresses of a prespecified timestamp ping are not kept, they
+	// are the ones the replies carry
+	for _, r := range p.Responses {
+		if len(r.Tsandaddr) == 0 {
+			continue
+		}
+		for _, ts := range r.Tsandaddr {
+			f.TS = append(f.TS, wartsAddr(ts.Ip))
+		}
+		break
+	}
+	for _, flag := range p.Flags {
+		if flag == "tsandaddr" && len(f.TS) > 0 {
+			continue
+		}
+		f.PF |= pingFlag(flag)
+	}
+	for _, r := range p.Responses {
+		wr := warts.PingReplyFlags{
+			Addr:       wartsAddr(r.From),
+			ProbeID:    uint16(r.Seq),
+			ReplySize:  uint16(r.ReplySize),
+			ReplyTTL:   uint8(r.ReplyTtl),
+			ReplyProto: replyProto(r.ReplyProto),
+			RTT:        syscall.NsecToTimeval(int64(r.Rtt) * 1000),
+			ProbeIPID:  uint16(r.ProbeIpid),
+			ReplyIPID:  uint16(r.ReplyIpid),
+			ICMP:       uint16(r.IcmpType<<8 | r.IcmpCode),
+		}
+		if r.Tx != nil {
+			wr.Tx = timeval(r.Tx.Sec, r.Tx.Usec)
+		}
+		for _, rr := range r.RR {
+			wr.V4RR.Addrs = append(wr.V4RR.Addrs, wartsAddr(rr))
+		}
+		wr.V4TS.TimeStamps = append(wr.V4TS.TimeStamps, r.Tsonly...)
+		for _, ts := range r.Tsandaddr {
+			wr.V4TS.TimeStamps = append(wr.V4TS.TimeStamps, ts.Ts)
+			wr.V4TS.Addrs = append(wr.V4TS.Addrs, wartsAddr(ts.Ip))
+		}
+		wp.PingReplies = append(wp.PingReplies, wr)
+	}
+	wp.ReplyCount = uint16(len(wp.PingReplies))
+	return wp
+}
+
+// TracerouteToWarts converts a datamodel Traceroute to a warts Traceroute
+func TracerouteToWarts(t *Traceroute) warts.Traceroute {
+	var wt warts.Traceroute
+	f := &wt.Flags
+	f.UserID = t.UserId
+	f.Src = wartsAddr(t.Src)
+	f.Dst = wartsAddr(t.Dst)
+	f.TraceType = warts.TraceType(traceType(t.Method))
+	f.SourcePort = uint16(t.Sport)
+	f.DestPort = uint16(t.Dport)
+	f.StopReason = warts.StopReason(stopReason(t.StopReason))
+	f.StopData = uint8(t.StopData)
+	if t.Start != nil {
+		f.StartTime = timeval(t.Start.Sec, t.Start.Usec)
+	}
+	f.Attempts = uint8(t.Attempts)
+	f.HopLimit = uint8(t.Hoplimit)
+	f.StartTTL = uint8(t.Firsthop)
+	f.TimeoutS = uint8(t.Wait)
+	f.MinWaitCenti = uint8(t.WaitProbe)
+	f.IPToS = uint8(t.Tos)
+	f.ProbeSize = uint16(t.ProbeSize)
+	f.GapLimit = uint8(t.GapLimit)
+	for _, h := range t.Hops {
+		wh := warts.TracerouteHop{
+			Address:        wartsAddr(h.Addr),
+			ProbeTTL:       uint8(h.ProbeTtl),
+			ProbeID:        uint8(h.ProbeId),
+			ProbeSize:      uint16(h.ProbeSize),
+			ReplyTTL:       uint8(h.ReplyTtl),
+			ToS:            uint8(h.ReplyTos),
+			ReplySize:      uint16(h.ReplySize),
+			IPID:           uint16(h.ReplyIpid),
+			ICMPTypeCode:   uint16(h.IcmpType<<8 | h.IcmpCode),
+			QuotedTTL:      uint8(h.IcmpQTtl),
+			QuotedIPLength: uint16(h.IcmpQIpl),
+			QuotesToS:      uint8(h.IcmpQTos),
+		}
+		if h.Rtt != nil {
+			wh.RTT = timeval(h.Rtt.Sec, h.Rtt.Usec)
+		}
+		for _, ext := range h.IcmpExt {
+			wh.ICMPExt.Extensions = append(wh.ICMPExt.Extensions, warts.ICMPExtension{
+				Length:      uint16(len(ext.Data)),
+				ClassNumber: uint8(ext.ClassNumber),
+				TypeNumber:  uint8(ext.TypeNumber),
+				Data:        ext.Data,
+			})
+		}
+		wt.Hops = append(wt.Hops, wh)
+	}
+	wt.HopCount = uint16(len(wt.Hops))
+	return wt
+}
+
+func convertICMPExt(in warts.ICMPExtensionList) []*ICMPExtension {
+	var ret []*ICMPExtension
+	for _, ext := range in.Extensions {
+		ret = append(ret, &ICMPExtension{
+			ClassNumber: uint32(ext.ClassNumber),
+			TypeNumber:  uint32(ext.TypeNumber),
+			Data:        ext.Data,
+		})
+	}
+	return ret
+}
+
+func wartsAddr(ip uint32) warts.Address {
+	return warts.Address{Type: 0x01, Address: uint64(ip)}
+}
+
+func timeval(sec, usec int64) syscall.Timeval {
+	return syscall.NsecToTimeval(sec*1000000000 + usec*1000)
+}
+
+// pingMethod, traceType and stopReason find the warts value with the
+// name the conversion from warts gave it
+func pingMethod(name string) uint8 {
+	for i := uint8(0); i < 7; i++ {
+		if warts.PingMethod(i).String() == name {
+			return i
+		}
+	}
+	return 0
+}
+
+func traceType(name string) uint8 {
+	for i := uint8(0); i < 7; i++ {
+		if warts.TraceType(i).String() == name {
+			return i
+		}
+	}
+	return 0
+}
+
+func stopReason(name string) uint8 {
+	for i := uint8(0); i < 10; i++ {
+		if warts.StopReason(i).String() == name {
+			return i
+		}
+	}
+	return 0
+}
+
+func pingFlag(name string) warts.PingFlag {
+	for i := uint(0); i < 8; i++ {
+		if f := warts.PingFlag(1 << i); f.Strings()[0] == name {
+			return f
+		}
+	}
+	return 0
+}
+
+func replyProto(name string) warts.RProto {
+	switch name {
+	case "tcp":
+		return 6
+	case "udp":
+		return 17
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package datamodel
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// DealiasAliases is the result of a dealias that found its addresses are aliases
+	DealiasAliases = "aliases"
+	// DealiasNotAliases is the result of a dealias that found its addresses are not aliases
+	DealiasNotAliases = "not-aliases"
+)
+
+// Aliases returns the addresses a dealias found to be aliases of each other,
+// nil if it did not find any
+func (d *Dealias) Aliases() []uint32 {
+	if d.Result != DealiasAliases || len(d.Addrs) < 2 {
+		return nil
+	}
+	return d.Addrs
+}
+
+// CMarshal marshals a dealias for storing in a cache
+func (d *Dealias) CMarshal() []byte {
+	ret, err := proto.Marshal(d)
+	if err != nil {
+		return nil
+	}
+	return ret
+}
+
+// Key generates a key for a dealias measurement
+func (dm *DealiasMeasurement) Key() string {
+	return fmt.Sprintf("%s_%d_%s_%v", "XXDA", dm.Src, dm.Method, dm.Addrs)
+}
@@ -0,0 +1,274 @@
+// Code generated by protoc-gen-go.
+// source: github.com/NEU-SNS/ReverseTraceroute/datamodel/dealias.proto
+// DO NOT EDIT!
+
+/*
+Package datamodel is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/dealias.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/ping.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/recspoof.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/time.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/tracelb.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/traceroute.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/update.proto
+	github.com/NEU-SNS/ReverseTraceroute/datamodel/vantagepoint.proto
+
+It has these top-level messages:
+	DealiasMeasurement
+	DealiasArg
+	DealiasProbeDef
+	DealiasReply
+	DealiasProbe
+	Dealias
+	PingMeasurement
+	PingArg
+	PingArgResp
+	PingStats
+	PingResponse
+	TsAndAddr
+	Ping
+	RecSpoof
+	Spoof
+	SpoofedProbes
+	SpoofedProbesResponse
+	Probe
+	RecordRoute
+	TimeStamp
+	Stamp
+	NotifyRecSpoofResponse
+	ReceiveSpoofedProbesResponse
+	Time
+	RTT
+	TracelbMeasurement
+	TracelbArg
+	TracelbArgResp
+	TracelbReply
+	TracelbProbe
+	TracelbProbeSet
+	TracelbNode
+	TracelbLink
+	Tracelb
+	TracerouteMeasurement
+	TracerouteArg
+	TracerouteArgResp
+	TracerouteHop
+	ICMPExtension
+	Traceroute
+	TracerouteTime
+	UpdateResponse
+	VantagePoint
+	SelfTest
+	SelfTestResponse
+	VPRequest
+	VPReturn
+	VPStatusRequest
+	VPStatus
+	VPStatusReturn
+	RRSpooferRequest
+	RRSpooferResponse
+	TSSpooferRequest
+	TSSpooferResponse
+*/
+package datamodel
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type DealiasMeasurement struct {
+	Src         uint32   `protobuf:"varint,1,opt,name=src" json:"src,omitempty"`
+	Method      string   `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
+	Addrs       []uint32 `protobuf:"varint,3,rep,name=addrs" json:"addrs,omitempty"`
+	Prefix      uint32   `protobuf:"varint,4,opt,name=prefix" json:"prefix,omitempty"`
+	ProbeMethod string   `protobuf:"bytes,5,opt,name=probe_method" json:"probe_method,omitempty"`
+	Attempts    string   `protobuf:"bytes,6,opt,name=attempts" json:"attempts,omitempty"`
+	Fudge       string   `protobuf:"bytes,7,opt,name=fudge" json:"fudge,omitempty"`
+	WaitProbe   string   `protobuf:"bytes,8,opt,name=wait_probe" json:"wait_probe,omitempty"`
+	WaitTimeout string   `protobuf:"bytes,9,opt,name=wait_timeout" json:"wait_timeout,omitempty"`
+	UserId      string   `protobuf:"bytes,10,opt,name=user_id" json:"user_id,omitempty"`
+	Timeout     int64    `protobuf:"varint,11,opt,name=timeout" json:"timeout,omitempty"`
+}
+
+func (m *DealiasMeasurement) Reset()                    { *m = DealiasMeasurement{} }
+func (m *DealiasMeasurement) String() string            { return proto.CompactTextString(m) }
+func (*DealiasMeasurement) ProtoMessage()               {}
+func (*DealiasMeasurement) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type DealiasArg struct {
+	Dealiases []*DealiasMeasurement `protobuf:"bytes,1,rep,name=dealiases" json:"dealiases,omitempty"`
+	Priority  uint32                `protobuf:"varint,2,opt,name=priority" json:"priority,omitempty"`
+}
+
+func (m *DealiasArg) Reset()                    { *m = DealiasArg{} }
+func (m *DealiasArg) String() string            { return proto.CompactTextString(m) }
+func (*DealiasArg) ProtoMessage()               {}
+func (*DealiasArg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *DealiasArg) GetDealiases() []*DealiasMeasurement {
+	if m != nil {
+		return m.Dealiases
+	}
+	return nil
+}
+
+type DealiasProbeDef struct {
+	Id     uint32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Src    uint32 `protobuf:"varint,2,opt,name=src" json:"src,omitempty"`
+	Dst    uint32 `protobuf:"varint,3,opt,name=dst" json:"dst,omitempty"`
+	Method string `protobuf:"bytes,4,opt,name=method" json:"method,omitempty"`
+	Ttl    uint32 `protobuf:"varint,5,opt,name=ttl" json:"ttl,omitempty"`
+}
+
+func (m *DealiasProbeDef) Reset()                    { *m = DealiasProbeDef{} }
+func (m *DealiasProbeDef) String() string            { return proto.CompactTextString(m) }
+func (*DealiasProbeDef) ProtoMessage()               {}
+func (*DealiasProbeDef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type DealiasReply struct {
+	Src      uint32 `protobuf:"varint,1,opt,name=src" json:"src,omitempty"`
+	Rtt      *RTT   `protobuf:"bytes,2,opt,name=rtt" json:"rtt,omitempty"`
+	Ipid     uint32 `protobuf:"varint,3,opt,name=ipid" json:"ipid,omitempty"`
+	Ttl      uint32 `protobuf:"varint,4,opt,name=ttl" json:"ttl,omitempty"`
+	IcmpType uint32 `protobuf:"varint,5,opt,name=icmp_type" json:"icmp_type,omitempty"`
+	IcmpCode uint32 `protobuf:"varint,6,opt,name=icmp_code" json:"icmp_code,omitempty"`
+}
+
+func (m *DealiasReply) Reset()                    { *m = DealiasReply{} }
+func (m *DealiasReply) String() string            { return proto.CompactTextString(m) }
+func (*DealiasReply) ProtoMessage()               {}
+func (*DealiasReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *DealiasReply) GetRtt() *RTT {
+	if m != nil {
+		return m.Rtt
+	}
+	return nil
+}
+
+type DealiasProbe struct {
+	Def     uint32          `protobuf:"varint,1,opt,name=def" json:"def,omitempty"`
+	Tx      *TracerouteTime `protobuf:"bytes,2,opt,name=tx" json:"tx,omitempty"`
+	Ipid    uint32          `protobuf:"varint,3,opt,name=ipid" json:"ipid,omitempty"`
+	Replies []*DealiasReply `protobuf:"bytes,4,rep,name=replies" json:"replies,omitempty"`
+}
+
+func (m *DealiasProbe) Reset()                    { *m = DealiasProbe{} }
+func (m *DealiasProbe) String() string            { return proto.CompactTextString(m) }
+func (*DealiasProbe) ProtoMessage()               {}
+func (*DealiasProbe) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *DealiasProbe) GetTx() *TracerouteTime {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *DealiasProbe) GetReplies() []*DealiasReply {
+	if m != nil {
+		return m.Replies
+	}
+	return nil
+}
+
+type Dealias struct {
+	Type      string             `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	UserId    uint32             `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Method    string             `protobuf:"bytes,3,opt,name=method" json:"method,omitempty"`
+	Result    string             `protobuf:"bytes,4,opt,name=result" json:"result,omitempty"`
+	Src       uint32             `protobuf:"varint,5,opt,name=src" json:"src,omitempty"`
+	Start     *TracerouteTime    `protobuf:"bytes,6,opt,name=start" json:"start,omitempty"`
+	ProbeDefs []*DealiasProbeDef `protobuf:"bytes,7,rep,name=probe_defs" json:"probe_defs,omitempty"`
+	Probes    []*DealiasProbe    `protobuf:"bytes,8,rep,name=probes" json:"probes,omitempty"`
+	Addrs     []uint32           `protobuf:"varint,9,rep,name=addrs" json:"addrs,omitempty"`
+	Error     string             `protobuf:"bytes,10,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *Dealias) Reset()                    { *m = Dealias{} }
+func (m *Dealias) String() string            { return proto.CompactTextString(m) }
+func (*Dealias) ProtoMessage()               {}
+func (*Dealias) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Dealias) GetStart() *TracerouteTime {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *Dealias) GetProbeDefs() []*DealiasProbeDef {
+	if m != nil {
+		return m.ProbeDefs
+	}
+	return nil
+}
+
+func (m *Dealias) GetProbes() []*DealiasProbe {
+	if m != nil {
+		return m.Probes
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DealiasMeasurement)(nil), "datamodel.DealiasMeasurement")
+	proto.RegisterType((*DealiasArg)(nil), "datamodel.DealiasArg")
+	proto.RegisterType((*DealiasProbeDef)(nil), "datamodel.DealiasProbeDef")
+	proto.RegisterType((*DealiasReply)(nil), "datamodel.DealiasReply")
+	proto.RegisterType((*DealiasProbe)(nil), "datamodel.DealiasProbe")
+	proto.RegisterType((*Dealias)(nil), "datamodel.Dealias")
+}
+
+func init() {
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/dealias.proto", fileDescriptor0)
+}
+
+var fileDescriptor0 = []byte{
+	// 523 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xdf, 0x8b, 0xd3, 0x40,
+	0x10, 0xc7, 0x49, 0xd3, 0xb4, 0xcd, 0xa4, 0xe9, 0x9d, 0xe1, 0xc0, 0xb5, 0x22, 0x94, 0x80, 0xd8,
+	0x17, 0x5b, 0x39, 0x9f, 0x04, 0x41, 0x94, 0xf3, 0xd1, 0xe3, 0xe8, 0xd5, 0x17, 0x5f, 0x4a, 0xda,
+	0x9d, 0xf6, 0x16, 0x12, 0x37, 0xec, 0x4e, 0xf4, 0xea, 0xbf, 0xe9, 0xff, 0xe2, 0xb3, 0x74, 0xb2,
+	0xfd, 0x71, 0x3d, 0xe5, 0xb8, 0xc7, 0x4c, 0x66, 0xbe, 0xf3, 0x9d, 0xcf, 0xce, 0xc0, 0xfb, 0x95,
+	0xa2, 0x9b, 0x6a, 0x3e, 0x5a, 0xe8, 0x62, 0x7c, 0xf9, 0xf9, 0xeb, 0xeb, 0xeb, 0xcb, 0xeb, 0xf1,
+	0x04, 0x7f, 0xa0, 0xb1, 0x38, 0x35, 0xd9, 0x02, 0x8d, 0xae, 0x08, 0xc7, 0x32, 0xa3, 0xac, 0xd0,
+	0x12, 0xf3, 0xb1, 0xc4, 0x2c, 0x57, 0x99, 0x1d, 0x95, 0x46, 0x93, 0x4e, 0xc2, 0xdd, 0x8f, 0xfe,
+	0xbb, 0x47, 0x0a, 0x91, 0x2a, 0xb0, 0x56, 0xe9, 0x7f, 0x78, 0x6c, 0xe9, 0x2e, 0x58, 0x0b, 0xa4,
+	0xbf, 0x3d, 0x48, 0x2e, 0x6a, 0x63, 0x5f, 0x30, 0xb3, 0x95, 0xc1, 0x02, 0xbf, 0x53, 0x12, 0x81,
+	0x6f, 0xcd, 0x42, 0x78, 0x03, 0x6f, 0x18, 0x27, 0x3d, 0x68, 0x15, 0x48, 0x37, 0x5a, 0x8a, 0xc6,
+	0xc0, 0x1b, 0x86, 0x49, 0x0c, 0x41, 0x26, 0xa5, 0xb1, 0xc2, 0x1f, 0xf8, 0xf5, 0xef, 0xd2, 0xe0,
+	0x52, 0xdd, 0x8a, 0x26, 0xa7, 0x9f, 0x41, 0xb7, 0x34, 0x7a, 0x8e, 0x33, 0x57, 0x14, 0x70, 0xd1,
+	0x29, 0x74, 0x32, 0x22, 0x2c, 0x4a, 0xb2, 0xa2, 0xb5, 0x95, 0x59, 0x56, 0x72, 0x85, 0xa2, 0xcd,
+	0x9f, 0x09, 0xc0, 0xcf, 0x4c, 0xd1, 0x8c, 0x6b, 0x45, 0x87, 0x63, 0x67, 0xd0, 0xe5, 0xd8, 0x66,
+	0x62, 0x5d, 0x91, 0x08, 0x39, 0x7a, 0x02, 0xed, 0xca, 0xa2, 0x99, 0x29, 0x29, 0x60, 0x1b, 0xd8,
+	0x66, 0x44, 0x03, 0x6f, 0xe8, 0xa7, 0x57, 0x00, 0x6e, 0xa8, 0x8f, 0x66, 0x95, 0xbc, 0x81, 0xd0,
+	0xb1, 0x47, 0x2b, 0xbc, 0x81, 0x3f, 0x8c, 0xce, 0x5f, 0x8c, 0x76, 0x4c, 0x46, 0xff, 0x18, 0xff,
+	0x14, 0x3a, 0xa5, 0x51, 0xda, 0x28, 0x5a, 0xf3, 0xcc, 0x71, 0x3a, 0x85, 0x13, 0x97, 0x77, 0xb5,
+	0xf1, 0x77, 0x81, 0xcb, 0x04, 0xa0, 0xa1, 0xa4, 0x43, 0xe4, 0x78, 0x35, 0xb6, 0x1f, 0xd2, 0x92,
+	0xf0, 0x8f, 0xe0, 0x35, 0xd9, 0x6b, 0x04, 0x3e, 0x51, 0xce, 0x50, 0xe2, 0x74, 0x0d, 0x5d, 0xa7,
+	0x3a, 0xc1, 0x32, 0x5f, 0xdf, 0xc5, 0xfe, 0x1c, 0x7c, 0x43, 0xc4, 0x9a, 0xd1, 0x79, 0xef, 0xc0,
+	0xf0, 0x64, 0x3a, 0x4d, 0xba, 0xd0, 0x54, 0xa5, 0x92, 0xae, 0x89, 0x13, 0xad, 0xf9, 0x3f, 0x81,
+	0x50, 0x2d, 0x8a, 0x72, 0x46, 0xeb, 0x12, 0x45, 0x70, 0x27, 0xb4, 0xd0, 0x12, 0x99, 0x7e, 0x9c,
+	0xfe, 0x82, 0xee, 0xe1, 0x40, 0x6c, 0x1a, 0x97, 0xae, 0xf5, 0x4b, 0x68, 0xd0, 0xad, 0xeb, 0xfc,
+	0xec, 0xa0, 0xf3, 0x7e, 0xa7, 0xa6, 0xaa, 0xc0, 0x23, 0x13, 0x43, 0x68, 0x1b, 0x2c, 0x73, 0x85,
+	0x56, 0x34, 0x19, 0xf2, 0xd3, 0xfb, 0x90, 0x79, 0xcc, 0xf4, 0x8f, 0x07, 0x6d, 0x17, 0xd8, 0x68,
+	0xb0, 0x51, 0xef, 0xf8, 0x69, 0x1b, 0x47, 0xf8, 0x7c, 0x4e, 0xe8, 0x41, 0xcb, 0xa0, 0xad, 0x72,
+	0xda, 0xe3, 0xdc, 0x10, 0x0b, 0x9c, 0x83, 0xc0, 0x52, 0x66, 0x48, 0xb4, 0x1e, 0x72, 0x3e, 0x02,
+	0xa8, 0x77, 0x54, 0xe2, 0xd2, 0x8a, 0x36, 0xdb, 0xed, 0xdf, 0xb7, 0xbb, 0x7b, 0xeb, 0x57, 0x9b,
+	0x1d, 0xd7, 0x73, 0xb4, 0xa2, 0xf3, 0xbf, 0xd1, 0x6a, 0x8c, 0xbb, 0xdb, 0x08, 0xf9, 0x36, 0x62,
+	0x08, 0xd0, 0x18, 0x6d, 0xea, 0x45, 0xfd, 0x14, 0x7d, 0xdb, 0x9f, 0xfd, 0xbc, 0xc5, 0x17, 0xf8,
+	0xf6, 0xef, 0x00, 0x10, 0x65, 0x6d, 0x2a, 0x48, 0x04, 0x00, 0x00,
+}
@@ -2,52 +2,6 @@
 // source: github.com/NEU-SNS/ReverseTraceroute/datamodel/ping.proto
 // DO NOT EDIT!
 
-/*
-Package datamodel is a generated protocol buffer package.
-
-It is generated from these files:
-	github.com/NEU-SNS/ReverseTraceroute/datamodel/ping.proto
-	github.com/NEU-SNS/ReverseTraceroute/datamodel/recspoof.proto
-	github.com/NEU-SNS/ReverseTraceroute/datamodel/time.proto
-	github.com/NEU-SNS/ReverseTraceroute/datamodel/traceroute.proto
-	github.com/NEU-SNS/ReverseTraceroute/datamodel/update.proto
-	github.com/NEU-SNS/ReverseTraceroute/datamodel/vantagepoint.proto
-
-It has these top-level messages:
-	PingMeasurement
-	PingArg
-	PingArgResp
-	PingStats
-	PingResponse
-	TsAndAddr
-	Ping
-	RecSpoof
-	Spoof
-	SpoofedProbes
-	SpoofedProbesResponse
-	Probe
-	RecordRoute
-	TimeStamp
-	Stamp
-	NotifyRecSpoofResponse
-	ReceiveSpoofedProbesResponse
-	Time
-	RTT
-	TracerouteMeasurement
-	TracerouteArg
-	TracerouteArgResp
-	TracerouteHop
-	Traceroute
-	TracerouteTime
-	UpdateResponse
-	VantagePoint
-	VPRequest
-	VPReturn
-	RRSpooferRequest
-	RRSpooferResponse
-	TSSpooferRequest
-	TSSpooferResponse
-*/
 package datamodel
 
 import proto "github.com/golang/protobuf/proto"
@@ -59,12 +13,6 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
-
 type PingMeasurement struct {
 	Src         uint32 `protobuf:"varint,1,opt,name=src" json:"src,omitempty"`
 	Dst         uint32 `protobuf:"varint,2,opt,name=dst" json:"dst,omitempty"`
@@ -96,16 +44,17 @@ type PingMeasurement struct {
 func (m *PingMeasurement) Reset()                    { *m = PingMeasurement{} }
 func (m *PingMeasurement) String() string            { return proto.CompactTextString(m) }
 func (*PingMeasurement) ProtoMessage()               {}
-func (*PingMeasurement) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (*PingMeasurement) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
 
 type PingArg struct {
-	Pings []*PingMeasurement `protobuf:"bytes,1,rep,name=pings" json:"pings,omitempty"`
+	Pings    []*PingMeasurement `protobuf:"bytes,1,rep,name=pings" json:"pings,omitempty"`
+	Priority uint32             `protobuf:"varint,2,opt,name=priority" json:"priority,omitempty"`
 }
 
 func (m *PingArg) Reset()                    { *m = PingArg{} }
 func (m *PingArg) String() string            { return proto.CompactTextString(m) }
 func (*PingArg) ProtoMessage()               {}
-func (*PingArg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*PingArg) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
 
 func (m *PingArg) GetPings() []*PingMeasurement {
 	if m != nil {
@@ -121,7 +70,7 @@ type PingArgResp struct {
 func (m *PingArgResp) Reset()                    { *m = PingArgResp{} }
 func (m *PingArgResp) String() string            { return proto.CompactTextString(m) }
 func (*PingArgResp) ProtoMessage()               {}
-func (*PingArgResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*PingArgResp) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
 
 func (m *PingArgResp) GetPings() []*Ping {
 	if m != nil {
@@ -142,7 +91,7 @@ type PingStats struct {
 func (m *PingStats) Reset()                    { *m = PingStats{} }
 func (m *PingStats) String() string            { return proto.CompactTextString(m) }
 func (*PingStats) ProtoMessage()               {}
-func (*PingStats) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*PingStats) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
 
 type PingResponse struct {
 	From       uint32       `protobuf:"varint,1,opt,name=from" json:"from,omitempty"`
@@ -160,12 +109,17 @@ type PingResponse struct {
 	RR         []uint32     `protobuf:"varint,13,rep,name=RR" json:"RR,omitempty"`
 	Tsonly     []uint32     `protobuf:"varint,14,rep,name=tsonly" json:"tsonly,omitempty"`
 	Tsandaddr  []*TsAndAddr `protobuf:"bytes,15,rep,name=tsandaddr" json:"tsandaddr,omitempty"`
+	// Timestamp option overflow and pointer, set when the
+	// reply option was parsed directly rather than by scamper.
+	// A pointer of 0 means the stamped slots are unknown
+	TsOverflow uint32 `protobuf:"varint,16,opt,name=ts_overflow" json:"ts_overflow,omitempty"`
+	TsPointer  uint32 `protobuf:"varint,17,opt,name=ts_pointer" json:"ts_pointer,omitempty"`
 }
 
 func (m *PingResponse) Reset()                    { *m = PingResponse{} }
 func (m *PingResponse) String() string            { return proto.CompactTextString(m) }
 func (*PingResponse) ProtoMessage()               {}
-func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
 
 func (m *PingResponse) GetTx() *Time {
 	if m != nil {
@@ -191,12 +145,15 @@ func (m *PingResponse) GetTsandaddr() []*TsAndAddr {
 type TsAndAddr struct {
 	Ip uint32 `protobuf:"varint,1,opt,name=ip" json:"ip,omitempty"`
 	Ts uint32 `protobuf:"varint,2,opt,name=ts" json:"ts,omitempty"`
+	// Set if a hop filled in the slot, only known
+	// when ts_pointer of the response is set
+	Stamped bool `protobuf:"varint,3,opt,name=stamped" json:"stamped,omitempty"`
 }
 
 func (m *TsAndAddr) Reset()                    { *m = TsAndAddr{} }
 func (m *TsAndAddr) String() string            { return proto.CompactTextString(m) }
 func (*TsAndAddr) ProtoMessage()               {}
-func (*TsAndAddr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*TsAndAddr) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
 
 type Ping struct {
 	Type        string          `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
@@ -222,7 +179,7 @@ type Ping struct {
 func (m *Ping) Reset()                    { *m = Ping{} }
 func (m *Ping) String() string            { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()               {}
-func (*Ping) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*Ping) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
 
 func (m *Ping) GetStart() *Time {
 	if m != nil {
@@ -256,56 +213,59 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/ping.proto", fileDescriptor0)
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/ping.proto", fileDescriptor1)
 }
 
-var fileDescriptor0 = []byte{
-	// 751 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0xcb, 0x6e, 0xdb, 0x3a,
-	0x10, 0x45, 0x64, 0x3b, 0xb6, 0x28, 0x3b, 0x4e, 0x94, 0xdc, 0x1b, 0xde, 0x5c, 0xa0, 0x28, 0xdc,
-	0x45, 0xd3, 0x02, 0xb1, 0x81, 0xb4, 0x9b, 0x2e, 0x53, 0xa0, 0xcb, 0x06, 0x85, 0x93, 0x6e, 0xba,
-	0x11, 0x68, 0x91, 0x71, 0x84, 0x4a, 0xa2, 0x4a, 0xd2, 0x69, 0xdc, 0xff, 0xe9, 0x77, 0xf4, 0x7b,
-	0xfa, 0x17, 0x9d, 0x19, 0x99, 0xf2, 0x03, 0xd9, 0x74, 0x65, 0x9f, 0x11, 0x39, 0x33, 0x3c, 0xe7,
-	0xcc, 0xb0, 0x77, 0xf3, 0xcc, 0xdd, 0x2f, 0x66, 0xe3, 0x54, 0x17, 0x93, 0xeb, 0x0f, 0x9f, 0x2f,
-	0x6e, 0xae, 0x6f, 0x26, 0x53, 0xf5, 0xa0, 0x8c, 0x55, 0xb7, 0x46, 0xa4, 0xca, 0xe8, 0x85, 0x53,
-	0x13, 0x29, 0x9c, 0x28, 0xb4, 0x54, 0xf9, 0xa4, 0xca, 0xca, 0xf9, 0xb8, 0x32, 0xda, 0xe9, 0x38,
-	0x6c, 0xa2, 0x67, 0x7f, 0x9b, 0xc5, 0x65, 0x85, 0xaa, 0xb3, 0x8c, 0x7e, 0xb6, 0xd8, 0xf0, 0x13,
-	0x24, 0xfd, 0xa8, 0x84, 0x5d, 0x18, 0x55, 0xa8, 0xd2, 0xc5, 0x11, 0x6b, 0x59, 0x93, 0xf2, 0xbd,
-	0xe7, 0x7b, 0xe7, 0x03, 0x04, 0xd2, 0x3a, 0x1e, 0x10, 0x38, 0x61, 0x7d, 0x5b, 0x69, 0x7d, 0xa7,
-	0x4c, 0x22, 0xa4, 0x34, 0xbc, 0x45, 0xd1, 0x01, 0xeb, 0x50, 0x94, 0xb7, 0x01, 0xf6, 0x62, 0xc6,
-	0x82, 0xe9, 0x94, 0x77, 0xe8, 0xff, 0x01, 0xdb, 0xb7, 0xf5, 0xd1, 0x7d, 0xc0, 0x61, 0x3c, 0x64,
-	0xdd, 0x4a, 0x2c, 0x73, 0x2d, 0x24, 0xef, 0x52, 0x00, 0xee, 0xa6, 0x7a, 0x51, 0x3a, 0xde, 0x23,
-	0x78, 0xc8, 0x7a, 0x59, 0x5a, 0x54, 0x89, 0x5d, 0x14, 0x3c, 0xf4, 0x07, 0x64, 0xa5, 0x8d, 0xe3,
-	0xcc, 0x43, 0x4b, 0x30, 0x22, 0xd8, 0x67, 0xed, 0xef, 0x22, 0x73, 0xbc, 0x4f, 0x08, 0x7a, 0x75,
-	0x2e, 0xe7, 0x03, 0x0f, 0x0a, 0xb7, 0xe0, 0x07, 0x04, 0x8e, 0x59, 0x64, 0x54, 0x95, 0x2f, 0x93,
-	0xba, 0xd8, 0x70, 0xdd, 0x8c, 0x73, 0xca, 0x94, 0xfc, 0x90, 0x02, 0xd0, 0x6d, 0xa1, 0xdc, 0xbd,
-	0x96, 0xfc, 0xc8, 0x67, 0xb7, 0xd9, 0x0f, 0xc5, 0x63, 0x7f, 0x7c, 0x61, 0xe1, 0xe5, 0x99, 0xe4,
-	0xc7, 0x4d, 0x39, 0x6d, 0xf9, 0x09, 0x01, 0x78, 0x36, 0xd2, 0x9a, 0x58, 0x20, 0xb9, 0xe2, 0xff,
-	0xf8, 0x1b, 0x18, 0x03, 0xf6, 0xf9, 0xbf, 0x10, 0x68, 0x61, 0x1b, 0xe9, 0xbd, 0x4a, 0xbf, 0x26,
-	0xa9, 0x80, 0x5f, 0x7e, 0x4a, 0x1c, 0xc1, 0x9b, 0xeb, 0xa0, 0x9c, 0x71, 0x4e, 0x91, 0x23, 0x16,
-	0x42, 0x9a, 0x5c, 0x95, 0xca, 0x5a, 0xfe, 0x1f, 0xde, 0x1c, 0xbd, 0x65, 0x5d, 0x94, 0xe9, 0xca,
-	0xcc, 0xe3, 0x57, 0xac, 0x83, 0x36, 0xb0, 0x20, 0x50, 0xeb, 0x3c, 0xba, 0x3c, 0x1b, 0x37, 0xc2,
-	0x8e, 0x77, 0x94, 0x1c, 0x5d, 0xb0, 0x68, 0x75, 0x6b, 0xaa, 0x6c, 0x15, 0x3f, 0xdb, 0xbe, 0x39,
-	0xdc, 0xb9, 0x39, 0x12, 0x2c, 0xc4, 0xdf, 0x1b, 0x27, 0x9c, 0xc5, 0xe6, 0x91, 0xb2, 0x4c, 0x59,
-	0x72, 0x42, 0x07, 0xd9, 0xc8, 0x35, 0x34, 0x84, 0x56, 0x08, 0x88, 0xde, 0xac, 0x24, 0x07, 0xd4,
-	0x40, 0x3c, 0x92, 0xfe, 0x04, 0xc4, 0xc3, 0x9c, 0x0c, 0x10, 0x90, 0x01, 0x9c, 0x94, 0xea, 0x81,
-	0x0c, 0x10, 0x8c, 0x7e, 0x05, 0xac, 0x8f, 0x35, 0xb0, 0x1f, 0x5d, 0x5a, 0x85, 0x59, 0xef, 0x8c,
-	0x2e, 0xd6, 0x6e, 0xb3, 0xea, 0xdb, 0xca, 0x6d, 0x40, 0x69, 0x2d, 0x1a, 0x89, 0x50, 0x7b, 0x0d,
-	0xa8, 0xa9, 0x63, 0x28, 0x74, 0x9b, 0x42, 0x8d, 0xb6, 0xe4, 0x68, 0xaa, 0x1b, 0xc6, 0xff, 0xb3,
-	0xc0, 0x3d, 0x52, 0xcd, 0xed, 0x77, 0xde, 0x82, 0x1e, 0xf8, 0xd1, 0x3c, 0x92, 0x01, 0x9f, 0xf8,
-	0x08, 0x2d, 0x18, 0x57, 0xfb, 0x91, 0x5a, 0x80, 0xac, 0x33, 0x95, 0x64, 0x15, 0xc8, 0x1e, 0x6e,
-	0xb7, 0x45, 0x31, 0xe6, 0xdb, 0x22, 0xdf, 0xba, 0x65, 0xa5, 0xc8, 0x9a, 0xeb, 0x50, 0x0a, 0xa9,
-	0xc9, 0x9f, 0x83, 0xd5, 0x64, 0x0c, 0x80, 0xfc, 0x01, 0x12, 0xe3, 0xac, 0x2e, 0xf3, 0x25, 0x38,
-	0x14, 0xf1, 0x4b, 0x16, 0x3a, 0x2b, 0x4a, 0x49, 0xc3, 0x32, 0x24, 0x7d, 0x4e, 0x36, 0x5b, 0xb3,
-	0x57, 0xa5, 0xbc, 0x82, 0x6f, 0xa3, 0x17, 0x2c, 0x6c, 0x00, 0x66, 0xcc, 0xaa, 0x15, 0x77, 0xf0,
-	0xdf, 0xd5, 0xea, 0x0c, 0x46, 0xbf, 0x03, 0xd6, 0x46, 0x9a, 0x91, 0x5e, 0xea, 0x69, 0x6f, 0xc7,
-	0xe0, 0x81, 0x77, 0x30, 0x4e, 0x7a, 0x6b, 0x73, 0xd2, 0x6b, 0x52, 0xc1, 0x2a, 0x60, 0x41, 0x98,
-	0xb3, 0xce, 0xd3, 0x2c, 0xc1, 0xeb, 0xd0, 0x4a, 0x89, 0x05, 0x9b, 0x11, 0xcd, 0x1b, 0x5c, 0x91,
-	0x5c, 0x5d, 0x8a, 0x6d, 0xcc, 0x4c, 0xcf, 0x17, 0x41, 0xe5, 0x6a, 0x26, 0xfd, 0xf4, 0x32, 0x7f,
-	0xd6, 0x4f, 0x4b, 0xe4, 0xf7, 0xca, 0x5d, 0x2e, 0xc0, 0xae, 0x7d, 0xa0, 0x23, 0x8c, 0x5f, 0xa3,
-	0xf4, 0xb5, 0x6b, 0x2c, 0x91, 0x18, 0x5d, 0x9e, 0xee, 0x38, 0xb8, 0x71, 0xd5, 0x39, 0x63, 0xd0,
-	0xbe, 0xcb, 0xac, 0xcb, 0x52, 0x4b, 0x3b, 0x60, 0x9b, 0xce, 0xb5, 0xcd, 0xa1, 0x88, 0x32, 0x46,
-	0x9b, 0xf5, 0x4e, 0xc0, 0xbd, 0x99, 0x69, 0xbf, 0x13, 0x9a, 0x95, 0x27, 0x13, 0xf2, 0xe9, 0x91,
-	0xe7, 0x1a, 0x9e, 0x84, 0x7b, 0xa1, 0xf5, 0x3e, 0xfa, 0xb2, 0x5e, 0xc5, 0xb3, 0x7d, 0x32, 0xe1,
-	0x9b, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x69, 0x3d, 0xa3, 0xaa, 0xd9, 0x05, 0x00, 0x00,
+var fileDescriptor1 = []byte{
+	// 795 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4d, 0x6f, 0x23, 0x45,
+	0x10, 0x95, 0x67, 0xfc, 0x35, 0x35, 0x76, 0x6c, 0xcf, 0x06, 0xb6, 0x59, 0xa4, 0x95, 0xe5, 0x0b,
+	0x06, 0x69, 0x13, 0x69, 0xe1, 0xc2, 0x31, 0x48, 0x70, 0x63, 0x85, 0x92, 0xe5, 0xc2, 0x65, 0xd4,
+	0x99, 0xae, 0x38, 0x2d, 0x3c, 0xd3, 0x43, 0x57, 0x39, 0x1b, 0xf3, 0x7f, 0xf8, 0x6f, 0xf0, 0x2f,
+	0x50, 0xd7, 0xb8, 0xed, 0x4d, 0xb4, 0x97, 0x3d, 0xd9, 0xf5, 0xa6, 0xbb, 0xaa, 0xfa, 0xbd, 0x57,
+	0x05, 0x3f, 0x6e, 0x2c, 0xdf, 0xef, 0x6e, 0x2f, 0x2a, 0x57, 0x5f, 0xbe, 0xfb, 0xf9, 0xf7, 0x37,
+	0x37, 0xef, 0x6e, 0x2e, 0xaf, 0xf1, 0x01, 0x3d, 0xe1, 0x7b, 0xaf, 0x2b, 0xf4, 0x6e, 0xc7, 0x78,
+	0x69, 0x34, 0xeb, 0xda, 0x19, 0xdc, 0x5e, 0xb6, 0xb6, 0xd9, 0x5c, 0xb4, 0xde, 0xb1, 0x2b, 0xb2,
+	0x23, 0xfa, 0xea, 0x73, 0xb3, 0xb0, 0xad, 0xb1, 0xcb, 0xb2, 0xfa, 0x27, 0x85, 0xd9, 0x6f, 0xb6,
+	0xd9, 0xfc, 0x8a, 0x9a, 0x76, 0x1e, 0x6b, 0x6c, 0xb8, 0xc8, 0x21, 0x25, 0x5f, 0xa9, 0xde, 0xb2,
+	0xb7, 0x9e, 0x86, 0xc0, 0x10, 0xab, 0x44, 0x82, 0x73, 0x98, 0x50, 0xeb, 0xdc, 0x1d, 0xfa, 0x52,
+	0x1b, 0xe3, 0x55, 0x2a, 0xe8, 0x14, 0x06, 0x82, 0xaa, 0xfe, 0xb2, 0xb7, 0x1e, 0x17, 0x00, 0xc9,
+	0xf5, 0xb5, 0x1a, 0xc8, 0xff, 0x33, 0x18, 0x52, 0x77, 0x74, 0xb8, 0xec, 0xad, 0xb3, 0x62, 0x06,
+	0xa3, 0x56, 0xef, 0xb7, 0x4e, 0x1b, 0x35, 0x12, 0x60, 0x0a, 0x83, 0xca, 0xed, 0x1a, 0x56, 0x63,
+	0x09, 0xe7, 0x30, 0xb6, 0x55, 0xdd, 0x96, 0xb4, 0xab, 0x55, 0x16, 0x0f, 0x98, 0xd6, 0x79, 0x56,
+	0x10, 0x43, 0x92, 0x30, 0x97, 0x70, 0x02, 0xfd, 0x0f, 0xda, 0xb2, 0x9a, 0x48, 0x94, 0x43, 0xca,
+	0xbc, 0x55, 0xd3, 0x18, 0xd4, 0xbc, 0x53, 0x67, 0x12, 0xbc, 0x80, 0xdc, 0x63, 0xbb, 0xdd, 0x97,
+	0x5d, 0xb1, 0xd9, 0xa9, 0x19, 0x66, 0xf4, 0x8d, 0x9a, 0x0b, 0x70, 0x06, 0xc3, 0x1a, 0xf9, 0xde,
+	0x19, 0xb5, 0x88, 0xd9, 0xc9, 0xfe, 0x8d, 0xaa, 0x88, 0xc7, 0x77, 0x84, 0xbe, 0xb4, 0x46, 0xbd,
+	0x38, 0x96, 0x73, 0xa4, 0xce, 0x25, 0x28, 0x00, 0x02, 0xad, 0x25, 0xb1, 0xae, 0x5b, 0xf5, 0x45,
+	0xbc, 0x11, 0x30, 0xb7, 0x63, 0xf5, 0xe5, 0xb2, 0xb7, 0x4e, 0x43, 0x1b, 0xd5, 0x3d, 0x56, 0x7f,
+	0x96, 0x95, 0xae, 0xee, 0x51, 0xbd, 0x14, 0x8e, 0xe6, 0x30, 0xee, 0x40, 0x73, 0xab, 0x94, 0x20,
+	0x0b, 0xc8, 0x88, 0xf5, 0x16, 0x1b, 0x24, 0x52, 0x5f, 0x85, 0x9b, 0xab, 0x5f, 0x60, 0x14, 0x64,
+	0xba, 0xf2, 0x9b, 0xe2, 0x5b, 0x18, 0x04, 0x1b, 0x90, 0xea, 0x2d, 0xd3, 0x75, 0xfe, 0xf6, 0xd5,
+	0xc5, 0x51, 0xd8, 0x8b, 0xe7, 0x4a, 0xce, 0x61, 0xdc, 0x7a, 0xeb, 0xbc, 0xe5, 0x7d, 0xa7, 0xe0,
+	0xea, 0x0d, 0xe4, 0x87, 0x3c, 0xd7, 0x48, 0x6d, 0xf1, 0xfa, 0x69, 0xae, 0xd9, 0xb3, 0x5c, 0x2b,
+	0x0d, 0x59, 0xf8, 0xbd, 0x61, 0xcd, 0x14, 0x9e, 0x13, 0x48, 0xb4, 0x48, 0xe2, 0x8d, 0x41, 0xe0,
+	0x67, 0xeb, 0x88, 0x24, 0x75, 0x22, 0x84, 0xdb, 0x46, 0xa5, 0xc7, 0x40, 0x3f, 0xaa, 0x7e, 0x0c,
+	0xf4, 0xc3, 0x46, 0x2c, 0x91, 0x88, 0x25, 0xd8, 0x18, 0x7c, 0x10, 0x4b, 0x24, 0xab, 0x7f, 0x13,
+	0x98, 0x84, 0x1a, 0xa1, 0x1f, 0xd7, 0x10, 0x86, 0xac, 0x77, 0xde, 0xd5, 0x27, 0xff, 0x11, 0xfe,
+	0x75, 0xf0, 0x5f, 0x01, 0xd0, 0xc9, 0x28, 0xb2, 0x74, 0xee, 0x5b, 0x40, 0xd6, 0x61, 0x41, 0xfa,
+	0xbe, 0x40, 0x47, 0xb5, 0xc5, 0xe3, 0x52, 0x37, 0x2b, 0xbe, 0x86, 0x84, 0x1f, 0xa5, 0xe6, 0xd3,
+	0x77, 0xbe, 0xb7, 0x35, 0x86, 0x8f, 0xfe, 0x51, 0x8d, 0x3e, 0xfd, 0x31, 0x87, 0xd4, 0x73, 0xe7,
+	0x50, 0x69, 0xa1, 0xf5, 0xee, 0x16, 0x4b, 0xdb, 0x5a, 0xa3, 0xb2, 0x88, 0x75, 0xf5, 0x04, 0x83,
+	0xd8, 0x96, 0x38, 0x99, 0xf7, 0x2d, 0xaa, 0xfc, 0x09, 0x54, 0x39, 0x83, 0xe2, 0xd8, 0xe9, 0x61,
+	0x56, 0xa6, 0xcb, 0x74, 0x3d, 0x0d, 0xc4, 0x30, 0xb9, 0x66, 0xbb, 0x57, 0x67, 0x12, 0x7f, 0x03,
+	0x19, 0x93, 0x6e, 0x8c, 0x8c, 0xcf, 0x4c, 0xf4, 0x39, 0xff, 0xb8, 0x35, 0xba, 0x6a, 0xcc, 0x95,
+	0x31, 0x3e, 0x3c, 0x97, 0xa9, 0x74, 0x0f, 0xe8, 0xef, 0xb6, 0xee, 0x83, 0x9a, 0xc7, 0x9e, 0x98,
+	0xca, 0xd6, 0xd9, 0x86, 0xd1, 0x8b, 0x9f, 0xa7, 0xab, 0x1f, 0x20, 0x3b, 0xdd, 0x02, 0x48, 0x6c,
+	0x7b, 0x20, 0x19, 0x20, 0x61, 0x3a, 0x70, 0x3c, 0x83, 0x91, 0x78, 0x18, 0x8d, 0x10, 0x3c, 0x5e,
+	0xfd, 0x97, 0x40, 0x3f, 0x08, 0x14, 0x84, 0x91, 0xd7, 0xf4, 0x9e, 0x0d, 0x4b, 0x12, 0xa7, 0x21,
+	0x6c, 0x8d, 0xf4, 0xe3, 0xad, 0xd1, 0xc9, 0xf1, 0x1a, 0x06, 0xc4, 0xda, 0xb3, 0x1a, 0x7c, 0x9a,
+	0xdf, 0x05, 0x64, 0xc1, 0x84, 0x25, 0x61, 0xc3, 0x6a, 0x18, 0xbb, 0xef, 0x58, 0x16, 0xa1, 0x47,
+	0xb1, 0xb1, 0x38, 0x7f, 0xe3, 0x58, 0x24, 0x68, 0xde, 0x69, 0x10, 0x37, 0x01, 0xc4, 0xb3, 0x71,
+	0xf2, 0xf2, 0xb8, 0xa3, 0xee, 0xb6, 0x7a, 0x43, 0x6a, 0xb2, 0x4c, 0xd7, 0x59, 0xf1, 0x5d, 0x30,
+	0x4d, 0xe7, 0x37, 0x12, 0xfa, 0xf3, 0xb7, 0x2f, 0x9f, 0x79, 0xff, 0xe8, 0xc7, 0x35, 0x00, 0xb1,
+	0x66, 0x4b, 0x6c, 0x2b, 0x92, 0x7d, 0xf2, 0x54, 0x88, 0xd3, 0x80, 0x4c, 0x61, 0x80, 0xde, 0x3b,
+	0x7f, 0xda, 0x2f, 0x61, 0x07, 0x5b, 0x17, 0xf7, 0xcb, 0x71, 0x7d, 0x9a, 0x52, 0x1c, 0xbe, 0x88,
+	0xe4, 0x5b, 0x23, 0x3b, 0x26, 0xfd, 0x29, 0xff, 0xe3, 0xb4, 0xd6, 0x6f, 0x87, 0x62, 0xdf, 0xef,
+	0xff, 0x1f, 0x00, 0xe5, 0x36, 0xdd, 0x6e, 0x25, 0x06, 0x00, 0x00,
 }
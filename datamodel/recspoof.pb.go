@@ -35,7 +35,7 @@ var TSType_value = map[string]int32{
 func (x TSType) String() string {
 	return proto.EnumName(TSType_name, int32(x))
 }
-func (TSType) EnumDescriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+func (TSType) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
 type RecSpoof struct {
 	Spoofs []*Spoof `protobuf:"bytes,1,rep,name=spoofs" json:"spoofs,omitempty"`
@@ -44,7 +44,7 @@ type RecSpoof struct {
 func (m *RecSpoof) Reset()                    { *m = RecSpoof{} }
 func (m *RecSpoof) String() string            { return proto.CompactTextString(m) }
 func (*RecSpoof) ProtoMessage()               {}
-func (*RecSpoof) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+func (*RecSpoof) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
 func (m *RecSpoof) GetSpoofs() []*Spoof {
 	if m != nil {
@@ -63,7 +63,7 @@ type Spoof struct {
 func (m *Spoof) Reset()                    { *m = Spoof{} }
 func (m *Spoof) String() string            { return proto.CompactTextString(m) }
 func (*Spoof) ProtoMessage()               {}
-func (*Spoof) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+func (*Spoof) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
 
 type SpoofedProbes struct {
 	Probes []*Probe `protobuf:"bytes,1,rep,name=probes" json:"probes,omitempty"`
@@ -72,7 +72,7 @@ type SpoofedProbes struct {
 func (m *SpoofedProbes) Reset()                    { *m = SpoofedProbes{} }
 func (m *SpoofedProbes) String() string            { return proto.CompactTextString(m) }
 func (*SpoofedProbes) ProtoMessage()               {}
-func (*SpoofedProbes) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+func (*SpoofedProbes) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
 
 func (m *SpoofedProbes) GetProbes() []*Probe {
 	if m != nil {
@@ -87,7 +87,7 @@ type SpoofedProbesResponse struct {
 func (m *SpoofedProbesResponse) Reset()                    { *m = SpoofedProbesResponse{} }
 func (m *SpoofedProbesResponse) String() string            { return proto.CompactTextString(m) }
 func (*SpoofedProbesResponse) ProtoMessage()               {}
-func (*SpoofedProbesResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+func (*SpoofedProbesResponse) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
 
 type Probe struct {
 	SpooferIp uint32       `protobuf:"varint,1,opt,name=spoofer_ip" json:"spoofer_ip,omitempty"`
@@ -104,7 +104,7 @@ type Probe struct {
 func (m *Probe) Reset()                    { *m = Probe{} }
 func (m *Probe) String() string            { return proto.CompactTextString(m) }
 func (*Probe) ProtoMessage()               {}
-func (*Probe) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*Probe) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
 
 func (m *Probe) GetRR() *RecordRoute {
 	if m != nil {
@@ -127,17 +127,21 @@ type RecordRoute struct {
 func (m *RecordRoute) Reset()                    { *m = RecordRoute{} }
 func (m *RecordRoute) String() string            { return proto.CompactTextString(m) }
 func (*RecordRoute) ProtoMessage()               {}
-func (*RecordRoute) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+func (*RecordRoute) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5} }
 
 type TimeStamp struct {
 	Type   TSType   `protobuf:"varint,1,opt,name=type,enum=datamodel.TSType" json:"type,omitempty"`
 	Stamps []*Stamp `protobuf:"bytes,2,rep,name=stamps" json:"stamps,omitempty"`
+	// Count of hops that couldn't stamp because the option was full
+	Overflow uint32 `protobuf:"varint,3,opt,name=overflow" json:"overflow,omitempty"`
+	// The option pointer as received, 0 if unknown
+	Pointer uint32 `protobuf:"varint,4,opt,name=pointer" json:"pointer,omitempty"`
 }
 
 func (m *TimeStamp) Reset()                    { *m = TimeStamp{} }
 func (m *TimeStamp) String() string            { return proto.CompactTextString(m) }
 func (*TimeStamp) ProtoMessage()               {}
-func (*TimeStamp) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+func (*TimeStamp) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{6} }
 
 func (m *TimeStamp) GetStamps() []*Stamp {
 	if m != nil {
@@ -149,12 +153,15 @@ func (m *TimeStamp) GetStamps() []*Stamp {
 type Stamp struct {
 	Time uint32 `protobuf:"varint,1,opt,name=time" json:"time,omitempty"`
 	Ip   uint32 `protobuf:"varint,2,opt,name=ip" json:"ip,omitempty"`
+	// Set if a hop filled in the slot. Prespecified addresses
+	// which did not stamp are reported with this unset
+	Stamped bool `protobuf:"varint,3,opt,name=stamped" json:"stamped,omitempty"`
 }
 
 func (m *Stamp) Reset()                    { *m = Stamp{} }
 func (m *Stamp) String() string            { return proto.CompactTextString(m) }
 func (*Stamp) ProtoMessage()               {}
-func (*Stamp) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
+func (*Stamp) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{7} }
 
 type NotifyRecSpoofResponse struct {
 	Error string `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
@@ -163,15 +170,20 @@ type NotifyRecSpoofResponse struct {
 func (m *NotifyRecSpoofResponse) Reset()                    { *m = NotifyRecSpoofResponse{} }
 func (m *NotifyRecSpoofResponse) String() string            { return proto.CompactTextString(m) }
 func (*NotifyRecSpoofResponse) ProtoMessage()               {}
-func (*NotifyRecSpoofResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
+func (*NotifyRecSpoofResponse) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{8} }
 
 type ReceiveSpoofedProbesResponse struct {
+	Acked []uint32 `protobuf:"varint,1,rep,packed,name=acked" json:"acked,omitempty"`
+	// Set by controllers which only ack the probes they handled.
+	// Older controllers leave it and acked unset, they received
+	// every probe of a stream that closed without error
+	PerProbe bool `protobuf:"varint,2,opt,name=per_probe" json:"per_probe,omitempty"`
 }
 
 func (m *ReceiveSpoofedProbesResponse) Reset()                    { *m = ReceiveSpoofedProbesResponse{} }
 func (m *ReceiveSpoofedProbesResponse) String() string            { return proto.CompactTextString(m) }
 func (*ReceiveSpoofedProbesResponse) ProtoMessage()               {}
-func (*ReceiveSpoofedProbesResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{9} }
+func (*ReceiveSpoofedProbesResponse) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{9} }
 
 func init() {
 	proto.RegisterType((*RecSpoof)(nil), "datamodel.RecSpoof")
@@ -188,36 +200,39 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/recspoof.proto", fileDescriptor1)
-}
-
-var fileDescriptor1 = []byte{
-	// 431 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x52, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xc5, 0x71, 0x92, 0xc6, 0x63, 0x02, 0xae, 0x05, 0xc5, 0x07, 0x44, 0xcb, 0x72, 0xa0, 0x42,
-	0x90, 0x88, 0x70, 0xe2, 0xc0, 0xa1, 0x95, 0xb8, 0x86, 0x2a, 0x0e, 0x17, 0x2e, 0x51, 0xe2, 0x9d,
-	0x52, 0x4b, 0x8d, 0x77, 0x99, 0xdd, 0x54, 0xca, 0xcf, 0xf1, 0x6d, 0x8c, 0xc7, 0x24, 0x75, 0xd5,
-	0xdc, 0x66, 0x76, 0xde, 0xbe, 0x79, 0xf3, 0x66, 0xe0, 0xdb, 0xef, 0xd2, 0xdf, 0x6c, 0x56, 0xa3,
-	0xc2, 0xac, 0xc7, 0xd3, 0xef, 0x3f, 0x3f, 0xe5, 0xd3, 0x7c, 0x3c, 0xc3, 0x3b, 0x24, 0x87, 0x73,
-	0x5a, 0x16, 0x48, 0x66, 0xe3, 0x71, 0xac, 0x97, 0x7e, 0xb9, 0x36, 0x1a, 0x6f, 0xc7, 0x84, 0x85,
-	0xb3, 0xc6, 0x5c, 0x8f, 0x2c, 0x19, 0x6f, 0xd2, 0x68, 0x5f, 0x51, 0x1f, 0x61, 0x30, 0xc3, 0x22,
-	0xaf, 0x8b, 0xe9, 0x19, 0xf4, 0x05, 0xe5, 0xb2, 0xe0, 0x2c, 0x3c, 0x8f, 0x27, 0xc9, 0x68, 0x8f,
-	0x1b, 0x09, 0x42, 0x7d, 0x85, 0x5e, 0x03, 0x05, 0xe8, 0x94, 0x96, 0x61, 0xc1, 0xf9, 0x50, 0x62,
-	0x9d, 0x75, 0x24, 0x8e, 0x21, 0x74, 0x5c, 0x08, 0x77, 0x89, 0x76, 0x3e, 0xeb, 0xd6, 0x89, 0xfa,
-	0x0c, 0x43, 0xf9, 0x8a, 0xfa, 0x8a, 0xcc, 0x0a, 0x5d, 0xdd, 0xcd, 0x4a, 0x74, 0xa0, 0x9b, 0x40,
-	0xd4, 0x2b, 0x78, 0xf9, 0xe0, 0xcb, 0x0c, 0x59, 0x5e, 0xe5, 0x50, 0xfd, 0x0d, 0xa0, 0x27, 0x4f,
-	0x29, 0x37, 0x17, 0xc9, 0x48, 0x8b, 0xbd, 0x9e, 0x04, 0x06, 0x42, 0xbc, 0x78, 0xa0, 0x8a, 0x8a,
-	0x46, 0xc8, 0x4e, 0x55, 0xaf, 0xa5, 0xbd, 0x2f, 0xf1, 0x73, 0x38, 0x72, 0xf8, 0x67, 0x51, 0x6d,
-	0xd6, 0xd9, 0x91, 0x3c, 0xbc, 0x83, 0x90, 0x16, 0x94, 0x0d, 0x38, 0x89, 0x27, 0x27, 0x2d, 0x79,
-	0xec, 0x98, 0x21, 0x3d, 0xab, 0x4d, 0xe6, 0x31, 0x3a, 0xde, 0x65, 0x91, 0x60, 0x5e, 0xb4, 0x30,
-	0xf3, 0x72, 0x8d, 0x39, 0x27, 0x36, 0x3d, 0x86, 0xc8, 0x61, 0xa5, 0x1b, 0x89, 0x20, 0x66, 0x9c,
-	0x42, 0xdc, 0xe6, 0x48, 0xa0, 0x7b, 0x63, 0x6c, 0x63, 0xc4, 0xf0, 0xb2, 0x93, 0x04, 0x6a, 0x0a,
-	0xd1, 0x3d, 0xc1, 0x29, 0x74, 0xfd, 0xd6, 0xa2, 0x8c, 0xf7, 0x6c, 0x72, 0xdc, 0x6e, 0x92, 0xcf,
-	0xb9, 0x20, 0x8b, 0xab, 0x91, 0x8e, 0xe7, 0x7d, 0xb4, 0xb8, 0xba, 0xa0, 0xde, 0xf2, 0xe2, 0x84,
-	0xeb, 0x29, 0x73, 0x31, 0x71, 0x6b, 0x75, 0xb6, 0x31, 0x49, 0xbd, 0x87, 0x93, 0xa9, 0xf1, 0xe5,
-	0xf5, 0x76, 0x77, 0x0f, 0x3b, 0xbb, 0xd3, 0x21, 0xf4, 0x90, 0xc8, 0x90, 0x7c, 0x8a, 0xd4, 0x1b,
-	0x78, 0xcd, 0x10, 0x2c, 0xef, 0xf0, 0xe0, 0x76, 0x3e, 0x4c, 0xa0, 0xff, 0x5f, 0x17, 0xd4, 0xd1,
-	0x8f, 0xea, 0x76, 0x9b, 0x3c, 0x61, 0x92, 0x68, 0x9e, 0x5f, 0x54, 0xfa, 0x42, 0x6b, 0x4a, 0x82,
-	0x26, 0xbd, 0x22, 0xa6, 0xc0, 0x22, 0x09, 0x2f, 0xe3, 0x5f, 0xf7, 0x37, 0xb9, 0xea, 0xcb, 0x95,
-	0x7e, 0xf9, 0x17, 0x00, 0x00, 0xff, 0xff, 0x75, 0xce, 0xb2, 0xcb, 0xe6, 0x02, 0x00, 0x00,
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/recspoof.proto", fileDescriptor2)
+}
+
+var fileDescriptor2 = []byte{
+	// 476 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xc5, 0x4e, 0x9c, 0xd8, 0x13, 0x42, 0x1d, 0x0b, 0xca, 0x1e, 0x90, 0x6a, 0x99, 0x03, 0x11,
+	0x82, 0x44, 0x98, 0x13, 0x07, 0x0e, 0xad, 0xe0, 0x1a, 0x2a, 0x3b, 0x5c, 0xb8, 0x58, 0x8e, 0x77,
+	0x42, 0x2d, 0x62, 0xef, 0x76, 0x77, 0x13, 0x94, 0x3f, 0xc7, 0x6f, 0x43, 0x1e, 0xe7, 0xc3, 0x55,
+	0x7b, 0x9b, 0xd9, 0x7d, 0xfb, 0xe6, 0xed, 0x7b, 0x03, 0x5f, 0x7f, 0x97, 0xe6, 0x6e, 0xbb, 0x9a,
+	0x15, 0xa2, 0x9a, 0x2f, 0xbe, 0xff, 0xfc, 0x98, 0x2e, 0xd2, 0x79, 0x82, 0x3b, 0x54, 0x1a, 0x97,
+	0x2a, 0x2f, 0x50, 0x89, 0xad, 0xc1, 0x39, 0xcf, 0x4d, 0x5e, 0x09, 0x8e, 0x9b, 0xb9, 0xc2, 0x42,
+	0x4b, 0x21, 0xd6, 0x33, 0xa9, 0x84, 0x11, 0x81, 0x77, 0xba, 0x89, 0x3e, 0x80, 0x9b, 0x60, 0x91,
+	0x36, 0x97, 0x41, 0x08, 0x03, 0x42, 0x69, 0x66, 0x85, 0xbd, 0xe9, 0x28, 0xf6, 0x67, 0x27, 0xdc,
+	0x8c, 0x10, 0xd1, 0x17, 0x70, 0x5a, 0x28, 0x80, 0x5d, 0x4a, 0x66, 0x85, 0xd6, 0x74, 0x4c, 0x35,
+	0x67, 0x36, 0xd5, 0x23, 0xe8, 0xe9, 0x52, 0xb2, 0xde, 0xb1, 0xe1, 0xda, 0xb0, 0x7e, 0xd3, 0x44,
+	0x9f, 0x60, 0x4c, 0x4f, 0x91, 0xdf, 0x2a, 0xb1, 0x42, 0xdd, 0x4c, 0x93, 0x54, 0x3d, 0x31, 0x8d,
+	0x20, 0xd1, 0x6b, 0x78, 0xf5, 0xe0, 0x49, 0x82, 0x5a, 0x8a, 0x5a, 0x63, 0xf4, 0xcf, 0x02, 0x87,
+	0x8e, 0x82, 0x00, 0x80, 0x24, 0xa3, 0xca, 0x4e, 0x7a, 0x7c, 0x70, 0x89, 0x38, 0x7b, 0xa0, 0x4a,
+	0x15, 0xac, 0x7f, 0x6c, 0x1a, 0x55, 0x4e, 0x47, 0xfb, 0x80, 0xea, 0x0b, 0x18, 0x6a, 0xbc, 0xcf,
+	0xea, 0x6d, 0xc5, 0x86, 0x74, 0xf0, 0x16, 0x7a, 0x2a, 0x53, 0xcc, 0x0d, 0xad, 0xe9, 0x28, 0xbe,
+	0xec, 0xc8, 0x4b, 0xb0, 0x10, 0x8a, 0x27, 0x8d, 0xc9, 0x41, 0x08, 0xb6, 0xd1, 0xcc, 0x23, 0xcc,
+	0xcb, 0x0e, 0x66, 0x59, 0x56, 0x98, 0x9a, 0xbc, 0x92, 0xc1, 0x04, 0x3c, 0x8d, 0x35, 0x6f, 0x25,
+	0x02, 0x99, 0x71, 0x05, 0xa3, 0x2e, 0x87, 0x0f, 0xfd, 0x3b, 0x21, 0x5b, 0x23, 0xc6, 0x37, 0xb6,
+	0x6f, 0x45, 0xf7, 0xe0, 0x9d, 0x09, 0xae, 0xa0, 0x6f, 0xf6, 0x12, 0xe9, 0x7b, 0x2f, 0xe2, 0x49,
+	0x77, 0x48, 0xba, 0xdc, 0x4b, 0xa4, 0xe0, 0x1a, 0xa4, 0x66, 0xf6, 0xe3, 0xe0, 0x88, 0xc2, 0x07,
+	0x57, 0xec, 0x50, 0xad, 0x37, 0xe2, 0xef, 0x21, 0x9c, 0x0b, 0x18, 0x4a, 0x51, 0xd6, 0x06, 0xd5,
+	0x21, 0xa0, 0x18, 0x9c, 0x16, 0xfb, 0x1c, 0xfa, 0xa6, 0xac, 0xb0, 0x93, 0xae, 0x64, 0xf6, 0xc9,
+	0xa1, 0x06, 0x82, 0x9c, 0x48, 0xdc, 0xe8, 0x1d, 0x5c, 0x2e, 0x84, 0x29, 0xd7, 0xfb, 0xe3, 0x0e,
+	0x1d, 0x23, 0x0a, 0xc6, 0xe0, 0xa0, 0x52, 0x42, 0x11, 0x8b, 0x17, 0x7d, 0x83, 0x37, 0x09, 0x16,
+	0x58, 0xee, 0xf0, 0xc9, 0x44, 0x83, 0x09, 0x38, 0x79, 0xf1, 0x07, 0xf9, 0xd9, 0x82, 0xc6, 0x36,
+	0x89, 0x2a, 0xa3, 0x28, 0x69, 0xbe, 0xfb, 0x3e, 0x86, 0xc1, 0xe1, 0xc7, 0xd0, 0x54, 0x3f, 0xea,
+	0xcd, 0xde, 0x7f, 0x16, 0x8c, 0xc1, 0x5b, 0xa6, 0xd7, 0x35, 0xbf, 0xe6, 0x5c, 0xf9, 0x56, 0xdb,
+	0xde, 0x2a, 0x4c, 0x25, 0x16, 0x7e, 0xef, 0x66, 0xf4, 0xeb, 0xbc, 0xed, 0xab, 0x01, 0xed, 0xff,
+	0xe7, 0xff, 0x03, 0x00, 0xce, 0x5c, 0x91, 0x33, 0x40, 0x03, 0x00, 0x00,
 }
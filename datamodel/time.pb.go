@@ -21,7 +21,7 @@ type Time struct {
 func (m *Time) Reset()                    { *m = Time{} }
 func (m *Time) String() string            { return proto.CompactTextString(m) }
 func (*Time) ProtoMessage()               {}
-func (*Time) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
+func (*Time) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
 
 type RTT struct {
 	Sec  int64 `protobuf:"varint,1,opt,name=sec" json:"sec,omitempty"`
@@ -31,7 +31,7 @@ type RTT struct {
 func (m *RTT) Reset()                    { *m = RTT{} }
 func (m *RTT) String() string            { return proto.CompactTextString(m) }
 func (*RTT) ProtoMessage()               {}
-func (*RTT) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+func (*RTT) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
 
 func init() {
 	proto.RegisterType((*Time)(nil), "datamodel.Time")
@@ -39,18 +39,18 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/time.proto", fileDescriptor2)
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/time.proto", fileDescriptor3)
 }
 
-var fileDescriptor2 = []byte{
-	// 136 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xb2, 0x4c, 0xcf, 0x2c, 0xc9,
+var fileDescriptor3 = []byte{
+	// 134 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x4c, 0xcf, 0x2c, 0xc9,
 	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xf7, 0x73, 0x0d, 0xd5, 0x0d, 0xf6, 0x0b, 0xd6, 0x0f,
 	0x4a, 0x2d, 0x4b, 0x2d, 0x2a, 0x4e, 0x0d, 0x29, 0x4a, 0x4c, 0x4e, 0x2d, 0xca, 0x2f, 0x2d, 0x49,
 	0xd5, 0x4f, 0x49, 0x2c, 0x49, 0xcc, 0xcd, 0x4f, 0x49, 0xcd, 0xd1, 0x2f, 0xc9, 0xcc, 0x4d, 0xd5,
-	0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x84, 0x8b, 0x2a, 0x29, 0x72, 0xb1, 0x84, 0x00, 0x25,
-	0x84, 0xb8, 0xb9, 0x98, 0x8b, 0x53, 0x93, 0x25, 0x18, 0x15, 0x18, 0x35, 0x98, 0x85, 0x78, 0xb8,
-	0x58, 0x4a, 0x41, 0x3c, 0x26, 0x10, 0x4f, 0x49, 0x81, 0x8b, 0x39, 0x28, 0x24, 0x04, 0x8f, 0x0a,
-	0x27, 0xee, 0x28, 0x84, 0x89, 0x49, 0x6c, 0x60, 0x3b, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff,
-	0xcc, 0x9e, 0x2c, 0x21, 0xa0, 0x00, 0x00, 0x00,
+	0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x84, 0x8b, 0x2a, 0x29, 0x72, 0xb1, 0x84, 0x64, 0xe6,
+	0xa6, 0x0a, 0x71, 0x73, 0x31, 0x17, 0xa7, 0x26, 0x4b, 0x30, 0x2a, 0x30, 0x6a, 0x30, 0x0b, 0xf1,
+	0x70, 0xb1, 0x94, 0x82, 0x78, 0x4c, 0x20, 0x9e, 0x92, 0x02, 0x17, 0x73, 0x50, 0x48, 0x08, 0x1e,
+	0x15, 0x4e, 0xdc, 0x51, 0x08, 0x13, 0x93, 0xd8, 0xc0, 0x76, 0x18, 0x03, 0x06, 0x00, 0xcc, 0x9e,
+	0x2c, 0x21, 0xa0, 0x00, 0x00, 0x00,
 }
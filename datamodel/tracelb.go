@@ -0,0 +1,112 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package datamodel
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Key generates a cache key for a Tracelb
+func (t *Tracelb) Key() string {
+	return fmt.Sprintf("%s_%d_%d", "XXTRLB", t.Src, t.Dst)
+}
+
+// CMarshal marshals a tracelb for storing in a cache
+func (t *Tracelb) CMarshal() []byte {
+	ret, err := proto.Marshal(t)
+	if err != nil {
+		return nil
+	}
+	return ret
+}
+
+// CUnmarshal unmarshals a tracelb which is retrieved from a cache
+func (t *Tracelb) CUnmarshal(data []byte) error {
+	return proto.Unmarshal(data, t)
+}
+
+// Paths enumerates the forward paths a tracelb discovered, up to max of them.
+// Each path is the list of interfaces from the first hop onward; a path that
+// ends in an unresponsive hop ends with 0.
+// If max is less than 1 all paths are returned
+func (t *Tracelb) Paths(max int) [][]uint32 {
+	next := make(map[uint32][]uint32)
+	incoming := make(map[uint32]bool)
+	for _, l := range t.GetLinks() {
+		next[l.From] = append(next[l.From], l.To)
+		if l.To != 0 {
+			incoming[l.To] = true
+		}
+	}
+	var ret [][]uint32
+	var walk func(path []uint32, seen map[uint32]bool)
+	walk = func(path []uint32, seen map[uint32]bool) {
+		if max > 0 && len(ret) >= max {
+			return
+		}
+		last := path[len(path)-1]
+		succ := next[last]
+		if last == 0 || len(succ) == 0 {
+			ret = append(ret, append([]uint32(nil), path...))
+			return
+		}
+		for _, s := range succ {
+			if s != 0 && seen[s] {
+				// A loop in the graph ends the path
+				ret = append(ret, append([]uint32(nil), path...))
+				continue
+			}
+			seen[s] = true
+			walk(append(path, s), seen)
+			delete(seen, s)
+		}
+	}
+	for _, n := range t.GetNodes() {
+		if incoming[n.Addr] {
+			continue
+		}
+		walk([]uint32{n.Addr}, map[uint32]bool{n.Addr: true})
+	}
+	return ret
+}
+
+// CMarshal marshals a tracelb measurement for storing in a cache
+func (tm *TracelbMeasurement) CMarshal() []byte {
+	ret, err := proto.Marshal(tm)
+	if err != nil {
+		return nil
+	}
+	return ret
+}
+
+// Key generates a key for storing a tracelb measurement in a cache
+func (tm *TracelbMeasurement) Key() string {
+	return fmt.Sprintf("%s_%d_%d", "XXTRLB", tm.Src, tm.Dst)
+}
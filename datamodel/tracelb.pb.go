@@ -0,0 +1,283 @@
+// Code generated by protoc-gen-go.
+// source: github.com/NEU-SNS/ReverseTraceroute/datamodel/tracelb.proto
+// DO NOT EDIT!
+
+package datamodel
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+type TracelbMeasurement struct {
+	Src         uint32 `protobuf:"varint,1,opt,name=src" json:"src,omitempty"`
+	Dst         uint32 `protobuf:"varint,2,opt,name=dst" json:"dst,omitempty"`
+	Confidence  string `protobuf:"bytes,3,opt,name=confidence" json:"confidence,omitempty"`
+	Dport       string `protobuf:"bytes,4,opt,name=dport" json:"dport,omitempty"`
+	FirstHop    string `protobuf:"bytes,5,opt,name=first_hop" json:"first_hop,omitempty"`
+	GapLimit    string `protobuf:"bytes,6,opt,name=gap_limit" json:"gap_limit,omitempty"`
+	Method      string `protobuf:"bytes,7,opt,name=method" json:"method,omitempty"`
+	Attempts    string `protobuf:"bytes,8,opt,name=attempts" json:"attempts,omitempty"`
+	MaxProbec   string `protobuf:"bytes,9,opt,name=max_probec" json:"max_probec,omitempty"`
+	Sport       string `protobuf:"bytes,10,opt,name=sport" json:"sport,omitempty"`
+	Tos         string `protobuf:"bytes,11,opt,name=tos" json:"tos,omitempty"`
+	UserId      string `protobuf:"bytes,12,opt,name=user_id" json:"user_id,omitempty"`
+	WaitTimeout string `protobuf:"bytes,13,opt,name=wait_timeout" json:"wait_timeout,omitempty"`
+	WaitProbe   string `protobuf:"bytes,14,opt,name=wait_probe" json:"wait_probe,omitempty"`
+	Timeout     int64  `protobuf:"varint,15,opt,name=timeout" json:"timeout,omitempty"`
+}
+
+func (m *TracelbMeasurement) Reset()                    { *m = TracelbMeasurement{} }
+func (m *TracelbMeasurement) String() string            { return proto.CompactTextString(m) }
+func (*TracelbMeasurement) ProtoMessage()               {}
+func (*TracelbMeasurement) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{0} }
+
+type TracelbArg struct {
+	Tracelbs []*TracelbMeasurement `protobuf:"bytes,1,rep,name=tracelbs" json:"tracelbs,omitempty"`
+	Priority uint32                `protobuf:"varint,2,opt,name=priority" json:"priority,omitempty"`
+}
+
+func (m *TracelbArg) Reset()                    { *m = TracelbArg{} }
+func (m *TracelbArg) String() string            { return proto.CompactTextString(m) }
+func (*TracelbArg) ProtoMessage()               {}
+func (*TracelbArg) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{1} }
+
+func (m *TracelbArg) GetTracelbs() []*TracelbMeasurement {
+	if m != nil {
+		return m.Tracelbs
+	}
+	return nil
+}
+
+type TracelbArgResp struct {
+	Tracelbs []*Tracelb `protobuf:"bytes,1,rep,name=tracelbs" json:"tracelbs,omitempty"`
+}
+
+func (m *TracelbArgResp) Reset()                    { *m = TracelbArgResp{} }
+func (m *TracelbArgResp) String() string            { return proto.CompactTextString(m) }
+func (*TracelbArgResp) ProtoMessage()               {}
+func (*TracelbArgResp) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{2} }
+
+func (m *TracelbArgResp) GetTracelbs() []*Tracelb {
+	if m != nil {
+		return m.Tracelbs
+	}
+	return nil
+}
+
+type TracelbReply struct {
+	From      uint32 `protobuf:"varint,1,opt,name=from" json:"from,omitempty"`
+	Rtt       *RTT   `protobuf:"bytes,2,opt,name=rtt" json:"rtt,omitempty"`
+	ReplyTtl  uint32 `protobuf:"varint,3,opt,name=reply_ttl" json:"reply_ttl,omitempty"`
+	ReplyIpid uint32 `protobuf:"varint,4,opt,name=reply_ipid" json:"reply_ipid,omitempty"`
+	IcmpType  uint32 `protobuf:"varint,5,opt,name=icmp_type" json:"icmp_type,omitempty"`
+	IcmpCode  uint32 `protobuf:"varint,6,opt,name=icmp_code" json:"icmp_code,omitempty"`
+	IcmpQTtl  uint32 `protobuf:"varint,7,opt,name=icmp_q_ttl" json:"icmp_q_ttl,omitempty"`
+	IcmpQTos  uint32 `protobuf:"varint,8,opt,name=icmp_q_tos" json:"icmp_q_tos,omitempty"`
+	TcpFlags  uint32 `protobuf:"varint,9,opt,name=tcp_flags" json:"tcp_flags,omitempty"`
+}
+
+func (m *TracelbReply) Reset()                    { *m = TracelbReply{} }
+func (m *TracelbReply) String() string            { return proto.CompactTextString(m) }
+func (*TracelbReply) ProtoMessage()               {}
+func (*TracelbReply) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{3} }
+
+func (m *TracelbReply) GetRtt() *RTT {
+	if m != nil {
+		return m.Rtt
+	}
+	return nil
+}
+
+type TracelbProbe struct {
+	Tx      *TracerouteTime `protobuf:"bytes,1,opt,name=tx" json:"tx,omitempty"`
+	Flowid  uint32          `protobuf:"varint,2,opt,name=flowid" json:"flowid,omitempty"`
+	Ttl     uint32          `protobuf:"varint,3,opt,name=ttl" json:"ttl,omitempty"`
+	Attempt uint32          `protobuf:"varint,4,opt,name=attempt" json:"attempt,omitempty"`
+	Replies []*TracelbReply `protobuf:"bytes,5,rep,name=replies" json:"replies,omitempty"`
+}
+
+func (m *TracelbProbe) Reset()                    { *m = TracelbProbe{} }
+func (m *TracelbProbe) String() string            { return proto.CompactTextString(m) }
+func (*TracelbProbe) ProtoMessage()               {}
+func (*TracelbProbe) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{4} }
+
+func (m *TracelbProbe) GetTx() *TracerouteTime {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *TracelbProbe) GetReplies() []*TracelbReply {
+	if m != nil {
+		return m.Replies
+	}
+	return nil
+}
+
+type TracelbProbeSet struct {
+	Probes []*TracelbProbe `protobuf:"bytes,1,rep,name=probes" json:"probes,omitempty"`
+}
+
+func (m *TracelbProbeSet) Reset()                    { *m = TracelbProbeSet{} }
+func (m *TracelbProbeSet) String() string            { return proto.CompactTextString(m) }
+func (*TracelbProbeSet) ProtoMessage()               {}
+func (*TracelbProbeSet) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{5} }
+
+func (m *TracelbProbeSet) GetProbes() []*TracelbProbe {
+	if m != nil {
+		return m.Probes
+	}
+	return nil
+}
+
+type TracelbNode struct {
+	Addr uint32 `protobuf:"varint,1,opt,name=addr" json:"addr,omitempty"`
+	QTtl uint32 `protobuf:"varint,2,opt,name=q_ttl" json:"q_ttl,omitempty"`
+	Name string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *TracelbNode) Reset()                    { *m = TracelbNode{} }
+func (m *TracelbNode) String() string            { return proto.CompactTextString(m) }
+func (*TracelbNode) ProtoMessage()               {}
+func (*TracelbNode) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{6} }
+
+type TracelbLink struct {
+	From   uint32             `protobuf:"varint,1,opt,name=from" json:"from,omitempty"`
+	To     uint32             `protobuf:"varint,2,opt,name=to" json:"to,omitempty"`
+	Probes []*TracelbProbeSet `protobuf:"bytes,3,rep,name=probes" json:"probes,omitempty"`
+}
+
+func (m *TracelbLink) Reset()                    { *m = TracelbLink{} }
+func (m *TracelbLink) String() string            { return proto.CompactTextString(m) }
+func (*TracelbLink) ProtoMessage()               {}
+func (*TracelbLink) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{7} }
+
+func (m *TracelbLink) GetProbes() []*TracelbProbeSet {
+	if m != nil {
+		return m.Probes
+	}
+	return nil
+}
+
+type Tracelb struct {
+	Type        string          `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	UserId      uint32          `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Method      string          `protobuf:"bytes,3,opt,name=method" json:"method,omitempty"`
+	Src         uint32          `protobuf:"varint,4,opt,name=src" json:"src,omitempty"`
+	Dst         uint32          `protobuf:"varint,5,opt,name=dst" json:"dst,omitempty"`
+	Sport       uint32          `protobuf:"varint,6,opt,name=sport" json:"sport,omitempty"`
+	Dport       uint32          `protobuf:"varint,7,opt,name=dport" json:"dport,omitempty"`
+	Start       *TracerouteTime `protobuf:"bytes,8,opt,name=start" json:"start,omitempty"`
+	ProbeSize   uint32          `protobuf:"varint,9,opt,name=probe_size" json:"probe_size,omitempty"`
+	Firsthop    uint32          `protobuf:"varint,10,opt,name=firsthop" json:"firsthop,omitempty"`
+	WaitTimeout uint32          `protobuf:"varint,11,opt,name=wait_timeout" json:"wait_timeout,omitempty"`
+	WaitProbe   uint32          `protobuf:"varint,12,opt,name=wait_probe" json:"wait_probe,omitempty"`
+	Attempts    uint32          `protobuf:"varint,13,opt,name=attempts" json:"attempts,omitempty"`
+	Confidence  uint32          `protobuf:"varint,14,opt,name=confidence" json:"confidence,omitempty"`
+	Tos         uint32          `protobuf:"varint,15,opt,name=tos" json:"tos,omitempty"`
+	GapLimit    uint32          `protobuf:"varint,16,opt,name=gap_limit" json:"gap_limit,omitempty"`
+	Probec      uint32          `protobuf:"varint,17,opt,name=probec" json:"probec,omitempty"`
+	ProbecMax   uint32          `protobuf:"varint,18,opt,name=probec_max" json:"probec_max,omitempty"`
+	Nodes       []*TracelbNode  `protobuf:"bytes,19,rep,name=nodes" json:"nodes,omitempty"`
+	Links       []*TracelbLink  `protobuf:"bytes,20,rep,name=links" json:"links,omitempty"`
+	Error       string          `protobuf:"bytes,21,opt,name=error" json:"error,omitempty"`
+	Id          int64           `protobuf:"varint,22,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *Tracelb) Reset()                    { *m = Tracelb{} }
+func (m *Tracelb) String() string            { return proto.CompactTextString(m) }
+func (*Tracelb) ProtoMessage()               {}
+func (*Tracelb) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{8} }
+
+func (m *Tracelb) GetStart() *TracerouteTime {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *Tracelb) GetNodes() []*TracelbNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *Tracelb) GetLinks() []*TracelbLink {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*TracelbMeasurement)(nil), "datamodel.TracelbMeasurement")
+	proto.RegisterType((*TracelbArg)(nil), "datamodel.TracelbArg")
+	proto.RegisterType((*TracelbArgResp)(nil), "datamodel.TracelbArgResp")
+	proto.RegisterType((*TracelbReply)(nil), "datamodel.TracelbReply")
+	proto.RegisterType((*TracelbProbe)(nil), "datamodel.TracelbProbe")
+	proto.RegisterType((*TracelbProbeSet)(nil), "datamodel.TracelbProbeSet")
+	proto.RegisterType((*TracelbNode)(nil), "datamodel.TracelbNode")
+	proto.RegisterType((*TracelbLink)(nil), "datamodel.TracelbLink")
+	proto.RegisterType((*Tracelb)(nil), "datamodel.Tracelb")
+}
+
+func init() {
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/tracelb.proto", fileDescriptor4)
+}
+
+var fileDescriptor4 = []byte{
+	// 733 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x55, 0xe2, 0x38, 0x1f, 0xe3, 0x38, 0x69, 0x4d, 0x29, 0x4b, 0x11, 0x52, 0x64, 0x51, 0x11,
+	0x21, 0x91, 0x48, 0x45, 0x42, 0x80, 0x90, 0x10, 0x48, 0xdc, 0xa0, 0xa0, 0x24, 0x5c, 0xb8, 0x58,
+	0x8e, 0x77, 0x93, 0xae, 0x6a, 0x67, 0xcd, 0xee, 0x86, 0xb6, 0xfc, 0x07, 0x8e, 0xfc, 0x1c, 0x0e,
+	0xfc, 0x33, 0xb4, 0x63, 0x6f, 0x12, 0x92, 0x56, 0x08, 0x6e, 0x9d, 0xd7, 0x9d, 0xe7, 0x99, 0xf7,
+	0x66, 0x26, 0xf0, 0x72, 0xce, 0xf5, 0xd9, 0x72, 0x3a, 0x48, 0x44, 0x36, 0x3c, 0x7d, 0xfb, 0xe9,
+	0xf1, 0xf8, 0x74, 0x3c, 0x1c, 0xb1, 0xaf, 0x4c, 0x2a, 0x36, 0x91, 0x71, 0xc2, 0xa4, 0x58, 0x6a,
+	0x36, 0xa4, 0xb1, 0x8e, 0x33, 0x41, 0x59, 0x3a, 0xd4, 0x06, 0x4c, 0xa7, 0x83, 0x5c, 0x0a, 0x2d,
+	0x82, 0xd6, 0xea, 0x1f, 0x47, 0xcf, 0xff, 0x95, 0x88, 0x67, 0xac, 0x60, 0x39, 0x7a, 0xf5, 0x3f,
+	0x35, 0x20, 0x58, 0x10, 0x84, 0x3f, 0xaa, 0x10, 0x4c, 0x8a, 0xc2, 0xde, 0xb3, 0x58, 0x2d, 0x25,
+	0xcb, 0xd8, 0x42, 0x07, 0x1e, 0x38, 0x4a, 0x26, 0xa4, 0xd2, 0xab, 0xf4, 0x7d, 0x13, 0x50, 0xa5,
+	0x49, 0x15, 0x83, 0x00, 0x20, 0x11, 0x8b, 0x19, 0xa7, 0x6c, 0x91, 0x30, 0xe2, 0xf4, 0x2a, 0xfd,
+	0x56, 0xe0, 0x83, 0x4b, 0x73, 0x21, 0x35, 0xa9, 0x61, 0xb8, 0x0f, 0xad, 0x19, 0x97, 0x4a, 0x47,
+	0x67, 0x22, 0x27, 0xae, 0x85, 0xe6, 0x71, 0x1e, 0xa5, 0x3c, 0xe3, 0x9a, 0xd4, 0x11, 0xea, 0x40,
+	0x3d, 0x63, 0xfa, 0x4c, 0x50, 0xd2, 0xc0, 0x78, 0x0f, 0x9a, 0xb1, 0xd6, 0x2c, 0xcb, 0xb5, 0x22,
+	0x4d, 0x44, 0x02, 0x80, 0x2c, 0xbe, 0x8c, 0x72, 0x29, 0xa6, 0x2c, 0x21, 0x2d, 0xfb, 0x29, 0x85,
+	0x9f, 0x02, 0x0c, 0x3d, 0x70, 0xb4, 0x50, 0xc4, 0xc3, 0xa0, 0x0b, 0x8d, 0xa5, 0x62, 0x32, 0xe2,
+	0x94, 0xb4, 0x11, 0x38, 0x80, 0xf6, 0x45, 0xcc, 0x75, 0x64, 0x04, 0x13, 0x4b, 0x4d, 0x7c, 0x4b,
+	0x8b, 0x28, 0xf2, 0x92, 0x8e, 0x4d, 0xb5, 0x8f, 0xba, 0xbd, 0x4a, 0xdf, 0x09, 0x3f, 0x00, 0x94,
+	0xb2, 0xbc, 0x96, 0xf3, 0x60, 0x08, 0xcd, 0xd2, 0x3d, 0x45, 0x2a, 0x3d, 0xa7, 0xef, 0x9d, 0xdc,
+	0x1f, 0xac, 0x44, 0x1d, 0x5c, 0xa3, 0xdf, 0x1e, 0x34, 0x73, 0xc9, 0x85, 0xe4, 0xfa, 0xaa, 0xd0,
+	0x2d, 0x7c, 0x0a, 0x9d, 0x35, 0xe1, 0x88, 0xa9, 0x3c, 0x78, 0xb0, 0x43, 0x1a, 0xec, 0x92, 0x86,
+	0xbf, 0x2a, 0xd0, 0x2e, 0xff, 0x1e, 0xb1, 0x3c, 0xbd, 0x0a, 0xda, 0x50, 0x9b, 0x49, 0x91, 0x95,
+	0xde, 0xdc, 0x03, 0x47, 0xea, 0xc2, 0x1b, 0xef, 0xa4, 0xb3, 0x91, 0x3f, 0x9a, 0x4c, 0x8c, 0xea,
+	0xd2, 0xe4, 0x44, 0x5a, 0xa7, 0xc4, 0xb1, 0xf6, 0x15, 0x10, 0xcf, 0x39, 0x45, 0xbf, 0x7c, 0xf3,
+	0x8c, 0x27, 0x59, 0x1e, 0xe9, 0xab, 0x9c, 0x11, 0xf7, 0x0f, 0x28, 0x11, 0x94, 0x91, 0xba, 0xcd,
+	0x44, 0xe8, 0x0b, 0xb2, 0x35, 0xb6, 0x31, 0x51, 0xb8, 0x86, 0xa9, 0x3a, 0xc9, 0xa3, 0x59, 0x1a,
+	0xcf, 0x15, 0x9a, 0xe6, 0x87, 0xdf, 0xd7, 0x3d, 0x7c, 0x34, 0xa2, 0x07, 0xc7, 0x50, 0xd5, 0x97,
+	0xd8, 0x81, 0x77, 0x72, 0x77, 0xbb, 0x69, 0x1c, 0xcf, 0x09, 0xcf, 0x98, 0x19, 0x91, 0x59, 0x2a,
+	0x2e, 0x38, 0x2d, 0x67, 0xcf, 0xb8, 0xbd, 0xea, 0xa4, 0x0b, 0x8d, 0x72, 0x5e, 0xca, 0x36, 0xfa,
+	0xd0, 0x30, 0xad, 0x71, 0xa6, 0x88, 0x8b, 0x72, 0xde, 0xd9, 0x95, 0x13, 0x25, 0x0c, 0x5f, 0x40,
+	0x77, 0xb3, 0x9c, 0x31, 0xd3, 0xc1, 0x43, 0xa8, 0xe3, 0x3c, 0x58, 0x2b, 0xae, 0xc9, 0xc5, 0xb7,
+	0xe1, 0x33, 0xf0, 0xca, 0xf8, 0x54, 0x50, 0x66, 0xdc, 0x88, 0x29, 0x95, 0xa5, 0x1b, 0x3e, 0xb8,
+	0x85, 0x3c, 0x45, 0xbd, 0x6d, 0xa8, 0x2d, 0xe2, 0xac, 0xdc, 0x92, 0x70, 0xbc, 0xca, 0x7c, 0xc7,
+	0x17, 0xe7, 0x5b, 0x3e, 0x02, 0x54, 0xb5, 0x28, 0xd3, 0x1e, 0xad, 0x6a, 0x71, 0xb0, 0x96, 0xa3,
+	0x1b, 0x6a, 0x19, 0x33, 0x1d, 0xfe, 0x74, 0xa0, 0x51, 0x62, 0x86, 0x11, 0x2d, 0xac, 0x6c, 0x6f,
+	0x43, 0x41, 0xbb, 0x5e, 0x38, 0xc7, 0xee, 0x8e, 0xd9, 0xf1, 0xda, 0xe6, 0x8e, 0xbb, 0xb6, 0x8d,
+	0x62, 0xc9, 0xea, 0x36, 0x2c, 0xd6, 0xbb, 0x51, 0xea, 0xec, 0x2a, 0x1d, 0x4b, 0x4d, 0x9a, 0x7f,
+	0xf3, 0x2f, 0x00, 0xc0, 0x46, 0x22, 0xc5, 0xbf, 0xb1, 0x62, 0x16, 0xcc, 0x66, 0xe0, 0x71, 0x30,
+	0xb7, 0x01, 0x10, 0xd9, 0xde, 0x52, 0xcf, 0x8e, 0xd6, 0xc6, 0x96, 0xb6, 0x6d, 0xee, 0xea, 0x44,
+	0xf8, 0xd7, 0x5c, 0xa3, 0xce, 0x6a, 0x4a, 0x84, 0x22, 0x5d, 0x3b, 0x8d, 0xeb, 0xc3, 0xb3, 0x67,
+	0x75, 0x28, 0x4f, 0xca, 0xbe, 0xe5, 0x28, 0xe2, 0x28, 0x8b, 0x2f, 0x49, 0x80, 0xd8, 0x31, 0xb8,
+	0x0b, 0x41, 0x99, 0x22, 0xb7, 0xd0, 0x81, 0xc3, 0x5d, 0x07, 0xd0, 0xfd, 0x63, 0x70, 0x53, 0xbe,
+	0x38, 0x57, 0xe4, 0xe0, 0xa6, 0x67, 0x68, 0xb5, 0x0f, 0x2e, 0x93, 0x52, 0x48, 0x72, 0x1b, 0x85,
+	0x07, 0xa8, 0x72, 0x4a, 0x0e, 0xcd, 0x9d, 0x79, 0xe3, 0x7d, 0x5e, 0xff, 0x10, 0x4c, 0xeb, 0x78,
+	0x93, 0x9f, 0xfc, 0x1e, 0x00, 0x9c, 0xf9, 0xcb, 0x9c, 0x5a, 0x06, 0x00, 0x00,
+}
@@ -46,16 +46,17 @@ type TracerouteMeasurement struct {
 func (m *TracerouteMeasurement) Reset()                    { *m = TracerouteMeasurement{} }
 func (m *TracerouteMeasurement) String() string            { return proto.CompactTextString(m) }
 func (*TracerouteMeasurement) ProtoMessage()               {}
-func (*TracerouteMeasurement) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+func (*TracerouteMeasurement) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
 
 type TracerouteArg struct {
 	Traceroutes []*TracerouteMeasurement `protobuf:"bytes,1,rep,name=traceroutes" json:"traceroutes,omitempty"`
+	Priority    uint32                   `protobuf:"varint,2,opt,name=priority" json:"priority,omitempty"`
 }
 
 func (m *TracerouteArg) Reset()                    { *m = TracerouteArg{} }
 func (m *TracerouteArg) String() string            { return proto.CompactTextString(m) }
 func (*TracerouteArg) ProtoMessage()               {}
-func (*TracerouteArg) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+func (*TracerouteArg) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{1} }
 
 func (m *TracerouteArg) GetTraceroutes() []*TracerouteMeasurement {
 	if m != nil {
@@ -71,7 +72,7 @@ type TracerouteArgResp struct {
 func (m *TracerouteArgResp) Reset()                    { *m = TracerouteArgResp{} }
 func (m *TracerouteArgResp) String() string            { return proto.CompactTextString(m) }
 func (*TracerouteArgResp) ProtoMessage()               {}
-func (*TracerouteArgResp) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+func (*TracerouteArgResp) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{2} }
 
 func (m *TracerouteArgResp) GetTraceroutes() []*Traceroute {
 	if m != nil {
@@ -81,26 +82,27 @@ func (m *TracerouteArgResp) GetTraceroutes() []*Traceroute {
 }
 
 type TracerouteHop struct {
-	Addr      uint32 `protobuf:"varint,1,opt,name=addr" json:"addr,omitempty"`
-	ProbeTtl  uint32 `protobuf:"varint,2,opt,name=probe_ttl" json:"probe_ttl,omitempty"`
-	ProbeId   uint32 `protobuf:"varint,3,opt,name=probe_id" json:"probe_id,omitempty"`
-	ProbeSize uint32 `protobuf:"varint,4,opt,name=probe_size" json:"probe_size,omitempty"`
-	Rtt       *RTT   `protobuf:"bytes,5,opt,name=rtt" json:"rtt,omitempty"`
-	ReplyTtl  uint32 `protobuf:"varint,6,opt,name=reply_ttl" json:"reply_ttl,omitempty"`
-	ReplyTos  uint32 `protobuf:"varint,7,opt,name=reply_tos" json:"reply_tos,omitempty"`
-	ReplySize uint32 `protobuf:"varint,8,opt,name=reply_size" json:"reply_size,omitempty"`
-	ReplyIpid uint32 `protobuf:"varint,9,opt,name=reply_ipid" json:"reply_ipid,omitempty"`
-	IcmpType  uint32 `protobuf:"varint,10,opt,name=icmp_type" json:"icmp_type,omitempty"`
-	IcmpCode  uint32 `protobuf:"varint,11,opt,name=icmp_code" json:"icmp_code,omitempty"`
-	IcmpQTtl  uint32 `protobuf:"varint,12,opt,name=icmp_q_ttl" json:"icmp_q_ttl,omitempty"`
-	IcmpQIpl  uint32 `protobuf:"varint,13,opt,name=icmp_q_ipl" json:"icmp_q_ipl,omitempty"`
-	IcmpQTos  uint32 `protobuf:"varint,14,opt,name=icmp_q_tos" json:"icmp_q_tos,omitempty"`
+	Addr      uint32           `protobuf:"varint,1,opt,name=addr" json:"addr,omitempty"`
+	ProbeTtl  uint32           `protobuf:"varint,2,opt,name=probe_ttl" json:"probe_ttl,omitempty"`
+	ProbeId   uint32           `protobuf:"varint,3,opt,name=probe_id" json:"probe_id,omitempty"`
+	ProbeSize uint32           `protobuf:"varint,4,opt,name=probe_size" json:"probe_size,omitempty"`
+	Rtt       *RTT             `protobuf:"bytes,5,opt,name=rtt" json:"rtt,omitempty"`
+	ReplyTtl  uint32           `protobuf:"varint,6,opt,name=reply_ttl" json:"reply_ttl,omitempty"`
+	ReplyTos  uint32           `protobuf:"varint,7,opt,name=reply_tos" json:"reply_tos,omitempty"`
+	ReplySize uint32           `protobuf:"varint,8,opt,name=reply_size" json:"reply_size,omitempty"`
+	ReplyIpid uint32           `protobuf:"varint,9,opt,name=reply_ipid" json:"reply_ipid,omitempty"`
+	IcmpType  uint32           `protobuf:"varint,10,opt,name=icmp_type" json:"icmp_type,omitempty"`
+	IcmpCode  uint32           `protobuf:"varint,11,opt,name=icmp_code" json:"icmp_code,omitempty"`
+	IcmpQTtl  uint32           `protobuf:"varint,12,opt,name=icmp_q_ttl" json:"icmp_q_ttl,omitempty"`
+	IcmpQIpl  uint32           `protobuf:"varint,13,opt,name=icmp_q_ipl" json:"icmp_q_ipl,omitempty"`
+	IcmpQTos  uint32           `protobuf:"varint,14,opt,name=icmp_q_tos" json:"icmp_q_tos,omitempty"`
+	IcmpExt   []*ICMPExtension `protobuf:"bytes,15,rep,name=icmp_ext" json:"icmp_ext,omitempty"`
 }
 
 func (m *TracerouteHop) Reset()                    { *m = TracerouteHop{} }
 func (m *TracerouteHop) String() string            { return proto.CompactTextString(m) }
 func (*TracerouteHop) ProtoMessage()               {}
-func (*TracerouteHop) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+func (*TracerouteHop) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{3} }
 
 func (m *TracerouteHop) GetRtt() *RTT {
 	if m != nil {
@@ -109,6 +111,24 @@ func (m *TracerouteHop) GetRtt() *RTT {
 	return nil
 }
 
+func (m *TracerouteHop) GetIcmpExt() []*ICMPExtension {
+	if m != nil {
+		return m.IcmpExt
+	}
+	return nil
+}
+
+type ICMPExtension struct {
+	ClassNumber uint32 `protobuf:"varint,1,opt,name=class_number" json:"class_number,omitempty"`
+	TypeNumber  uint32 `protobuf:"varint,2,opt,name=type_number" json:"type_number,omitempty"`
+	Data        []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *ICMPExtension) Reset()                    { *m = ICMPExtension{} }
+func (m *ICMPExtension) String() string            { return proto.CompactTextString(m) }
+func (*ICMPExtension) ProtoMessage()               {}
+func (*ICMPExtension) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+
 type Traceroute struct {
 	Type       string           `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
 	UserId     uint32           `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
@@ -138,7 +158,7 @@ type Traceroute struct {
 func (m *Traceroute) Reset()                    { *m = Traceroute{} }
 func (m *Traceroute) String() string            { return proto.CompactTextString(m) }
 func (*Traceroute) ProtoMessage()               {}
-func (*Traceroute) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+func (*Traceroute) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
 
 func (m *Traceroute) GetStart() *TracerouteTime {
 	if m != nil {
@@ -163,69 +183,74 @@ type TracerouteTime struct {
 func (m *TracerouteTime) Reset()                    { *m = TracerouteTime{} }
 func (m *TracerouteTime) String() string            { return proto.CompactTextString(m) }
 func (*TracerouteTime) ProtoMessage()               {}
-func (*TracerouteTime) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+func (*TracerouteTime) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
 
 func init() {
 	proto.RegisterType((*TracerouteMeasurement)(nil), "datamodel.TracerouteMeasurement")
 	proto.RegisterType((*TracerouteArg)(nil), "datamodel.TracerouteArg")
 	proto.RegisterType((*TracerouteArgResp)(nil), "datamodel.TracerouteArgResp")
 	proto.RegisterType((*TracerouteHop)(nil), "datamodel.TracerouteHop")
+	proto.RegisterType((*ICMPExtension)(nil), "datamodel.ICMPExtension")
 	proto.RegisterType((*Traceroute)(nil), "datamodel.Traceroute")
 	proto.RegisterType((*TracerouteTime)(nil), "datamodel.TracerouteTime")
 }
 
 func init() {
-	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/traceroute.proto", fileDescriptor3)
-}
-
-var fileDescriptor3 = []byte{
-	// 761 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x41, 0x6e, 0xdb, 0x3a,
-	0x10, 0x45, 0x2c, 0xc7, 0xb1, 0x69, 0xcb, 0x89, 0x95, 0xef, 0x7c, 0x26, 0xf9, 0x8b, 0xc0, 0x8b,
-	0x8f, 0xa0, 0x40, 0x6d, 0x20, 0x45, 0x17, 0xed, 0x26, 0x68, 0x81, 0x16, 0xdd, 0x34, 0x8b, 0xc4,
-	0xdd, 0x74, 0x23, 0xd0, 0xd2, 0x24, 0x16, 0x2a, 0x89, 0x2c, 0x49, 0xa7, 0x71, 0xaf, 0xd3, 0x4b,
-	0xf4, 0x5e, 0xbd, 0x40, 0x87, 0x23, 0x53, 0x76, 0x52, 0x6f, 0xba, 0x12, 0xe6, 0x89, 0x7c, 0xc3,
-	0x79, 0xf3, 0x66, 0xd8, 0xe5, 0x5d, 0x66, 0xe7, 0x8b, 0xd9, 0x38, 0x91, 0xc5, 0xe4, 0xea, 0xdd,
-	0xa7, 0xe7, 0x37, 0x57, 0x37, 0x93, 0x6b, 0xb8, 0x07, 0x6d, 0x60, 0xaa, 0x45, 0x02, 0x5a, 0x2e,
-	0x2c, 0x4c, 0x52, 0x61, 0x45, 0x21, 0x53, 0xc8, 0x27, 0xb6, 0x06, 0xc7, 0x4a, 0x4b, 0x2b, 0xa3,
-	0x4e, 0xfd, 0xef, 0xe4, 0xd5, 0xdf, 0x72, 0x65, 0xc5, 0x8a, 0x65, 0xf4, 0x2b, 0x60, 0xc3, 0xf5,
-	0x99, 0x8f, 0x20, 0xcc, 0x42, 0x43, 0x01, 0xa5, 0x8d, 0x06, 0xac, 0x63, 0xac, 0xc8, 0xa1, 0x04,
-	0x63, 0xf8, 0xce, 0xd9, 0xce, 0x79, 0x10, 0x75, 0x59, 0x90, 0x1a, 0xcb, 0x03, 0x0c, 0xc2, 0x28,
-	0x62, 0x2c, 0x91, 0xe5, 0x6d, 0x96, 0x42, 0x99, 0x00, 0x6f, 0x22, 0xd6, 0x89, 0x42, 0xb6, 0x9b,
-	0x2a, 0xa9, 0x2d, 0xdf, 0xa5, 0x10, 0x29, 0x6e, 0x33, 0x6d, 0x6c, 0x3c, 0x97, 0x8a, 0xb7, 0x3c,
-	0x74, 0x27, 0x54, 0x9c, 0x67, 0x45, 0x66, 0xf9, 0x1e, 0x41, 0x48, 0xe4, 0x20, 0x91, 0xd8, 0x4c,
-	0x96, 0xbc, 0x4d, 0xd8, 0x3e, 0xdb, 0x2b, 0xc4, 0x43, 0x6c, 0x6d, 0xce, 0x3b, 0x04, 0x1c, 0xb2,
-	0xae, 0x12, 0x76, 0x1e, 0xa7, 0x99, 0x49, 0xe4, 0x3d, 0x67, 0x08, 0xb6, 0x5d, 0xba, 0x5c, 0x4a,
-	0x65, 0x78, 0xd7, 0x9f, 0x71, 0xa1, 0x67, 0xea, 0x79, 0x26, 0x25, 0x96, 0xb9, 0x14, 0x29, 0x0f,
-	0x09, 0xe8, 0xb3, 0x56, 0x01, 0x76, 0x2e, 0x53, 0xde, 0xa7, 0xf8, 0x80, 0xb5, 0x85, 0xb5, 0x50,
-	0x28, 0x6b, 0xf8, 0xbe, 0x47, 0x0c, 0x94, 0x69, 0x2c, 0xf2, 0x9c, 0x1f, 0xf8, 0x44, 0x86, 0xea,
-	0x1a, 0xd0, 0x01, 0xd4, 0xc1, 0xe8, 0x84, 0x47, 0xa4, 0x03, 0x06, 0x56, 0x1a, 0x7e, 0x48, 0x7f,
-	0x86, 0x2c, 0x74, 0xe2, 0xc6, 0xf0, 0x90, 0x00, 0xa4, 0x90, 0xf2, 0x7f, 0xe8, 0x3e, 0x3e, 0x62,
-	0x61, 0x40, 0xc7, 0x59, 0xca, 0x87, 0x74, 0xae, 0xc7, 0x9a, 0xdf, 0x04, 0x2a, 0x70, 0xe4, 0x15,
-	0x70, 0x51, 0x8c, 0x2d, 0x99, 0x01, 0xff, 0xb7, 0x16, 0xca, 0x98, 0x18, 0x3b, 0xa1, 0x97, 0x9c,
-	0xfb, 0x77, 0xe5, 0x08, 0x95, 0xa2, 0x00, 0x7e, 0xec, 0x8b, 0x73, 0xe9, 0xb0, 0x75, 0xfc, 0x84,
-	0x3a, 0x84, 0x12, 0x24, 0x73, 0x48, 0xbe, 0xc4, 0x89, 0xc0, 0x2f, 0x3f, 0xa5, 0xec, 0x78, 0xaf,
-	0x02, 0xd3, 0x19, 0xff, 0xcf, 0x21, 0xa3, 0xf7, 0x2c, 0x5c, 0x37, 0xfd, 0x8d, 0xbe, 0x8b, 0x5e,
-	0xb2, 0xee, 0xda, 0x60, 0xae, 0xdd, 0xc1, 0x79, 0xf7, 0xe2, 0x6c, 0x5c, 0x5b, 0x66, 0xbc, 0xd5,
-	0x23, 0xa3, 0x4b, 0x36, 0x78, 0xc4, 0x73, 0x0d, 0x46, 0x45, 0xcf, 0xb6, 0x71, 0x0d, 0xb7, 0x72,
-	0x8d, 0x7e, 0x34, 0x36, 0x5f, 0xf2, 0x41, 0x2a, 0xa7, 0x8c, 0x48, 0x53, 0x4d, 0x8e, 0x0b, 0x9d,
-	0x0a, 0x24, 0x0a, 0x39, 0xa1, 0x41, 0x10, 0x56, 0x53, 0x41, 0x28, 0x66, 0xed, 0xc4, 0x0a, 0x31,
-	0xd9, 0xf7, 0xca, 0x89, 0x61, 0x74, 0xca, 0x02, 0x6d, 0x2b, 0x1f, 0x76, 0x2f, 0xfa, 0x1b, 0xc9,
-	0xaf, 0xa7, 0x53, 0xc7, 0xaa, 0x41, 0xe5, 0x4b, 0x62, 0x6d, 0xf9, 0x44, 0x2b, 0x08, 0x7b, 0xb9,
-	0xe7, 0x69, 0x2b, 0x88, 0x68, 0xdb, 0x8f, 0xb1, 0x4c, 0x61, 0xfa, 0x8e, 0xbf, 0x9a, 0x25, 0x85,
-	0x8a, 0xed, 0x52, 0x01, 0x19, 0x73, 0x0d, 0x25, 0x98, 0x92, 0xcc, 0x49, 0x37, 0x09, 0xfa, 0x4a,
-	0x49, 0x7b, 0x4f, 0xb0, 0x4c, 0xe5, 0x64, 0xcf, 0x47, 0xe7, 0xf0, 0x25, 0xce, 0xa2, 0xe1, 0xe8,
-	0x67, 0xc0, 0xd8, 0x5a, 0x25, 0x27, 0x11, 0xe5, 0xda, 0xf1, 0x1e, 0xf0, 0xde, 0xaa, 0x04, 0x5a,
-	0x1b, 0x3c, 0xd8, 0x74, 0x6b, 0xd3, 0xbb, 0xd5, 0x8d, 0xf0, 0x2e, 0x05, 0xb5, 0xad, 0x5b, 0x3e,
-	0xac, 0xa6, 0xb7, 0xaa, 0x1f, 0xbd, 0x64, 0x2c, 0x8e, 0x93, 0xc6, 0x86, 0xd7, 0x83, 0x49, 0x5b,
-	0x01, 0x41, 0x27, 0xe8, 0xaa, 0xfe, 0x73, 0x64, 0xb1, 0x02, 0xaf, 0x31, 0x12, 0xfb, 0x78, 0x6b,
-	0xa7, 0xa7, 0x68, 0x53, 0x77, 0x19, 0x37, 0x01, 0xaa, 0xb2, 0x28, 0xed, 0x4a, 0x96, 0xcd, 0xe9,
-	0xeb, 0x79, 0x04, 0x0f, 0x55, 0x0b, 0x22, 0xf4, 0x08, 0xad, 0x11, 0xb7, 0x45, 0x48, 0x90, 0x7a,
-	0x7c, 0xf6, 0xbd, 0x64, 0x1b, 0xe3, 0x73, 0xb0, 0x39, 0x95, 0x83, 0x2d, 0x06, 0xa9, 0xc6, 0xf6,
-	0x7f, 0xd6, 0x9c, 0xbb, 0xd5, 0x71, 0x48, 0xf6, 0xe4, 0x5b, 0x1f, 0xed, 0xfc, 0x88, 0xa2, 0x80,
-	0xd6, 0x52, 0xd3, 0x24, 0x93, 0xda, 0x6e, 0xaf, 0xba, 0xfd, 0x32, 0xfc, 0x73, 0xa1, 0x1d, 0x11,
-	0x35, 0x63, 0x0d, 0x6c, 0x86, 0x1b, 0xe3, 0x60, 0xf4, 0x9a, 0xf5, 0x9f, 0x88, 0xe0, 0xda, 0x01,
-	0xc9, 0x6a, 0xa3, 0x62, 0x21, 0x0b, 0x17, 0x35, 0x28, 0xc2, 0x5c, 0xb7, 0x6e, 0x9e, 0xab, 0xc6,
-	0xbd, 0xed, 0x7e, 0x5e, 0xef, 0xf8, 0x59, 0x8b, 0xf6, 0xf5, 0x8b, 0xdf, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x85, 0x45, 0x0b, 0x76, 0x38, 0x06, 0x00, 0x00,
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/traceroute.proto", fileDescriptor5)
+}
+
+var fileDescriptor5 = []byte{
+	// 828 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xcd, 0x8e, 0xdb, 0x36,
+	0x10, 0x86, 0x57, 0xfe, 0x1d, 0x59, 0xfe, 0x91, 0xd7, 0x29, 0x93, 0xf4, 0x60, 0xf8, 0x50, 0x18,
+	0x01, 0xba, 0x0b, 0x6c, 0xd1, 0x43, 0x7b, 0x09, 0xda, 0x22, 0x40, 0x7a, 0x48, 0x50, 0x6c, 0xb6,
+	0x40, 0xd1, 0x8b, 0x40, 0x8b, 0xb3, 0x2b, 0xa2, 0x92, 0xc8, 0x92, 0x74, 0xba, 0xee, 0x93, 0xf5,
+	0x2d, 0xfa, 0x30, 0x7d, 0x81, 0x82, 0x23, 0xd3, 0x3f, 0x1b, 0x5f, 0x72, 0x9c, 0x4f, 0xe4, 0xcc,
+	0xf0, 0x9b, 0x6f, 0x3e, 0xc1, 0xeb, 0x07, 0xe9, 0x8a, 0xcd, 0xfa, 0x2a, 0x57, 0xd5, 0xf5, 0xfb,
+	0x37, 0xbf, 0x7e, 0xfd, 0xe1, 0xfd, 0x87, 0xeb, 0x5b, 0xfc, 0x88, 0xc6, 0xe2, 0x9d, 0xe1, 0x39,
+	0x1a, 0xb5, 0x71, 0x78, 0x2d, 0xb8, 0xe3, 0x95, 0x12, 0x58, 0x5e, 0xbb, 0x3d, 0x78, 0xa5, 0x8d,
+	0x72, 0x2a, 0x1d, 0xec, 0xbf, 0xbd, 0xf8, 0xee, 0x73, 0x73, 0xc9, 0x6a, 0x97, 0x65, 0xf9, 0x5f,
+	0x04, 0xf3, 0xc3, 0x99, 0x77, 0xc8, 0xed, 0xc6, 0x60, 0x85, 0xb5, 0x4b, 0xa7, 0x30, 0xb0, 0x8e,
+	0x97, 0x58, 0xa3, 0xb5, 0xac, 0xb5, 0x68, 0xad, 0xa2, 0x34, 0x86, 0x48, 0x58, 0xc7, 0xa2, 0x45,
+	0x6b, 0x95, 0xa4, 0x29, 0x40, 0xae, 0xea, 0x7b, 0x29, 0xb0, 0xce, 0x91, 0xb5, 0x17, 0xad, 0xd5,
+	0x20, 0x4d, 0xa0, 0x23, 0xb4, 0x32, 0x8e, 0x75, 0x28, 0x9c, 0xc2, 0xe0, 0x5e, 0x1a, 0xeb, 0xb2,
+	0x42, 0x69, 0xd6, 0x0d, 0xd0, 0x03, 0xd7, 0x59, 0x29, 0x2b, 0xe9, 0x58, 0x8f, 0xa0, 0x14, 0xc0,
+	0x43, 0x3c, 0x77, 0x52, 0xd5, 0xac, 0x4f, 0xd8, 0x18, 0x7a, 0x15, 0x7f, 0xcc, 0x9c, 0x2b, 0xd9,
+	0x80, 0x80, 0x19, 0xc4, 0x9a, 0xbb, 0x22, 0x13, 0xd2, 0xe6, 0xea, 0x23, 0x83, 0x45, 0x6b, 0xd5,
+	0xf7, 0xe5, 0x4a, 0xa5, 0xb4, 0x65, 0x71, 0x38, 0xe3, 0xc3, 0x90, 0x69, 0x18, 0x32, 0x69, 0xbe,
+	0x2d, 0x15, 0x17, 0x2c, 0x21, 0x60, 0x04, 0xdd, 0x0a, 0x5d, 0xa1, 0x04, 0x1b, 0x51, 0x3c, 0x81,
+	0x3e, 0x77, 0x0e, 0x2b, 0xed, 0x2c, 0x1b, 0x07, 0xc4, 0x62, 0x2d, 0x32, 0x5e, 0x96, 0x6c, 0x12,
+	0x0a, 0x59, 0x7a, 0xd7, 0x94, 0x0e, 0xc4, 0x10, 0x59, 0x93, 0xb3, 0x94, 0x78, 0x88, 0x21, 0x72,
+	0xca, 0xb2, 0x19, 0x7d, 0x99, 0x43, 0xe2, 0xc9, 0xcd, 0xf0, 0x31, 0x47, 0x14, 0x28, 0xd8, 0x25,
+	0xdd, 0x1f, 0x43, 0x6f, 0x63, 0xd1, 0x64, 0x52, 0xb0, 0x39, 0x9d, 0x1b, 0x42, 0xfb, 0x2f, 0x2e,
+	0x1d, 0x7b, 0x16, 0x18, 0xf0, 0x51, 0xa6, 0x8d, 0x5a, 0x23, 0xfb, 0x62, 0x4f, 0x94, 0xb5, 0x19,
+	0xd6, 0xce, 0x6c, 0x19, 0x0b, 0x7d, 0x95, 0xd6, 0x66, 0x35, 0xaf, 0x90, 0x3d, 0x0f, 0x8f, 0xf3,
+	0xe5, 0xd4, 0xc6, 0xb1, 0x17, 0x34, 0xa1, 0x19, 0xc4, 0x79, 0x81, 0xf9, 0x1f, 0x59, 0xce, 0xf3,
+	0x02, 0xd9, 0x4b, 0xaa, 0x3e, 0x81, 0x7e, 0x03, 0x8a, 0x35, 0xfb, 0xd2, 0x23, 0xcb, 0xdf, 0x20,
+	0x39, 0x0c, 0xfd, 0x07, 0xf3, 0x90, 0x7e, 0x0b, 0xf1, 0x41, 0x60, 0x7e, 0xdc, 0xd1, 0x2a, 0xbe,
+	0x59, 0x5c, 0xed, 0x25, 0x73, 0x75, 0x5e, 0x23, 0x13, 0xe8, 0x6b, 0x23, 0x95, 0x91, 0x6e, 0xcb,
+	0x2e, 0x3c, 0x1b, 0xcb, 0xd7, 0x30, 0x3d, 0xc9, 0x7c, 0x8b, 0x56, 0xa7, 0xaf, 0xce, 0x65, 0x9f,
+	0x9f, 0xcd, 0xbe, 0xfc, 0xf7, 0xe2, 0xb8, 0xb7, 0xb7, 0x4a, 0x7b, 0xae, 0xb8, 0x10, 0x86, 0x34,
+	0x98, 0x78, 0x5e, 0x88, 0x26, 0xd2, 0x06, 0xd5, 0x6c, 0xba, 0xf0, 0x90, 0x14, 0x07, 0x6d, 0x36,
+	0x88, 0x95, 0x7f, 0x37, 0xda, 0x4c, 0xd2, 0x97, 0x10, 0x19, 0xd7, 0x28, 0x33, 0xbe, 0x19, 0x1d,
+	0x15, 0xbf, 0xbd, 0xbb, 0xf3, 0x59, 0x0d, 0xea, 0x72, 0x4b, 0x59, 0xbb, 0xa1, 0xd0, 0x0e, 0x52,
+	0x96, 0xf5, 0x42, 0xda, 0x06, 0xa2, 0xb4, 0xfd, 0x53, 0x4c, 0x6a, 0x29, 0xd8, 0x20, 0x5c, 0x95,
+	0x79, 0xa5, 0x33, 0xb7, 0xd5, 0xc8, 0xe0, 0x04, 0xca, 0x95, 0x40, 0x16, 0x87, 0x9b, 0x04, 0xfd,
+	0x49, 0x45, 0x87, 0x4f, 0x30, 0xa9, 0x4b, 0x96, 0x3c, 0xc1, 0x7c, 0x27, 0x23, 0xc2, 0x5e, 0x41,
+	0x9f, 0x30, 0x7c, 0x74, 0x6c, 0x4c, 0x74, 0xb2, 0xa3, 0x17, 0xfd, 0xfc, 0xd3, 0xbb, 0x5f, 0xde,
+	0x3c, 0x3a, 0xac, 0xad, 0x54, 0xf5, 0xf2, 0x2d, 0x24, 0x27, 0x40, 0x7a, 0x09, 0xc3, 0xbc, 0xe4,
+	0x5e, 0x49, 0x9b, 0x6a, 0x8d, 0x81, 0xd8, 0x19, 0xc4, 0xbe, 0xdf, 0x00, 0x36, 0xd4, 0x0e, 0xa1,
+	0xed, 0xd3, 0x12, 0xad, 0xc3, 0xe5, 0x3f, 0x11, 0xc0, 0x61, 0x36, 0xfe, 0x23, 0xbd, 0xb0, 0x15,
+	0xb4, 0x18, 0x34, 0xde, 0xdc, 0x3d, 0x2c, 0x5a, 0x74, 0xbc, 0x35, 0xed, 0xb0, 0x35, 0xc2, 0x36,
+	0xd3, 0x48, 0x0e, 0xeb, 0xd5, 0x0d, 0x61, 0xe3, 0x22, 0xbd, 0xd0, 0x98, 0x75, 0x4a, 0x67, 0x06,
+	0xb9, 0xdd, 0x1b, 0x04, 0xb9, 0x93, 0xd2, 0x19, 0x75, 0xd7, 0xb0, 0xbe, 0x82, 0x8e, 0x75, 0xdc,
+	0x38, 0x62, 0x3c, 0xbe, 0x79, 0x7e, 0x56, 0x5f, 0x77, 0xb2, 0x42, 0x7f, 0xb9, 0x50, 0x7e, 0x16,
+	0x9b, 0xda, 0xed, 0x86, 0x71, 0xec, 0x02, 0xc3, 0x80, 0x14, 0x4a, 0x37, 0x46, 0x95, 0x04, 0x84,
+	0xec, 0xcc, 0xbb, 0xd9, 0x28, 0xd0, 0x43, 0x6b, 0x3c, 0x0e, 0x83, 0x3a, 0x5a, 0xe3, 0xc9, 0xb1,
+	0x3b, 0x4c, 0xcf, 0xc8, 0xb2, 0xb1, 0x8f, 0xaf, 0xa0, 0x5d, 0x78, 0x0b, 0x9b, 0x7d, 0x32, 0xc5,
+	0xd3, 0x2d, 0x48, 0xa0, 0x83, 0xc6, 0x28, 0xc3, 0x2e, 0x03, 0xdb, 0xde, 0xdf, 0xbd, 0xcf, 0xcd,
+	0x3f, 0x35, 0xd6, 0x67, 0x94, 0x1a, 0xe0, 0x42, 0x0a, 0xb2, 0x93, 0x68, 0xf9, 0x3d, 0x8c, 0x9e,
+	0x90, 0xe0, 0xc7, 0x81, 0xf9, 0xce, 0xd9, 0x87, 0xd0, 0xde, 0xf8, 0xe8, 0x82, 0xa2, 0x04, 0x3a,
+	0xf7, 0xde, 0x57, 0x9a, 0xc1, 0xfd, 0x18, 0xff, 0x7e, 0xf8, 0xd7, 0xac, 0xbb, 0xf4, 0xdf, 0xf8,
+	0xe6, 0xff, 0x01, 0x00, 0x0c, 0x55, 0xf1, 0x59, 0xc0, 0x06, 0x00, 0x00,
 }
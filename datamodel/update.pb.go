@@ -19,23 +19,23 @@ type UpdateResponse struct {
 func (m *UpdateResponse) Reset()                    { *m = UpdateResponse{} }
 func (m *UpdateResponse) String() string            { return proto.CompactTextString(m) }
 func (*UpdateResponse) ProtoMessage()               {}
-func (*UpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{0} }
+func (*UpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{0} }
 
 func init() {
 	proto.RegisterType((*UpdateResponse)(nil), "datamodel.UpdateResponse")
 }
 
 func init() {
-	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/update.proto", fileDescriptor4)
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/update.proto", fileDescriptor6)
 }
 
-var fileDescriptor4 = []byte{
-	// 112 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xb2, 0x4e, 0xcf, 0x2c, 0xc9,
+var fileDescriptor6 = []byte{
+	// 111 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x4e, 0xcf, 0x2c, 0xc9,
 	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xf7, 0x73, 0x0d, 0xd5, 0x0d, 0xf6, 0x0b, 0xd6, 0x0f,
 	0x4a, 0x2d, 0x4b, 0x2d, 0x2a, 0x4e, 0x0d, 0x29, 0x4a, 0x4c, 0x4e, 0x2d, 0xca, 0x2f, 0x2d, 0x49,
-	0xd5, 0x4f, 0x49, 0x2c, 0x49, 0xcc, 0xcd, 0x4f, 0x49, 0xcd, 0xd1, 0x2f, 0x2d, 0x00, 0xb2, 0x53,
-	0xf5, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85, 0x38, 0xe1, 0xe2, 0x4a, 0x02, 0x5c, 0x7c, 0xa1, 0x60,
-	0xa9, 0xa0, 0xd4, 0xe2, 0x82, 0xfc, 0xbc, 0xe2, 0x54, 0x27, 0xee, 0x28, 0x84, 0x74, 0x12, 0x1b,
-	0x58, 0x83, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0xb1, 0x53, 0xe6, 0x33, 0x6f, 0x00, 0x00, 0x00,
+	0xd5, 0x4f, 0x49, 0x2c, 0x49, 0xcc, 0xcd, 0x4f, 0x49, 0xcd, 0xd1, 0x2f, 0x2d, 0x48, 0x49, 0x2c,
+	0x49, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x84, 0x8b, 0x2b, 0x09, 0x70, 0xf1, 0x85,
+	0x82, 0xa5, 0x82, 0x52, 0x8b, 0x0b, 0xf2, 0xf3, 0x8a, 0x53, 0x9d, 0xb8, 0xa3, 0x10, 0xd2, 0x49,
+	0x6c, 0x60, 0x0d, 0xc6, 0x80, 0x01, 0x00, 0xb1, 0x53, 0xe6, 0x33, 0x6f, 0x00, 0x00, 0x00,
 }
@@ -14,24 +14,59 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 type VantagePoint struct {
-	Hostname     string `protobuf:"bytes,1,opt,name=hostname" json:"hostname,omitempty"`
-	Ip           uint32 `protobuf:"varint,2,opt,name=ip" json:"ip,omitempty"`
-	Sshable      bool   `protobuf:"varint,3,opt,name=sshable" json:"sshable,omitempty"`
-	Timestamp    bool   `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
-	RecordRoute  bool   `protobuf:"varint,5,opt,name=record_route" json:"record_route,omitempty"`
-	LastUpdated  int64  `protobuf:"varint,6,opt,name=last_updated" json:"last_updated,omitempty"`
-	CanSpoof     bool   `protobuf:"varint,7,opt,name=can_spoof" json:"can_spoof,omitempty"`
-	Controller   uint32 `protobuf:"varint,8,opt,name=controller" json:"controller,omitempty"`
-	ReceiveSpoof bool   `protobuf:"varint,9,opt,name=receive_spoof" json:"receive_spoof,omitempty"`
-	Site         string `protobuf:"bytes,10,opt,name=site" json:"site,omitempty"`
-	SpoofChecked int64  `protobuf:"varint,11,opt,name=spoof_checked" json:"spoof_checked,omitempty"`
-	Port         uint32 `protobuf:"varint,12,opt,name=port" json:"port,omitempty"`
+	Hostname     string    `protobuf:"bytes,1,opt,name=hostname" json:"hostname,omitempty"`
+	Ip           uint32    `protobuf:"varint,2,opt,name=ip" json:"ip,omitempty"`
+	Sshable      bool      `protobuf:"varint,3,opt,name=sshable" json:"sshable,omitempty"`
+	Timestamp    bool      `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	RecordRoute  bool      `protobuf:"varint,5,opt,name=record_route" json:"record_route,omitempty"`
+	LastUpdated  int64     `protobuf:"varint,6,opt,name=last_updated" json:"last_updated,omitempty"`
+	CanSpoof     bool      `protobuf:"varint,7,opt,name=can_spoof" json:"can_spoof,omitempty"`
+	Controller   uint32    `protobuf:"varint,8,opt,name=controller" json:"controller,omitempty"`
+	ReceiveSpoof bool      `protobuf:"varint,9,opt,name=receive_spoof" json:"receive_spoof,omitempty"`
+	Site         string    `protobuf:"bytes,10,opt,name=site" json:"site,omitempty"`
+	SpoofChecked int64     `protobuf:"varint,11,opt,name=spoof_checked" json:"spoof_checked,omitempty"`
+	Port         uint32    `protobuf:"varint,12,opt,name=port" json:"port,omitempty"`
+	SelfTest     *SelfTest `protobuf:"bytes,13,opt,name=self_test" json:"self_test,omitempty"`
 }
 
 func (m *VantagePoint) Reset()                    { *m = VantagePoint{} }
 func (m *VantagePoint) String() string            { return proto.CompactTextString(m) }
 func (*VantagePoint) ProtoMessage()               {}
-func (*VantagePoint) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
+func (*VantagePoint) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{0} }
+
+func (m *VantagePoint) GetSelfTest() *SelfTest {
+	if m != nil {
+		return m.SelfTest
+	}
+	return nil
+}
+
+// SelfTest is what a vp found it can do when testing itself
+type SelfTest struct {
+	Ip uint32 `protobuf:"varint,1,opt,name=ip" json:"ip,omitempty"`
+	// Unix time in seconds the test ran
+	Tested int64 `protobuf:"varint,2,opt,name=tested" json:"tested,omitempty"`
+	// Raw sockets with the ip header included can be opened
+	RawSocket       bool `protobuf:"varint,3,opt,name=raw_socket" json:"raw_socket,omitempty"`
+	SendRecordRoute bool `protobuf:"varint,4,opt,name=send_record_route" json:"send_record_route,omitempty"`
+	SendTimestamp   bool `protobuf:"varint,5,opt,name=send_timestamp" json:"send_timestamp,omitempty"`
+	// ICMP echo replies from outside reach the vp
+	ReceiveIcmp bool     `protobuf:"varint,6,opt,name=receive_icmp" json:"receive_icmp,omitempty"`
+	Errors      []string `protobuf:"bytes,7,rep,name=errors" json:"errors,omitempty"`
+}
+
+func (m *SelfTest) Reset()                    { *m = SelfTest{} }
+func (m *SelfTest) String() string            { return proto.CompactTextString(m) }
+func (*SelfTest) ProtoMessage()               {}
+func (*SelfTest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1} }
+
+type SelfTestResponse struct {
+}
+
+func (m *SelfTestResponse) Reset()                    { *m = SelfTestResponse{} }
+func (m *SelfTestResponse) String() string            { return proto.CompactTextString(m) }
+func (*SelfTestResponse) ProtoMessage()               {}
+func (*SelfTestResponse) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{2} }
 
 type VPRequest struct {
 }
@@ -39,7 +74,7 @@ type VPRequest struct {
 func (m *VPRequest) Reset()                    { *m = VPRequest{} }
 func (m *VPRequest) String() string            { return proto.CompactTextString(m) }
 func (*VPRequest) ProtoMessage()               {}
-func (*VPRequest) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{1} }
+func (*VPRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{3} }
 
 type VPReturn struct {
 	Vps []*VantagePoint `protobuf:"bytes,1,rep,name=vps" json:"vps,omitempty"`
@@ -48,7 +83,7 @@ type VPReturn struct {
 func (m *VPReturn) Reset()                    { *m = VPReturn{} }
 func (m *VPReturn) String() string            { return proto.CompactTextString(m) }
 func (*VPReturn) ProtoMessage()               {}
-func (*VPReturn) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{2} }
+func (*VPReturn) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{4} }
 
 func (m *VPReturn) GetVps() []*VantagePoint {
 	if m != nil {
@@ -57,6 +92,50 @@ func (m *VPReturn) GetVps() []*VantagePoint {
 	return nil
 }
 
+type VPStatusRequest struct {
+	Vps []uint32 `protobuf:"varint,1,rep,name=vps" json:"vps,omitempty"`
+}
+
+func (m *VPStatusRequest) Reset()                    { *m = VPStatusRequest{} }
+func (m *VPStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*VPStatusRequest) ProtoMessage()               {}
+func (*VPStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{5} }
+
+type VPStatus struct {
+	Ip          uint32 `protobuf:"varint,1,opt,name=ip" json:"ip,omitempty"`
+	Outstanding uint32 `protobuf:"varint,2,opt,name=outstanding" json:"outstanding,omitempty"`
+	Queued      uint32 `protobuf:"varint,3,opt,name=queued" json:"queued,omitempty"`
+	Issued      uint64 `protobuf:"varint,4,opt,name=issued" json:"issued,omitempty"`
+	Completed   uint64 `protobuf:"varint,5,opt,name=completed" json:"completed,omitempty"`
+	Errors      uint64 `protobuf:"varint,6,opt,name=errors" json:"errors,omitempty"`
+	Abandoned   uint64 `protobuf:"varint,7,opt,name=abandoned" json:"abandoned,omitempty"`
+	// Moving average of the time from issue to result in microseconds
+	Latency int64 `protobuf:"varint,8,opt,name=latency" json:"latency,omitempty"`
+	// Age of the oldest command still waiting for a result in microseconds
+	OldestOutstanding int64 `protobuf:"varint,9,opt,name=oldest_outstanding" json:"oldest_outstanding,omitempty"`
+}
+
+func (m *VPStatus) Reset()                    { *m = VPStatus{} }
+func (m *VPStatus) String() string            { return proto.CompactTextString(m) }
+func (*VPStatus) ProtoMessage()               {}
+func (*VPStatus) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{6} }
+
+type VPStatusReturn struct {
+	Statuses []*VPStatus `protobuf:"bytes,1,rep,name=statuses" json:"statuses,omitempty"`
+}
+
+func (m *VPStatusReturn) Reset()                    { *m = VPStatusReturn{} }
+func (m *VPStatusReturn) String() string            { return proto.CompactTextString(m) }
+func (*VPStatusReturn) ProtoMessage()               {}
+func (*VPStatusReturn) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{7} }
+
+func (m *VPStatusReturn) GetStatuses() []*VPStatus {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
 type RRSpooferRequest struct {
 	Addr uint32 `protobuf:"varint,1,opt,name=addr" json:"addr,omitempty"`
 	Max  uint32 `protobuf:"varint,2,opt,name=max" json:"max,omitempty"`
@@ -65,7 +144,7 @@ type RRSpooferRequest struct {
 func (m *RRSpooferRequest) Reset()                    { *m = RRSpooferRequest{} }
 func (m *RRSpooferRequest) String() string            { return proto.CompactTextString(m) }
 func (*RRSpooferRequest) ProtoMessage()               {}
-func (*RRSpooferRequest) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{3} }
+func (*RRSpooferRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{8} }
 
 type RRSpooferResponse struct {
 	Addr     uint32          `protobuf:"varint,1,opt,name=addr" json:"addr,omitempty"`
@@ -76,7 +155,7 @@ type RRSpooferResponse struct {
 func (m *RRSpooferResponse) Reset()                    { *m = RRSpooferResponse{} }
 func (m *RRSpooferResponse) String() string            { return proto.CompactTextString(m) }
 func (*RRSpooferResponse) ProtoMessage()               {}
-func (*RRSpooferResponse) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+func (*RRSpooferResponse) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{9} }
 
 func (m *RRSpooferResponse) GetSpoofers() []*VantagePoint {
 	if m != nil {
@@ -92,7 +171,7 @@ type TSSpooferRequest struct {
 func (m *TSSpooferRequest) Reset()                    { *m = TSSpooferRequest{} }
 func (m *TSSpooferRequest) String() string            { return proto.CompactTextString(m) }
 func (*TSSpooferRequest) ProtoMessage()               {}
-func (*TSSpooferRequest) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
+func (*TSSpooferRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{10} }
 
 type TSSpooferResponse struct {
 	Max      uint32          `protobuf:"varint,1,opt,name=max" json:"max,omitempty"`
@@ -102,7 +181,7 @@ type TSSpooferResponse struct {
 func (m *TSSpooferResponse) Reset()                    { *m = TSSpooferResponse{} }
 func (m *TSSpooferResponse) String() string            { return proto.CompactTextString(m) }
 func (*TSSpooferResponse) ProtoMessage()               {}
-func (*TSSpooferResponse) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
+func (*TSSpooferResponse) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{11} }
 
 func (m *TSSpooferResponse) GetSpoofers() []*VantagePoint {
 	if m != nil {
@@ -113,8 +192,13 @@ func (m *TSSpooferResponse) GetSpoofers() []*VantagePoint {
 
 func init() {
 	proto.RegisterType((*VantagePoint)(nil), "datamodel.VantagePoint")
+	proto.RegisterType((*SelfTest)(nil), "datamodel.SelfTest")
+	proto.RegisterType((*SelfTestResponse)(nil), "datamodel.SelfTestResponse")
 	proto.RegisterType((*VPRequest)(nil), "datamodel.VPRequest")
 	proto.RegisterType((*VPReturn)(nil), "datamodel.VPReturn")
+	proto.RegisterType((*VPStatusRequest)(nil), "datamodel.VPStatusRequest")
+	proto.RegisterType((*VPStatus)(nil), "datamodel.VPStatus")
+	proto.RegisterType((*VPStatusReturn)(nil), "datamodel.VPStatusReturn")
 	proto.RegisterType((*RRSpooferRequest)(nil), "datamodel.RRSpooferRequest")
 	proto.RegisterType((*RRSpooferResponse)(nil), "datamodel.RRSpooferResponse")
 	proto.RegisterType((*TSSpooferRequest)(nil), "datamodel.TSSpooferRequest")
@@ -122,33 +206,47 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/vantagepoint.proto", fileDescriptor5)
-}
-
-var fileDescriptor5 = []byte{
-	// 382 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x92, 0x51, 0xcf, 0x93, 0x30,
-	0x14, 0x86, 0xb3, 0xf1, 0xb9, 0xc1, 0x81, 0xc5, 0xad, 0xd1, 0xd8, 0x3b, 0x0d, 0xf1, 0x62, 0x5e,
-	0x0c, 0x8c, 0xfe, 0x02, 0x4d, 0xbc, 0x32, 0x59, 0x16, 0x98, 0x4b, 0xf4, 0x86, 0x74, 0x70, 0x1c,
-	0x44, 0xa0, 0xb5, 0x2d, 0xc4, 0x3f, 0xed, 0x7f, 0xb0, 0x74, 0x38, 0xf1, 0xbb, 0xd8, 0x1d, 0x9c,
-	0x3e, 0xbc, 0xcf, 0xcb, 0x49, 0xe1, 0xc3, 0xa5, 0xd2, 0x65, 0x77, 0x8e, 0x72, 0xde, 0xc4, 0xfb,
-	0x4f, 0x5f, 0x76, 0xe9, 0x3e, 0x8d, 0x13, 0xec, 0x51, 0x2a, 0x3c, 0x4a, 0x96, 0xa3, 0xe4, 0x9d,
-	0xc6, 0xb8, 0x60, 0x9a, 0x35, 0xbc, 0xc0, 0x3a, 0xee, 0x59, 0xab, 0xd9, 0x05, 0x05, 0xaf, 0x5a,
-	0x1d, 0x09, 0xc9, 0x35, 0x27, 0xde, 0xed, 0x34, 0xfc, 0x3d, 0x83, 0xe0, 0x74, 0x25, 0x0e, 0x03,
-	0x41, 0xd6, 0xe0, 0x96, 0x5c, 0xe9, 0x96, 0x35, 0x48, 0x67, 0xaf, 0x66, 0x5b, 0x8f, 0x00, 0xcc,
-	0x2b, 0x41, 0xe7, 0xe6, 0x79, 0x45, 0x9e, 0xc2, 0x52, 0xa9, 0x92, 0x9d, 0x6b, 0xa4, 0x8e, 0x19,
-	0xb8, 0x64, 0x03, 0x9e, 0xae, 0x1a, 0x54, 0x26, 0x4f, 0xd0, 0x07, 0x3b, 0x7a, 0x06, 0x81, 0xc4,
-	0x9c, 0xcb, 0x22, 0xb3, 0x55, 0xe8, 0x93, 0xbf, 0xd3, 0x9a, 0x29, 0x9d, 0x75, 0xc2, 0xc8, 0xb1,
-	0xa0, 0x0b, 0x33, 0x75, 0x86, 0xcf, 0x73, 0xd6, 0x66, 0x4a, 0x70, 0xfe, 0x9d, 0x2e, 0x2d, 0x68,
-	0x7c, 0x39, 0x6f, 0xb5, 0xe4, 0x75, 0x8d, 0x92, 0xba, 0x56, 0xfb, 0x1c, 0x56, 0x26, 0x12, 0xab,
-	0x1e, 0x47, 0xd4, 0xb3, 0x68, 0x00, 0x0f, 0xaa, 0x32, 0x06, 0xb0, 0x3d, 0x0d, 0x64, 0x0f, 0xb3,
-	0xbc, 0xc4, 0xfc, 0x87, 0x51, 0xf8, 0x56, 0x61, 0x20, 0xc1, 0xa5, 0xa6, 0xc1, 0x90, 0x14, 0xfa,
-	0xe0, 0x9d, 0x0e, 0x09, 0xfe, 0xec, 0x4c, 0xe5, 0xf0, 0x2d, 0xb8, 0xc3, 0x8b, 0xee, 0x64, 0x4b,
-	0x5e, 0x83, 0xd3, 0x0b, 0x65, 0x7e, 0xd9, 0xd9, 0xfa, 0xef, 0x5e, 0x44, 0xb7, 0x0d, 0x45, 0xd3,
-	0xed, 0x84, 0x3b, 0x58, 0x27, 0x49, 0x3a, 0x58, 0x50, 0x8e, 0x29, 0x83, 0x80, 0x15, 0x85, 0xb4,
-	0xdb, 0x5a, 0x11, 0x1f, 0x9c, 0x86, 0xfd, 0xba, 0xae, 0x2b, 0xfc, 0x0a, 0x9b, 0x09, 0x6e, 0xda,
-	0xb5, 0x0a, 0xef, 0xf0, 0xe4, 0x0d, 0xb8, 0xea, 0x4a, 0x2b, 0xb3, 0xdf, 0xbb, 0x4d, 0x5e, 0xc2,
-	0xfa, 0x98, 0x3e, 0x6a, 0x32, 0x66, 0xd9, 0xe0, 0xf0, 0x33, 0x6c, 0x26, 0xc0, 0xe8, 0x9e, 0x12,
-	0xff, 0xd9, 0xe6, 0x77, 0x6d, 0x1f, 0xfd, 0x6f, 0xff, 0xee, 0xcc, 0x79, 0x61, 0x6f, 0xd1, 0xfb,
-	0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x6e, 0xcd, 0x6a, 0x6d, 0x8a, 0x02, 0x00, 0x00,
+	proto.RegisterFile("github.com/NEU-SNS/ReverseTraceroute/datamodel/vantagepoint.proto", fileDescriptor7)
+}
+
+var fileDescriptor7 = []byte{
+	// 607 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x94, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0xc7, 0x95, 0xa6, 0xeb, 0xd2, 0x97, 0xb6, 0x6b, 0x33, 0x06, 0x86, 0x03, 0x44, 0x11, 0xa0,
+	0x70, 0x58, 0x8b, 0xc6, 0x81, 0x33, 0x48, 0x9c, 0x90, 0xa6, 0xa9, 0x29, 0x93, 0xe0, 0x12, 0xb9,
+	0xf1, 0xdb, 0x1a, 0x2d, 0xb1, 0x33, 0xdb, 0x29, 0xf0, 0x39, 0x10, 0x5f, 0x83, 0xcf, 0x88, 0xec,
+	0x26, 0x6b, 0xb6, 0xc3, 0x8e, 0xcf, 0xfe, 0xfb, 0xbd, 0xf7, 0xff, 0xbd, 0x27, 0xc3, 0xa7, 0xeb,
+	0x5c, 0x6f, 0xea, 0xf5, 0x3c, 0x13, 0xe5, 0xe2, 0xfc, 0xcb, 0xb7, 0xd3, 0xe4, 0x3c, 0x59, 0x2c,
+	0x71, 0x8b, 0x52, 0xe1, 0x4a, 0xd2, 0x0c, 0xa5, 0xa8, 0x35, 0x2e, 0x18, 0xd5, 0xb4, 0x14, 0x0c,
+	0x8b, 0xc5, 0x96, 0x72, 0x4d, 0xaf, 0xb1, 0x12, 0x39, 0xd7, 0xf3, 0x4a, 0x0a, 0x2d, 0x82, 0xe1,
+	0xdd, 0x6d, 0xf4, 0xb7, 0x07, 0xa3, 0xcb, 0x9d, 0xe2, 0xc2, 0x28, 0x82, 0x29, 0x78, 0x1b, 0xa1,
+	0x34, 0xa7, 0x25, 0x12, 0x27, 0x74, 0xe2, 0x61, 0x00, 0xd0, 0xcb, 0x2b, 0xd2, 0x0b, 0x9d, 0x78,
+	0x1c, 0x1c, 0xc1, 0xa1, 0x52, 0x1b, 0xba, 0x2e, 0x90, 0xb8, 0xa1, 0x13, 0x7b, 0xc1, 0x0c, 0x86,
+	0x3a, 0x2f, 0x51, 0x69, 0x5a, 0x56, 0xa4, 0x6f, 0x8f, 0x9e, 0xc0, 0x48, 0x62, 0x26, 0x24, 0x4b,
+	0x6d, 0x2b, 0xe4, 0xa0, 0x3d, 0x2d, 0xa8, 0xd2, 0x69, 0x5d, 0x31, 0xaa, 0x91, 0x91, 0x41, 0xe8,
+	0xc4, 0xae, 0x79, 0x9e, 0x51, 0x9e, 0xaa, 0x4a, 0x88, 0x2b, 0x72, 0x68, 0x85, 0x01, 0x40, 0x26,
+	0xb8, 0x96, 0xa2, 0x28, 0x50, 0x12, 0xcf, 0x96, 0x3d, 0x81, 0xb1, 0xc4, 0x0c, 0xf3, 0x2d, 0x36,
+	0xd2, 0xa1, 0x95, 0x8e, 0xa0, 0xaf, 0x72, 0x8d, 0x04, 0x6c, 0x9f, 0x27, 0x30, 0xb6, 0x97, 0x69,
+	0xb6, 0xc1, 0xec, 0x06, 0x19, 0xf1, 0x6d, 0x89, 0x11, 0xf4, 0x2b, 0x21, 0x35, 0x19, 0xd9, 0x4c,
+	0x6f, 0x61, 0xa8, 0xb0, 0xb8, 0x4a, 0x35, 0x2a, 0x4d, 0xc6, 0xa1, 0x13, 0xfb, 0x67, 0xc7, 0xf3,
+	0x3b, 0x1c, 0xf3, 0x04, 0x8b, 0xab, 0x15, 0x2a, 0x1d, 0xfd, 0x71, 0xc0, 0x6b, 0x83, 0x86, 0x80,
+	0x63, 0x13, 0x4c, 0x60, 0x60, 0xde, 0x22, 0xb3, 0x44, 0x5c, 0xd3, 0xae, 0xa4, 0x3f, 0x53, 0x25,
+	0xb2, 0x1b, 0xd4, 0x0d, 0x94, 0xe7, 0x30, 0x53, 0xc8, 0x59, 0x7a, 0x0f, 0xc3, 0x0e, 0xce, 0x53,
+	0x98, 0xd8, 0xab, 0x3d, 0xb4, 0x83, 0x0e, 0x34, 0xeb, 0x30, 0xcf, 0xca, 0xca, 0xe2, 0xf1, 0x4c,
+	0x31, 0x94, 0x52, 0x48, 0x45, 0x0e, 0x43, 0x37, 0x1e, 0x46, 0x01, 0x4c, 0xdb, 0xa6, 0x96, 0xa8,
+	0x2a, 0xc1, 0x15, 0x46, 0x3e, 0x0c, 0x2f, 0x2f, 0x96, 0x78, 0x5b, 0x9b, 0xb6, 0xdf, 0x83, 0x67,
+	0x02, 0x5d, 0x4b, 0x1e, 0xbc, 0x06, 0x77, 0x5b, 0x29, 0xe2, 0x84, 0x6e, 0xec, 0x9f, 0x3d, 0xeb,
+	0x98, 0xec, 0xce, 0x3b, 0x7a, 0x09, 0x47, 0x97, 0x17, 0x89, 0xa6, 0xba, 0x56, 0x4d, 0x92, 0xc0,
+	0xdf, 0x3f, 0x1c, 0x47, 0xff, 0x1c, 0xf0, 0x5a, 0xc1, 0x3d, 0x10, 0xc7, 0xe0, 0x8b, 0x5a, 0x2b,
+	0x4d, 0x39, 0xcb, 0xf9, 0x75, 0xb3, 0x1f, 0x13, 0x18, 0xdc, 0xd6, 0x58, 0x23, 0x23, 0x6e, 0x1b,
+	0xe7, 0x4a, 0x99, 0xd8, 0xd8, 0xef, 0xdb, 0x79, 0x8b, 0xb2, 0x2a, 0xd0, 0x00, 0x3c, 0xb0, 0x47,
+	0x7b, 0x8f, 0x83, 0x56, 0x42, 0xd7, 0x94, 0x33, 0xc1, 0x91, 0xd9, 0x95, 0xe8, 0x9b, 0xad, 0x2b,
+	0xa8, 0x46, 0x9e, 0xfd, 0xb6, 0xfb, 0xe0, 0x06, 0x2f, 0x20, 0x10, 0x05, 0x43, 0xa5, 0xd3, 0x6e,
+	0x0b, 0x66, 0x29, 0xdc, 0xe8, 0x23, 0x4c, 0xf6, 0x86, 0x2c, 0x88, 0x37, 0xe0, 0x29, 0x1b, 0x63,
+	0x4b, 0xa3, 0x3b, 0xf2, 0x56, 0x1c, 0x9d, 0xc2, 0x74, 0xb9, 0x4c, 0xcc, 0x06, 0xa1, 0x6c, 0x51,
+	0x8c, 0xa0, 0x4f, 0x19, 0x93, 0x8d, 0x65, 0x1f, 0xdc, 0x92, 0xfe, 0xda, 0x59, 0x8d, 0xbe, 0xc3,
+	0xac, 0x23, 0xdf, 0x0d, 0xe3, 0x11, 0x7d, 0xf0, 0x0e, 0x3c, 0xb5, 0x53, 0x2b, 0xe2, 0x3e, 0x3e,
+	0x93, 0x57, 0x30, 0x5d, 0x25, 0x0f, 0x3a, 0x69, 0x72, 0xd9, 0xc4, 0xd1, 0x57, 0x98, 0xad, 0x92,
+	0x87, 0xb5, 0xbb, 0x8a, 0x7b, 0xd5, 0x7a, 0x8f, 0x56, 0xfb, 0xec, 0xff, 0xd8, 0xff, 0x07, 0xeb,
+	0x81, 0xfd, 0x21, 0x3e, 0xfc, 0x1f, 0x00, 0xd4, 0x2b, 0x4c, 0x90, 0x66, 0x04, 0x00, 0x00,
 }
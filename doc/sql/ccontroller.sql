@@ -202,6 +202,109 @@ CREATE TABLE `trace_batch_trace` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `tracelb_links`
+--
+
+DROP TABLE IF EXISTS `tracelb_links`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `tracelb_links` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `tracelb_id` bigint(20) NOT NULL,
+  `link` int(10) unsigned NOT NULL,
+  `addr_from` int(10) unsigned NOT NULL,
+  `addr_to` int(10) unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `tracelb_id` (`tracelb_id`),
+  CONSTRAINT `tracelb_link` FOREIGN KEY (`tracelb_id`) REFERENCES `tracelbs` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `tracelb_nodes`
+--
+
+DROP TABLE IF EXISTS `tracelb_nodes`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `tracelb_nodes` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `tracelb_id` bigint(20) NOT NULL,
+  `node` int(10) unsigned NOT NULL,
+  `addr` int(10) unsigned NOT NULL,
+  `q_ttl` int(10) unsigned NOT NULL,
+  `name` varchar(255) COLLATE utf8_unicode_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `tracelb_id` (`tracelb_id`),
+  KEY `addr` (`addr`),
+  CONSTRAINT `tracelb_node` FOREIGN KEY (`tracelb_id`) REFERENCES `tracelbs` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `tracelb_replies`
+--
+
+DROP TABLE IF EXISTS `tracelb_replies`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `tracelb_replies` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `tracelb_link_id` bigint(20) NOT NULL,
+  `hop` int(10) unsigned NOT NULL,
+  `flowid` int(10) unsigned NOT NULL,
+  `ttl` int(10) unsigned NOT NULL,
+  `attempt` int(10) unsigned NOT NULL,
+  `addr` int(10) unsigned NOT NULL,
+  `rtt` int(10) unsigned NOT NULL,
+  `reply_ttl` int(10) unsigned DEFAULT NULL,
+  `reply_ipid` int(10) unsigned DEFAULT NULL,
+  `icmp_type` int(10) unsigned DEFAULT NULL,
+  `icmp_code` int(10) unsigned DEFAULT NULL,
+  `icmp_q_ttl` int(10) unsigned DEFAULT NULL,
+  `icmp_q_tos` int(10) unsigned DEFAULT NULL,
+  `tcp_flags` int(10) unsigned DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `tracelb_link_id` (`tracelb_link_id`),
+  CONSTRAINT `tracelb_reply` FOREIGN KEY (`tracelb_link_id`) REFERENCES `tracelb_links` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `tracelbs`
+--
+
+DROP TABLE IF EXISTS `tracelbs`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `tracelbs` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `src` int(10) unsigned NOT NULL,
+  `dst` int(10) unsigned NOT NULL,
+  `type` varchar(45) COLLATE utf8_unicode_ci DEFAULT NULL,
+  `user_id` int(10) unsigned NOT NULL,
+  `method` varchar(45) COLLATE utf8_unicode_ci DEFAULT NULL,
+  `sport` int(10) unsigned NOT NULL,
+  `dport` int(10) unsigned NOT NULL,
+  `start` datetime NOT NULL,
+  `probe_size` int(10) unsigned NOT NULL,
+  `first_hop` int(10) unsigned NOT NULL,
+  `wait_timeout` int(10) unsigned NOT NULL,
+  `wait_probe` int(10) unsigned NOT NULL,
+  `attempts` int(10) unsigned NOT NULL,
+  `confidence` int(10) unsigned NOT NULL,
+  `tos` int(10) unsigned NOT NULL,
+  `gap_limit` int(10) unsigned NOT NULL,
+  `probec` int(10) unsigned NOT NULL,
+  `probec_max` int(10) unsigned NOT NULL,
+  `created` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `src_dst` (`src`,`dst`) USING BTREE,
+  KEY `created` (`created`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `traceroute_hops`
 --
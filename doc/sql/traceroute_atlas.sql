@@ -44,6 +44,7 @@ CREATE TABLE `atlas_traceroutes` (
   `dest` int(10) unsigned NOT NULL DEFAULT '0',
   `src` int(10) unsigned NOT NULL DEFAULT '0',
   `date` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `source` tinyint(3) unsigned NOT NULL DEFAULT '0',
   PRIMARY KEY (`Id`),
   KEY `index2` (`dest`,`date`) USING BTREE,
   KEY `index3` (`src`,`dest`,`date`)
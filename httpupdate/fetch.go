@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package httpupdate
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// maxSize is the largest binary or signature Fetch will download
+const maxSize = 256 << 20
+
+// ParseKey parses a base64 encoded PKIX ECDSA public key, the form a key is
+// embedded in a binary with -ldflags -X
+func ParseKey(s string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Update key is not an ECDSA key: %T", pub)
+	}
+	return key, nil
+}
+
+type ecdsaSig struct {
+	R, S *big.Int
+}
+
+// Verify checks that sig is a signature of bin by key. The signature is the
+// ASN.1 ECDSA signature of the SHA-256 of bin, as made by
+// openssl dgst -sha256 -sign
+func Verify(bin, sig []byte, key *ecdsa.PublicKey) error {
+	var s ecdsaSig
+	rest, err := asn1.Unmarshal(sig, &s)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 || s.R == nil || s.S == nil {
+		return fmt.Errorf("Malformed signature")
+	}
+	sum := sha256.Sum256(bin)
+	if !ecdsa.Verify(key, sum[:], s.R, s.S) {
+		return fmt.Errorf("Signature verification failed")
+	}
+	return nil
+}
+
+func get(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to get %s: %d", url, resp.StatusCode)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxSize))
+}
+
+// Fetch downloads the binary at url and its detached signature at url.sig
+// and returns the binary if the signature verifies against key
+func Fetch(url string, key *ecdsa.PublicKey) ([]byte, error) {
+	bin, err := get(url)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := get(url + ".sig")
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(bin, sig, key); err != nil {
+		return nil, err
+	}
+	return bin, nil
+}
+
+// Install replaces the file at path with bin. The new file is written next
+// to path and renamed over it so path is never partially written, and the
+// old file is kept at path.old for Rollback
+func Install(path string, bin []byte) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(bin); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), fi.Mode().Perm()); err != nil {
+		return err
+	}
+	old := path + ".old"
+	if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Link(path, old); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Rollback puts back the file Install replaced
+func Rollback(path string) error {
+	return os.Rename(path+".old", path)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+package httpupdate_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NEU-SNS/ReverseTraceroute/httpupdate"
+)
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, base64.StdEncoding.EncodeToString(der)
+}
+
+func sign(t *testing.T, priv *ecdsa.PrivateKey, b []byte) []byte {
+	sum := sha256.Sum256(b)
+	r, s, err := ecdsa.Sign(rand.Reader, priv, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestFetch(t *testing.T) {
+	priv, enc := newKey(t)
+	key, err := httpupdate.ParseKey(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := []byte("new plvp")
+	files := map[string][]byte{
+		"/good":         bin,
+		"/good.sig":     sign(t, priv, bin),
+		"/tampered":     []byte("evil plvp"),
+		"/tampered.sig": sign(t, priv, bin),
+		"/unsigned":     bin,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, ok := files[req.URL.Path]
+		if !ok {
+			http.NotFound(rw, req)
+			return
+		}
+		rw.Write(b)
+	}))
+	defer srv.Close()
+	got, err := httpupdate.Fetch(srv.URL+"/good", key)
+	if err != nil || string(got) != string(bin) {
+		t.Fatalf("Fetch(good) = %q, %v expected %q", got, err, bin)
+	}
+	for _, name := range []string{"/tampered", "/unsigned", "/missing"} {
+		if _, err := httpupdate.Fetch(srv.URL+name, key); err == nil {
+			t.Errorf("Fetch(%s) expected an error", name)
+		}
+	}
+}
+
+func TestInstallRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpupdate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "plvp")
+	if err := ioutil.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := httpupdate.Install(path, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(path)
+	fi, err := os.Stat(path)
+	if err != nil || string(b) != "new" || fi.Mode().Perm() != 0755 {
+		t.Fatalf("After Install got %q mode %v, expected \"new\" mode 0755", b, fi.Mode())
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 2 {
+		t.Fatalf("Install left %d files, expected the binary and its backup", len(files))
+	}
+	if err := httpupdate.Rollback(path); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = ioutil.ReadFile(path)
+	if string(b) != "old" {
+		t.Fatalf("After Rollback got %q, expected \"old\"", b)
+	}
+}
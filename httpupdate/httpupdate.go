@@ -79,6 +79,7 @@ func (u *updater) CheckUpdate(statUrl, version string) (bool, error) {
 		return false, err
 	}
 	u.fetchUrl = stat.Get
+	u.newVersion = stat.Version
 	vr, err := semver.Make(version)
 	if err != nil {
 		// Return true if the version passed in isn't a semvar
@@ -165,8 +165,9 @@ func (p *Process) Wait() chan error {
 		pr := p.proc
 		p.mu.Unlock()
 		state, err := pr.Wait()
-		if err == nil {
+		if err != nil {
 			done <- err
+			return
 		}
 		p.mu.Lock()
 		p.procState = state
@@ -8,6 +8,20 @@ type VPStore struct {
 	mock.Mock
 }
 
+// ClearAllVPs provides a mock function with given fields:
+func (_m *VPStore) ClearAllVPs() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateController provides a mock function with given fields: _a0, _a1, _a2
 func (_m *VPStore) UpdateController(_a0 uint32, _a1 uint32, _a2 uint32) error {
 	ret := _m.Called(_a0, _a1, _a2)
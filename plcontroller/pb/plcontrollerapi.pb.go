@@ -19,6 +19,8 @@ import datamodel1 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 import datamodel2 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 import datamodel3 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 import datamodel4 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+import datamodel5 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+import datamodel6 "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 
 import (
 	context "golang.org/x/net/context"
@@ -49,9 +51,13 @@ const _ = grpc.SupportPackageIsVersion3
 type PLControllerClient interface {
 	Ping(ctx context.Context, opts ...grpc.CallOption) (PLController_PingClient, error)
 	Traceroute(ctx context.Context, opts ...grpc.CallOption) (PLController_TracerouteClient, error)
-	ReceiveSpoof(ctx context.Context, in *datamodel4.RecSpoof, opts ...grpc.CallOption) (PLController_ReceiveSpoofClient, error)
-	GetVPs(ctx context.Context, in *datamodel3.VPRequest, opts ...grpc.CallOption) (PLController_GetVPsClient, error)
-	AcceptProbes(ctx context.Context, in *datamodel4.SpoofedProbes, opts ...grpc.CallOption) (*datamodel4.SpoofedProbesResponse, error)
+	Tracelb(ctx context.Context, opts ...grpc.CallOption) (PLController_TracelbClient, error)
+	Dealias(ctx context.Context, opts ...grpc.CallOption) (PLController_DealiasClient, error)
+	ReceiveSpoof(ctx context.Context, in *datamodel6.RecSpoof, opts ...grpc.CallOption) (PLController_ReceiveSpoofClient, error)
+	GetVPs(ctx context.Context, in *datamodel5.VPRequest, opts ...grpc.CallOption) (PLController_GetVPsClient, error)
+	GetVPStatus(ctx context.Context, in *datamodel5.VPStatusRequest, opts ...grpc.CallOption) (*datamodel5.VPStatusReturn, error)
+	ReportSelfTest(ctx context.Context, in *datamodel5.SelfTest, opts ...grpc.CallOption) (*datamodel5.SelfTestResponse, error)
+	AcceptProbes(ctx context.Context, in *datamodel6.SpoofedProbes, opts ...grpc.CallOption) (*datamodel6.SpoofedProbesResponse, error)
 }
 
 type pLControllerClient struct {
@@ -124,8 +130,70 @@ func (x *pLControllerTracerouteClient) Recv() (*datamodel2.Traceroute, error) {
 	return m, nil
 }
 
-func (c *pLControllerClient) ReceiveSpoof(ctx context.Context, in *datamodel4.RecSpoof, opts ...grpc.CallOption) (PLController_ReceiveSpoofClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_PLController_serviceDesc.Streams[2], c.cc, "/pb.PLController/ReceiveSpoof", opts...)
+func (c *pLControllerClient) Tracelb(ctx context.Context, opts ...grpc.CallOption) (PLController_TracelbClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PLController_serviceDesc.Streams[2], c.cc, "/pb.PLController/Tracelb", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pLControllerTracelbClient{stream}
+	return x, nil
+}
+
+type PLController_TracelbClient interface {
+	Send(*datamodel3.TracelbArg) error
+	Recv() (*datamodel3.Tracelb, error)
+	grpc.ClientStream
+}
+
+type pLControllerTracelbClient struct {
+	grpc.ClientStream
+}
+
+func (x *pLControllerTracelbClient) Send(m *datamodel3.TracelbArg) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pLControllerTracelbClient) Recv() (*datamodel3.Tracelb, error) {
+	m := new(datamodel3.Tracelb)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pLControllerClient) Dealias(ctx context.Context, opts ...grpc.CallOption) (PLController_DealiasClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PLController_serviceDesc.Streams[3], c.cc, "/pb.PLController/Dealias", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pLControllerDealiasClient{stream}
+	return x, nil
+}
+
+type PLController_DealiasClient interface {
+	Send(*datamodel4.DealiasArg) error
+	Recv() (*datamodel4.Dealias, error)
+	grpc.ClientStream
+}
+
+type pLControllerDealiasClient struct {
+	grpc.ClientStream
+}
+
+func (x *pLControllerDealiasClient) Send(m *datamodel4.DealiasArg) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pLControllerDealiasClient) Recv() (*datamodel4.Dealias, error) {
+	m := new(datamodel4.Dealias)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pLControllerClient) ReceiveSpoof(ctx context.Context, in *datamodel6.RecSpoof, opts ...grpc.CallOption) (PLController_ReceiveSpoofClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PLController_serviceDesc.Streams[4], c.cc, "/pb.PLController/ReceiveSpoof", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +208,7 @@ func (c *pLControllerClient) ReceiveSpoof(ctx context.Context, in *datamodel4.Re
 }
 
 type PLController_ReceiveSpoofClient interface {
-	Recv() (*datamodel4.NotifyRecSpoofResponse, error)
+	Recv() (*datamodel6.NotifyRecSpoofResponse, error)
 	grpc.ClientStream
 }
 
@@ -148,16 +216,16 @@ type pLControllerReceiveSpoofClient struct {
 	grpc.ClientStream
 }
 
-func (x *pLControllerReceiveSpoofClient) Recv() (*datamodel4.NotifyRecSpoofResponse, error) {
-	m := new(datamodel4.NotifyRecSpoofResponse)
+func (x *pLControllerReceiveSpoofClient) Recv() (*datamodel6.NotifyRecSpoofResponse, error) {
+	m := new(datamodel6.NotifyRecSpoofResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *pLControllerClient) GetVPs(ctx context.Context, in *datamodel3.VPRequest, opts ...grpc.CallOption) (PLController_GetVPsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_PLController_serviceDesc.Streams[3], c.cc, "/pb.PLController/GetVPs", opts...)
+func (c *pLControllerClient) GetVPs(ctx context.Context, in *datamodel5.VPRequest, opts ...grpc.CallOption) (PLController_GetVPsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PLController_serviceDesc.Streams[5], c.cc, "/pb.PLController/GetVPs", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +240,7 @@ func (c *pLControllerClient) GetVPs(ctx context.Context, in *datamodel3.VPReques
 }
 
 type PLController_GetVPsClient interface {
-	Recv() (*datamodel3.VPReturn, error)
+	Recv() (*datamodel5.VPReturn, error)
 	grpc.ClientStream
 }
 
@@ -180,16 +248,34 @@ type pLControllerGetVPsClient struct {
 	grpc.ClientStream
 }
 
-func (x *pLControllerGetVPsClient) Recv() (*datamodel3.VPReturn, error) {
-	m := new(datamodel3.VPReturn)
+func (x *pLControllerGetVPsClient) Recv() (*datamodel5.VPReturn, error) {
+	m := new(datamodel5.VPReturn)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *pLControllerClient) AcceptProbes(ctx context.Context, in *datamodel4.SpoofedProbes, opts ...grpc.CallOption) (*datamodel4.SpoofedProbesResponse, error) {
-	out := new(datamodel4.SpoofedProbesResponse)
+func (c *pLControllerClient) GetVPStatus(ctx context.Context, in *datamodel5.VPStatusRequest, opts ...grpc.CallOption) (*datamodel5.VPStatusReturn, error) {
+	out := new(datamodel5.VPStatusReturn)
+	err := grpc.Invoke(ctx, "/pb.PLController/GetVPStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pLControllerClient) ReportSelfTest(ctx context.Context, in *datamodel5.SelfTest, opts ...grpc.CallOption) (*datamodel5.SelfTestResponse, error) {
+	out := new(datamodel5.SelfTestResponse)
+	err := grpc.Invoke(ctx, "/pb.PLController/ReportSelfTest", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pLControllerClient) AcceptProbes(ctx context.Context, in *datamodel6.SpoofedProbes, opts ...grpc.CallOption) (*datamodel6.SpoofedProbesResponse, error) {
+	out := new(datamodel6.SpoofedProbesResponse)
 	err := grpc.Invoke(ctx, "/pb.PLController/AcceptProbes", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
@@ -202,9 +288,13 @@ func (c *pLControllerClient) AcceptProbes(ctx context.Context, in *datamodel4.Sp
 type PLControllerServer interface {
 	Ping(PLController_PingServer) error
 	Traceroute(PLController_TracerouteServer) error
-	ReceiveSpoof(*datamodel4.RecSpoof, PLController_ReceiveSpoofServer) error
-	GetVPs(*datamodel3.VPRequest, PLController_GetVPsServer) error
-	AcceptProbes(context.Context, *datamodel4.SpoofedProbes) (*datamodel4.SpoofedProbesResponse, error)
+	Tracelb(PLController_TracelbServer) error
+	Dealias(PLController_DealiasServer) error
+	ReceiveSpoof(*datamodel6.RecSpoof, PLController_ReceiveSpoofServer) error
+	GetVPs(*datamodel5.VPRequest, PLController_GetVPsServer) error
+	GetVPStatus(context.Context, *datamodel5.VPStatusRequest) (*datamodel5.VPStatusReturn, error)
+	ReportSelfTest(context.Context, *datamodel5.SelfTest) (*datamodel5.SelfTestResponse, error)
+	AcceptProbes(context.Context, *datamodel6.SpoofedProbes) (*datamodel6.SpoofedProbesResponse, error)
 }
 
 func RegisterPLControllerServer(s *grpc.Server, srv PLControllerServer) {
@@ -263,8 +353,60 @@ func (x *pLControllerTracerouteServer) Recv() (*datamodel2.TracerouteArg, error)
 	return m, nil
 }
 
+func _PLController_Tracelb_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PLControllerServer).Tracelb(&pLControllerTracelbServer{stream})
+}
+
+type PLController_TracelbServer interface {
+	Send(*datamodel3.Tracelb) error
+	Recv() (*datamodel3.TracelbArg, error)
+	grpc.ServerStream
+}
+
+type pLControllerTracelbServer struct {
+	grpc.ServerStream
+}
+
+func (x *pLControllerTracelbServer) Send(m *datamodel3.Tracelb) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pLControllerTracelbServer) Recv() (*datamodel3.TracelbArg, error) {
+	m := new(datamodel3.TracelbArg)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _PLController_Dealias_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PLControllerServer).Dealias(&pLControllerDealiasServer{stream})
+}
+
+type PLController_DealiasServer interface {
+	Send(*datamodel4.Dealias) error
+	Recv() (*datamodel4.DealiasArg, error)
+	grpc.ServerStream
+}
+
+type pLControllerDealiasServer struct {
+	grpc.ServerStream
+}
+
+func (x *pLControllerDealiasServer) Send(m *datamodel4.Dealias) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pLControllerDealiasServer) Recv() (*datamodel4.DealiasArg, error) {
+	m := new(datamodel4.DealiasArg)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _PLController_ReceiveSpoof_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(datamodel4.RecSpoof)
+	m := new(datamodel6.RecSpoof)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -272,7 +414,7 @@ func _PLController_ReceiveSpoof_Handler(srv interface{}, stream grpc.ServerStrea
 }
 
 type PLController_ReceiveSpoofServer interface {
-	Send(*datamodel4.NotifyRecSpoofResponse) error
+	Send(*datamodel6.NotifyRecSpoofResponse) error
 	grpc.ServerStream
 }
 
@@ -280,12 +422,12 @@ type pLControllerReceiveSpoofServer struct {
 	grpc.ServerStream
 }
 
-func (x *pLControllerReceiveSpoofServer) Send(m *datamodel4.NotifyRecSpoofResponse) error {
+func (x *pLControllerReceiveSpoofServer) Send(m *datamodel6.NotifyRecSpoofResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
 func _PLController_GetVPs_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(datamodel3.VPRequest)
+	m := new(datamodel5.VPRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -293,7 +435,7 @@ func _PLController_GetVPs_Handler(srv interface{}, stream grpc.ServerStream) err
 }
 
 type PLController_GetVPsServer interface {
-	Send(*datamodel3.VPReturn) error
+	Send(*datamodel5.VPReturn) error
 	grpc.ServerStream
 }
 
@@ -301,12 +443,48 @@ type pLControllerGetVPsServer struct {
 	grpc.ServerStream
 }
 
-func (x *pLControllerGetVPsServer) Send(m *datamodel3.VPReturn) error {
+func (x *pLControllerGetVPsServer) Send(m *datamodel5.VPReturn) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _PLController_GetVPStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(datamodel5.VPStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PLControllerServer).GetVPStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.PLController/GetVPStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PLControllerServer).GetVPStatus(ctx, req.(*datamodel5.VPStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PLController_ReportSelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(datamodel5.SelfTest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PLControllerServer).ReportSelfTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.PLController/ReportSelfTest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PLControllerServer).ReportSelfTest(ctx, req.(*datamodel5.SelfTest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PLController_AcceptProbes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(datamodel4.SpoofedProbes)
+	in := new(datamodel6.SpoofedProbes)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -318,7 +496,7 @@ func _PLController_AcceptProbes_Handler(srv interface{}, ctx context.Context, de
 		FullMethod: "/pb.PLController/AcceptProbes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PLControllerServer).AcceptProbes(ctx, req.(*datamodel4.SpoofedProbes))
+		return srv.(PLControllerServer).AcceptProbes(ctx, req.(*datamodel6.SpoofedProbes))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -327,6 +505,14 @@ var _PLController_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.PLController",
 	HandlerType: (*PLControllerServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVPStatus",
+			Handler:    _PLController_GetVPStatus_Handler,
+		},
+		{
+			MethodName: "ReportSelfTest",
+			Handler:    _PLController_ReportSelfTest_Handler,
+		},
 		{
 			MethodName: "AcceptProbes",
 			Handler:    _PLController_AcceptProbes_Handler,
@@ -345,6 +531,18 @@ var _PLController_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Tracelb",
+			Handler:       _PLController_Tracelb_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Dealias",
+			Handler:       _PLController_Dealias_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "ReceiveSpoof",
 			Handler:       _PLController_ReceiveSpoof_Handler,
@@ -364,25 +562,30 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 317 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x92, 0x4d, 0x4b, 0xf3, 0x40,
-	0x14, 0x85, 0xdf, 0x96, 0xd2, 0xc5, 0x50, 0x78, 0x61, 0xaa, 0x20, 0x59, 0xa9, 0x2b, 0x37, 0x26,
-	0x7e, 0x2c, 0xc4, 0x85, 0x48, 0x2d, 0x22, 0x94, 0x52, 0x42, 0xaa, 0x5d, 0xb8, 0x9b, 0x4c, 0x6f,
-	0xe3, 0x40, 0x3a, 0x77, 0x9c, 0xb9, 0x29, 0xf8, 0x53, 0xfd, 0x37, 0x4e, 0xd3, 0xda, 0xa6, 0x45,
-	0xc1, 0x2c, 0xe7, 0x9c, 0x7b, 0x9e, 0xfb, 0xc1, 0xb0, 0x41, 0xa6, 0xe8, 0xad, 0x48, 0x43, 0x89,
-	0xf3, 0x68, 0xf4, 0xf8, 0x72, 0x3e, 0x1e, 0x8d, 0xa3, 0x04, 0x16, 0x60, 0x1d, 0x3c, 0x5b, 0x21,
-	0xc1, 0x62, 0x41, 0x10, 0x99, 0x5c, 0xa2, 0x26, 0x8b, 0x79, 0x0e, 0x36, 0x32, 0xe9, 0xce, 0x5b,
-	0x18, 0x15, 0x1a, 0x8b, 0x84, 0xbc, 0x69, 0xd2, 0xe0, 0xf6, 0x4f, 0xbc, 0xa9, 0x20, 0x31, 0xc7,
-	0x29, 0xe4, 0x91, 0x51, 0x3a, 0x5b, 0xc5, 0x83, 0xfb, 0x9a, 0x51, 0xda, 0x88, 0x6b, 0x40, 0xaf,
-	0x26, 0x60, 0x21, 0x34, 0x89, 0x0c, 0x0c, 0x2a, 0x4d, 0x6b, 0xc4, 0x5d, 0x4d, 0x84, 0x05, 0xe9,
-	0x0c, 0xe2, 0x6c, 0x15, 0xbf, 0xfa, 0x6c, 0xb2, 0x4e, 0x3c, 0xec, 0x6f, 0x6e, 0xc3, 0x2f, 0x59,
-	0x2b, 0xf6, 0x1b, 0x72, 0x1e, 0x6e, 0x32, 0xe1, 0x52, 0xe8, 0xd9, 0x2c, 0xf8, 0xbf, 0xa7, 0x9d,
-	0xfe, 0x3b, 0x6b, 0x5c, 0x34, 0x78, 0x9f, 0xb1, 0x6d, 0x2b, 0x7e, 0x54, 0x29, 0xda, 0xca, 0xcb,
-	0xf8, 0xe1, 0x8f, 0xce, 0x1a, 0x32, 0x60, 0x9d, 0x04, 0x24, 0xa8, 0x05, 0x8c, 0x97, 0xe3, 0xf1,
-	0x6e, 0xa5, 0xd8, 0x1b, 0xa5, 0x18, 0x9c, 0x54, 0xc4, 0x11, 0x92, 0x9a, 0x7d, 0x7c, 0x5b, 0x09,
-	0xf8, 0xad, 0xb4, 0xf3, 0x34, 0xcf, 0xba, 0x61, 0xed, 0x27, 0xa0, 0x49, 0xec, 0xf8, 0x41, 0x25,
-	0x30, 0x89, 0x13, 0x78, 0x2f, 0xc0, 0x51, 0xd0, 0xdd, 0x53, 0xa9, 0xb0, 0xba, 0x0c, 0x0e, 0x59,
-	0xa7, 0x27, 0x25, 0x18, 0x8a, 0x2d, 0xa6, 0xe0, 0x76, 0x76, 0x29, 0xdb, 0xc0, 0x74, 0xe5, 0x04,
-	0xc7, 0xbf, 0x39, 0xdb, 0x41, 0x1e, 0x5a, 0xaf, 0xfe, 0x7f, 0xa5, 0xed, 0xf2, 0xd0, 0xd7, 0x5f,
-	0x01, 0x00, 0x00, 0xff, 0xff, 0x06, 0x05, 0x2e, 0x0f, 0xb8, 0x02, 0x00, 0x00,
+	// 400 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xcf, 0x8b, 0xd3, 0x40,
+	0x14, 0xc7, 0xb7, 0x4b, 0x59, 0x61, 0x2c, 0x0a, 0xb3, 0x0a, 0x3a, 0x5e, 0x74, 0x4f, 0x5e, 0x4c,
+	0x56, 0x3d, 0x88, 0xa2, 0x48, 0xdd, 0x55, 0xa1, 0x94, 0x12, 0x92, 0xda, 0x83, 0xb7, 0x99, 0xc9,
+	0x6b, 0x1c, 0x98, 0x66, 0xc6, 0x99, 0x97, 0x82, 0x7f, 0xad, 0xff, 0x8a, 0xe4, 0x47, 0x9b, 0xa4,
+	0xad, 0xb0, 0xed, 0xb1, 0x9f, 0xef, 0xfb, 0x7c, 0xcb, 0x7b, 0x13, 0x32, 0xc9, 0x14, 0xfe, 0x2a,
+	0x44, 0x20, 0xcd, 0x2a, 0x9c, 0x7d, 0xfd, 0xf1, 0x2a, 0x99, 0x25, 0x61, 0x0c, 0x6b, 0x70, 0x1e,
+	0xe6, 0x8e, 0x4b, 0x70, 0xa6, 0x40, 0x08, 0xad, 0x96, 0x26, 0x47, 0x67, 0xb4, 0x06, 0x17, 0x5a,
+	0xd1, 0xfb, 0xcd, 0xad, 0x0a, 0xac, 0x33, 0x68, 0xe8, 0xb9, 0x15, 0xec, 0xfd, 0x9d, 0xfa, 0x52,
+	0x8e, 0x7c, 0x65, 0x52, 0xd0, 0xa1, 0x55, 0x79, 0x56, 0xeb, 0xec, 0xf3, 0x91, 0x2a, 0x6e, 0x61,
+	0x53, 0xf0, 0xf1, 0x94, 0x02, 0x2d, 0x4e, 0xb4, 0x53, 0xe0, 0x5a, 0x71, 0xdf, 0xd8, 0xe3, 0x23,
+	0xed, 0x35, 0xcf, 0x91, 0x67, 0x60, 0x8d, 0xca, 0xb1, 0xa9, 0xf8, 0x74, 0x64, 0x85, 0x03, 0xe9,
+	0xad, 0x31, 0xcb, 0x5a, 0x7f, 0xf3, 0x77, 0x48, 0x46, 0xd1, 0xf4, 0x66, 0xfb, 0x2e, 0xf4, 0x35,
+	0x19, 0x46, 0x2a, 0xcf, 0x28, 0x0d, 0xb6, 0x4e, 0x50, 0x82, 0xb1, 0xcb, 0xd8, 0xc3, 0x1d, 0x76,
+	0x75, 0xf6, 0x72, 0x70, 0x3d, 0xa0, 0x37, 0x84, 0xb4, 0x7f, 0x45, 0x9f, 0x74, 0x86, 0x5a, 0x5c,
+	0xea, 0x8f, 0x0f, 0x26, 0x4d, 0xc9, 0x07, 0x72, 0x6f, 0x5e, 0x5f, 0x96, 0xee, 0xcd, 0x69, 0x51,
+	0xea, 0x74, 0x1f, 0xb7, 0xee, 0x6d, 0x7d, 0xd7, 0x9e, 0xdb, 0xb0, 0x5d, 0xb7, 0xc1, 0x8d, 0x3b,
+	0x21, 0xa3, 0x18, 0x24, 0xa8, 0x35, 0x24, 0xe5, 0x59, 0xe8, 0x65, 0x67, 0x32, 0x06, 0x59, 0x41,
+	0xf6, 0xa2, 0x03, 0x67, 0x06, 0xd5, 0xf2, 0xcf, 0x26, 0x8a, 0xc1, 0x5b, 0x93, 0x7b, 0xb8, 0x3a,
+	0xbb, 0x1e, 0xd0, 0x77, 0xe4, 0xe2, 0x3b, 0xe0, 0x22, 0xf2, 0xf4, 0x51, 0x47, 0x58, 0x44, 0x31,
+	0xfc, 0x2e, 0xc0, 0x23, 0xbb, 0xdc, 0xa1, 0x58, 0xb8, 0xbc, 0x12, 0xbf, 0x91, 0xfb, 0x95, 0x98,
+	0x20, 0xc7, 0xc2, 0x53, 0xd6, 0x9b, 0xab, 0xe1, 0xa6, 0xe3, 0xe9, 0xc1, 0xac, 0x6e, 0xa2, 0xb7,
+	0xe4, 0x41, 0x0c, 0xd6, 0x38, 0x4c, 0x40, 0x2f, 0xe7, 0xe0, 0xb1, 0xb7, 0xce, 0x06, 0xb2, 0x67,
+	0x07, 0x60, 0xbb, 0x08, 0x9d, 0x92, 0xd1, 0x58, 0x4a, 0xb0, 0x18, 0x39, 0x23, 0xc0, 0xf7, 0x5e,
+	0xb4, 0x5a, 0x1a, 0xd2, 0x3a, 0x61, 0xcf, 0xff, 0x97, 0xb4, 0x6d, 0x5f, 0x86, 0x3f, 0xcf, 0xad,
+	0x10, 0x17, 0xd5, 0xe7, 0xf6, 0xf6, 0xdf, 0x00, 0xea, 0xb2, 0x95, 0x3d, 0x3a, 0x04, 0x00, 0x00,
 }
@@ -32,6 +32,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/context"
@@ -39,6 +41,7 @@ import (
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
 	plc "github.com/NEU-SNS/ReverseTraceroute/plcontroller/pb"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
 	"github.com/NEU-SNS/ReverseTraceroute/spoofmap"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
 	"github.com/NEU-SNS/ReverseTraceroute/warts"
@@ -108,6 +111,30 @@ var (
 		Name:      "traceroute_response_times",
 		Help:      "The time it takes for traceroutes to respond",
 	})
+	tracelbGoroutineGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: nameSpace,
+		Subsystem: "measurements",
+		Name:      "tracelb_goroutines",
+		Help:      "The current number of goroutines running tracelbs",
+	})
+	tracelbResponseTimes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: nameSpace,
+		Subsystem: "measurements",
+		Name:      "tracelb_response_times",
+		Help:      "The time it takes for tracelbs to respond",
+	})
+	dealiasGoroutineGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: nameSpace,
+		Subsystem: "measurements",
+		Name:      "dealias_goroutines",
+		Help:      "The current number of goroutines running dealiases",
+	})
+	dealiasResponseTimes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: nameSpace,
+		Subsystem: "measurements",
+		Name:      "dealias_response_times",
+		Help:      "The time it takes for dealiases to respond",
+	})
 	ipOptionsResponseTimes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: nameSpace,
 		Subsystem: "measurements",
@@ -134,11 +161,16 @@ func init() {
 	prometheus.MustRegister(tracerouteGoroutineGauge)
 	prometheus.MustRegister(pingResponseTimes)
 	prometheus.MustRegister(tracerouteResponseTimes)
+	prometheus.MustRegister(tracelbGoroutineGauge)
+	prometheus.MustRegister(tracelbResponseTimes)
+	prometheus.MustRegister(dealiasGoroutineGauge)
+	prometheus.MustRegister(dealiasResponseTimes)
 	prometheus.MustRegister(vpsConnected)
 }
 
 type PlController struct {
 	server   *grpc.Server
+	ln       net.Listener
 	config   Config
 	db       VPStore
 	w        watcher.Watcher
@@ -149,6 +181,12 @@ type PlController struct {
 	shutdown chan struct{}
 	ec       chan error
 	started  chan struct{}
+	// format is the output vantage points are asked for
+	format scamper.Format
+	// Access atomically, 1 once a handover has started
+	draining  int32
+	stmu      sync.Mutex // protect selfTests
+	selfTests map[uint32]*dm.SelfTest
 }
 
 type options struct {
@@ -214,6 +252,13 @@ func New(opts ...ServerOption) (*PlController, error) {
 	pl.send = o.send
 	pl.config = o.c
 	pl.w = o.watch
+	if o.c.Scamper.Format != nil {
+		f, err := scamper.ParseFormat(*o.c.Scamper.Format)
+		if err != nil {
+			return nil, err
+		}
+		pl.format = f
+	}
 	ips, err := util.GetBindAddr()
 	if err != nil {
 		return nil, err
@@ -225,8 +270,13 @@ func New(opts ...ServerOption) (*PlController, error) {
 	log.Debug("Using bind addr: ", ips)
 	pl.ip = ip
 	pl.shutdown = make(chan struct{})
-	pl.spoofs = spoofmap.New(pl.send)
+	sc := spoofmap.DefaultConfig
+	if o.c.Local.SpoofExpire != nil && *o.c.Local.SpoofExpire > 0 {
+		sc.Expire = time.Duration(*o.c.Local.SpoofExpire) * time.Second
+	}
+	pl.spoofs = spoofmap.NewWithConfig(pl.send, sc)
 	pl.started = make(chan struct{})
+	pl.selfTests = make(map[uint32]*dm.SelfTest)
 	return &pl, nil
 }
 
@@ -272,6 +322,7 @@ func (c *PlController) run() error {
 		c.Stop()
 		return e
 	}
+	c.ln = l
 	go c.handlEvents()
 	close(c.started)
 	return c.server.Serve(loggingListener{l})
@@ -293,6 +344,119 @@ func (c *PlController) Stop() {
 	<-time.After(time.Second * 10)
 }
 
+// vpStatus reports the load on the sockets of the vps in vps, or all of
+// them when vps is empty
+func (c *PlController) vpStatus(vps []uint32) []*dm.VPStatus {
+	want := make(map[uint32]bool)
+	for _, vp := range vps {
+		want[vp] = true
+	}
+	var ret []*dm.VPStatus
+	for sock := range c.client.GetAllSockets() {
+		st := sock.Status()
+		ip, err := util.IPStringToInt32(st.IP)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		if len(want) > 0 && !want[ip] {
+			continue
+		}
+		ret = append(ret, &dm.VPStatus{
+			Ip:                ip,
+			Outstanding:       uint32(st.Outstanding),
+			Queued:            uint32(st.Queued),
+			Issued:            st.Issued,
+			Completed:         st.Completed,
+			Errors:            st.Errors,
+			Abandoned:         st.Abandoned,
+			Latency:           int64(st.Latency / time.Microsecond),
+			OldestOutstanding: int64(st.Oldest / time.Microsecond),
+		})
+	}
+	return ret
+}
+
+func (c *PlController) addSelfTest(st *dm.SelfTest) {
+	c.stmu.Lock()
+	defer c.stmu.Unlock()
+	c.selfTests[st.Ip] = st
+}
+
+// withSelfTests adds the last self test each vp reported to vps
+func (c *PlController) withSelfTests(vps []*dm.VantagePoint) {
+	c.stmu.Lock()
+	defer c.stmu.Unlock()
+	for _, vp := range vps {
+		vp.SelfTest = c.selfTests[vp.Ip]
+	}
+}
+
+// Handover stops the plcontroller so a new process started with handover
+// set can take over its vps. Unlike Stop, the vps are left connected to
+// scamper and assigned to this controller in the db. It goes:
+//
+//  1. New measurements are refused with ErrorDraining.
+//  2. The listener is closed so the new plcontroller can bind the port,
+//     Start returns but the connections already made keep being served.
+//  3. The running measurements and the spoofed probes that have not been
+//     acknowledged by the controller get until timeout to finish.
+//  4. The sockets are closed and the grpc server is stopped, whatever
+//     is still outstanding is dropped.
+func (c *PlController) Handover(timeout time.Duration) {
+	<-c.started
+	atomic.StoreInt32(&c.draining, 1)
+	if c.ln != nil {
+		c.ln.Close()
+	}
+	c.drain(timeout)
+	if c.shutdown != nil {
+		close(c.shutdown)
+	}
+	if c.spoofs != nil {
+		c.spoofs.Quit()
+	}
+	for sock := range c.client.GetAllSockets() {
+		sock.Stop()
+	}
+	if c.server != nil {
+		c.server.Stop()
+	}
+}
+
+// drain waits until no socket has a command outstanding and every spoofed
+// probe was delivered or timeout passes
+func (c *PlController) drain(timeout time.Duration) {
+	tick := time.NewTicker(time.Millisecond * 100)
+	defer tick.Stop()
+	deadline := time.After(timeout)
+	for {
+		var out, spoofs int
+		for sock := range c.client.GetAllSockets() {
+			out += sock.Status().Outstanding
+		}
+		if c.spoofs != nil {
+			spoofs = c.spoofs.Pending()
+		}
+		if out == 0 && spoofs == 0 {
+			return
+		}
+		select {
+		case <-tick.C:
+		case <-deadline:
+			log.Infof("Handing over with %d measurements outstanding and %d spoofs pending", out, spoofs)
+			return
+		}
+	}
+}
+
+func (c *PlController) doMeasurement(src string, arg interface{}) (<-chan scamper.Response, uint32, error) {
+	if atomic.LoadInt32(&c.draining) == 1 {
+		return nil, 0, ErrorDraining
+	}
+	return c.client.DoMeasurement(src, arg)
+}
+
 func (c *PlController) recSpoof(ctx context.Context, rs *dm.Spoof) (*dm.NotifyRecSpoofResponse, error) {
 	resp := &dm.NotifyRecSpoofResponse{}
 	// omitted hole-punching for public release
@@ -310,7 +474,7 @@ func (c *PlController) runPing(ctx context.Context, pa *dm.PingMeasurement) (dm.
 		errorCounterByVPMT.WithLabelValues(src, "PING").Inc()
 		return dm.Ping{}, err
 	}
-	resp, id, err := c.client.DoMeasurement(src, pa)
+	resp, id, err := c.doMeasurement(src, pa)
 	if err != nil {
 		errorCounterByVPMT.WithLabelValues(src, "PING").Inc()
 		return dm.Ping{}, err
@@ -320,6 +484,8 @@ func (c *PlController) runPing(ctx context.Context, pa *dm.PingMeasurement) (dm.
 		switch t := r.Ret.(type) {
 		case warts.Ping:
 			return dm.ConvertPing(t), nil
+		case dm.Ping:
+			return t, nil
 		default:
 			errorCounter.Inc()
 			errorCounterByVPMT.WithLabelValues(src, "PING").Inc()
@@ -359,7 +525,7 @@ func (c *PlController) runTraceroute(ctx context.Context, ta *dm.TracerouteMeasu
 		errorCounterByVPMT.WithLabelValues(src, "TRACEROUTE").Inc()
 		return dm.Traceroute{}, err
 	}
-	resp, id, err := c.client.DoMeasurement(src, ta)
+	resp, id, err := c.doMeasurement(src, ta)
 	if err != nil {
 		errorCounterByVPMT.WithLabelValues(src, "TRACEROUTE").Inc()
 		return dm.Traceroute{}, err
@@ -369,6 +535,8 @@ func (c *PlController) runTraceroute(ctx context.Context, ta *dm.TracerouteMeasu
 		switch t := r.Ret.(type) {
 		case warts.Traceroute:
 			return dm.ConvertTraceroute(t), nil
+		case dm.Traceroute:
+			return t, nil
 		default:
 			errorCounter.Inc()
 			errorCounterByVPMT.WithLabelValues(src, "TRACEROUTE").Inc()
@@ -392,3 +560,93 @@ func (c *PlController) runTraceroute(ctx context.Context, ta *dm.TracerouteMeasu
 		return dm.Traceroute{}, ctx.Err()
 	}
 }
+
+func (c *PlController) runTracelb(ctx context.Context, ta *dm.TracelbMeasurement) (dm.Tracelb, error) {
+	rpcCounter.Inc()
+	timeout := ta.Timeout
+	if timeout == 0 {
+		timeout = *c.config.Local.Timeout
+	}
+
+	src, err := util.Int32ToIPString(ta.Src)
+	if err != nil {
+		errorCounterByVPMT.WithLabelValues(src, "TRACELB").Inc()
+		return dm.Tracelb{}, err
+	}
+	resp, id, err := c.doMeasurement(src, ta)
+	if err != nil {
+		errorCounterByVPMT.WithLabelValues(src, "TRACELB").Inc()
+		return dm.Tracelb{}, err
+	}
+	select {
+	case r := <-resp:
+		switch t := r.Ret.(type) {
+		case warts.Tracelb:
+			return dm.ConvertTracelb(t), nil
+		default:
+			errorCounter.Inc()
+			errorCounterByVPMT.WithLabelValues(src, "TRACELB").Inc()
+			return dm.Tracelb{}, fmt.Errorf("Wrong type in tracelb response")
+		}
+	case <-time.After(time.Second * time.Duration(timeout)):
+		timeoutCounter.Inc()
+		timeoutCounterByVPMT.WithLabelValues(src, "TRACELB").Inc()
+		err = c.client.RemoveMeasurement(src, id)
+		if err != nil {
+			log.Error(err)
+		}
+		return dm.Tracelb{}, fmt.Errorf("Tracelb timed out")
+	case <-ctx.Done():
+		err = c.client.RemoveMeasurement(src, id)
+		if err != nil {
+			log.Error(err)
+		}
+		errorCounterByVPMT.WithLabelValues(src, "TRACELB").Inc()
+		return dm.Tracelb{}, ctx.Err()
+	}
+}
+
+func (c *PlController) runDealias(ctx context.Context, da *dm.DealiasMeasurement) (dm.Dealias, error) {
+	rpcCounter.Inc()
+	timeout := da.Timeout
+	if timeout == 0 {
+		timeout = *c.config.Local.Timeout
+	}
+
+	src, err := util.Int32ToIPString(da.Src)
+	if err != nil {
+		errorCounterByVPMT.WithLabelValues(src, "DEALIAS").Inc()
+		return dm.Dealias{}, err
+	}
+	resp, id, err := c.doMeasurement(src, da)
+	if err != nil {
+		errorCounterByVPMT.WithLabelValues(src, "DEALIAS").Inc()
+		return dm.Dealias{}, err
+	}
+	select {
+	case r := <-resp:
+		switch t := r.Ret.(type) {
+		case warts.Dealias:
+			return dm.ConvertDealias(t), nil
+		default:
+			errorCounter.Inc()
+			errorCounterByVPMT.WithLabelValues(src, "DEALIAS").Inc()
+			return dm.Dealias{}, fmt.Errorf("Wrong type in dealias response")
+		}
+	case <-time.After(time.Second * time.Duration(timeout)):
+		timeoutCounter.Inc()
+		timeoutCounterByVPMT.WithLabelValues(src, "DEALIAS").Inc()
+		err = c.client.RemoveMeasurement(src, id)
+		if err != nil {
+			log.Error(err)
+		}
+		return dm.Dealias{}, fmt.Errorf("Dealias timed out")
+	case <-ctx.Done():
+		err = c.client.RemoveMeasurement(src, id)
+		if err != nil {
+			log.Error(err)
+		}
+		errorCounterByVPMT.WithLabelValues(src, "DEALIAS").Inc()
+		return dm.Dealias{}, ctx.Err()
+	}
+}
@@ -28,15 +28,23 @@
 package plcontroller
 
 import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/plcontroller/mocks"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
 	smock "github.com/NEU-SNS/ReverseTraceroute/spoofmap/mocks"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
 	mmock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/peer"
 )
 
 var (
@@ -104,3 +112,151 @@ func TestRecSpoof(t *testing.T) {
 	sm.AssertNumberOfCalls(t, "Register", 1)
 	sm.AssertExpectations(t)
 }
+
+func TestAdoptSockets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plcsockets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	l, err := net.Listen("unix", filepath.Join(dir, "10.0.0.1:5000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	// Not a socket, left over from something else
+	if err := ioutil.WriteFile(filepath.Join(dir, "10.0.0.3:5000"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	db := &mocks.VPStore{}
+	plc := &PlController{
+		config: Config{Scamper: ScamperConfig{SockDir: &dir}},
+		db:     db,
+		client: scamper.NewClient(),
+		ip:     100,
+	}
+	db.On("UpdateController", uint32(167772161), uint32(100), uint32(100)).Return(nil)
+	db.On("GetActiveVPs").Return([]*datamodel.VantagePoint{
+		&datamodel.VantagePoint{Ip: 167772161, Controller: 100},
+		&datamodel.VantagePoint{Ip: 167772162, Controller: 100},
+		&datamodel.VantagePoint{Ip: 167772164, Controller: 200},
+	}, nil)
+	db.On("UpdateController", uint32(167772162), uint32(0), uint32(100)).Return(nil)
+	plc.adoptSockets()
+	db.AssertExpectations(t)
+	db.AssertNumberOfCalls(t, "UpdateController", 2)
+	sock, err := plc.client.GetSocket("10.0.0.1")
+	if err != nil {
+		t.Fatalf("Socket 10.0.0.1 was not adopted: %v", err)
+	}
+	sock.Stop()
+	if _, err := plc.client.GetSocket("10.0.0.3"); err == nil {
+		t.Fatal("Adopted a file that isn't a socket")
+	}
+}
+
+func TestHandoverRefusesMeasurements(t *testing.T) {
+	cl := &mocks.Client{}
+	timeout := int64(1)
+	plc := &PlController{
+		config:  Config{Local: LocalConfig{Timeout: &timeout}},
+		client:  cl,
+		started: make(chan struct{}),
+	}
+	close(plc.started)
+	cl.On("GetAllSockets").Return(func() <-chan *scamper.Socket {
+		c := make(chan *scamper.Socket)
+		close(c)
+		return c
+	})
+	plc.Handover(time.Second)
+	_, err := plc.runPing(context.Background(), &datamodel.PingMeasurement{Src: 1, Dst: 2})
+	if err != ErrorDraining {
+		t.Fatalf("runPing during handover, got[%v], expected[%v]", err, ErrorDraining)
+	}
+	cl.AssertNotCalled(t, "DoMeasurement", mmock.Anything, mmock.Anything)
+}
+
+func TestHandoverWaitsForSpoofs(t *testing.T) {
+	cl := &mocks.Client{}
+	sm := &smock.SpoofMap{}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plc := &PlController{
+		client:  cl,
+		spoofs:  sm,
+		ln:      l,
+		started: make(chan struct{}),
+	}
+	close(plc.started)
+	cl.On("GetAllSockets").Return(func() <-chan *scamper.Socket {
+		c := make(chan *scamper.Socket)
+		close(c)
+		return c
+	})
+	sm.On("Pending").Return(2).Twice()
+	sm.On("Pending").Return(0).Once()
+	sm.On("Quit").Return()
+	plc.Handover(time.Second * 5)
+	sm.AssertExpectations(t)
+	if _, err := l.Accept(); err == nil {
+		t.Fatal("Listener was not closed by the handover")
+	}
+}
+
+func TestAcked(t *testing.T) {
+	sps := []*datamodel.Probe{{ProbeId: 1}, {ProbeId: 2}}
+	for _, test := range []struct {
+		desc     string
+		resp     *datamodel.ReceiveSpoofedProbesResponse
+		expected []uint32
+	}{
+		{
+			desc:     "Controller without per probe acks",
+			resp:     &datamodel.ReceiveSpoofedProbesResponse{},
+			expected: []uint32{1, 2},
+		},
+		{
+			desc:     "Per probe acks",
+			resp:     &datamodel.ReceiveSpoofedProbesResponse{Acked: []uint32{2}, PerProbe: true},
+			expected: []uint32{2},
+		},
+		{
+			desc:     "Nothing handled",
+			resp:     &datamodel.ReceiveSpoofedProbesResponse{PerProbe: true},
+			expected: nil,
+		},
+	} {
+		if got := acked(sps, test.resp); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: got %v, expected %v", test.desc, got, test.expected)
+		}
+	}
+}
+
+func TestReportSelfTestChecksPeer(t *testing.T) {
+	plc := &PlController{selfTests: make(map[uint32]*datamodel.SelfTest)}
+	from := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000},
+		})
+	}
+	// 10.0.0.1
+	st := &datamodel.SelfTest{Ip: 167772161, RawSocket: true}
+	if _, err := plc.ReportSelfTest(context.Background(), st); err == nil {
+		t.Fatal("Accepted a self test without a peer")
+	}
+	if _, err := plc.ReportSelfTest(from("10.0.0.2"), st); err == nil {
+		t.Fatal("Accepted a self test for another vp")
+	}
+	if len(plc.selfTests) != 0 {
+		t.Fatalf("Recorded a refused self test: %v", plc.selfTests)
+	}
+	if _, err := plc.ReportSelfTest(from("10.0.0.1"), st); err != nil {
+		t.Fatal(err)
+	}
+	if plc.selfTests[st.Ip] != st {
+		t.Fatal("Self test from the vp was not recorded")
+	}
+}
@@ -29,15 +29,21 @@
 package plcontroller
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"sort"
 	"sync"
 	"time"
 
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
 	plc "github.com/NEU-SNS/ReverseTraceroute/plcontroller/pb"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
 	con "golang.org/x/net/context"
+	"google.golang.org/grpc/peer"
 )
 
 var (
@@ -49,6 +55,9 @@ var (
 	ErrorNilArgList = fmt.Errorf("Nil argument list.")
 	// ErrorTimeout is returned when a measurement times out
 	ErrorTimeout = fmt.Errorf("Measurement timed out.")
+	// ErrorDraining is returned for measurements requested while the
+	// plcontroller is handing over to a new process
+	ErrorDraining = fmt.Errorf("Handing over, not accepting measurements.")
 )
 
 func (c *PlController) Ping(server plc.PLController_PingServer) error {
@@ -194,6 +203,133 @@ func (c *PlController) Traceroute(server plc.PLController_TracerouteServer) erro
 	}
 }
 
+func (c *PlController) Tracelb(server plc.PLController_TracelbServer) error {
+	ctx, cancel := con.WithCancel(server.Context())
+	defer cancel()
+	for {
+		ta, err := server.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		tls := ta.GetTracelbs()
+		if tls == nil {
+			return ErrorNilArgList
+		}
+		sendChan := make(chan *dm.Tracelb, len(tls))
+		var wg sync.WaitGroup
+		for _, tl := range tls {
+			wg.Add(1)
+			tracelbGoroutineGauge.Add(1)
+			go func(t *dm.TracelbMeasurement) {
+				start := time.Now()
+				defer wg.Done()
+				defer tracelbGoroutineGauge.Sub(1)
+				tr, err := c.runTracelb(ctx, t)
+				if err != nil {
+					log.Debugf("Got tracelb result: %v, with error %v", tr, err)
+					tr.Error = err.Error()
+					tr.Src = t.Src
+					tr.Dst = t.Dst
+					tr.Start = &dm.TracerouteTime{
+						Sec:   start.Unix(),
+						Usec:  int64(start.Nanosecond() / 1000),
+						Ftime: dm.TTime(start).String(),
+					}
+				}
+				select {
+				case sendChan <- &tr:
+				case <-ctx.Done():
+				}
+				tracelbResponseTimes.Observe(time.Since(start).Seconds())
+			}(tl)
+		}
+		go func() {
+			wg.Wait()
+			close(sendChan)
+		}()
+		for {
+			select {
+			case t, ok := <-sendChan:
+				if !ok {
+					return nil
+				}
+				if err := server.Send(t); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (c *PlController) Dealias(server plc.PLController_DealiasServer) error {
+	ctx, cancel := con.WithCancel(server.Context())
+	defer cancel()
+	for {
+		da, err := server.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		das := da.GetDealiases()
+		if das == nil {
+			return ErrorNilArgList
+		}
+		sendChan := make(chan *dm.Dealias, len(das))
+		var wg sync.WaitGroup
+		for _, a := range das {
+			wg.Add(1)
+			dealiasGoroutineGauge.Add(1)
+			go func(t *dm.DealiasMeasurement) {
+				start := time.Now()
+				defer wg.Done()
+				defer dealiasGoroutineGauge.Sub(1)
+				d, err := c.runDealias(ctx, t)
+				if err != nil {
+					log.Debugf("Got dealias result: %v, with error %v", d, err)
+					d.Error = err.Error()
+					d.Src = t.Src
+					d.Method = t.Method
+					d.Addrs = t.Addrs
+					d.Start = &dm.TracerouteTime{
+						Sec:   start.Unix(),
+						Usec:  int64(start.Nanosecond() / 1000),
+						Ftime: dm.TTime(start).String(),
+					}
+				}
+				select {
+				case sendChan <- &d:
+				case <-ctx.Done():
+				}
+				dealiasResponseTimes.Observe(time.Since(start).Seconds())
+			}(a)
+		}
+		go func() {
+			wg.Wait()
+			close(sendChan)
+		}()
+		for {
+			select {
+			case t, ok := <-sendChan:
+				if !ok {
+					return nil
+				}
+				if err := server.Send(t); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
 func (c *PlController) ReceiveSpoof(rs *dm.RecSpoof, stream plc.PLController_ReceiveSpoofServer) error {
 	spoofs := rs.GetSpoofs()
 	ctx, cancel := con.WithCancel(stream.Context())
@@ -240,8 +376,82 @@ func (c *PlController) GetVPs(vpr *dm.VPRequest, stream plc.PLController_GetVPsS
 	if err != nil {
 		return nil
 	}
+	c.withSelfTests(vps)
 	if err := stream.Send(&dm.VPReturn{Vps: vps}); err != nil {
 		return err
 	}
 	return nil
 }
+
+// ReportSelfTest records the result of a vp testing itself, it is returned
+// with the vp from GetVPs. A vp can only report for its own address
+func (c *PlController) ReportSelfTest(ctx con.Context, st *dm.SelfTest) (*dm.SelfTestResponse, error) {
+	if st.Ip == 0 {
+		return nil, fmt.Errorf("Self test without a vp ip")
+	}
+	ips, _ := util.Int32ToIPString(st.Ip)
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil, fmt.Errorf("Self test for %s from an unknown peer", ips)
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return nil, err
+	}
+	if host != ips {
+		return nil, fmt.Errorf("Self test for %s reported from %s", ips, host)
+	}
+	log.Infof("Self test from %s: raw socket %v, rr %v, ts %v, receive icmp %v, errors %v",
+		ips, st.RawSocket, st.SendRecordRoute, st.SendTimestamp, st.ReceiveIcmp, st.Errors)
+	c.addSelfTest(st)
+	return &dm.SelfTestResponse{}, nil
+}
+
+// GetVPStatus reports the load on the scamper sockets of the requested vps
+func (c *PlController) GetVPStatus(ctx con.Context, req *dm.VPStatusRequest) (*dm.VPStatusReturn, error) {
+	return &dm.VPStatusReturn{Statuses: c.vpStatus(req.Vps)}, nil
+}
+
+type vpStatus struct {
+	IP          string `json:"ip"`
+	Outstanding uint32 `json:"outstanding"`
+	Queued      uint32 `json:"queued"`
+	Issued      uint64 `json:"issued"`
+	Completed   uint64 `json:"completed"`
+	Errors      uint64 `json:"errors"`
+	Abandoned   uint64 `json:"abandoned"`
+	Latency     string `json:"latency"`
+	Oldest      string `json:"oldest_outstanding"`
+}
+
+// byOutstanding sorts the busiest vps first
+type byOutstanding []*dm.VPStatus
+
+func (b byOutstanding) Len() int           { return len(b) }
+func (b byOutstanding) Less(i, j int) bool { return b[i].Outstanding > b[j].Outstanding }
+func (b byOutstanding) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// VPStatusHandler serves the load on every connected vp as JSON
+func (c *PlController) VPStatusHandler(rw http.ResponseWriter, req *http.Request) {
+	statuses := c.vpStatus(nil)
+	sort.Sort(byOutstanding(statuses))
+	ret := make([]vpStatus, 0, len(statuses))
+	for _, st := range statuses {
+		ips, _ := util.Int32ToIPString(st.Ip)
+		ret = append(ret, vpStatus{
+			IP:          ips,
+			Outstanding: st.Outstanding,
+			Queued:      st.Queued,
+			Issued:      st.Issued,
+			Completed:   st.Completed,
+			Errors:      st.Errors,
+			Abandoned:   st.Abandoned,
+			Latency:     (time.Duration(st.Latency) * time.Microsecond).String(),
+			Oldest:      (time.Duration(st.OldestOutstanding) * time.Microsecond).String(),
+		})
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(ret); err != nil {
+		log.Error(err)
+	}
+}
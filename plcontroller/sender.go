@@ -50,17 +50,17 @@ type ControllerSender struct {
 }
 
 // Send satisfies the Sender interface for a ControllerSender
-func (cs *ControllerSender) Send(sps []*dm.Probe, addr uint32) error {
+func (cs *ControllerSender) Send(sps []*dm.Probe, addr uint32) ([]uint32, error) {
 	if cs.conn == nil {
 		ip, _ := util.Int32ToIPString(addr)
 		saddr := fmt.Sprintf("%s:%d", ip, controllerPort)
 		creds, err := credentials.NewClientTLSFromFile(cs.RootCA, "controller.revtr.ccs.neu.edu")
 		if err != nil {
-			return err
+			return nil, err
 		}
 		cc, err := grpc.Dial(saddr, grpc.WithTransportCredentials(creds))
 		if err != nil {
-			return err
+			return nil, err
 		}
 		cs.conn = cc
 	}
@@ -69,15 +69,31 @@ func (cs *ControllerSender) Send(sps []*dm.Probe, addr uint32) error {
 	defer cancel()
 	stream, err := cl.ReceiveSpoofedProbes(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, sp := range sps {
 		if err := stream.Send(sp); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	_, err = stream.CloseAndRecv()
-	return err
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return acked(sps, resp), nil
+}
+
+// acked is the ids of the probes resp acknowledges. Controllers from
+// before per probe acks received the whole stream
+func acked(sps []*dm.Probe, resp *dm.ReceiveSpoofedProbesResponse) []uint32 {
+	if resp.PerProbe {
+		return resp.Acked
+	}
+	ids := make([]uint32, 0, len(sps))
+	for _, sp := range sps {
+		ids = append(ids, sp.ProbeId)
+	}
+	return ids
 }
 
 // Close closes the connection the sender uses
@@ -29,8 +29,11 @@
 package plcontroller
 
 import (
+	"io/ioutil"
 	"net"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/NEU-SNS/ReverseTraceroute/log"
@@ -40,7 +43,11 @@ import (
 )
 
 func (c *PlController) handlEvents() {
-	c.clearAllVps()
+	if c.config.Local.Handover != nil && *c.config.Local.Handover {
+		c.adoptSockets()
+	} else {
+		c.clearAllVps()
+	}
 	for {
 		event, err := c.w.GetEvent(c.shutdown)
 		if err == watcher.ErrWatcherClosed {
@@ -53,32 +60,7 @@ func (c *PlController) handlEvents() {
 		switch event.Type() {
 		case watcher.Create:
 			log.Debugf("Create socket: %s", event.Name())
-			con, err := net.Dial("unix", event.Name())
-			if err != nil {
-				log.Error(err)
-				continue
-			}
-			s, err := scamper.NewSocket(
-				event.Name(),
-				con)
-			if err != nil {
-				log.Error(err)
-				continue
-			}
-			ip, err := util.IPStringToInt32(s.IP())
-			if err != nil {
-				log.Errorf("Failed to convert socket IP: %v", err)
-				s.Stop()
-				continue
-			}
-			err = c.db.UpdateController(ip, c.ip, c.ip)
-			if err != nil {
-				log.Errorf("Failed to update controller  %v", err)
-				s.Stop()
-				continue
-			}
-			c.client.AddSocket(s)
-			vpsConnected.Add(1)
+			c.addSocket(event.Name())
 		case watcher.Remove:
 			log.Debugf("Remove socket: %s", event.Name())
 			ip := strings.Split(path.Base(event.Name()), ":")[0]
@@ -101,6 +83,83 @@ func (c *PlController) handlEvents() {
 	}
 }
 
+func (c *PlController) addSocket(name string) bool {
+	if _, err := c.client.GetSocket(strings.Split(path.Base(name), ":")[0]); err == nil {
+		// Already adopted from the socket directory
+		return true
+	}
+	con, err := net.Dial("unix", name)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	f, err := scamper.Negotiate(con, c.format)
+	if err != nil {
+		log.Error(err)
+		con.Close()
+		return false
+	}
+	s, err := scamper.NewSocketFormat(name, con, f)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	ip, err := util.IPStringToInt32(s.IP())
+	if err != nil {
+		log.Errorf("Failed to convert socket IP: %v", err)
+		s.Stop()
+		return false
+	}
+	err = c.db.UpdateController(ip, c.ip, c.ip)
+	if err != nil {
+		log.Errorf("Failed to update controller  %v", err)
+		s.Stop()
+		return false
+	}
+	c.client.AddSocket(s)
+	vpsConnected.Add(1)
+	return true
+}
+
+// adoptSockets connects to the sockets a plcontroller that handed over left
+// in the socket directory. Their vps stayed connected to scamper so they keep
+// this controller in the db, the vps whose sockets are gone are cleared
+func (c *PlController) adoptSockets() {
+	dir := *c.config.Scamper.SockDir
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		log.Errorf("Failed to read socket dir, clearing vps: %v", err)
+		c.clearAllVps()
+		return
+	}
+	adopted := make(map[uint32]bool)
+	for _, f := range files {
+		if f.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		log.Debugf("Adopt socket: %s", f.Name())
+		if !c.addSocket(filepath.Join(dir, f.Name())) {
+			continue
+		}
+		ip, _ := util.IPStringToInt32(strings.Split(f.Name(), ":")[0])
+		adopted[ip] = true
+	}
+	log.Infof("Adopted %d vps", len(adopted))
+	vps, err := c.db.GetActiveVPs()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, vp := range vps {
+		if vp.Controller != c.ip || adopted[vp.Ip] {
+			continue
+		}
+		if err := c.db.UpdateController(vp.Ip, 0, c.ip); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
 func (c *PlController) clearAllVps() {
 	err := c.db.ClearAllVPs()
 	if err != nil {
@@ -52,6 +52,9 @@ type LocalConfig struct {
 	PLUName      *string `flag:"pluname"`
 	UpdateURL    *string `flag:"update-url"`
 	RootCA       *string `flag:"root-ca"`
+	SpoofExpire  *int64  `flag:"spoof-expire"`
+	Handover     *bool   `flag:"handover"`
+	DrainTimeout *int64  `flag:"drain-timeout"`
 }
 
 // ScamperConfig is the scamper config info
@@ -60,6 +63,7 @@ type ScamperConfig struct {
 	SockDir       *string `flag:"socket-dir"`
 	BinPath       *string `flag:"scamper-bin"`
 	ConverterPath *string `flag:"converter-path"`
+	Format        *string `flag:"scamper-format"`
 }
 
 // DbConfig is the DB config options
@@ -85,12 +89,16 @@ func NewConfig() Config {
 		PLUName:      new(string),
 		UpdateURL:    new(string),
 		RootCA:       new(string),
+		SpoofExpire:  new(int64),
+		Handover:     new(bool),
+		DrainTimeout: new(int64),
 	}
 	sc := ScamperConfig{
 		Port:          new(string),
 		SockDir:       new(string),
 		BinPath:       new(string),
 		ConverterPath: new(string),
+		Format:        new(string),
 	}
 	return Config{
 		Local:   lc,
@@ -106,7 +114,7 @@ func NewConfig() Config {
 }
 
 type Sender interface {
-	Send([]*datamodel.Probe, uint32) error
+	Send([]*datamodel.Probe, uint32) ([]uint32, error)
 	Close() error
 }
 
@@ -30,7 +30,9 @@ package plvp
 
 import (
 	"fmt"
+	"io"
 	"net"
+	"time"
 
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
@@ -55,6 +57,7 @@ var (
 // SpoofPingMonitor monitors for ICMP echo replies that match the magic numbers
 type SpoofPingMonitor struct {
 	quit chan struct{}
+	tee  *PcapWriter
 }
 
 // NewSpoofPingMonitor makes a SpoofPingMonitor
@@ -63,12 +66,23 @@ func NewSpoofPingMonitor() *SpoofPingMonitor {
 	return &SpoofPingMonitor{quit: qc}
 }
 
-func reconnect(addr string) (*ipv4.RawConn, error) {
+// Tee makes the monitor write every spoofed echo it receives to w.
+// It must be called before the monitor is started
+func (sm *SpoofPingMonitor) Tee(w *PcapWriter) {
+	sm.tee = w
+}
+
+func reconnect(addr string) (PacketSource, error) {
 	pc, err := net.ListenPacket(fmt.Sprintf("ip4:%d", icmpProtocolNum), addr)
 	if err != nil {
 		return nil, err
 	}
-	return ipv4.NewRawConn(pc)
+	c, err := ipv4.NewRawConn(pc)
+	if err != nil {
+		return nil, err
+	}
+	// 1500 should be good because we're sending small packets and its the standard MTU
+	return &rawSource{c: c, buf: make([]byte, 1500)}, nil
 }
 
 var (
@@ -107,25 +121,59 @@ func makeRecordRoute(rr opt.RecordRouteOption) (dm.RecordRoute, error) {
 	return rec, nil
 }
 
+// The pointer of a timestamp option starts at the first slot
+// and advances by the slot size as hops stamp
+const (
+	tsFirstSlot   = 5
+	tsOnlySlot    = 4
+	tsAndAddrSlot = 8
+)
+
 func makeTimestamp(ts opt.TimeStampOption) (dm.TimeStamp, error) {
 	time := dm.TimeStamp{}
 	time.Type = dm.TSType(ts.Flags)
-	for _, st := range ts.Stamps {
-		nst := dm.Stamp{Time: uint32(st.Time), Ip: uint32(st.Addr)}
+	time.Overflow = uint32(ts.Over)
+	time.Pointer = uint32(ts.Pointer)
+	slot := tsAndAddrSlot
+	if ts.Flags == opt.TSOnly {
+		slot = tsOnlySlot
+	}
+	// The parser returns every slot in the option, prespecified
+	// addresses included, so use the pointer to tell which were filled
+	filled := (int(ts.Pointer) - tsFirstSlot) / slot
+	for i, st := range ts.Stamps {
+		nst := dm.Stamp{
+			Time:    uint32(st.Time),
+			Ip:      uint32(st.Addr),
+			Stamped: i < filled,
+		}
 		time.Stamps = append(time.Stamps, &nst)
 	}
 	return time, nil
 }
 
-func getProbe(conn *ipv4.RawConn) (*dm.Probe, error) {
-	// 1500 should be good because we're sending small packets and its the standard MTU
-	pBuf := make([]byte, 1500)
-	probe := &dm.Probe{}
+// getProbe reads a packet from src and parses it, the packet is
+// returned with the probe so it can be recorded
+func getProbe(src PacketSource) (*dm.Probe, []byte, error) {
 	// Try and get a packet
-	header, pload, _, err := conn.ReadFrom(pBuf)
+	pkt, err := src.ReadPacket()
+	if err == io.EOF {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, ErrorReadError
+	}
+	probe, err := parseProbe(pkt)
+	return probe, pkt, err
+}
+
+func parseProbe(pkt []byte) (*dm.Probe, error) {
+	probe := &dm.Probe{}
+	header, err := ipv4.ParseHeader(pkt)
 	if err != nil {
 		return nil, ErrorReadError
 	}
+	pload := pkt[header.Len:]
 	// Parse the payload for ICMP stuff
 	mess, err := icmp.ParseMessage(icmpProtocolNum, pload)
 	if err != nil {
@@ -191,27 +239,30 @@ func getProbe(conn *ipv4.RawConn) (*dm.Probe, error) {
 	return nil, ErrorNotICMPEcho
 }
 
-func (sm *SpoofPingMonitor) poll(addr string, probes chan<- dm.Probe, ec chan error) {
-	c, err := reconnect(addr)
-	if err != nil {
-		ec <- err
-		return
-	}
+// poll reads probes from c until it is exhausted or the monitor quits.
+// On a read error c is replaced with one from reopen, if reopen is nil
+// polling stops instead
+func (sm *SpoofPingMonitor) poll(c PacketSource, reopen func() (PacketSource, error), probes chan<- dm.Probe, ec chan error) {
 	for {
 		select {
 		case <-sm.quit:
-			err = c.Close()
-			if err != nil {
-				log.Error(err)
-			}
+			closeSource(c)
 			return
 		default:
-			var pr *dm.Probe
-			if pr, err = getProbe(c); err != nil {
+			pr, pkt, err := getProbe(c)
+			if err == io.EOF {
+				closeSource(c)
+				return
+			}
+			if err != nil {
 				ec <- err
 				switch err {
 				case ErrorReadError:
-					c, err = reconnect(addr)
+					closeSource(c)
+					if reopen == nil {
+						return
+					}
+					c, err = reopen()
 					if err != nil {
 						ec <- err
 						return
@@ -219,14 +270,38 @@ func (sm *SpoofPingMonitor) poll(addr string, probes chan<- dm.Probe, ec chan er
 				}
 				continue
 			}
+			if sm.tee != nil {
+				if err = sm.tee.WritePacket(time.Now(), pkt); err != nil {
+					log.Errorf("Failed to record spoofed probe: %v", err)
+				}
+			}
 			probes <- *pr
 		}
 	}
 }
 
+func closeSource(c PacketSource) {
+	if err := c.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
 // Start the SpoofPingMonitor
 func (sm *SpoofPingMonitor) Start(addr string, probes chan<- dm.Probe, ec chan error) {
-	go sm.poll(addr, probes, ec)
+	c, err := reconnect(addr)
+	if err != nil {
+		ec <- err
+		return
+	}
+	go sm.poll(c, func() (PacketSource, error) { return reconnect(addr) }, probes, ec)
+}
+
+// Replay runs the monitor over the packets from src, such as a capture
+// opened with OpenPcap. It blocks until src is exhausted or the monitor quits,
+// and closes src before returning. Errors for packets that aren't spoofed
+// echoes are sent on ec as they are by Start, so ec must be drained
+func (sm *SpoofPingMonitor) Replay(src PacketSource, probes chan<- dm.Probe, ec chan error) {
+	sm.poll(src, nil, probes, ec)
 }
 
 // Quit shuts down the monitor
@@ -56,3 +56,64 @@ func TestMakeRecordRoute(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeTimestamp(t *testing.T) {
+	prespec := []opt.Stamp{
+		{Time: 10, Addr: 1},
+		{Time: 11, Addr: 2},
+		{Time: 0, Addr: 3},
+		{Time: 0, Addr: 4},
+	}
+	for _, test := range []struct {
+		ts      opt.TimeStampOption
+		stamped []bool
+		over    uint32
+	}{
+		{
+			ts: opt.TimeStampOption{
+				Type:    opt.InternetTimestamp,
+				Pointer: 21,
+				Flags:   opt.TSPrespec,
+				Stamps:  prespec,
+			},
+			stamped: []bool{true, true, false, false},
+		}, {
+			ts: opt.TimeStampOption{
+				Type:    opt.InternetTimestamp,
+				Pointer: 37,
+				Flags:   opt.TSPrespec,
+				Over:    2,
+				Stamps:  prespec,
+			},
+			stamped: []bool{true, true, true, true},
+			over:    2,
+		}, {
+			ts: opt.TimeStampOption{
+				Type:    opt.InternetTimestamp,
+				Pointer: 13,
+				Flags:   opt.TSOnly,
+				Stamps:  []opt.Stamp{{Time: 10}, {Time: 0}, {Time: 0}},
+			},
+			stamped: []bool{true, true, false},
+		},
+	} {
+		res, err := makeTimestamp(test.ts)
+		if err != nil {
+			t.Fatalf("makeTimestamp(%v) unexpected error %v", test.ts, err)
+		}
+		if res.Type != dm.TSType(test.ts.Flags) {
+			t.Fatalf("makeTimestamp(%v) Wanted type %v got %v", test.ts, test.ts.Flags, res.Type)
+		}
+		if res.Overflow != test.over || res.Pointer != uint32(test.ts.Pointer) {
+			t.Fatalf("makeTimestamp(%v) Wanted overflow %d pointer %d got %d %d", test.ts, test.over, test.ts.Pointer, res.Overflow, res.Pointer)
+		}
+		if len(res.Stamps) != len(test.stamped) {
+			t.Fatalf("makeTimestamp(%v) Wanted %d stamps got %d", test.ts, len(test.stamped), len(res.Stamps))
+		}
+		for i, st := range res.Stamps {
+			if st.Stamped != test.stamped[i] {
+				t.Fatalf("makeTimestamp(%v) stamp %d Wanted stamped %v got %v", test.ts, i, test.stamped[i], st.Stamped)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	outboxBatch      = 500
+	outboxMinBackoff = 2 * time.Second
+	outboxMaxBackoff = 2 * time.Minute
+)
+
+var (
+	outboxQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: getName(),
+		Subsystem: "outbox",
+		Name:      "queued",
+		Help:      "The number of spoofed probes waiting to be sent to the plcontroller",
+	})
+	outboxSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: getName(),
+		Subsystem: "outbox",
+		Name:      "sent",
+		Help:      "Count of the spoofed probes sent to the plcontroller",
+	})
+	outboxDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: getName(),
+		Subsystem: "outbox",
+		Name:      "dropped",
+		Help:      "Count of the spoofed probes dropped because the outbox was full",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(outboxQueued)
+	prometheus.MustRegister(outboxSent)
+	prometheus.MustRegister(outboxDropped)
+}
+
+// Outbox holds spoofed probes until the plcontroller accepts them.
+// The queue is kept on disk so that probes survive a restart of the vp,
+// and when it is full the oldest probes are dropped.
+type Outbox struct {
+	mu      sync.Mutex
+	path    string
+	max     int
+	send    SendCloser
+	probes  []*dm.Probe
+	ids     map[uint32]bool
+	backoff time.Duration
+	next    time.Time
+	now     func() time.Time
+}
+
+// NewOutbox creates an Outbox holding at most max probes that sends with s.
+// If path is not empty the queue is persisted there and any probes already
+// saved are loaded.
+func NewOutbox(path string, max int, s SendCloser) (*Outbox, error) {
+	if max <= 0 {
+		return nil, fmt.Errorf("Outbox size must be positive, got %d", max)
+	}
+	o := &Outbox{
+		path: path,
+		max:  max,
+		send: s,
+		ids:  make(map[uint32]bool),
+		now:  time.Now,
+	}
+	if path == "" {
+		return o, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return o, nil
+	}
+	if err != nil {
+		return o, err
+	}
+	var saved dm.SpoofedProbes
+	if err := proto.Unmarshal(data, &saved); err != nil {
+		return o, fmt.Errorf("Failed to load outbox %s: %v", path, err)
+	}
+	o.add(saved.Probes)
+	return o, nil
+}
+
+// Len returns the number of probes waiting to be sent
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.probes)
+}
+
+// Add queues the probes, ignoring any whose ProbeId is already queued
+func (o *Outbox) Add(ps []*dm.Probe) error {
+	if len(ps) == 0 {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.add(ps)
+	return o.save()
+}
+
+func (o *Outbox) add(ps []*dm.Probe) {
+	for _, p := range ps {
+		if o.ids[p.ProbeId] {
+			continue
+		}
+		if len(o.probes) == o.max {
+			delete(o.ids, o.probes[0].ProbeId)
+			o.probes[0] = nil
+			o.probes = o.probes[1:]
+			outboxDropped.Inc()
+		}
+		o.ids[p.ProbeId] = true
+		o.probes = append(o.probes, p)
+	}
+	outboxQueued.Set(float64(len(o.probes)))
+}
+
+// Flush sends the queued probes in batches. After a failed send no
+// further attempt is made until the backoff has passed, the backoff
+// doubling with each failure.
+func (o *Outbox) Flush() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.probes) == 0 || o.now().Before(o.next) {
+		return nil
+	}
+	for len(o.probes) > 0 {
+		n := len(o.probes)
+		if n > outboxBatch {
+			n = outboxBatch
+		}
+		batch := o.probes[:n]
+		if err := o.send.Send(batch); err != nil {
+			switch {
+			case o.backoff == 0:
+				o.backoff = outboxMinBackoff
+			case o.backoff < outboxMaxBackoff:
+				o.backoff *= 2
+				if o.backoff > outboxMaxBackoff {
+					o.backoff = outboxMaxBackoff
+				}
+			}
+			o.next = o.now().Add(o.backoff)
+			return err
+		}
+		o.backoff = 0
+		o.next = time.Time{}
+		for _, p := range batch {
+			delete(o.ids, p.ProbeId)
+		}
+		o.probes = append([]*dm.Probe(nil), o.probes[n:]...)
+		outboxSent.Add(float64(n))
+		outboxQueued.Set(float64(len(o.probes)))
+		if err := o.save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) save() error {
+	if o.path == "" {
+		return nil
+	}
+	data, err := proto.Marshal(&dm.SpoofedProbes{Probes: o.probes})
+	if err != nil {
+		return err
+	}
+	tmp := o.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.path)
+}
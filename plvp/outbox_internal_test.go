@@ -0,0 +1,156 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+)
+
+type fakeSender struct {
+	fail bool
+	sent []uint32
+}
+
+func (fs *fakeSender) Send(ps []*dm.Probe) error {
+	if fs.fail {
+		return fmt.Errorf("Controller unreachable")
+	}
+	for _, p := range ps {
+		fs.sent = append(fs.sent, p.ProbeId)
+	}
+	return nil
+}
+
+func (fs *fakeSender) Report(*dm.SelfTest) error {
+	return nil
+}
+
+func (fs *fakeSender) Close() error {
+	return nil
+}
+
+func probes(ids ...uint32) []*dm.Probe {
+	var ps []*dm.Probe
+	for _, id := range ids {
+		ps = append(ps, &dm.Probe{ProbeId: id, Src: 1, Dst: 2})
+	}
+	return ps
+}
+
+func TestOutboxRetry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "outbox")
+	fs := &fakeSender{fail: true}
+	o, err := NewOutbox(path, 10, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+	o.now = func() time.Time { return now }
+	if err := o.Add(probes(1, 2, 3, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Flush(); err == nil {
+		t.Fatal("Flush with a failing sender should return an error")
+	}
+	if o.Len() != 3 {
+		t.Fatalf("Expected 3 queued probes, got %d", o.Len())
+	}
+	// A vp that restarts during the outage keeps its probes
+	o, err = NewOutbox(path, 10, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Len() != 3 {
+		t.Fatalf("Expected 3 probes loaded from disk, got %d", o.Len())
+	}
+	o.now = func() time.Time { return now }
+	o.Flush()
+	fs.fail = false
+	now = now.Add(outboxMinBackoff / 2)
+	if err := o.Flush(); err != nil || len(fs.sent) != 0 {
+		t.Fatalf("Flush sent during the backoff: %v, %v", fs.sent, err)
+	}
+	now = now.Add(outboxMinBackoff)
+	if err := o.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if o.Len() != 0 || fmt.Sprint(fs.sent) != "[1 2 3]" {
+		t.Fatalf("Expected probes [1 2 3] sent, got %v with %d queued", fs.sent, o.Len())
+	}
+	o, err = NewOutbox(path, 10, fs)
+	if err != nil || o.Len() != 0 {
+		t.Fatalf("Expected an empty outbox on disk, got %d, %v", o.Len(), err)
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	o, err := NewOutbox("", 10, &fakeSender{fail: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+	o.now = func() time.Time { return now }
+	o.Add(probes(1))
+	for i := 0; i < 10; i++ {
+		o.Flush()
+		now = o.next
+	}
+	if o.backoff != outboxMaxBackoff {
+		t.Fatalf("Expected backoff capped at %v, got %v", outboxMaxBackoff, o.backoff)
+	}
+}
+
+func TestOutboxFull(t *testing.T) {
+	fs := &fakeSender{}
+	o, err := NewOutbox("", 3, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Add(probes(1, 2, 3, 4, 5))
+	if err := o.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(fs.sent) != "[3 4 5]" {
+		t.Fatalf("Expected the oldest probes dropped, sent %v", fs.sent)
+	}
+	if _, err := NewOutbox("", 0, fs); err == nil {
+		t.Fatal("NewOutbox with size 0 should fail")
+	}
+}
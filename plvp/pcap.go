@@ -0,0 +1,313 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+// PacketSource supplies the IPv4 packets the SpoofPingMonitor looks
+// through for spoofed echoes
+type PacketSource interface {
+	// ReadPacket returns the next packet, IPv4 header included.
+	// The packet is only valid until the next call.
+	// io.EOF is returned when there are no more packets
+	ReadPacket() ([]byte, error)
+	Close() error
+}
+
+// rawSource reads packets from a raw ICMP socket
+type rawSource struct {
+	c   *ipv4.RawConn
+	buf []byte
+}
+
+func (rs *rawSource) ReadPacket() ([]byte, error) {
+	h, p, _, err := rs.c.ReadFrom(rs.buf)
+	if err != nil {
+		return nil, err
+	}
+	return rs.buf[:h.Len+len(p)], nil
+}
+
+func (rs *rawSource) Close() error {
+	return rs.c.Close()
+}
+
+const (
+	pcapMagic      = 0xa1b2c3d4
+	pcapMagicNano  = 0xa1b23c4d
+	pcapVersionMaj = 2
+	pcapVersionMin = 4
+	pcapSnapLen    = 65535
+	pcapHeaderLen  = 24
+	pcapRecordLen  = 16
+	// Largest record read, the biggest snaplen libpcap will write
+	pcapMaxRecord = 262144
+)
+
+// Link types of the captures that can be read
+const (
+	linkEthernet = 1
+	linkRaw      = 101
+	linkLinuxSLL = 113
+	linkIPv4     = 228
+)
+
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeVLAN = 0x8100
+)
+
+var (
+	// ErrorPcapFormat is returned when a file isn't a pcap file
+	ErrorPcapFormat = fmt.Errorf("Invalid pcap file")
+	// ErrorPcapLinkType is returned when a pcap file has a link type that can't be read
+	ErrorPcapLinkType = fmt.Errorf("Unsupported pcap link type")
+	// ErrorPcapRecord is returned when a pcap record is too large to be valid
+	ErrorPcapRecord = fmt.Errorf("Invalid pcap record")
+)
+
+// PcapSource is a PacketSource that replays the IPv4 packets in a pcap file.
+// Captures from tcpdump on an ethernet or any interface can be read as well as
+// the files written by a PcapWriter. Packets other than IPv4 are skipped
+type PcapSource struct {
+	r     io.Reader
+	c     io.Closer
+	order binary.ByteOrder
+	link  uint32
+	hdr   [pcapRecordLen]byte
+	buf   []byte
+}
+
+// NewPcapSource reads the pcap file header from r and returns a PcapSource
+// for the packets that follow it
+func NewPcapSource(r io.Reader) (*PcapSource, error) {
+	var hdr [pcapHeaderLen]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, ErrorPcapFormat
+	}
+	ps := &PcapSource{r: r}
+	switch {
+	case isPcapMagic(binary.LittleEndian.Uint32(hdr[:4])):
+		ps.order = binary.LittleEndian
+	case isPcapMagic(binary.BigEndian.Uint32(hdr[:4])):
+		ps.order = binary.BigEndian
+	default:
+		return nil, ErrorPcapFormat
+	}
+	ps.link = ps.order.Uint32(hdr[20:24])
+	switch ps.link {
+	case linkEthernet, linkRaw, linkLinuxSLL, linkIPv4:
+	default:
+		return nil, ErrorPcapLinkType
+	}
+	return ps, nil
+}
+
+// OpenPcap opens the pcap file at path as a PcapSource.
+// Closing the source closes the file
+func OpenPcap(path string) (*PcapSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := NewPcapSource(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	ps.c = f
+	return ps, nil
+}
+
+func isPcapMagic(m uint32) bool {
+	return m == pcapMagic || m == pcapMagicNano
+}
+
+// ReadPacket returns the next IPv4 packet in the file
+func (ps *PcapSource) ReadPacket() ([]byte, error) {
+	for {
+		if _, err := io.ReadFull(ps.r, ps.hdr[:]); err != nil {
+			return nil, err
+		}
+		l := ps.order.Uint32(ps.hdr[8:12])
+		if l > pcapMaxRecord {
+			return nil, ErrorPcapRecord
+		}
+		if cap(ps.buf) < int(l) {
+			ps.buf = make([]byte, l)
+		}
+		rec := ps.buf[:l]
+		if _, err := io.ReadFull(ps.r, rec); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if pkt := ps.ipv4(rec); pkt != nil {
+			return pkt, nil
+		}
+	}
+}
+
+// ipv4 strips the link layer header from rec and trims any padding,
+// nil is returned if rec isn't an IPv4 packet
+func (ps *PcapSource) ipv4(rec []byte) []byte {
+	switch ps.link {
+	case linkEthernet:
+		if len(rec) < 14 {
+			return nil
+		}
+		et, off := binary.BigEndian.Uint16(rec[12:14]), 14
+		if et == etherTypeVLAN && len(rec) >= 18 {
+			et, off = binary.BigEndian.Uint16(rec[16:18]), 18
+		}
+		if et != etherTypeIPv4 {
+			return nil
+		}
+		rec = rec[off:]
+	case linkLinuxSLL:
+		if len(rec) < 16 || binary.BigEndian.Uint16(rec[14:16]) != etherTypeIPv4 {
+			return nil
+		}
+		rec = rec[16:]
+	}
+	if len(rec) < ipv4.HeaderLen || rec[0]>>4 != ipv4.Version {
+		return nil
+	}
+	// Captures always have the total length in network order
+	if tl := int(binary.BigEndian.Uint16(rec[2:4])); tl >= ipv4.HeaderLen && tl < len(rec) {
+		rec = rec[:tl]
+	}
+	return rec
+}
+
+// Close closes the file if the source was made with OpenPcap
+func (ps *PcapSource) Close() error {
+	if ps.c != nil {
+		return ps.c.Close()
+	}
+	return nil
+}
+
+// PcapWriter writes IPv4 packets to a pcap file that tcpdump,
+// wireshark or a PcapSource can read
+type PcapWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+}
+
+// NewPcapWriter writes a pcap file header to w and returns a PcapWriter
+// which appends packets after it
+func NewPcapWriter(w io.Writer) (*PcapWriter, error) {
+	var hdr [pcapHeaderLen]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:6], pcapVersionMaj)
+	binary.LittleEndian.PutUint16(hdr[6:8], pcapVersionMin)
+	binary.LittleEndian.PutUint32(hdr[16:20], pcapSnapLen)
+	binary.LittleEndian.PutUint32(hdr[20:24], linkRaw)
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	return &PcapWriter{w: w}, nil
+}
+
+// CreatePcap opens the pcap file at path for writing. An existing file
+// is appended to if it was written by a PcapWriter, so captures
+// survive restarts of the vp. Closing the writer closes the file
+func CreatePcap(path string) (*PcapWriter, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.Size() == 0 {
+		pw, err := NewPcapWriter(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return pw, nil
+	}
+	ps, err := NewPcapSource(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if ps.order != binary.LittleEndian || ps.link != linkRaw {
+		f.Close()
+		return nil, fmt.Errorf("Can't append to pcap file %s, it wasn't written by the plvp", path)
+	}
+	return &PcapWriter{w: f}, nil
+}
+
+// WritePacket writes the IPv4 packet pkt captured at t
+func (pw *PcapWriter) WritePacket(t time.Time, pkt []byte) error {
+	l := len(pkt)
+	if l > pcapSnapLen {
+		l = pcapSnapLen
+	}
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	// The record goes out in one write so a failure can't leave
+	// a header without its packet in the file
+	if cap(pw.buf) < pcapRecordLen+l {
+		pw.buf = make([]byte, pcapRecordLen+l)
+	}
+	rec := pw.buf[:pcapRecordLen+l]
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(t.Unix()))
+	binary.LittleEndian.PutUint32(rec[4:8], uint32(t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(rec[8:12], uint32(l))
+	binary.LittleEndian.PutUint32(rec[12:16], uint32(len(pkt)))
+	copy(rec[pcapRecordLen:], pkt)
+	_, err := pw.w.Write(rec)
+	return err
+}
+
+// Close closes the underlying writer if it is an io.Closer
+func (pw *PcapWriter) Close() error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if c, ok := pw.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// echoReply builds an echo reply from src to dst carrying a prespecified
+// timestamp option with the first two of three slots stamped
+func echoReply(t *testing.T, src, dst [4]byte, id, seq int, data []byte) []byte {
+	m := icmp.Message{
+		Type: ipv4.ICMPTypeEchoReply,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: data},
+	}
+	body, err := m.Marshal(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := []byte{
+		68, 28, 21, 3,
+		1, 1, 1, 1, 0, 0, 0, 10,
+		2, 2, 2, 2, 0, 0, 0, 11,
+		3, 3, 3, 3, 0, 0, 0, 0,
+	}
+	hl := ipv4.HeaderLen + len(ts)
+	pkt := make([]byte, hl+len(body))
+	pkt[0] = byte(ipv4.Version<<4 | hl/4)
+	binary.BigEndian.PutUint16(pkt[2:4], uint16(len(pkt)))
+	pkt[8] = 64
+	pkt[9] = byte(icmpProtocolNum)
+	copy(pkt[12:16], src[:])
+	copy(pkt[16:20], dst[:])
+	copy(pkt[ipv4.HeaderLen:], ts)
+	copy(pkt[hl:], body)
+	return pkt
+}
+
+var (
+	spooferIP = [4]byte{10, 0, 0, 9}
+	probeID   = []byte{0, 0, 0, 42}
+)
+
+func spoofedReply(t *testing.T) []byte {
+	data := append(spooferIP[:], probeID...)
+	return echoReply(t, [4]byte{10, 0, 0, 1}, [4]byte{10, 0, 0, 2}, ID, SEQ, data)
+}
+
+func replay(t *testing.T, sm *SpoofPingMonitor, src PacketSource) ([]dm.Probe, []error) {
+	probes := make(chan dm.Probe)
+	ec := make(chan error)
+	done := make(chan struct{})
+	go func() {
+		sm.Replay(src, probes, ec)
+		close(done)
+	}()
+	var ps []dm.Probe
+	var errs []error
+	for {
+		select {
+		case p := <-probes:
+			ps = append(ps, p)
+		case err := <-ec:
+			errs = append(errs, err)
+		case <-done:
+			return ps, errs
+		case <-time.After(5 * time.Second):
+			t.Fatal("Replay did not finish")
+		}
+	}
+}
+
+func TestPcapReplay(t *testing.T) {
+	var capture, tee bytes.Buffer
+	pw, err := NewPcapWriter(&capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := echoReply(t, [4]byte{10, 0, 0, 3}, [4]byte{10, 0, 0, 2}, 1, 1, []byte("ping"))
+	now := time.Now()
+	for _, pkt := range [][]byte{other, spoofedReply(t)} {
+		if err := pw.WritePacket(now, pkt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src, err := NewPcapSource(&capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm := NewSpoofPingMonitor()
+	tw, err := NewPcapWriter(&tee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.Tee(tw)
+	ps, errs := replay(t, sm, src)
+	if len(errs) != 1 || errs[0] != ErrorNonSpoofedProbe {
+		t.Fatalf("Replay errors Wanted [%v] got %v", ErrorNonSpoofedProbe, errs)
+	}
+	if len(ps) != 1 {
+		t.Fatalf("Replay Wanted 1 probe got %d", len(ps))
+	}
+	p := ps[0]
+	if p.ProbeId != 42 || p.SpooferIp != 0x0a000009 || p.Src != 0x0a000001 || p.Dst != 0x0a000002 {
+		t.Fatalf("Replay got wrong probe %v", p)
+	}
+	if p.Ts == nil || p.Ts.Type != dm.TSType_TSPreSpec || p.Ts.Pointer != 21 || len(p.Ts.Stamps) != 3 {
+		t.Fatalf("Replay got wrong timestamp %v", p.Ts)
+	}
+	if !p.Ts.Stamps[1].Stamped || p.Ts.Stamps[2].Stamped {
+		t.Fatalf("Replay got wrong stamped slots %v", p.Ts.Stamps)
+	}
+	// Only the spoofed echo is recorded
+	teed, err := NewPcapSource(&tee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt, err := teed.ReadPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pkt, spoofedReply(t)) {
+		t.Fatalf("Tee Wanted %v got %v", spoofedReply(t), pkt)
+	}
+	if _, err := teed.ReadPacket(); err != io.EOF {
+		t.Fatalf("Tee Wanted EOF got %v", err)
+	}
+}
+
+func TestPcapSourceEthernet(t *testing.T) {
+	pkt := spoofedReply(t)
+	// A tcpdump capture, big endian, with VLAN tagged ethernet frames padded
+	// past the end of the IPv4 packet and an ARP frame between them
+	var buf bytes.Buffer
+	hdr := make([]byte, pcapHeaderLen)
+	binary.BigEndian.PutUint32(hdr[0:4], pcapMagic)
+	binary.BigEndian.PutUint32(hdr[20:24], linkEthernet)
+	buf.Write(hdr)
+	frame := func(et uint16, vlan bool, payload []byte) {
+		var f []byte
+		f = append(f, make([]byte, 12)...)
+		if vlan {
+			f = append(f, 0x81, 0x00, 0, 1)
+		}
+		f = append(f, byte(et>>8), byte(et))
+		f = append(f, payload...)
+		f = append(f, make([]byte, 6)...)
+		rec := make([]byte, pcapRecordLen)
+		binary.BigEndian.PutUint32(rec[8:12], uint32(len(f)))
+		binary.BigEndian.PutUint32(rec[12:16], uint32(len(f)))
+		buf.Write(rec)
+		buf.Write(f)
+	}
+	frame(etherTypeIPv4, false, pkt)
+	frame(0x0806, false, make([]byte, 28))
+	frame(etherTypeIPv4, true, pkt)
+	src, err := NewPcapSource(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		got, err := src.ReadPacket()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, pkt) {
+			t.Fatalf("ReadPacket %d Wanted %v got %v", i, pkt, got)
+		}
+	}
+	if _, err := src.ReadPacket(); err != io.EOF {
+		t.Fatalf("ReadPacket Wanted EOF got %v", err)
+	}
+}
+
+func TestPcapSourceInvalid(t *testing.T) {
+	if _, err := NewPcapSource(bytes.NewReader(make([]byte, pcapHeaderLen))); err != ErrorPcapFormat {
+		t.Fatalf("NewPcapSource Wanted %v got %v", ErrorPcapFormat, err)
+	}
+	hdr := make([]byte, pcapHeaderLen)
+	binary.LittleEndian.PutUint32(hdr[0:4], pcapMagic)
+	binary.LittleEndian.PutUint32(hdr[20:24], 105)
+	if _, err := NewPcapSource(bytes.NewReader(hdr)); err != ErrorPcapLinkType {
+		t.Fatalf("NewPcapSource Wanted %v got %v", ErrorPcapLinkType, err)
+	}
+	var buf bytes.Buffer
+	pw, err := NewPcapWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pw.WritePacket(time.Now(), spoofedReply(t)); err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewPcapSource(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.ReadPacket(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadPacket Wanted %v got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestCreatePcapAppends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plvp-pcap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spoofed.pcap")
+	pkt := spoofedReply(t)
+	for i := 0; i < 2; i++ {
+		pw, err := CreatePcap(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pw.WritePacket(time.Now(), pkt); err != nil {
+			t.Fatal(err)
+		}
+		if err := pw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src, err := OpenPcap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	for i := 0; i < 2; i++ {
+		if _, err := src.ReadPacket(); err != nil {
+			t.Fatalf("ReadPacket %d: %v", i, err)
+		}
+	}
+	if _, err := src.ReadPacket(); err != io.EOF {
+		t.Fatalf("ReadPacket Wanted EOF got %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte("not a pcap file at all, no...."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreatePcap(path); err != ErrorPcapFormat {
+		t.Fatalf("CreatePcap Wanted %v got %v", ErrorPcapFormat, err)
+	}
+}
@@ -35,11 +35,13 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
 	"github.com/NEU-SNS/ReverseTraceroute/mproc"
+	"github.com/NEU-SNS/ReverseTraceroute/mproc/proc"
 	plc "github.com/NEU-SNS/ReverseTraceroute/plcontroller/pb"
 	"github.com/NEU-SNS/ReverseTraceroute/scamper"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
@@ -81,6 +83,7 @@ func init() {
 
 type SendCloser interface {
 	Send([]*dm.Probe) error
+	Report(*dm.SelfTest) error
 	Close() error
 }
 
@@ -89,7 +92,7 @@ type PLControllerSender struct {
 	conn   *grpc.ClientConn
 }
 
-func (cs *PLControllerSender) Send(ps []*dm.Probe) error {
+func (cs *PLControllerSender) dial() error {
 	if cs.conn == nil {
 		_, srvs, err := net.LookupSRV("plcontroller", "tcp", "revtr.ccs.neu.edu")
 		if err != nil {
@@ -106,6 +109,13 @@ func (cs *PLControllerSender) Send(ps []*dm.Probe) error {
 		}
 		cs.conn = cc
 	}
+	return nil
+}
+
+func (cs *PLControllerSender) Send(ps []*dm.Probe) error {
+	if err := cs.dial(); err != nil {
+		return err
+	}
 	client := plc.NewPLControllerClient(cs.conn)
 	contx, cancel := ctx.WithTimeout(ctx.Background(), time.Second*2)
 	defer cancel()
@@ -113,6 +123,18 @@ func (cs *PLControllerSender) Send(ps []*dm.Probe) error {
 	return err
 }
 
+// Report sends the result of a self test to the plcontroller
+func (cs *PLControllerSender) Report(st *dm.SelfTest) error {
+	if err := cs.dial(); err != nil {
+		return err
+	}
+	client := plc.NewPLControllerClient(cs.conn)
+	contx, cancel := ctx.WithTimeout(ctx.Background(), time.Second*10)
+	defer cancel()
+	_, err := client.ReportSelfTest(contx, st)
+	return err
+}
+
 func (cs *PLControllerSender) Close() error {
 	if cs.conn != nil {
 		return cs.conn.Close()
@@ -131,6 +153,13 @@ type plVantagepointT struct {
 	am       sync.Mutex // protect addr
 	addr     string
 	send     SendCloser
+	outbox   *Outbox
+	pcap     *PcapWriter
+
+	hm        sync.Mutex // protect wantScamp, scamp and reported
+	wantScamp bool
+	scamp     *proc.Process
+	reported  bool
 }
 
 var plVantagepoint plVantagepointT
@@ -148,11 +177,19 @@ func (vp *plVantagepointT) stop() {
 	if vp.spoofmon != nil {
 		vp.spoofmon.Quit()
 	}
+	if vp.pcap != nil {
+		vp.pcap.Close()
+	}
 	if vp.send != nil {
 		vp.send.Close()
 	}
 }
 
+// Stop stops the plvp and the processes it manages
+func Stop() {
+	plVantagepoint.stop()
+}
+
 // HandleSig handles signals
 func HandleSig(s os.Signal) {
 	plVantagepoint.handleSig(s)
@@ -161,6 +198,9 @@ func HandleSig(s os.Signal) {
 // The vp is dead if this method needs to return, so call stop() to clean up before returning
 func (vp *plVantagepointT) run(c Config, s SendCloser, ec chan error) {
 	vp.config = c
+	vp.hm.Lock()
+	vp.wantScamp = *c.Local.StartScamp
+	vp.hm.Unlock()
 	con := new(scamper.Config)
 	con.ScPath = *c.Scamper.BinPath
 	con.IP = *c.Scamper.Host
@@ -180,10 +220,29 @@ func (vp *plVantagepointT) run(c Config, s SendCloser, ec chan error) {
 		return
 	}
 	vp.send = s
+	vp.outbox, err = NewOutbox(*c.Local.OutboxPath, *c.Local.OutboxSize, s)
+	if err != nil {
+		if vp.outbox == nil {
+			log.Errorf("Could not create outbox: %v", err)
+			vp.stop()
+			ec <- err
+			return
+		}
+		log.Errorf("Could not load outbox, starting empty: %v", err)
+	}
 	vp.addr = sip
 	vp.sc = *con
 	vp.mp = mproc.New()
 	vp.spoofmon = NewSpoofPingMonitor()
+	if *c.Local.SpoofPcap != "" {
+		// Recording is for debugging, the vp works without it
+		vp.pcap, err = CreatePcap(*c.Local.SpoofPcap)
+		if err != nil {
+			log.Errorf("Could not open spoofed probe capture: %v", err)
+		} else {
+			vp.spoofmon.Tee(vp.pcap)
+		}
+	}
 	monaddr, err := util.GetBindAddr()
 	if err != nil {
 		log.Errorf("Could not get bind addr: %v", err)
@@ -198,6 +257,37 @@ func (vp *plVantagepointT) run(c Config, s SendCloser, ec chan error) {
 	if *c.Local.StartScamp {
 		plVantagepoint.startScamperProcs()
 	}
+	go vp.selfTests(selfTestTarget(c))
+}
+
+// selfTestTarget is the host pinged to check ICMP gets to the vp
+func selfTestTarget(c Config) string {
+	if *c.Local.SelfTestTarget != "" {
+		return *c.Local.SelfTestTarget
+	}
+	return *c.Local.Host
+}
+
+// RunSelfTest runs the self test once and reports it to the plcontroller
+func RunSelfTest(c Config, s SendCloser) (*dm.SelfTest, error) {
+	st := SelfTest(selfTestTarget(c), selfTestTimeout)
+	return st, s.Report(st)
+}
+
+// selfTests reports a self test every selfTestInterval
+func (vp *plVantagepointT) selfTests(target string) {
+	for {
+		st := SelfTest(target, selfTestTimeout)
+		log.Infof("Self test: %v", st)
+		if err := vp.send.Report(st); err != nil {
+			log.Errorf("Failed to report self test: %v", err)
+		} else {
+			vp.hm.Lock()
+			vp.reported = true
+			vp.hm.Unlock()
+		}
+		<-time.After(selfTestInterval)
+	}
 }
 
 func startHTTP(addr string) {
@@ -208,7 +298,7 @@ func startHTTP(addr string) {
 
 // Start a plvp with the given config
 func Start(c Config, s SendCloser) chan error {
-	log.Info("Starting plvp with config: %v", c)
+	log.Infof("Starting plvp with config: %v", c)
 	http.Handle("/metrics", prometheus.Handler())
 	go startHTTP(*c.Local.PProfAddr)
 	errChan := make(chan error, 1)
@@ -231,8 +321,13 @@ func (vp *plVantagepointT) monitorSpoofedPings(probes chan dm.Probe, ec chan err
 					continue
 				}
 			case <-time.After(2 * time.Second):
-				vp.send.Send(sprobes)
+				if err := vp.outbox.Add(sprobes); err != nil {
+					log.Errorf("Failed to save outbox: %v", err)
+				}
 				sprobes = make([]*dm.Probe, 0)
+				if err := vp.outbox.Flush(); err != nil {
+					log.Errorf("Failed to send spoofed probes, %d queued: %v", vp.outbox.Len(), err)
+				}
 			}
 		}
 	}()
@@ -251,5 +346,35 @@ func pickIP(host string) (string, error) {
 func (vp *plVantagepointT) startScamperProcs() {
 	log.Info("Starting scamper procs")
 	sp := scamper.GetVPProc(vp.sc.ScPath, vp.sc.IP, vp.sc.Port)
-	vp.mp.ManageProcess(sp, true, 10000)
+	if _, err := vp.mp.ManageProcess(sp, true, 10000); err != nil {
+		log.Errorf("Failed to start scamper: %v", err)
+		return
+	}
+	vp.hm.Lock()
+	vp.scamp = sp
+	vp.hm.Unlock()
+}
+
+// Health reports if the plvp is working, scamper is running and the
+// plcontroller has accepted a self test report since the plvp started
+func Health() error {
+	return plVantagepoint.health()
+}
+
+func (vp *plVantagepointT) health() error {
+	vp.hm.Lock()
+	defer vp.hm.Unlock()
+	if vp.wantScamp {
+		if vp.scamp == nil {
+			return fmt.Errorf("Scamper not started")
+		}
+		// Signal 0 only checks the process is there
+		if err := vp.scamp.Signal(syscall.Signal(0)); err != nil {
+			return fmt.Errorf("Scamper not running: %v", err)
+		}
+	}
+	if !vp.reported {
+		return fmt.Errorf("No report accepted by the plcontroller")
+	}
+	return nil
 }
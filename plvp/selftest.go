@@ -0,0 +1,187 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// selfTestID is the ICMP id of self test probes, it differs from ID so the
+// spoof monitor ignores them
+const selfTestID = 0xf4f5
+
+var (
+	selfTestTimeout  = time.Second * 5
+	selfTestInterval = time.Hour * 24
+)
+
+// recordRoute is an empty record route option with room for 9 addresses
+// padded to a multiple of 4 bytes
+func recordRoute() []byte {
+	opt := make([]byte, 40)
+	opt[0], opt[1], opt[2] = 7, 39, 4
+	return opt
+}
+
+// timeStamp is an empty timestamp only option with room for 9 timestamps
+func timeStamp() []byte {
+	opt := make([]byte, 40)
+	opt[0], opt[1], opt[2] = 68, 40, 5
+	return opt
+}
+
+// rawSocket opens a raw ICMP socket that takes the ip header from the
+// caller, it receives all ICMP with the ip header
+func rawSocket() (int, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_RAW, syscall.IPPROTO_ICMP)
+	if err != nil {
+		return -1, err
+	}
+	if err := syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, syscall.IP_HDRINCL, 1); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	return fd, nil
+}
+
+// echo sends an ICMP echo request to dst with the ip options opts and
+// waits for the reply. When opts are given the reply must carry an option
+// of the same type
+func echo(fd int, dst net.IP, opts []byte, seq int, timeout time.Duration) error {
+	body, err := (&icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: selfTestID, Seq: seq, Data: []byte("revtr self test")},
+	}).Marshal(nil)
+	if err != nil {
+		return err
+	}
+	h, err := (&ipv4.Header{
+		Version:  ipv4.Version,
+		Len:      ipv4.HeaderLen,
+		TotalLen: ipv4.HeaderLen + len(opts) + len(body),
+		TTL:      64,
+		Protocol: icmpProtocolNum,
+		Dst:      dst,
+		Options:  opts,
+	}).Marshal()
+	if err != nil {
+		return err
+	}
+	to := &syscall.SockaddrInet4{}
+	copy(to.Addr[:], dst.To4())
+	if err := syscall.Sendto(fd, append(h, body...), 0, to); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, 1500)
+	for {
+		left := deadline.Sub(time.Now())
+		if left <= 0 {
+			return fmt.Errorf("No reply from %v", dst)
+		}
+		tv := syscall.NsecToTimeval(left.Nanoseconds())
+		if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+			return err
+		}
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err == syscall.EAGAIN || err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		rh, err := ipv4.ParseHeader(buf[:n])
+		if err != nil || rh.Len > n {
+			continue
+		}
+		m, err := icmp.ParseMessage(icmpProtocolNum, buf[rh.Len:n])
+		if err != nil || m.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+		e, ok := m.Body.(*icmp.Echo)
+		if !ok || e.ID != selfTestID || e.Seq != seq {
+			continue
+		}
+		if len(opts) > 0 && (len(rh.Options) == 0 || rh.Options[0] != opts[0]) {
+			return fmt.Errorf("Reply from %v without the option", rh.Src)
+		}
+		return nil
+	}
+}
+
+// SelfTest checks what the vp can measure from the host itself. It opens a
+// raw socket and pings target, the plcontroller host, with the record route
+// and timestamp options and without options to check that probes with
+// options leave the host and ICMP from outside gets through the local
+// firewall. Loopback isn't used as filtering doesn't apply to it. The network
+// between vps is what vpservice tests from outside
+func SelfTest(target string, timeout time.Duration) *dm.SelfTest {
+	st := &dm.SelfTest{Tested: time.Now().Unix()}
+	if addr, err := util.GetBindAddr(); err == nil {
+		st.Ip, _ = util.IPStringToInt32(addr)
+	}
+	fail := func(test string, err error) {
+		st.Errors = append(st.Errors, fmt.Sprintf("%s: %v", test, err))
+	}
+	fd, err := rawSocket()
+	if err != nil {
+		fail("raw socket", err)
+		return st
+	}
+	defer syscall.Close(fd)
+	st.RawSocket = true
+	dst, err := net.ResolveIPAddr("ip4", target)
+	if err != nil {
+		fail("resolve target", err)
+		return st
+	}
+	if err := echo(fd, dst.IP, recordRoute(), 1, timeout); err != nil {
+		fail("record route", err)
+	} else {
+		st.SendRecordRoute = true
+	}
+	if err := echo(fd, dst.IP, timeStamp(), 2, timeout); err != nil {
+		fail("timestamp", err)
+	} else {
+		st.SendTimestamp = true
+	}
+	if err := echo(fd, dst.IP, nil, 3, timeout); err != nil {
+		fail("receive icmp", err)
+	} else {
+		st.ReceiveIcmp = true
+	}
+	return st
+}
@@ -42,17 +42,23 @@ type Config struct {
 
 // LocalConfig represents the configuration of the vantage-point minus Scamper
 type LocalConfig struct {
-	Addr         *string `flag:"a"`
-	CloseStdDesc *bool   `flag:"d"`
-	Port         *int    `flag:"p"`
-	PProfAddr    *string `flag:"pprof-addr"`
-	AutoConnect  *bool   `flag:"auto-connect"`
-	SecureConn   *bool   `flag:"secure-conn"`
-	CertPath     *string `flag:"cert-path"`
-	KeyPath      *string `flag:"key-path"`
-	StartScamp   *bool   `flag:"start-scamper"`
-	Host         *string `flag:"host"`
-	RootCA       *string `flag:"root-ca"`
+	Addr           *string `flag:"a"`
+	CloseStdDesc   *bool   `flag:"d"`
+	Port           *int    `flag:"p"`
+	PProfAddr      *string `flag:"pprof-addr"`
+	AutoConnect    *bool   `flag:"auto-connect"`
+	SecureConn     *bool   `flag:"secure-conn"`
+	CertPath       *string `flag:"cert-path"`
+	KeyPath        *string `flag:"key-path"`
+	StartScamp     *bool   `flag:"start-scamper"`
+	Host           *string `flag:"host"`
+	RootCA         *string `flag:"root-ca"`
+	OutboxPath     *string `flag:"outbox-path"`
+	OutboxSize     *int    `flag:"outbox-size"`
+	UpdateURL      *string `flag:"update-url"`
+	UpdateEvery    *int64  `flag:"update-interval"`
+	SelfTestTarget *string `flag:"self-test-target"`
+	SpoofPcap      *string `flag:"spoof-pcap"`
 }
 
 // ScamperConfig represents the scamper configuration options
@@ -65,17 +71,23 @@ type ScamperConfig struct {
 // NewConfig creates a new config struct for the plvp
 func NewConfig() Config {
 	lc := LocalConfig{
-		Addr:         new(string),
-		AutoConnect:  new(bool),
-		CloseStdDesc: new(bool),
-		PProfAddr:    new(string),
-		SecureConn:   new(bool),
-		CertPath:     new(string),
-		KeyPath:      new(string),
-		StartScamp:   new(bool),
-		Host:         new(string),
-		Port:         new(int),
-		RootCA:       new(string),
+		Addr:           new(string),
+		AutoConnect:    new(bool),
+		CloseStdDesc:   new(bool),
+		PProfAddr:      new(string),
+		SecureConn:     new(bool),
+		CertPath:       new(string),
+		KeyPath:        new(string),
+		StartScamp:     new(bool),
+		Host:           new(string),
+		Port:           new(int),
+		RootCA:         new(string),
+		OutboxPath:     new(string),
+		OutboxSize:     new(int),
+		UpdateURL:      new(string),
+		UpdateEvery:    new(int64),
+		SelfTestTarget: new(string),
+		SpoofPcap:      new(string),
 	}
 	sc := ScamperConfig{
 		Port:    new(string),
@@ -0,0 +1,246 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/httpupdate"
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/mproc"
+	"github.com/NEU-SNS/ReverseTraceroute/mproc/proc"
+	"github.com/blang/semver"
+)
+
+// UpdatedEnv is set in the environment of a plvp started by an update to
+// the version it replaced
+const UpdatedEnv = "REVTR_PLVP_UPDATED_FROM"
+
+var (
+	// versionTimeout is how long a new binary has to print its version
+	versionTimeout = time.Second * 10
+	// healthTimeout is how long an updated plvp has to become healthy
+	healthTimeout  = time.Minute
+	healthInterval = time.Second
+)
+
+// Updater replaces the plvp binary with the version the status url
+// announces and restarts the plvp as the new version
+type Updater struct {
+	url     string
+	version string
+	path    string
+	key     *ecdsa.PublicKey
+	mp      mproc.MProc
+	exec    func(string, []string, []string) error
+	// Stop is called before the new binary is executed
+	Stop func()
+	// Health reports if the plvp is working after an update
+	Health func() error
+}
+
+// NewUpdater creates an Updater for the binary at path running version.
+// New binaries must be signed by key
+func NewUpdater(url, version, path string, key *ecdsa.PublicKey) *Updater {
+	return &Updater{
+		url:     url,
+		version: version,
+		path:    path,
+		key:     key,
+		mp:      mproc.New(),
+		exec:    syscall.Exec,
+		Stop:    func() {},
+		Health:  func() error { return nil },
+	}
+}
+
+// Run checks for an update every interval
+func (u *Updater) Run(interval time.Duration) {
+	for {
+		if err := u.Check(); err != nil {
+			log.Errorf("Failed to update plvp: %v", err)
+		}
+		<-time.After(interval)
+	}
+}
+
+// Check installs and restarts into a new version if there is one. A
+// binary that doesn't report the new version is rolled back and not tried
+// again
+func (u *Updater) Check() error {
+	up, err := httpupdate.CheckUpdate(u.url, u.version)
+	if err != nil {
+		return err
+	}
+	version := httpupdate.NewVersion()
+	if !up || version == u.badVersion() {
+		return nil
+	}
+	// The status isn't signed, only the binary is. Only newer versions
+	// are installed and the binary has to report the version itself, so an
+	// old signed binary can't be installed by announcing it as new
+	if err := newer(version, u.version); err != nil {
+		return err
+	}
+	bin, err := httpupdate.Fetch(httpupdate.FetchUrl(), u.key)
+	if err != nil {
+		return err
+	}
+	if err := httpupdate.Install(u.path, bin); err != nil {
+		return err
+	}
+	if err := u.checkBinary(version); err != nil {
+		u.markBad(version)
+		if rerr := httpupdate.Rollback(u.path); rerr != nil {
+			log.Error(rerr)
+		}
+		return err
+	}
+	log.Infof("Updated plvp from %s to %s, restarting", u.version, version)
+	return u.restart(append(environ(), UpdatedEnv+"="+u.version))
+}
+
+// Confirm waits for a plvp started by an update from version from to
+// become healthy. If it fails on ec or isn't healthy in time the old
+// binary is put back and restarted and this version won't be tried again
+func (u *Updater) Confirm(from string, ec <-chan error) error {
+	tick := time.NewTicker(healthInterval)
+	defer tick.Stop()
+	deadline := time.After(healthTimeout)
+	var err error
+	for err == nil {
+		select {
+		case err = <-ec:
+			if err == nil {
+				err = fmt.Errorf("Stopped")
+			}
+		case <-deadline:
+			err = fmt.Errorf("Not healthy after %v", healthTimeout)
+		case <-tick.C:
+			if u.Health() == nil {
+				log.Infof("Update from %s to %s is healthy", from, u.version)
+				return nil
+			}
+		}
+	}
+	log.Errorf("Update from %s to %s failed, rolling back: %v", from, u.version, err)
+	u.markBad(u.version)
+	if rerr := httpupdate.Rollback(u.path); rerr != nil {
+		return rerr
+	}
+	if rerr := u.restart(environ()); rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+// environ is the environment without the version an update replaced
+func environ() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, UpdatedEnv+"=") {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
+func (u *Updater) restart(env []string) error {
+	u.Stop()
+	return u.exec(u.path, os.Args, env)
+}
+
+// checkBinary runs the installed binary with -version under mproc and
+// checks it reports version
+func (u *Updater) checkBinary(version string) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	p := proc.New(u.path, &os.ProcAttr{Files: []*os.File{nil, w, os.Stderr}}, "-version")
+	id, err := u.mp.ManageProcess(p, false, 0)
+	w.Close()
+	if err != nil {
+		return err
+	}
+	out := make(chan []byte, 1)
+	go func() {
+		b, _ := ioutil.ReadAll(io.LimitReader(r, 1024))
+		out <- b
+	}()
+	select {
+	case b := <-out:
+		<-u.mp.WaitProc(id)
+		if got := strings.TrimSpace(string(b)); got != version {
+			return fmt.Errorf("New binary reports version %q, expected %q", got, version)
+		}
+		return nil
+	case <-time.After(versionTimeout):
+		u.mp.KillProc(id)
+		return fmt.Errorf("New binary did not report its version")
+	}
+}
+
+// newer checks that version is a later semantic version than running
+func newer(version, running string) error {
+	vr, err := semver.Make(running)
+	if err != nil {
+		return fmt.Errorf("Running version %q is not a semantic version, not updating", running)
+	}
+	vn, err := semver.Make(version)
+	if err != nil {
+		return err
+	}
+	if vn.LTE(vr) {
+		return fmt.Errorf("Version %s is not newer than %s", version, running)
+	}
+	return nil
+}
+
+// The last version that failed is kept next to the binary
+func (u *Updater) badVersion() string {
+	b, err := ioutil.ReadFile(u.path + ".bad")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func (u *Updater) markBad(version string) {
+	if err := ioutil.WriteFile(u.path+".bad", []byte(version+"\n"), 0644); err != nil {
+		log.Error(err)
+	}
+}
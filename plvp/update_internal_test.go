@@ -0,0 +1,270 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package plvp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/httpupdate"
+	"github.com/NEU-SNS/ReverseTraceroute/mproc/proc"
+)
+
+type updateServer struct {
+	*httptest.Server
+	fetches int32
+}
+
+// newUpdateServer serves a status announcing version 2.0.0 and a binary
+// that prints reports when run, signed with priv
+func newUpdateServer(t *testing.T, priv *ecdsa.PrivateKey, reports string) *updateServer {
+	bin := []byte(fmt.Sprintf("#!/bin/sh\necho %s\n", reports))
+	sum := sha256.Sum256(bin)
+	r, s, err := ecdsa.Sign(rand.Reader, priv, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	us := &updateServer{}
+	us.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/plvp.json":
+			fmt.Fprintf(rw, `{"version": "2.0.0", "get": "%s/plvp"}`, us.URL)
+		case "/plvp":
+			atomic.AddInt32(&us.fetches, 1)
+			rw.Write(bin)
+		case "/plvp.sig":
+			rw.Write(sig)
+		default:
+			http.NotFound(rw, req)
+		}
+	}))
+	return us
+}
+
+type execCall struct {
+	path string
+	env  []string
+}
+
+func newTestUpdater(t *testing.T, url string, priv *ecdsa.PrivateKey) (*Updater, *[]execCall, func()) {
+	dir, err := ioutil.TempDir("", "plvpupdate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "plvp")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\necho 1.0.0\n"), 0755); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	var calls []execCall
+	u := NewUpdater(url, "1.0.0", path, &priv.PublicKey)
+	u.exec = func(path string, args, env []string) error {
+		calls = append(calls, execCall{path: path, env: env})
+		return nil
+	}
+	return u, &calls, func() { os.RemoveAll(dir) }
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func hasEnv(env []string, kv string) bool {
+	for _, e := range env {
+		if e == kv {
+			return true
+		}
+	}
+	return false
+}
+
+func TestUpdaterCheck(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv := newUpdateServer(t, priv, "2.0.0")
+	defer srv.Close()
+	u, calls, done := newTestUpdater(t, srv.URL+"/plvp.json", priv)
+	defer done()
+	var stopped bool
+	u.Stop = func() { stopped = true }
+	if err := u.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(readFile(t, u.path), "2.0.0") {
+		t.Fatal("Binary was not replaced")
+	}
+	if !stopped || len(*calls) != 1 || (*calls)[0].path != u.path {
+		t.Fatalf("Expected the vp stopped and %s executed, got %v %v", u.path, stopped, *calls)
+	}
+	if !hasEnv((*calls)[0].env, UpdatedEnv+"=1.0.0") {
+		t.Fatalf("Restarted without %s", UpdatedEnv)
+	}
+}
+
+func TestUpdaterCheckBadKey(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv := newUpdateServer(t, other, "2.0.0")
+	defer srv.Close()
+	u, calls, done := newTestUpdater(t, srv.URL+"/plvp.json", priv)
+	defer done()
+	if err := u.Check(); err == nil {
+		t.Fatal("Expected a binary signed by another key to be refused")
+	}
+	if !strings.Contains(readFile(t, u.path), "1.0.0") || len(*calls) != 0 {
+		t.Fatal("Binary signed by another key was installed")
+	}
+}
+
+func TestUpdaterCheckRollback(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv := newUpdateServer(t, priv, "1.5.0")
+	defer srv.Close()
+	u, calls, done := newTestUpdater(t, srv.URL+"/plvp.json", priv)
+	defer done()
+	if err := u.Check(); err == nil {
+		t.Fatal("Expected a binary reporting the wrong version to fail")
+	}
+	if !strings.Contains(readFile(t, u.path), "1.0.0") || len(*calls) != 0 {
+		t.Fatal("Failed binary was not rolled back")
+	}
+	// The bad version isn't downloaded again
+	if err := u.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if f := atomic.LoadInt32(&srv.fetches); f != 1 {
+		t.Fatalf("Bad version fetched %d times, expected 1", f)
+	}
+}
+
+func TestUpdaterConfirm(t *testing.T) {
+	defer func(t, i time.Duration) {
+		healthTimeout, healthInterval = t, i
+	}(healthTimeout, healthInterval)
+	healthTimeout, healthInterval = time.Millisecond*200, time.Millisecond*10
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv := newUpdateServer(t, priv, "2.0.0")
+	defer srv.Close()
+	u, calls, done := newTestUpdater(t, srv.URL+"/plvp.json", priv)
+	defer done()
+	if err := u.Check(); err != nil {
+		t.Fatal(err)
+	}
+	// Now running as the new version
+	u.version = "2.0.0"
+	if err := u.Confirm("1.0.0", make(chan error)); err != nil {
+		t.Fatalf("Confirm of a healthy update: %v", err)
+	}
+	u.Health = func() error { return fmt.Errorf("down") }
+	if err := u.Confirm("1.0.0", make(chan error)); err == nil {
+		t.Fatal("Expected Confirm of an unhealthy update to fail")
+	}
+	if !strings.Contains(readFile(t, u.path), "1.0.0") {
+		t.Fatal("Unhealthy update was not rolled back")
+	}
+	if len(*calls) != 2 || hasEnv((*calls)[1].env, UpdatedEnv+"=1.0.0") {
+		t.Fatalf("Expected the old version restarted, got %v", *calls)
+	}
+	if u.badVersion() != "2.0.0" {
+		t.Fatalf("Bad version %q, expected 2.0.0", u.badVersion())
+	}
+	ec := make(chan error, 1)
+	ec <- fmt.Errorf("start failed")
+	u.Health = func() error { return nil }
+	if err := httpupdate.Install(u.path, []byte("2.0.0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Confirm("1.0.0", ec); err == nil {
+		t.Fatal("Expected Confirm of an update that failed to start to fail")
+	}
+}
+
+func TestUpdaterCheckNotNewer(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv := newUpdateServer(t, priv, "2.0.0")
+	defer srv.Close()
+	u, calls, done := newTestUpdater(t, srv.URL+"/plvp.json", priv)
+	defer done()
+	u.version = "3.0.0"
+	if err := u.Check(); err != nil {
+		t.Fatal(err)
+	}
+	u.version = "dev"
+	if err := u.Check(); err == nil {
+		t.Fatal("Expected an update from an unknown version to be refused")
+	}
+	if f := atomic.LoadInt32(&srv.fetches); f != 0 || len(*calls) != 0 {
+		t.Fatalf("Older version fetched %d times and installed %d times", f, len(*calls))
+	}
+}
+
+func TestHealth(t *testing.T) {
+	vp := &plVantagepointT{wantScamp: true}
+	if err := vp.health(); err == nil {
+		t.Fatal("Healthy without scamper")
+	}
+	p := proc.New("/bin/sleep", nil, "10")
+	if _, err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Kill()
+	vp.scamp = p
+	if err := vp.health(); err == nil {
+		t.Fatal("Healthy without a report accepted")
+	}
+	vp.reported = true
+	if err := vp.health(); err != nil {
+		t.Fatalf("Expected healthy, got %v", err)
+	}
+	p.Kill()
+	<-p.Wait()
+	if err := vp.health(); err == nil {
+		t.Fatal("Healthy after scamper exited")
+	}
+}
@@ -301,6 +301,53 @@ const (
 	dummyIP = "128.208.3.77"
 )
 
+// The option pointer starts at 5, the first slot, it is only
+// set on replies a VP parsed itself
+const tsFirstSlot = 5
+
+// tsReply answers which prespecified slots of a timestamp reply
+// were stamped. Replies parsed by a VP carry the option pointer,
+// overflow and which slots were stamped, others only have the
+// times, so a zero time is taken to mean the slot wasn't stamped
+type tsReply struct {
+	*datamodel.PingResponse
+}
+
+func (r tsReply) known() bool {
+	return r.TsPointer >= tsFirstSlot
+}
+
+func (r tsReply) slot(i int) *datamodel.TsAndAddr {
+	if i < len(r.Tsandaddr) {
+		return r.Tsandaddr[i]
+	}
+	return new(datamodel.TsAndAddr)
+}
+
+func (r tsReply) stamped(i int) bool {
+	if i >= len(r.Tsandaddr) {
+		return false
+	}
+	if r.known() {
+		return r.Tsandaddr[i].Stamped
+	}
+	return r.Tsandaddr[i].Ts != 0
+}
+
+// mangled is true when overflow was counted while the option still
+// had free slots. No hop should do that so the stamps can't be trusted
+func (r tsReply) mangled() bool {
+	if !r.known() || r.TsOverflow == 0 {
+		return false
+	}
+	for i := range r.Tsandaddr {
+		if !r.stamped(i) {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 	revtr.Stats.TSRoundCount++
 	start := time.Now()
@@ -402,24 +449,14 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 				logRevtr(revtr).Debug("Response ", rps[0].Tsandaddr)
 			}
 			if len(rps) > 0 {
-				var ts1, ts2, ts3 *datamodel.TsAndAddr
-				ts1 = new(datamodel.TsAndAddr)
-				ts2 = new(datamodel.TsAndAddr)
-				ts3 = new(datamodel.TsAndAddr)
-				switch len(rps[0].Tsandaddr) {
-				case 0:
-				case 1:
-					ts1 = rps[0].Tsandaddr[0]
-				case 2:
-					ts1 = rps[0].Tsandaddr[0]
-					ts2 = rps[0].Tsandaddr[1]
-				case 3:
-					ts1 = rps[0].Tsandaddr[0]
-					ts2 = rps[0].Tsandaddr[1]
-					ts3 = rps[0].Tsandaddr[2]
+				r := tsReply{rps[0]}
+				if r.mangled() {
+					logRevtr(revtr).Debug("TS probe is ", vp, p, "overflow with free slots, ignoring")
+					return
 				}
-				if ts3.Ts != 0 {
-					ss, _ := util.Int32ToIPString(rps[0].Tsandaddr[2].Ip)
+				ts1, ts2, ts3 := r.slot(0), r.slot(1), r.slot(2)
+				if r.stamped(2) {
+					ss, _ := util.Int32ToIPString(ts3.Ip)
 					var seg rt.Segment
 					if segClass == "SpoofTSAdjRevSegment" {
 						seg = rt.NewSpoofTSAdjRevSegment([]string{ss}, src, dsts, vp, false)
@@ -427,7 +464,7 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 						seg = rt.NewTSAdjRevSegment([]string{ss}, src, dsts, false)
 					}
 					revHopsSrcDstToRevSeg[pair{src: src, dst: dsts}] = []rt.Segment{seg}
-				} else if ts2.Ts != 0 {
+				} else if r.stamped(1) {
 					ts2ips, _ := util.Int32ToIPString(ts2.Ip)
 					if ts2.Ts-ts1.Ts > 3 || ts2.Ts < ts1.Ts {
 						// if 2nd slot is stamped with an increment from 1st, rev hop
@@ -442,8 +479,10 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 						// else, if 2nd stamp is clsoe to 1st, need to check for linux bug
 						linuxBugToCheckSrcDstVpToRevHops[triplet{src: src, dst: dsts, vp: vp}] = append(linuxBugToCheckSrcDstVpToRevHops[triplet{src: src, dst: dsts, vp: vp}], ts2ips)
 					}
-				} else if ts1.Ts == 0 {
+				} else if !r.stamped(0) {
 					// if dst responds, does not stamp, can try advanced techniques
+					// a dst that stamps with a time of 0 is only told apart
+					// from one that doesn't stamp when the pointer is known
 					ts2ips, _ := util.Int32ToIPString(ts2.Ip)
 					revtr.TSDstToStampsZero[dsts] = true
 					destDoesNotStamp = append(destDoesNotStamp, tripletTs{src: src, dst: dsts, tsip: ts2ips})
@@ -523,7 +562,10 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 			processTSCheckForLinuxBug := func(src, vp string, p *datamodel.Ping) {
 				dsts, _ := util.Int32ToIPString(p.Dst)
 				rps := p.GetResponses()
-				ts2 := rps[0].Tsandaddr[1]
+				if len(rps) == 0 {
+					return
+				}
+				r := tsReply{rps[0]}
 
 				segClass := "SpoofTSAdjRevSegment"
 				// if I got a response, must not be filtering, so dont need to use spoofing
@@ -532,7 +574,7 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 					revtr.TSSrcToHopToSendSpoofed[src][dsts] = false
 					segClass = "TSAdjRevSegment"
 				}
-				if ts2.Ts != 0 {
+				if r.stamped(1) {
 					logRevtr(revtr).Debug("TS probe is ", vp, p, "linux bug")
 					// TODO keep track of linux bugs
 					// at least once, i observed a bug not stamp one probe, so
@@ -592,31 +634,21 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 			if len(rps) <= 0 {
 				return
 			}
-			var ts1, ts2, ts4 *datamodel.TsAndAddr
-			ts1 = new(datamodel.TsAndAddr)
-			ts2 = new(datamodel.TsAndAddr)
-			ts4 = new(datamodel.TsAndAddr)
-			switch len(rps[0].Tsandaddr) {
-			case 0:
-			case 1:
-				ts1 = rps[0].Tsandaddr[0]
-			case 2, 3:
-				ts1 = rps[0].Tsandaddr[0]
-				ts2 = rps[0].Tsandaddr[1]
-			case 4:
-				ts1 = rps[0].Tsandaddr[0]
-				ts2 = rps[0].Tsandaddr[1]
-				ts4 = rps[0].Tsandaddr[3]
+			r := tsReply{rps[0]}
+			if r.mangled() {
+				logRevtr(revtr).Debug("TS probe is ", vp, p, "overflow with free slots, ignoring")
+				return
 			}
+			ts1, ts2 := r.slot(0), r.slot(1)
 			// if 2 stamps, we assume one was forward, one was reverse
 			// if 1 or 4, we need to verify it was reverse
 			// 3 should not happend according to justine?
-			if ts2.Ts != 0 && ts4.Ts == 0 {
+			if r.stamped(1) && !r.stamped(3) {
 				// declare reverse hop
-				ts2ips, _ := util.Int32ToIPString(ts2.Ts)
+				ts2ips, _ := util.Int32ToIPString(ts2.Ip)
 				revHopsSrcDstToRevSeg[pair{src: src, dst: dsts}] = []rt.Segment{rt.NewSpoofTSAdjRevSegmentTSZeroDoubleStamp([]string{ts2ips}, src, dsts, vp, false)}
 				logRevtr(revtr).Debug("TS Probe is ", vp, p, "reverse hop from dst that stamps 0!")
-			} else if ts1.Ts != 0 {
+			} else if r.stamped(0) {
 				logRevtr(revtr).Debug("TS probe is ", vp, p, "dst does not stamp, but spoofer ", vp, "got a stamp")
 				ts1ips, _ := util.Int32ToIPString(ts1.Ip)
 				destDoesNotStampToVerifySpooferToProbe[vp] = append(destDoesNotStampToVerifySpooferToProbe[vp], []string{dsts, ts1ips, ts1ips, ts1ips, ts1ips})
@@ -648,9 +680,13 @@ func (b *rtBatch) timestamp(revtr *rt.ReverseTraceroute) step {
 			processTSDestDoesNotStampToVerify := func(src, vp string, p *datamodel.Ping) {
 				dsts, _ := util.Int32ToIPString(p.Dst)
 				rps := p.GetResponses()
-				ts1 := rps[0].Tsandaddr[0]
+				if len(rps) == 0 {
+					return
+				}
+				r := tsReply{rps[0]}
+				ts1 := r.slot(0)
 				ts1ips, _ := util.Int32ToIPString(ts1.Ip)
-				if ts1.Ts == 0 {
+				if !r.stamped(0) {
 					logRevtr(revtr).Debug("Reverse hop! TS probe is ", vp, p, "dst does not stamp, but spoofer", vp, "got a stamp and didn't direclty")
 					maybeRevhopVPDstAdjToBool[tripletTs{src: src, dst: dsts, tsip: ts1ips}] = true
 				} else {
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
+)
+
+func TestTSReplyStamped(t *testing.T) {
+	slots := func(ts ...uint32) []*datamodel.TsAndAddr {
+		var ret []*datamodel.TsAndAddr
+		for i, v := range ts {
+			ret = append(ret, &datamodel.TsAndAddr{Ip: uint32(i + 1), Ts: v})
+		}
+		return ret
+	}
+	// stamp marks the first n slots stamped like a VP does
+	stamp := func(n int, s []*datamodel.TsAndAddr) []*datamodel.TsAndAddr {
+		for i := 0; i < n; i++ {
+			s[i].Stamped = true
+		}
+		return s
+	}
+	for _, test := range []struct {
+		name    string
+		pr      *datamodel.PingResponse
+		stamped []bool
+		mangled bool
+	}{
+		{
+			name:    "no pointer uses times",
+			pr:      &datamodel.PingResponse{Tsandaddr: slots(10, 0, 12)},
+			stamped: []bool{true, false, true, false},
+		},
+		{
+			name: "vp marks a zero time stamped",
+			pr: &datamodel.PingResponse{
+				Tsandaddr: stamp(2, slots(0, 11, 0, 0)),
+				TsPointer: 21,
+			},
+			stamped: []bool{true, true, false, false},
+		},
+		{
+			name: "overflow with a full option",
+			pr: &datamodel.PingResponse{
+				Tsandaddr:  stamp(4, slots(10, 11, 12, 13)),
+				TsPointer:  37,
+				TsOverflow: 1,
+			},
+			stamped: []bool{true, true, true, true},
+		},
+		{
+			name: "overflow with free slots",
+			pr: &datamodel.PingResponse{
+				Tsandaddr:  stamp(1, slots(10, 0, 0, 0)),
+				TsPointer:  13,
+				TsOverflow: 1,
+			},
+			stamped: []bool{true, false, false, false},
+			mangled: true,
+		},
+	} {
+		r := tsReply{test.pr}
+		for i, want := range test.stamped {
+			if got := r.stamped(i); got != want {
+				t.Fatalf("%s: stamped(%d) Wanted %v got %v", test.name, i, want, got)
+			}
+		}
+		if got := r.mangled(); got != test.mangled {
+			t.Fatalf("%s: mangled() Wanted %v got %v", test.name, test.mangled, got)
+		}
+	}
+}
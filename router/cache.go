@@ -0,0 +1,342 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package router
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/prometheus/client_golang/prometheus"
+	con "golang.org/x/net/context"
+)
+
+var (
+	// ErrServiceBackoff is returned when a service failed recently and is waiting to reconnect
+	ErrServiceBackoff = fmt.Errorf("Service failed recently, waiting to reconnect")
+	// ErrToolClosed is returned when a measurement tool is used after it was released
+	ErrToolClosed = fmt.Errorf("Measurement tool already closed")
+)
+
+var (
+	mtInUse = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "router_mt_in_use",
+		Help: "The number of measurement tools acquired and not yet released.",
+	}, []string{"service"})
+	mtConnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_mt_connects",
+		Help: "The number of times a measurement tool was created.",
+	}, []string{"service"})
+	mtFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_mt_failures",
+		Help: "The number of times a measurement tool failed to connect or broke.",
+	}, []string{"service"})
+	mtEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_mt_evictions",
+		Help: "The number of idle measurement tools that were closed.",
+	}, []string{"service"})
+	mtHealthChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_mt_health_checks",
+		Help: "The results of measurement tool health checks.",
+	}, []string{"service", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(mtInUse)
+	prometheus.MustRegister(mtConnects)
+	prometheus.MustRegister(mtFailures)
+	prometheus.MustRegister(mtEvictions)
+	prometheus.MustRegister(mtHealthChecks)
+}
+
+// healthChecker is implemented by measurement tools that can check
+// whether their connection still works
+type healthChecker interface {
+	Check(con.Context) error
+}
+
+// conn is one connection to a service. It is closed once it has been
+// retired and the last user has released it
+type conn struct {
+	mt      MeasurementTool
+	refs    int
+	retired bool
+}
+
+// cacheEntry is the state of a service in the cache
+type cacheEntry struct {
+	sd        ServiceDef
+	cur       *conn
+	lastUsed  time.Time
+	failures  uint
+	nextRetry time.Time
+	// connecting is closed when the dial in progress finishes,
+	// dialErr is its error if it failed
+	connecting chan struct{}
+	dialErr    error
+}
+
+// mtCache shares the connections to services between callers
+type mtCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	create  func(ServiceDef) (MeasurementTool, error)
+	opts    cacheOptions
+	now     func() time.Time
+	// onFail is told about services whose health check failed
+	onFail func(ServiceDef)
+}
+
+type cacheOptions struct {
+	healthInterval time.Duration
+	idleTimeout    time.Duration
+	backoffBase    time.Duration
+	backoffMax     time.Duration
+}
+
+func newMTCache(create func(ServiceDef) (MeasurementTool, error), opts cacheOptions) *mtCache {
+	return &mtCache{
+		entries: make(map[string]*cacheEntry),
+		create:  create,
+		opts:    opts,
+		now:     time.Now,
+	}
+}
+
+// backoff must be called with the lock held
+func (c *mtCache) backoff(e *cacheEntry) {
+	mtFailures.WithLabelValues(e.sd.key()).Inc()
+	wait := c.opts.backoffBase << e.failures
+	if wait > c.opts.backoffMax || wait <= 0 {
+		wait = c.opts.backoffMax
+	} else {
+		e.failures++
+	}
+	e.nextRetry = c.now().Add(wait)
+}
+
+// retire must be called with the lock held
+func (c *mtCache) retire(e *cacheEntry) {
+	cn := e.cur
+	if cn == nil {
+		return
+	}
+	e.cur = nil
+	cn.retired = true
+	if cn.refs == 0 {
+		go cn.mt.Close()
+	}
+}
+
+// acquire returns a handle to the service's tool, connecting if needed.
+// The lock isn't held while dialing so a slow service only holds up the
+// callers waiting for it. The handle must be closed to release it
+func (c *mtCache) acquire(sd ServiceDef) (MeasurementTool, error) {
+	c.mu.Lock()
+	key := sd.key()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry{sd: sd}
+		c.entries[key] = e
+	}
+	// Wait for a dial in progress and share its result
+	for e.connecting != nil {
+		wait := e.connecting
+		c.mu.Unlock()
+		<-wait
+		c.mu.Lock()
+		if e.cur == nil && e.dialErr != nil {
+			err := e.dialErr
+			c.mu.Unlock()
+			return nil, err
+		}
+	}
+	if e.cur == nil {
+		if c.now().Before(e.nextRetry) {
+			c.mu.Unlock()
+			return nil, ErrServiceBackoff
+		}
+		done := make(chan struct{})
+		e.connecting = done
+		c.mu.Unlock()
+		mt, err := c.create(sd)
+		c.mu.Lock()
+		e.connecting = nil
+		e.dialErr = err
+		close(done)
+		if err != nil {
+			if err == ErrCantCreateMt {
+				if c.entries[key] == e {
+					delete(c.entries, key)
+				}
+			} else {
+				c.backoff(e)
+			}
+			c.mu.Unlock()
+			return nil, err
+		}
+		mtConnects.WithLabelValues(key).Inc()
+		e.cur = &conn{mt: mt}
+	}
+	defer c.mu.Unlock()
+	e.cur.refs++
+	e.lastUsed = c.now()
+	mtInUse.WithLabelValues(key).Inc()
+	return &handle{MeasurementTool: e.cur.mt, c: c, e: e, cn: e.cur}, nil
+}
+
+// release gives back a handle's use of cn
+func (c *mtCache) release(e *cacheEntry, cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cn.refs--
+	e.lastUsed = c.now()
+	mtInUse.WithLabelValues(e.sd.key()).Dec()
+	if cn.retired && cn.refs == 0 {
+		go cn.mt.Close()
+	}
+}
+
+// failed retires cn if it is still the service's current connection so the
+// next caller reconnects after a backoff. It reports whether cn was retired
+func (c *mtCache) failed(e *cacheEntry, cn *conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.cur != cn {
+		return false
+	}
+	log.Infof("Measurement tool for %s failed, reconnecting", e.sd.key())
+	c.retire(e)
+	c.backoff(e)
+	return true
+}
+
+// failedTool retires the connection of sd if mt is still its current tool
+func (c *mtCache) failedTool(sd ServiceDef, mt MeasurementTool) bool {
+	c.mu.Lock()
+	e, ok := c.entries[sd.key()]
+	var cn *conn
+	if ok {
+		cn = e.cur
+	}
+	c.mu.Unlock()
+	if cn == nil || cn.mt != mt {
+		return false
+	}
+	return c.failed(e, cn)
+}
+
+// check health checks the connections and closes the ones that are idle
+func (c *mtCache) check() {
+	type target struct {
+		e  *cacheEntry
+		cn *conn
+		hc healthChecker
+	}
+	var targets []target
+	c.mu.Lock()
+	now := c.now()
+	for key, e := range c.entries {
+		if e.connecting != nil {
+			continue
+		}
+		if e.cur == nil {
+			// Keep the failure count so the backoff keeps growing
+			// while the service is still being retried
+			if now.Sub(e.nextRetry) > c.opts.idleTimeout {
+				delete(c.entries, key)
+			}
+			continue
+		}
+		if e.cur.refs == 0 && now.Sub(e.lastUsed) > c.opts.idleTimeout {
+			mtEvictions.WithLabelValues(key).Inc()
+			c.retire(e)
+			delete(c.entries, key)
+			continue
+		}
+		if hc, ok := e.cur.mt.(healthChecker); ok {
+			targets = append(targets, target{e: e, cn: e.cur, hc: hc})
+		}
+	}
+	c.mu.Unlock()
+	for _, t := range targets {
+		ctx, cancel := con.WithTimeout(con.Background(), c.opts.healthInterval)
+		err := t.hc.Check(ctx)
+		cancel()
+		key := t.e.sd.key()
+		if err != nil {
+			log.Errorf("Health check of %s failed: %v", key, err)
+			mtHealthChecks.WithLabelValues(key, "fail").Inc()
+			if c.failed(t.e, t.cn) && c.onFail != nil {
+				c.onFail(t.e.sd)
+			}
+			continue
+		}
+		mtHealthChecks.WithLabelValues(key, "ok").Inc()
+		c.mu.Lock()
+		if t.e.cur == t.cn {
+			t.e.failures = 0
+		}
+		c.mu.Unlock()
+	}
+}
+
+// run checks the cache every health interval until stop is closed.
+// A nil stop runs for the life of the process
+func (c *mtCache) run(stop <-chan struct{}) {
+	t := time.NewTicker(c.opts.healthInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			c.check()
+		}
+	}
+}
+
+// handle is a caller's use of a cached measurement tool.
+// Closing it releases the tool rather than closing it
+type handle struct {
+	MeasurementTool
+	c    *mtCache
+	e    *cacheEntry
+	cn   *conn
+	once sync.Once
+}
+
+func (h *handle) Close() error {
+	err := ErrToolClosed
+	h.once.Do(func() {
+		h.c.release(h.e, h.cn)
+		err = nil
+	})
+	return err
+}
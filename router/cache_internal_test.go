@@ -0,0 +1,350 @@
+package router
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	con "golang.org/x/net/context"
+)
+
+type fakeMT struct {
+	mu      sync.Mutex
+	closed  bool
+	healthy error
+}
+
+func (f *fakeMT) Ping(con.Context, *dm.PingArg) (<-chan *dm.Ping, error) { return nil, nil }
+func (f *fakeMT) Traceroute(con.Context, *dm.TracerouteArg) (<-chan *dm.Traceroute, error) {
+	return nil, nil
+}
+func (f *fakeMT) Tracelb(con.Context, *dm.TracelbArg) (<-chan *dm.Tracelb, error) {
+	return nil, nil
+}
+func (f *fakeMT) Dealias(con.Context, *dm.DealiasArg) (<-chan *dm.Dealias, error) {
+	return nil, nil
+}
+func (f *fakeMT) GetVPs(con.Context, *dm.VPRequest) (<-chan *dm.VPReturn, error) { return nil, nil }
+func (f *fakeMT) GetVPStatus(con.Context, *dm.VPStatusRequest) (*dm.VPStatusReturn, error) {
+	return nil, nil
+}
+func (f *fakeMT) ReceiveSpoof(con.Context, *dm.RecSpoof) (<-chan *dm.NotifyRecSpoofResponse, error) {
+	return nil, nil
+}
+func (f *fakeMT) Check(con.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.healthy
+}
+func (f *fakeMT) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+func (f *fakeMT) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+type fakeCreator struct {
+	created []*fakeMT
+	err     error
+}
+
+func (fc *fakeCreator) create(ServiceDef) (MeasurementTool, error) {
+	if fc.err != nil {
+		return nil, fc.err
+	}
+	mt := &fakeMT{}
+	fc.created = append(fc.created, mt)
+	return mt, nil
+}
+
+var testSD = ServiceDef{Addr: "127.0.0.1", Port: "4380", Service: PlanetLab}
+
+func newTestCache(fc *fakeCreator, now *time.Time) *mtCache {
+	c := newMTCache(fc.create, cacheOptions{
+		healthInterval: time.Second,
+		idleTimeout:    time.Minute,
+		backoffBase:    time.Second,
+		backoffMax:     4 * time.Second,
+	})
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func waitClosed(t *testing.T, mt *fakeMT) {
+	for i := 0; i < 100; i++ {
+		if mt.isClosed() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("measurement tool was not closed")
+}
+
+func TestCacheShares(t *testing.T) {
+	now := time.Unix(1000, 0)
+	fc := &fakeCreator{}
+	c := newTestCache(fc, &now)
+	a, err := c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.created) != 1 {
+		t.Fatalf("created %d tools, expected 1", len(fc.created))
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != ErrToolClosed {
+		t.Fatalf("second Close returned %v, expected %v", err, ErrToolClosed)
+	}
+	b.Close()
+	if fc.created[0].isClosed() {
+		t.Fatal("releasing the tool closed it")
+	}
+	if e := c.entries[testSD.key()]; e.cur.refs != 0 {
+		t.Fatalf("refs %d after releasing every handle", e.cur.refs)
+	}
+}
+
+func TestCacheFailureRetires(t *testing.T) {
+	now := time.Unix(1000, 0)
+	fc := &fakeCreator{}
+	c := newTestCache(fc, &now)
+	a, err := c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.failedTool(testSD, fc.created[0]) {
+		t.Fatal("failedTool did not retire the current tool")
+	}
+	// In use, so the old tool stays open for its current user
+	if fc.created[0].isClosed() {
+		t.Fatal("retired tool closed while still in use")
+	}
+	if _, err := c.acquire(testSD); err != ErrServiceBackoff {
+		t.Fatalf("acquire during backoff returned %v, expected %v", err, ErrServiceBackoff)
+	}
+	now = now.Add(2 * time.Second)
+	b, err := c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.created) != 2 {
+		t.Fatalf("created %d tools, expected a reconnect", len(fc.created))
+	}
+	// A failure of the old tool does not affect the new one
+	if c.failedTool(testSD, fc.created[0]) {
+		t.Fatal("failure of a retired tool retired the new one")
+	}
+	a.Close()
+	waitClosed(t, fc.created[0])
+	b.Close()
+	if fc.created[1].isClosed() {
+		t.Fatal("current tool closed on release")
+	}
+}
+
+func TestCacheBackoffGrows(t *testing.T) {
+	now := time.Unix(1000, 0)
+	fc := &fakeCreator{err: fmt.Errorf("dial failed")}
+	c := newTestCache(fc, &now)
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		if _, err := c.acquire(testSD); err != fc.err {
+			t.Fatalf("acquire returned %v, expected %v", err, fc.err)
+		}
+		e := c.entries[testSD.key()]
+		if got := e.nextRetry.Sub(now); got != wait {
+			t.Fatalf("backoff %v, expected %v", got, wait)
+		}
+		now = e.nextRetry
+	}
+}
+
+func TestCacheCheck(t *testing.T) {
+	now := time.Unix(1000, 0)
+	fc := &fakeCreator{}
+	c := newTestCache(fc, &now)
+	a, err := c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	// Unhealthy tools are retired
+	fc.created[0].healthy = fmt.Errorf("broken")
+	c.check()
+	waitClosed(t, fc.created[0])
+	// Idle tools are closed
+	now = now.Add(time.Hour)
+	a, err = c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	now = now.Add(2 * time.Minute)
+	c.check()
+	waitClosed(t, fc.created[1])
+	if _, ok := c.entries[testSD.key()]; ok {
+		t.Fatal("idle entry was not removed")
+	}
+}
+
+func TestCacheCheckKeepsBackoff(t *testing.T) {
+	now := time.Unix(1000, 0)
+	fc := &fakeCreator{}
+	c := newTestCache(fc, &now)
+	var reported []ServiceDef
+	c.onFail = func(sd ServiceDef) { reported = append(reported, sd) }
+	a, err := c.acquire(testSD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	// Each health check failure follows a reconnect, the
+	// backoff has to keep growing across the checks
+	for i, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		fc.created[i].healthy = fmt.Errorf("broken")
+		c.check()
+		e := c.entries[testSD.key()]
+		if got := e.nextRetry.Sub(now); got != wait {
+			t.Fatalf("backoff %v, expected %v", got, wait)
+		}
+		now = e.nextRetry.Add(time.Millisecond)
+		c.check()
+		a, err := c.acquire(testSD)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Close()
+	}
+	if len(reported) != 3 || reported[0] != testSD {
+		t.Fatalf("reported failures %v, expected 3 of %v", reported, testSD)
+	}
+}
+
+// blockingCreator dials instantly except for the services in block,
+// which wait until their channel is closed
+type blockingCreator struct {
+	mu    sync.Mutex
+	block map[string]chan struct{}
+	dials map[string]int
+	err   error
+}
+
+func (bc *blockingCreator) create(sd ServiceDef) (MeasurementTool, error) {
+	bc.mu.Lock()
+	bc.dials[sd.key()]++
+	wait := bc.block[sd.key()]
+	bc.mu.Unlock()
+	if wait != nil {
+		<-wait
+	}
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.err != nil {
+		return nil, bc.err
+	}
+	return &fakeMT{}, nil
+}
+
+func (bc *blockingCreator) getDials(sd ServiceDef) int {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	return bc.dials[sd.key()]
+}
+
+func TestCacheSlowDialOnlyBlocksItsService(t *testing.T) {
+	now := time.Unix(1000, 0)
+	slow := ServiceDef{Addr: "127.0.0.2", Port: "4380", Service: PlanetLab}
+	unblock := make(chan struct{})
+	bc := &blockingCreator{
+		block: map[string]chan struct{}{slow.key(): unblock},
+		dials: make(map[string]int),
+	}
+	c := newTestCache(&fakeCreator{}, &now)
+	c.create = bc.create
+	type result struct {
+		mt  MeasurementTool
+		err error
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			mt, err := c.acquire(slow)
+			results <- result{mt, err}
+		}()
+	}
+	for bc.getDials(slow) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Another service and the health check aren't held up by the dial
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mt, err := c.acquire(testSD)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mt.Close()
+		c.check()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("acquire of another service waited for a slow dial")
+	}
+	close(unblock)
+	for i := 0; i < 2; i++ {
+		r := <-results
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		r.mt.Close()
+	}
+	if d := bc.getDials(slow); d != 1 {
+		t.Fatalf("dialed the slow service %d times, expected 1", d)
+	}
+}
+
+func TestCacheWaitersShareDialError(t *testing.T) {
+	now := time.Unix(1000, 0)
+	unblock := make(chan struct{})
+	bc := &blockingCreator{
+		block: map[string]chan struct{}{testSD.key(): unblock},
+		dials: make(map[string]int),
+		err:   fmt.Errorf("unreachable"),
+	}
+	c := newTestCache(&fakeCreator{}, &now)
+	c.create = bc.create
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := c.acquire(testSD)
+			errs <- err
+		}()
+	}
+	for bc.getDials(testSD) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(unblock)
+	for i := 0; i < 2; i++ {
+		// A caller that arrives after the dial failed gets the backoff
+		if err := <-errs; err == nil {
+			t.Fatal("expected an error from a failed dial")
+		}
+	}
+	if d := bc.getDials(testSD); d != 1 {
+		t.Fatalf("dialed %d times, expected 1", d)
+	}
+}
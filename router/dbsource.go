@@ -0,0 +1,327 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+)
+
+var (
+	// ErrNoController is returned when a vantage point is not connected to a plcontroller
+	ErrNoController = fmt.Errorf("No plcontroller found for the vantage point")
+	// ErrControllerDraining is returned when a vantage point's plcontroller is being drained
+	ErrControllerDraining = fmt.Errorf("The plcontroller for the vantage point is draining")
+	// ErrControllerDown is returned when a vantage point's plcontroller has recently failed
+	ErrControllerDown = fmt.Errorf("The plcontroller for the vantage point is down")
+)
+
+// VPProvider provides the vantage points and the plcontrollers they are connected to
+type VPProvider interface {
+	GetVPs() ([]*dm.VantagePoint, error)
+}
+
+// minReroute is the least time between the refreshes made when a vantage
+// point's plcontroller is down or draining
+const minReroute = time.Second
+
+// DBSource is a Source that routes each vantage point to the plcontroller
+// recorded in its controller column. The routes are refreshed on a timer and
+// whenever a plcontroller fails. When a vantage point's plcontroller is down
+// or draining the routes are reloaded before giving up, so a vantage point that
+// has reconnected to another plcontroller is rerouted to it. Plcontrollers can
+// be drained so that no new measurements are sent to them
+type DBSource struct {
+	vps       VPProvider
+	port      string
+	refresh   time.Duration
+	downFor   time.Duration
+	mu        sync.RWMutex
+	routes    map[uint32]string
+	draining  map[string]bool
+	down      map[string]time.Time
+	kick      chan struct{}
+	now       func() time.Time
+	rmu       sync.Mutex // serializes reroute
+	refreshed time.Time
+}
+
+// NewDBSource creates a DBSource which reads the vantage points from vps.
+// Plcontrollers are contacted on port and routes are refreshed every refresh.
+// A plcontroller that fails is avoided for downFor or until a refresh no
+// longer routes vantage points to it
+func NewDBSource(vps VPProvider, port string, refresh, downFor time.Duration) *DBSource {
+	return &DBSource{
+		vps:      vps,
+		port:     port,
+		refresh:  refresh,
+		downFor:  downFor,
+		routes:   make(map[uint32]string),
+		draining: make(map[string]bool),
+		down:     make(map[string]time.Time),
+		kick:     make(chan struct{}, 1),
+		now:      time.Now,
+	}
+}
+
+// Refresh reloads the routes from the vantage points
+func (s *DBSource) Refresh() error {
+	vps, err := s.vps.GetVPs()
+	if err != nil {
+		return err
+	}
+	routes := make(map[uint32]string, len(vps))
+	for _, vp := range vps {
+		if vp.Controller == 0 {
+			continue
+		}
+		addr, err := util.Int32ToIPString(vp.Controller)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		routes[vp.Ip] = addr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = routes
+	s.refreshed = s.now()
+	return nil
+}
+
+// reroute reloads the routes unless they were loaded within minReroute
+func (s *DBSource) reroute() {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+	s.mu.RLock()
+	fresh := s.now().Sub(s.refreshed) < minReroute
+	s.mu.RUnlock()
+	if fresh {
+		return
+	}
+	if err := s.Refresh(); err != nil {
+		log.Error(err)
+	}
+}
+
+// Run refreshes the routes until stop is closed.
+// A nil stop runs for the life of the process
+func (s *DBSource) Run(stop <-chan struct{}) {
+	t := time.NewTicker(s.refresh)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		case <-s.kick:
+		}
+		if err := s.Refresh(); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func (s *DBSource) serviceDef(addr string) ServiceDef {
+	return ServiceDef{
+		Addr:    addr,
+		Port:    s.port,
+		Service: PlanetLab,
+	}
+}
+
+// isDown must be called with the lock held
+func (s *DBSource) isDown(addr string) bool {
+	at, ok := s.down[addr]
+	return ok && s.now().Sub(at) < s.downFor
+}
+
+// Get returns the service for the vantage point src
+func (s *DBSource) Get(src string) (ServiceDef, error) {
+	ip, err := util.IPStringToInt32(src)
+	if err != nil {
+		return ServiceDef{}, err
+	}
+	sd, err := s.get(ip)
+	switch err {
+	case ErrControllerDown, ErrControllerDraining:
+		// The vantage point may have moved to another plcontroller
+		s.reroute()
+		return s.get(ip)
+	}
+	return sd, err
+}
+
+func (s *DBSource) get(ip uint32) (ServiceDef, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	addr, ok := s.routes[ip]
+	switch {
+	case !ok:
+		return ServiceDef{}, ErrNoController
+	case s.draining[addr]:
+		return ServiceDef{}, ErrControllerDraining
+	case s.isDown(addr):
+		return ServiceDef{}, ErrControllerDown
+	}
+	return s.serviceDef(addr), nil
+}
+
+// All returns the services of the plcontrollers that are neither draining nor down
+func (s *DBSource) All() []ServiceDef {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := make(map[string]bool)
+	var addrs []string
+	for _, addr := range s.routes {
+		if seen[addr] || s.draining[addr] || s.isDown(addr) {
+			continue
+		}
+		seen[addr] = true
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	ret := make([]ServiceDef, 0, len(addrs))
+	for _, addr := range addrs {
+		ret = append(ret, s.serviceDef(addr))
+	}
+	return ret
+}
+
+// Drain stops new measurements from being routed to the plcontroller at addr
+func (s *DBSource) Drain(addr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.Infof("Draining plcontroller: %s", addr)
+	s.draining[addr] = true
+}
+
+// Undrain allows measurements to be routed to the plcontroller at addr again
+func (s *DBSource) Undrain(addr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.Infof("Undraining plcontroller: %s", addr)
+	delete(s.draining, addr)
+}
+
+// Failed marks the plcontroller of sd as down and refreshes the routes so
+// vantage points that have moved to another plcontroller are found
+func (s *DBSource) Failed(sd ServiceDef) {
+	s.mu.Lock()
+	log.Infof("Plcontroller failed: %s", sd.Addr)
+	s.down[sd.Addr] = s.now()
+	s.mu.Unlock()
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+type controllerStatus struct {
+	Addr     string `json:"addr"`
+	VPs      int    `json:"vps"`
+	Draining bool   `json:"draining"`
+	Down     bool   `json:"down"`
+}
+
+type controllerStatuses []*controllerStatus
+
+func (cs controllerStatuses) Len() int           { return len(cs) }
+func (cs controllerStatuses) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+func (cs controllerStatuses) Less(i, j int) bool { return cs[i].Addr < cs[j].Addr }
+
+// ServeHTTP shows the state of each plcontroller. It only reads the state so
+// it can go on a public listener, draining is done through DrainHandler
+func (s *DBSource) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(rw, "plcontrollers are drained on the admin address", http.StatusMethodNotAllowed)
+		return
+	}
+	s.writeStatus(rw)
+}
+
+// DrainHandler returns a handler where a POST with an addr and a drain
+// parameter drains or undrains a plcontroller. It has no authentication
+// so it must only be served on a listener operators can reach
+func (s *DBSource) DrainHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			addr := req.FormValue("addr")
+			drain, err := strconv.ParseBool(req.FormValue("drain"))
+			if addr == "" || err != nil {
+				http.Error(rw, "addr and drain are required", http.StatusBadRequest)
+				return
+			}
+			if drain {
+				s.Drain(addr)
+			} else {
+				s.Undrain(addr)
+			}
+		}
+		s.writeStatus(rw)
+	})
+}
+
+func (s *DBSource) writeStatus(rw http.ResponseWriter) {
+	s.mu.RLock()
+	stats := make(map[string]*controllerStatus)
+	for _, addr := range s.routes {
+		st, ok := stats[addr]
+		if !ok {
+			st = &controllerStatus{Addr: addr}
+			stats[addr] = st
+		}
+		st.VPs++
+	}
+	for addr := range s.draining {
+		if _, ok := stats[addr]; !ok {
+			stats[addr] = &controllerStatus{Addr: addr}
+		}
+	}
+	var ret controllerStatuses
+	for addr, st := range stats {
+		st.Draining = s.draining[addr]
+		st.Down = s.isDown(addr)
+		ret = append(ret, st)
+	}
+	s.mu.RUnlock()
+	sort.Sort(ret)
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(ret); err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,133 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/router"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+)
+
+type vpProvider struct {
+	vps []*dm.VantagePoint
+}
+
+func (v *vpProvider) GetVPs() ([]*dm.VantagePoint, error) {
+	return v.vps, nil
+}
+
+func ip(t *testing.T, s string) uint32 {
+	i, err := util.IPStringToInt32(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestDBSourceGet(t *testing.T) {
+	vps := &vpProvider{vps: []*dm.VantagePoint{
+		{Ip: ip(t, "10.0.0.1"), Controller: ip(t, "192.168.1.1")},
+		{Ip: ip(t, "10.0.0.2"), Controller: ip(t, "192.168.1.2")},
+		{Ip: ip(t, "10.0.0.3")},
+	}}
+	src := router.NewDBSource(vps, "4380", time.Minute, time.Minute)
+	if err := src.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	src.Drain("192.168.1.2")
+	for _, test := range []struct {
+		desc string
+		vp   string
+		addr string
+		err  error
+	}{
+		{desc: "Connected vp", vp: "10.0.0.1", addr: "192.168.1.1"},
+		{desc: "Draining controller", vp: "10.0.0.2", err: router.ErrControllerDraining},
+		{desc: "Unconnected vp", vp: "10.0.0.3", err: router.ErrNoController},
+		{desc: "Unknown vp", vp: "10.0.0.4", err: router.ErrNoController},
+	} {
+		sd, err := src.Get(test.vp)
+		if err != test.err {
+			t.Errorf("%s: Get(%s) error %v, expected %v", test.desc, test.vp, err, test.err)
+			continue
+		}
+		if err == nil && (sd.Addr != test.addr || sd.Port != "4380" || sd.Service != router.PlanetLab) {
+			t.Errorf("%s: Get(%s) = %v, expected addr %s", test.desc, test.vp, sd, test.addr)
+		}
+	}
+	all := src.All()
+	if len(all) != 1 || all[0].Addr != "192.168.1.1" {
+		t.Fatalf("All() = %v, expected only 192.168.1.1", all)
+	}
+	src.Undrain("192.168.1.2")
+	if _, err := src.Get("10.0.0.2"); err != nil {
+		t.Fatalf("Get after undrain: %v", err)
+	}
+}
+
+func TestDBSourceFailover(t *testing.T) {
+	vps := &vpProvider{vps: []*dm.VantagePoint{
+		{Ip: ip(t, "10.0.0.1"), Controller: ip(t, "192.168.1.1")},
+	}}
+	src := router.NewDBSource(vps, "4380", time.Minute, time.Minute)
+	if err := src.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	sd, err := src.Get("10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Failed(sd)
+	if _, err := src.Get("10.0.0.1"); err != router.ErrControllerDown {
+		t.Fatalf("Get after failure error %v, expected %v", err, router.ErrControllerDown)
+	}
+	// The vp reconnects to another plcontroller, Get reroutes
+	// it without waiting for the next refresh
+	vps.vps[0] = &dm.VantagePoint{Ip: ip(t, "10.0.0.1"), Controller: ip(t, "192.168.1.2")}
+	time.Sleep(1100 * time.Millisecond)
+	sd, err = src.Get("10.0.0.1")
+	if err != nil || sd.Addr != "192.168.1.2" {
+		t.Fatalf("Get after failover = %v, %v, expected 192.168.1.2", sd, err)
+	}
+}
+
+func TestDBSourceDrainHTTP(t *testing.T) {
+	vps := &vpProvider{vps: []*dm.VantagePoint{
+		{Ip: ip(t, "10.0.0.1"), Controller: ip(t, "192.168.1.1")},
+	}}
+	src := router.NewDBSource(vps, "4380", time.Minute, time.Minute)
+	if err := src.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	form := url.Values{"addr": {"192.168.1.1"}, "drain": {"true"}}
+	req := httptest.NewRequest(http.MethodPost, "/plcontrollers", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	src.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("drain on the public handler returned %d, expected %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/plcontrollers", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	src.DrainHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("drain returned %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"draining":true`) {
+		t.Fatalf("drain response missing draining state: %s", rec.Body.String())
+	}
+	if _, err := src.Get("10.0.0.1"); err != router.ErrControllerDraining {
+		t.Fatalf("Get after drain error %v, expected %v", err, router.ErrControllerDraining)
+	}
+	rec = httptest.NewRecorder()
+	src.DrainHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/plcontrollers", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("drain without addr returned %d, expected %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package router
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+	con "golang.org/x/net/context"
+)
+
+const (
+	// localSocket is the name the local scamper socket is added to the client under
+	localSocket = "local:scamper"
+	// localTimeout is used for measurements that do not set a timeout
+	localTimeout = 60
+)
+
+var (
+	// ErrLocalSpoof is returned when the local scamper is asked to receive spoofed probes
+	ErrLocalSpoof = fmt.Errorf("The local scamper can't receive spoofed probes")
+)
+
+// localSource routes every address to the same local scamper
+type localSource struct {
+	sd ServiceDef
+}
+
+// NewLocalSource creates a Source that sends every measurement to the local
+// scamper control socket at addr. If port is empty addr is the path of a unix socket.
+// Results are asked for in f, scampers without JSON output fall back to warts
+func NewLocalSource(addr, port string, f scamper.Format) Source {
+	return localSource{sd: ServiceDef{
+		Addr:    addr,
+		Port:    port,
+		Service: Local,
+		Format:  f,
+	}}
+}
+
+func (ls localSource) Get(string) (ServiceDef, error) {
+	return ls.sd, nil
+}
+
+func (ls localSource) All() []ServiceDef {
+	return []ServiceDef{ls.sd}
+}
+
+// localmt is a MeasurementTool that runs measurements on a scamper
+// control socket on the same host
+type localmt struct {
+	s    ServiceDef
+	cl   *scamper.Client
+	sock *scamper.Socket
+	r    *router
+}
+
+func createLocalMT(s ServiceDef, r *router) (*localmt, error) {
+	log.Debug("Creating: ", s)
+	network, addr := "unix", s.Addr
+	if s.Port != "" {
+		network, addr = "tcp", net.JoinHostPort(s.Addr, s.Port)
+	}
+	c, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	f, err := scamper.Attach(c, s.Format)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	sock, err := scamper.NewSocketFormat(localSocket, c, f)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	cl := scamper.NewClient()
+	cl.AddSocket(sock)
+	return &localmt{
+		s:    s,
+		cl:   cl,
+		sock: sock,
+		r:    r,
+	}, nil
+}
+
+// run runs the measurement arg and waits for its result
+func (l *localmt) run(ctx con.Context, arg interface{}, timeout int64) (interface{}, error) {
+	if timeout == 0 {
+		timeout = localTimeout
+	}
+	resp, id, err := l.cl.DoMeasurement(l.sock.IP(), arg)
+	if err == scamper.ErrSocketClosed {
+		l.r.cache.failedTool(l.s, l)
+	}
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case r, ok := <-resp:
+		if !ok {
+			return nil, scamper.ErrSocketClosed
+		}
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Ret, nil
+	case <-time.After(time.Second * time.Duration(timeout)):
+		l.cl.RemoveMeasurement(l.sock.IP(), id)
+		return nil, scamper.ErrorTimeout
+	case <-ctx.Done():
+		l.cl.RemoveMeasurement(l.sock.IP(), id)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *localmt) Ping(ctx con.Context, pa *dm.PingArg) (<-chan *dm.Ping, error) {
+	ret := make(chan *dm.Ping, len(pa.Pings))
+	var wg sync.WaitGroup
+	for _, pm := range pa.Pings {
+		wg.Add(1)
+		go func(pm *dm.PingMeasurement) {
+			defer wg.Done()
+			res, err := l.run(ctx, pm, pm.Timeout)
+			var p dm.Ping
+			switch r := res.(type) {
+			case warts.Ping:
+				p = dm.ConvertPing(r)
+			case dm.Ping:
+				p = r
+			default:
+				if err == nil {
+					err = fmt.Errorf("Wrong type in ping response")
+				}
+				p = dm.Ping{
+					Src:   pm.Src,
+					Dst:   pm.Dst,
+					Error: err.Error(),
+				}
+			}
+			ret <- &p
+		}(pm)
+	}
+	go func() {
+		wg.Wait()
+		close(ret)
+	}()
+	return ret, nil
+}
+
+func (l *localmt) Traceroute(ctx con.Context, ta *dm.TracerouteArg) (<-chan *dm.Traceroute, error) {
+	ret := make(chan *dm.Traceroute, len(ta.Traceroutes))
+	var wg sync.WaitGroup
+	for _, tm := range ta.Traceroutes {
+		wg.Add(1)
+		go func(tm *dm.TracerouteMeasurement) {
+			defer wg.Done()
+			res, err := l.run(ctx, tm, tm.Timeout)
+			var t dm.Traceroute
+			switch r := res.(type) {
+			case warts.Traceroute:
+				t = dm.ConvertTraceroute(r)
+			case dm.Traceroute:
+				t = r
+			default:
+				if err == nil {
+					err = fmt.Errorf("Wrong type in traceroute response")
+				}
+				t = dm.Traceroute{
+					Src:   tm.Src,
+					Dst:   tm.Dst,
+					Error: err.Error(),
+				}
+			}
+			ret <- &t
+		}(tm)
+	}
+	go func() {
+		wg.Wait()
+		close(ret)
+	}()
+	return ret, nil
+}
+
+func (l *localmt) Tracelb(ctx con.Context, ta *dm.TracelbArg) (<-chan *dm.Tracelb, error) {
+	ret := make(chan *dm.Tracelb, len(ta.Tracelbs))
+	var wg sync.WaitGroup
+	for _, tm := range ta.Tracelbs {
+		wg.Add(1)
+		go func(tm *dm.TracelbMeasurement) {
+			defer wg.Done()
+			res, err := l.run(ctx, tm, tm.Timeout)
+			var t dm.Tracelb
+			if wt, ok := res.(warts.Tracelb); ok {
+				t = dm.ConvertTracelb(wt)
+			} else {
+				if err == nil {
+					err = fmt.Errorf("Wrong type in tracelb response")
+				}
+				t = dm.Tracelb{
+					Src:   tm.Src,
+					Dst:   tm.Dst,
+					Error: err.Error(),
+				}
+			}
+			ret <- &t
+		}(tm)
+	}
+	go func() {
+		wg.Wait()
+		close(ret)
+	}()
+	return ret, nil
+}
+
+func (l *localmt) Dealias(ctx con.Context, da *dm.DealiasArg) (<-chan *dm.Dealias, error) {
+	ret := make(chan *dm.Dealias, len(da.Dealiases))
+	var wg sync.WaitGroup
+	for _, m := range da.Dealiases {
+		wg.Add(1)
+		go func(m *dm.DealiasMeasurement) {
+			defer wg.Done()
+			res, err := l.run(ctx, m, m.Timeout)
+			var d dm.Dealias
+			if wd, ok := res.(warts.Dealias); ok {
+				d = dm.ConvertDealias(wd)
+			} else {
+				if err == nil {
+					err = fmt.Errorf("Wrong type in dealias response")
+				}
+				d = dm.Dealias{
+					Src:    m.Src,
+					Method: m.Method,
+					Addrs:  m.Addrs,
+					Error:  err.Error(),
+				}
+			}
+			ret <- &d
+		}(m)
+	}
+	go func() {
+		wg.Wait()
+		close(ret)
+	}()
+	return ret, nil
+}
+
+// GetVPs returns the host the local scamper runs on as the only vantage point
+func (l *localmt) GetVPs(ctx con.Context, v *dm.VPRequest) (<-chan *dm.VPReturn, error) {
+	ret := make(chan *dm.VPReturn, 1)
+	vp := &dm.VantagePoint{
+		Timestamp:   true,
+		RecordRoute: true,
+	}
+	vp.Hostname, _ = os.Hostname()
+	vp.Ip = localIP()
+	ret <- &dm.VPReturn{Vps: []*dm.VantagePoint{vp}}
+	close(ret)
+	return ret, nil
+}
+
+// localIP is the address of the interface that would be used for outgoing
+// probes, dialing udp sends no packets
+func localIP() uint32 {
+	c, err := net.Dial("udp", "8.8.8.8:53")
+	if err != nil {
+		return 0
+	}
+	defer c.Close()
+	ip, _ := util.IPtoInt32(c.LocalAddr().(*net.UDPAddr).IP)
+	return ip
+}
+
+// GetVPStatus returns the load on the local scamper socket
+func (l *localmt) GetVPStatus(ctx con.Context, v *dm.VPStatusRequest) (*dm.VPStatusReturn, error) {
+	ip := localIP()
+	if len(v.Vps) > 0 {
+		var found bool
+		for _, vp := range v.Vps {
+			found = found || vp == ip
+		}
+		if !found {
+			return &dm.VPStatusReturn{}, nil
+		}
+	}
+	st := l.sock.Status()
+	return &dm.VPStatusReturn{
+		Statuses: []*dm.VPStatus{
+			&dm.VPStatus{
+				Ip:                ip,
+				Outstanding:       uint32(st.Outstanding),
+				Queued:            uint32(st.Queued),
+				Issued:            st.Issued,
+				Completed:         st.Completed,
+				Errors:            st.Errors,
+				Abandoned:         st.Abandoned,
+				Latency:           int64(st.Latency / time.Microsecond),
+				OldestOutstanding: int64(st.Oldest / time.Microsecond),
+			},
+		},
+	}, nil
+}
+
+func (l *localmt) ReceiveSpoof(ctx con.Context, rs *dm.RecSpoof) (<-chan *dm.NotifyRecSpoofResponse, error) {
+	return nil, ErrLocalSpoof
+}
+
+// Check checks that the scamper socket is still open
+func (l *localmt) Check(ctx con.Context) error {
+	select {
+	case <-l.sock.Done():
+		return scamper.ErrSocketClosed
+	default:
+		return nil
+	}
+}
+
+func (l *localmt) Close() error {
+	log.Debug("Closing: ", l.s)
+	l.sock.Stop()
+	return nil
+}
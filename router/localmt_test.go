@@ -0,0 +1,109 @@
+package router_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/router"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper/scampertest"
+	con "golang.org/x/net/context"
+)
+
+func startScamper(t *testing.T) (string, func()) {
+	files, err := filepath.Glob("../doc/*.warts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := scampertest.New(files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "localmt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "scamper")
+	if err := s.Listen("unix", path); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLocalMT(t *testing.T) {
+	testLocalMT(t, scamper.Warts)
+}
+
+func TestLocalMTJSONFallback(t *testing.T) {
+	// The test scamper has no JSON output, so the attach falls back to warts
+	testLocalMT(t, scamper.JSON)
+}
+
+func testLocalMT(t *testing.T, f scamper.Format) {
+	path, stop := startScamper(t)
+	defer stop()
+	r := router.New("")
+	r.SetSource(router.NewLocalSource(path, "", f))
+	sd, err := r.GetService("10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sd.Service != router.Local {
+		t.Fatalf("GetService returned %v, expected a local service", sd)
+	}
+	mt, err := r.GetMT(sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mt.Close()
+	ctx := con.Background()
+	pings, err := mt.Ping(ctx, &dm.PingArg{Pings: []*dm.PingMeasurement{
+		{Src: 1, Dst: 2, Timeout: 5},
+		{Src: 1, Dst: 3, RR: true, Timeout: 5},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for p := range pings {
+		count++
+		if p.Error != "" {
+			t.Errorf("ping failed: %s", p.Error)
+		}
+		if len(p.Responses) == 0 {
+			t.Errorf("ping has no responses: %v", p)
+		}
+	}
+	if count != 2 {
+		t.Fatalf("got %d pings, expected 2", count)
+	}
+	traces, err := mt.Traceroute(ctx, &dm.TracerouteArg{Traceroutes: []*dm.TracerouteMeasurement{
+		{Src: 1, Dst: 2, Timeout: 5},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count = 0
+	for tr := range traces {
+		count++
+		if tr.Error != "" {
+			t.Errorf("traceroute failed: %s", tr.Error)
+		}
+		if len(tr.Hops) == 0 {
+			t.Errorf("traceroute has no hops: %v", tr)
+		}
+	}
+	if count != 1 {
+		t.Fatalf("got %d traceroutes, expected 1", count)
+	}
+	if _, err := mt.ReceiveSpoof(ctx, &dm.RecSpoof{}); err != router.ErrLocalSpoof {
+		t.Fatalf("ReceiveSpoof error %v, expected %v", err, router.ErrLocalSpoof)
+	}
+}
@@ -46,11 +46,12 @@ type plmt struct {
 	r  *router
 }
 
-func (p plmt) ReceiveSpoof(ctx con.Context, rs *dm.RecSpoof) (<-chan *dm.NotifyRecSpoofResponse, error) {
+func (p *plmt) ReceiveSpoof(ctx con.Context, rs *dm.RecSpoof) (<-chan *dm.NotifyRecSpoofResponse, error) {
 	ret := make(chan *dm.NotifyRecSpoofResponse)
 	recsp, err := p.cl.ReceiveSpoof(ctx, rs)
 	if err != nil {
 		log.Error(err)
+		p.r.failed(p.s, p, err)
 		return nil, err
 	}
 	go func() {
@@ -62,6 +63,7 @@ func (p plmt) ReceiveSpoof(ctx con.Context, rs *dm.RecSpoof) (<-chan *dm.NotifyR
 			}
 			if err != nil {
 				log.Error(err)
+				p.r.failed(p.s, p, err)
 				return
 			}
 			select {
@@ -74,11 +76,12 @@ func (p plmt) ReceiveSpoof(ctx con.Context, rs *dm.RecSpoof) (<-chan *dm.NotifyR
 	return ret, nil
 }
 
-func (p plmt) Ping(ctx con.Context, pa *dm.PingArg) (<-chan *dm.Ping, error) {
+func (p *plmt) Ping(ctx con.Context, pa *dm.PingArg) (<-chan *dm.Ping, error) {
 	ret := make(chan *dm.Ping)
 	ps, err := p.cl.Ping(ctx)
 	if err != nil {
 		log.Error(err)
+		p.r.failed(p.s, p, err)
 		return nil, err
 	}
 	defer ps.CloseSend()
@@ -94,6 +97,7 @@ func (p plmt) Ping(ctx con.Context, pa *dm.PingArg) (<-chan *dm.Ping, error) {
 			}
 			if err != nil {
 				log.Error(err)
+				p.r.failed(p.s, p, err)
 				return
 			}
 			select {
@@ -106,10 +110,11 @@ func (p plmt) Ping(ctx con.Context, pa *dm.PingArg) (<-chan *dm.Ping, error) {
 	return ret, nil
 }
 
-func (p plmt) Traceroute(ctx con.Context, t *dm.TracerouteArg) (<-chan *dm.Traceroute, error) {
+func (p *plmt) Traceroute(ctx con.Context, t *dm.TracerouteArg) (<-chan *dm.Traceroute, error) {
 	ret := make(chan *dm.Traceroute)
 	ps, err := p.cl.Traceroute(ctx)
 	if err != nil {
+		p.r.failed(p.s, p, err)
 		return nil, err
 	}
 	defer ps.CloseSend()
@@ -125,6 +130,7 @@ func (p plmt) Traceroute(ctx con.Context, t *dm.TracerouteArg) (<-chan *dm.Trace
 			}
 			if err != nil {
 				log.Error(err)
+				p.r.failed(p.s, p, err)
 				return
 			}
 			select {
@@ -137,11 +143,78 @@ func (p plmt) Traceroute(ctx con.Context, t *dm.TracerouteArg) (<-chan *dm.Trace
 	return ret, nil
 }
 
-func (p plmt) GetVPs(ctx con.Context, v *dm.VPRequest) (<-chan *dm.VPReturn, error) {
+func (p *plmt) Tracelb(ctx con.Context, t *dm.TracelbArg) (<-chan *dm.Tracelb, error) {
+	ret := make(chan *dm.Tracelb)
+	ps, err := p.cl.Tracelb(ctx)
+	if err != nil {
+		p.r.failed(p.s, p, err)
+		return nil, err
+	}
+	defer ps.CloseSend()
+	if err := ps.Send(t); err != nil {
+		log.Error(err)
+	}
+	go func() {
+		defer close(ret)
+		for {
+			in, err := ps.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				log.Error(err)
+				p.r.failed(p.s, p, err)
+				return
+			}
+			select {
+			case ret <- in:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
+
+func (p *plmt) Dealias(ctx con.Context, t *dm.DealiasArg) (<-chan *dm.Dealias, error) {
+	ret := make(chan *dm.Dealias)
+	ps, err := p.cl.Dealias(ctx)
+	if err != nil {
+		p.r.failed(p.s, p, err)
+		return nil, err
+	}
+	defer ps.CloseSend()
+	if err := ps.Send(t); err != nil {
+		log.Error(err)
+	}
+	go func() {
+		defer close(ret)
+		for {
+			in, err := ps.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				log.Error(err)
+				p.r.failed(p.s, p, err)
+				return
+			}
+			select {
+			case ret <- in:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
+
+func (p *plmt) GetVPs(ctx con.Context, v *dm.VPRequest) (<-chan *dm.VPReturn, error) {
 	ret := make(chan *dm.VPReturn)
 	ps, err := p.cl.GetVPs(ctx, v)
 	if err != nil {
 		log.Error(err)
+		p.r.failed(p.s, p, err)
 		return nil, err
 	}
 	go func() {
@@ -153,6 +226,7 @@ func (p plmt) GetVPs(ctx con.Context, v *dm.VPRequest) (<-chan *dm.VPReturn, err
 			}
 			if err != nil {
 				log.Error(err)
+				p.r.failed(p.s, p, err)
 				return
 			}
 			select {
@@ -165,33 +239,48 @@ func (p plmt) GetVPs(ctx con.Context, v *dm.VPRequest) (<-chan *dm.VPReturn, err
 	return ret, nil
 }
 
-func (p plmt) Close() error {
-	log.Debug("Closing: ", p.s)
-	p.r.cache.mu.Lock()
-	defer p.r.cache.mu.Unlock()
-	log.Debug(p.r.cache)
-	mt, ok := p.r.cache.cache[p.s.key()]
-	if !ok {
-		log.Debug("No cache found calling close")
-		return p.c.Close()
+// GetVPStatus returns the load the plcontroller reports for its vps
+func (p *plmt) GetVPStatus(ctx con.Context, v *dm.VPStatusRequest) (*dm.VPStatusReturn, error) {
+	st, err := p.cl.GetVPStatus(ctx, v)
+	if err != nil {
+		log.Error(err)
+		p.r.failed(p.s, p, err)
+		return nil, err
 	}
-	mt.refCount--
-	return nil
+	return st, nil
 }
 
-func createPLMT(s ServiceDef, r *router) (plmt, error) {
+// Check checks that the plcontroller still answers requests
+func (p *plmt) Check(ctx con.Context) error {
+	vps, err := p.cl.GetVPs(ctx, &dm.VPRequest{})
+	if err != nil {
+		return err
+	}
+	_, err = vps.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func (p *plmt) Close() error {
+	log.Debug("Closing: ", p.s)
+	return p.c.Close()
+}
+
+func createPLMT(s ServiceDef, r *router) (*plmt, error) {
 	log.Debug("Creating: ", s)
-	var ret plmt
+	ret := &plmt{}
 	opts := make([]grpc.DialOption, 1)
 	creds, err := credentials.NewClientTLSFromFile(r.caPath, "plcontroller.revtr.ccs.neu.edu")
 	if err != nil {
-		return ret, err
+		return nil, err
 	}
 	opts[0] = grpc.WithTransportCredentials(creds)
 	conn, err := grpc.Dial(fmt.Sprintf("%s:%s", s.Addr, s.Port), opts...)
 	if err != nil {
 		log.Error(err)
-		return ret, err
+		return nil, err
 	}
 	cl := pb.NewPLControllerClient(conn)
 	ret.c = conn
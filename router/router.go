@@ -29,11 +29,14 @@ package router
 
 import (
 	"fmt"
-	"sync"
+	"time"
 
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
 )
 
 type service uint
@@ -41,6 +44,8 @@ type service uint
 const (
 	// PlanetLab is the Planet lab service
 	PlanetLab service = iota + 1
+	// Local is a scamper control socket on the same host
+	Local
 )
 
 var (
@@ -52,7 +57,10 @@ var (
 type MeasurementTool interface {
 	Ping(context.Context, *dm.PingArg) (<-chan *dm.Ping, error)
 	Traceroute(context.Context, *dm.TracerouteArg) (<-chan *dm.Traceroute, error)
+	Tracelb(context.Context, *dm.TracelbArg) (<-chan *dm.Tracelb, error)
+	Dealias(context.Context, *dm.DealiasArg) (<-chan *dm.Dealias, error)
 	GetVPs(context.Context, *dm.VPRequest) (<-chan *dm.VPReturn, error)
+	GetVPStatus(context.Context, *dm.VPStatusRequest) (*dm.VPStatusReturn, error)
 	ReceiveSpoof(context.Context, *dm.RecSpoof) (<-chan *dm.NotifyRecSpoofResponse, error)
 	Close() error
 }
@@ -61,6 +69,8 @@ func (r *router) create(s ServiceDef) (MeasurementTool, error) {
 	switch s.Service {
 	case PlanetLab:
 		return createPLMT(s, r)
+	case Local:
+		return createLocalMT(s, r)
 	}
 	return nil, ErrCantCreateMt
 }
@@ -70,6 +80,8 @@ type ServiceDef struct {
 	Addr    string
 	Port    string
 	Service service
+	// Format is the output a Local scamper is asked for
+	Format scamper.Format
 }
 
 func (sd ServiceDef) key() string {
@@ -100,18 +112,10 @@ type Source interface {
 	All() []ServiceDef
 }
 
-type mtCache struct {
-	mu    sync.Mutex
-	cache map[string]*mtCacheItem
-}
-
-type mtCacheItem struct {
-	mt       MeasurementTool
-	refCount uint32
-}
-
-func (mt *mtCacheItem) String() string {
-	return fmt.Sprintf("%v", *mt)
+// failureReporter is implemented by Sources that need to know
+// when the connection to a service breaks
+type failureReporter interface {
+	Failed(ServiceDef)
 }
 
 // Router is the interface for something that routes srcs to measurement tools
@@ -124,44 +128,87 @@ type Router interface {
 
 type router struct {
 	source Source
-	cache  mtCache
+	cache  *mtCache
 	caPath string
 }
 
+// Option configures a Router
+type Option func(*cacheOptions)
+
+// WithHealthInterval sets how often cached measurement tools are health checked
+// and idle ones are closed
+func WithHealthInterval(d time.Duration) Option {
+	return func(o *cacheOptions) {
+		o.healthInterval = d
+	}
+}
+
+// WithIdleTimeout sets how long an unused measurement tool stays open
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *cacheOptions) {
+		o.idleTimeout = d
+	}
+}
+
+// WithBackoff sets the initial and maximum time to wait before
+// reconnecting to a service that failed
+func WithBackoff(base, max time.Duration) Option {
+	return func(o *cacheOptions) {
+		o.backoffBase = base
+		o.backoffMax = max
+	}
+}
+
 // New creates a new Router
-func New(caPath string) Router {
-	return &router{
-		cache: mtCache{
-			cache: make(map[string]*mtCacheItem),
-		},
+func New(caPath string, opts ...Option) Router {
+	co := cacheOptions{
+		healthInterval: 30 * time.Second,
+		idleTimeout:    5 * time.Minute,
+		backoffBase:    time.Second,
+		backoffMax:     time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&co)
+	}
+	r := &router{
 		source: source{},
 		caPath: caPath,
 	}
+	r.cache = newMTCache(r.create, co)
+	r.cache.onFail = r.reportFailure
+	go r.cache.run(nil)
+	return r
 }
 
 func (r *router) SetSource(s Source) {
 	r.source = s
 }
 
+// GetMT gets the measurement tool for s. The tool is shared,
+// Close releases it for other callers
 func (r *router) GetMT(s ServiceDef) (MeasurementTool, error) {
-	r.cache.mu.Lock()
-	defer r.cache.mu.Unlock()
-	if mt, ok := r.cache.cache[s.key()]; ok {
-		mt.refCount++
-		return mt.mt, nil
-	}
-	mt, err := r.create(s)
-	if err != nil {
-		return nil, err
+	return r.cache.acquire(s)
+}
+
+// failed handles an error from mt, the tool for the service s. If the
+// connection is broken mt is retired so the next request reconnects
+// and the source is told about the failure
+func (r *router) failed(s ServiceDef, mt MeasurementTool, err error) {
+	if grpc.Code(err) != codes.Unavailable {
+		return
 	}
-	nc := &mtCacheItem{
-		mt:       mt,
-		refCount: 1,
+	if !r.cache.failedTool(s, mt) {
+		return
 	}
-	r.cache.cache[s.key()] = nc
-	return mt, nil
+	r.reportFailure(s)
 }
 
+// reportFailure tells the source that the connection to s broke
+func (r *router) reportFailure(s ServiceDef) {
+	if fr, ok := r.source.(failureReporter); ok {
+		fr.Failed(s)
+	}
+}
 func (r *router) GetService(addr string) (ServiceDef, error) {
 	return r.source.Get(addr)
 }
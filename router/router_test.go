@@ -54,7 +54,7 @@ func TestGetMT(t *testing.T) {
 		}
 		mt, err := r.GetMT(sd)
 		if err != test.errmt {
-			t.Fatalf("r.GetMT(%s), Expected[%v], Got[%v]", sd, test.errmt, err)
+			t.Fatalf("r.GetMT(%v), Expected[%v], Got[%v]", sd, test.errmt, err)
 		}
 		if test.close {
 			mt.Close()
@@ -29,11 +29,12 @@
 package scamper
 
 import (
-	"bytes"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net"
 	"strconv"
-	"sync"
+	"strings"
 
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/util"
@@ -41,233 +42,414 @@ import (
 
 // Cmd is a command that can run on scamper
 type Cmd struct {
-	ID          uint32
-	Arg         interface{}
-	userIDCache *string
+	ID  uint32
+	Arg interface{}
 }
 
-var cmdFree = sync.Pool{
-	New: func() interface{} { return new(bytes.Buffer) },
+// IssueCommand write the command to w
+func (c *Cmd) IssueCommand(w io.Writer) error {
+	cmd, err := buildCommand(c.Arg, c.ID)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, cmd.String()+"\n")
+	return err
+}
+
+var (
+	pingMethods    = []string{"icmp-echo", "icmp-time", "tcp-syn", "tcp-ack", "tcp-ack-sport", "udp", "udp-dport"}
+	traceMethods   = []string{"UDP", "TCP", "ICMP", "UDP-paris", "ICMP-paris", "TCP-ack"}
+	tracelbMethods = []string{"udp-dport", "icmp-echo", "udp-sport", "tcp-sport", "tcp-ack-sport"}
+	dealiasMethods = []string{"mercator", "ally", "radargun", "prefixscan", "bump"}
+	dealiasProbes  = []string{"icmp-echo", "tcp-ack", "tcp-ack-sport", "tcp-syn-sport", "udp", "udp-dport"}
+)
+
+// OptionError is returned when a measurement has an option scamper would reject
+type OptionError struct {
+	Cmd    string
+	Option string
+	Value  string
+	Reason string
+}
+
+func (e OptionError) Error() string {
+	return fmt.Sprintf("Invalid %s %s %q: %s", e.Cmd, e.Option, e.Value, e.Reason)
+}
+
+// Command is a validated scamper command line
+type Command struct {
+	Name    string
+	Options []string
+	Targets []string
+}
+
+// String returns the command line without the trailing newline
+func (c Command) String() string {
+	args := make([]string, 0, 1+len(c.Options)+len(c.Targets))
+	args = append(args, c.Name)
+	args = append(args, c.Options...)
+	args = append(args, c.Targets...)
+	return strings.Join(args, " ")
+}
+
+// builder builds a Command, keeping the first invalid option it is given
+type builder struct {
+	cmd Command
+	err error
+}
+
+func newBuilder(name string) *builder {
+	return &builder{cmd: Command{Name: name}}
+}
+
+func (b *builder) fail(option, value, reason string) {
+	if b.err == nil {
+		b.err = OptionError{Cmd: b.cmd.Name, Option: option, Value: value, Reason: reason}
+	}
+}
+
+func (b *builder) flag(f string) {
+	b.cmd.Options = append(b.cmd.Options, f)
+}
+
+func (b *builder) opt(f, v string) {
+	b.cmd.Options = append(b.cmd.Options, f, v)
+}
+
+// uint adds f with v if v is set and a whole number from min to max
+func (b *builder) uint(f, name, v string, min, max uint64) {
+	if v == "" {
+		return
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || n < min || n > max {
+		b.fail(name, v, fmt.Sprintf("must be a whole number from %d to %d", min, max))
+		return
+	}
+	b.opt(f, v)
+}
+
+func contains(choices []string, v string) bool {
+	for _, c := range choices {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+// choice adds f with v if v is set and one of choices. The case of v
+// is ignored when fold is true
+func (b *builder) choice(f, name, v string, fold bool, choices ...string) {
+	if v == "" {
+		return
+	}
+	for _, c := range choices {
+		if v == c || (fold && strings.EqualFold(v, c)) {
+			b.opt(f, v)
+			return
+		}
+	}
+	b.fail(name, v, "must be one of "+strings.Join(choices, ", "))
+}
+
+// hex adds f with v if v is set and at most max hex digits
+func (b *builder) hex(f, name, v string, max int) {
+	if v == "" {
+		return
+	}
+	if _, err := hex.DecodeString(v); err != nil || len(v) > max {
+		b.fail(name, v, fmt.Sprintf("must be an even number of hex digits, at most %d", max))
+		return
+	}
+	b.opt(f, v)
+}
+
+// addr adds f with v if v is set and an IPv4 address
+func (b *builder) addr(f, name, v string) {
+	if v == "" {
+		return
+	}
+	if ip := net.ParseIP(v); ip == nil || ip.To4() == nil {
+		b.fail(name, v, "must be an IPv4 address")
+		return
+	}
+	b.opt(f, v)
+}
+
+// seconds adds f with v if v is set and a positive number of seconds up to max
+func (b *builder) seconds(f, name, v string, max float64) {
+	if v == "" {
+		return
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n <= 0 || n > max {
+		b.fail(name, v, fmt.Sprintf("must be a number of seconds greater than 0 and at most %g", max))
+		return
+	}
+	b.opt(f, v)
+}
+
+// timestamp adds the ping IP timestamp option
+func (b *builder) timestamp(v string) {
+	if v == "" {
+		return
+	}
+	if v == "tsonly" || v == "tsandaddr" {
+		b.opt("-T", v)
+		return
+	}
+	if !strings.HasPrefix(v, "tsprespec=") {
+		b.fail("timestamp", v, "must be tsonly, tsandaddr or tsprespec=<addrs>")
+		return
+	}
+	addrs := strings.Split(strings.TrimPrefix(v, "tsprespec="), ",")
+	if len(addrs) > 4 {
+		b.fail("timestamp", v, "tsprespec takes at most 4 addresses")
+		return
+	}
+	for _, a := range addrs {
+		if ip := net.ParseIP(a); ip == nil || ip.To4() == nil {
+			b.fail("timestamp", v, "tsprespec addresses must be IPv4 addresses")
+			return
+		}
+	}
+	b.opt("-T", v)
+}
+
+// finish adds the user id and targets
+func (b *builder) finish(id uint32, targets ...string) (Command, error) {
+	if b.err != nil {
+		return Command{}, b.err
+	}
+	b.opt("-U", strconv.FormatUint(uint64(id), 10))
+	b.cmd.Targets = targets
+	return b.cmd, nil
 }
 
-func (c Cmd) issuePing(w io.Writer, p *dm.PingMeasurement) error {
-	ips, err := util.Int32ToIPString(p.Dst)
+func dst(cmd string, addr uint32) (string, error) {
+	ip, err := util.Int32ToIPString(addr)
 	if err != nil {
-		return err
+		return "", OptionError{Cmd: cmd, Option: "destination", Value: fmt.Sprint(addr), Reason: err.Error()}
+	}
+	return ip, nil
+}
+
+// PingCommand builds the scamper ping command for p with the user id id
+func PingCommand(p *dm.PingMeasurement, id uint32) (Command, error) {
+	ip, err := dst("ping", p.Dst)
+	if err != nil {
+		return Command{}, err
 	}
-	buf := cmdFree.Get().(*bytes.Buffer)
-	defer cmdFree.Put(buf)
-	buf.Reset()
-	buf.WriteString("ping ")
+	b := newBuilder("ping")
 	if p.RR {
-		buf.WriteString("-R ")
+		if p.TimeStamp != "" {
+			b.fail("timestamp", p.TimeStamp, "can't be combined with record route")
+		}
+		b.flag("-R")
 	}
 	if p.Spoof {
-		buf.WriteString("-O spoof ")
-		buf.WriteString("-S ")
-		buf.WriteString(p.SAddr)
-		buf.WriteByte(' ')
-		buf.WriteString("-F 61681 ")
-		buf.WriteString("-d 62195 ")
-	}
-	if p.Payload != "" {
-		buf.WriteString("-B ")
-		buf.WriteString(p.Payload)
-		buf.WriteByte(' ')
-	}
-	if p.Count != "" {
-		buf.WriteString("-c ")
-		buf.WriteString(p.Count)
-		buf.WriteByte(' ')
-	}
-	if p.IcmpSum != "" {
-		buf.WriteString("-C ")
-		buf.WriteString(p.IcmpSum)
-		buf.WriteByte(' ')
-	}
-	if p.Wait != "" {
-		buf.WriteString("-i ")
-		buf.WriteString(p.Wait)
-		buf.WriteByte(' ')
-	}
-	if p.Ttl != "" {
-		buf.WriteString("-m ")
-		buf.WriteString(p.Ttl)
-		buf.WriteByte(' ')
-	}
-	if p.Mtu != "" {
-		buf.WriteString("-M ")
-		buf.WriteString(p.Mtu)
-		buf.WriteByte(' ')
-	}
-	if p.ReplyCount != "" {
-		buf.WriteString("-o ")
-		buf.WriteString(p.ReplyCount)
-		buf.WriteByte(' ')
-	}
-	if p.Pattern != "" {
-		buf.WriteString("-p ")
-		buf.WriteString(p.Pattern)
-		buf.WriteByte(' ')
-	}
-	if p.Method != "" {
-		buf.WriteString("-P ")
-		buf.WriteString(p.Method)
-		buf.WriteByte(' ')
-	}
-	if p.Size != "" {
-		buf.WriteString("-s ")
-		buf.WriteString(p.Size)
-		buf.WriteByte(' ')
-	}
-	if p.Tos != "" {
-		buf.WriteString("-z ")
-		buf.WriteString(p.Tos)
-		buf.WriteByte(' ')
-	}
-	if p.TimeStamp != "" {
-		buf.WriteString("-T ")
-		buf.WriteString(p.TimeStamp)
-		buf.WriteByte(' ')
-	}
-	c.userIDCache = new(string)
-	*c.userIDCache = p.UserId
-	buf.WriteString("-U ")
-	uid := strconv.FormatUint(uint64(c.ID), 10)
-	buf.WriteString(uid)
-	buf.WriteByte(' ')
-	buf.WriteString(ips)
-	buf.WriteByte('\n')
-	_, err = w.Write(buf.Bytes())
-	return err
+		if p.SAddr == "" {
+			b.fail("spoof address", "", "is required to spoof")
+		}
+		b.opt("-O", "spoof")
+		b.addr("-S", "spoof address", p.SAddr)
+		b.opt("-F", "61681")
+		b.opt("-d", "62195")
+	}
+	b.hex("-B", "payload", p.Payload, 2*1500)
+	b.uint("-c", "count", p.Count, 1, 65535)
+	b.uint("-C", "icmp sum", p.IcmpSum, 0, 65535)
+	b.seconds("-i", "wait", p.Wait, 20)
+	b.uint("-m", "ttl", p.Ttl, 1, 255)
+	b.uint("-M", "mtu", p.Mtu, 68, 65535)
+	b.uint("-o", "reply count", p.ReplyCount, 0, 65535)
+	b.hex("-p", "pattern", p.Pattern, 32)
+	b.choice("-P", "method", p.Method, false, pingMethods...)
+	b.uint("-s", "size", p.Size, 0, 65535)
+	b.uint("-z", "tos", p.Tos, 0, 255)
+	b.timestamp(p.TimeStamp)
+	return b.finish(id, ip)
 }
 
-func (c Cmd) issueTraceroute(w io.Writer, t *dm.TracerouteMeasurement) error {
-	ips, err := util.Int32ToIPString(t.Dst)
+// TraceCommand builds the scamper trace command for t with the user id id
+func TraceCommand(t *dm.TracerouteMeasurement, id uint32) (Command, error) {
+	ip, err := dst("trace", t.Dst)
 	if err != nil {
-		return err
-	}
-	buf := cmdFree.Get().(*bytes.Buffer)
-	defer cmdFree.Put(buf)
-	buf.Reset()
-	buf.WriteString("trace ")
-
-	if t.Confidence != "" {
-		buf.WriteString("-c ")
-		buf.WriteString(t.Confidence)
-		buf.WriteByte(' ')
-	}
-	if t.Dport != "" {
-		buf.WriteString("-d ")
-		buf.WriteString(t.Dport)
-		buf.WriteByte(' ')
-	}
-	if t.FirstHop != "" {
-		buf.WriteString("-f ")
-		buf.WriteString(t.FirstHop)
-		buf.WriteByte(' ')
-	}
-	if t.GapLimit != "" {
-		buf.WriteString("-g ")
-		buf.WriteString(t.GapLimit)
-		buf.WriteByte(' ')
-	}
-	if t.GapAction != "" {
-		buf.WriteString("-G ")
-		buf.WriteString(t.GapAction)
-		buf.WriteByte(' ')
-	}
-	if t.MaxTtl != "" {
-		buf.WriteString("-m ")
-		buf.WriteString(t.MaxTtl)
-		buf.WriteByte(' ')
+		return Command{}, err
+	}
+	b := newBuilder("trace")
+	b.choice("-c", "confidence", t.Confidence, false, "95", "99")
+	b.uint("-d", "dport", t.Dport, 0, 65535)
+	b.uint("-f", "first hop", t.FirstHop, 1, 255)
+	b.uint("-g", "gap limit", t.GapLimit, 1, 255)
+	b.choice("-G", "gap action", t.GapAction, false, "1", "2")
+	b.uint("-m", "max ttl", t.MaxTtl, 1, 255)
+	if t.FirstHop != "" && t.MaxTtl != "" {
+		first, _ := strconv.Atoi(t.FirstHop)
+		max, _ := strconv.Atoi(t.MaxTtl)
+		if first > max {
+			b.fail("first hop", t.FirstHop, "must not be more than the max ttl")
+		}
 	}
 	if t.PathDiscov {
-		buf.WriteString("-M ")
-	}
-	if t.Loops != "" {
-		buf.WriteString("-l ")
-		buf.WriteString(t.Loops)
-		buf.WriteByte(' ')
-	}
-	if t.LoopAction != "" {
-		buf.WriteString("-L ")
-		buf.WriteString(t.LoopAction)
-		buf.WriteByte(' ')
-	}
-	if t.Payload != "" {
-		buf.WriteString("-p ")
-		buf.WriteString(t.Payload)
-		buf.WriteByte(' ')
-	}
-	buf.WriteString("-P ")
-	if t.Method != "" {
-		buf.WriteString(t.Method)
-	} else {
-		buf.WriteString("ICMP-paris")
-	}
-	buf.WriteByte(' ')
-	if t.Attempts != "" {
-		buf.WriteString("-q ")
-		buf.WriteString(t.Attempts)
-		buf.WriteByte(' ')
-	}
-	if t.SendAll {
-		buf.WriteString("-Q ")
+		b.flag("-M")
 	}
-	if t.Sport != "" {
-		buf.WriteString("-s ")
-		buf.WriteString(t.Sport)
-		buf.WriteByte(' ')
+	b.uint("-l", "loops", t.Loops, 0, 255)
+	b.choice("-L", "loop action", t.LoopAction, false, "0", "1")
+	b.hex("-p", "payload", t.Payload, 2*1500)
+	method := t.Method
+	if method == "" {
+		method = "ICMP-paris"
 	}
-	if t.Tos != "" {
-		buf.WriteString("-t ")
-		buf.WriteString(t.Tos)
-		buf.WriteByte(' ')
+	b.choice("-P", "method", method, true, traceMethods...)
+	b.uint("-q", "attempts", t.Attempts, 1, 10)
+	if t.SendAll {
+		b.flag("-Q")
 	}
+	b.uint("-s", "sport", t.Sport, 0, 65535)
+	b.uint("-t", "tos", t.Tos, 0, 255)
 	if t.TimeExceeded {
-		buf.WriteString("-T ")
-	}
-	if t.Wait != "" {
-		buf.WriteString("-w ")
-		buf.WriteString(t.Wait)
-		buf.WriteByte(' ')
-	}
-	if t.WaitProbe != "" {
-		buf.WriteString("-W ")
-		buf.WriteString(t.WaitProbe)
-		buf.WriteByte(' ')
+		b.flag("-T")
 	}
-	if t.GssEntry != "" {
-		buf.WriteString("-z ")
-		buf.WriteString(t.GssEntry)
-		buf.WriteByte(' ')
+	b.uint("-w", "wait", t.Wait, 1, 20)
+	b.uint("-W", "wait probe", t.WaitProbe, 0, 100)
+	b.addr("-z", "gss entry", t.GssEntry)
+	if strings.ContainsAny(t.LssName, " \t\n'") {
+		b.fail("lss name", t.LssName, "must not contain spaces or quotes")
 	}
 	if t.LssName != "" {
-		buf.WriteString("-Z ")
-		buf.WriteString(t.LssName)
-		buf.WriteByte(' ')
-	}
-	c.userIDCache = new(string)
-	*c.userIDCache = t.UserId
-	buf.WriteString("-U ")
-	uid := strconv.FormatUint(uint64(c.ID), 10)
-	buf.WriteString(uid)
-	buf.WriteByte(' ')
-	buf.WriteString(ips)
-	buf.WriteByte('\n')
-	_, err = w.Write(buf.Bytes())
-	return err
+		b.opt("-Z", t.LssName)
+	}
+	return b.finish(id, ip)
 }
 
-// IssueCommand write the command to w
-func (c *Cmd) IssueCommand(w io.Writer) error {
-	switch c.Arg.(type) {
+// TracelbCommand builds the scamper tracelb command for t with the user id id
+func TracelbCommand(t *dm.TracelbMeasurement, id uint32) (Command, error) {
+	ip, err := dst("tracelb", t.Dst)
+	if err != nil {
+		return Command{}, err
+	}
+	b := newBuilder("tracelb")
+	b.choice("-c", "confidence", t.Confidence, false, "95", "99")
+	b.uint("-d", "dport", t.Dport, 0, 65535)
+	b.uint("-f", "first hop", t.FirstHop, 1, 255)
+	b.uint("-g", "gap limit", t.GapLimit, 1, 255)
+	method := t.Method
+	if method == "" {
+		method = "icmp-echo"
+	}
+	b.choice("-P", "method", method, false, tracelbMethods...)
+	b.uint("-q", "attempts", t.Attempts, 1, 10)
+	b.uint("-Q", "max probes", t.MaxProbec, 1, 65535)
+	b.uint("-s", "sport", t.Sport, 0, 65535)
+	b.uint("-t", "tos", t.Tos, 0, 255)
+	b.uint("-w", "wait timeout", t.WaitTimeout, 1, 20)
+	b.uint("-W", "wait probe", t.WaitProbe, 15, 200)
+	return b.finish(id, ip)
+}
+
+// DealiasCommand builds the scamper dealias command for d with the user id id
+func DealiasCommand(d *dm.DealiasMeasurement, id uint32) (Command, error) {
+	method := d.Method
+	if method == "" {
+		method = "ally"
+	}
+	b := newBuilder("dealias")
+	b.choice("-m", "method", method, false, dealiasMethods...)
+	want := map[string]int{"mercator": 1, "ally": 2, "prefixscan": 2, "bump": 2}
+	if n, ok := want[method]; ok && len(d.Addrs) != n {
+		b.fail("addresses", fmt.Sprint(len(d.Addrs)), fmt.Sprintf("%s takes %d", method, n))
+	}
+	if len(d.Addrs) == 0 {
+		b.fail("addresses", "0", "at least one is required")
+	}
+	probe := d.ProbeMethod
+	if probe == "" {
+		probe = "icmp-echo"
+	}
+	if !contains(dealiasProbes, probe) {
+		b.fail("probe method", probe, "must be one of "+strings.Join(dealiasProbes, ", "))
+	}
+	b.opt("-p", "'-P "+probe+"'")
+	b.uint("-q", "attempts", d.Attempts, 1, 10)
+	b.uint("-f", "fudge", d.Fudge, 0, 65535)
+	b.uint("-w", "wait timeout", d.WaitTimeout, 1, 255)
+	b.uint("-W", "wait probe", d.WaitProbe, 1, 65535)
+	if d.Prefix != 0 && (method != "prefixscan" || d.Prefix > 32) {
+		b.fail("prefix", fmt.Sprint(d.Prefix), "must be from 1 to 32 and is only used by prefixscan")
+	}
+	var ips []string
+	for _, addr := range d.Addrs {
+		ip, err := dst("dealias", addr)
+		if err != nil {
+			return Command{}, err
+		}
+		ips = append(ips, ip)
+	}
+	// prefixscan takes the prefix on the last address
+	if method == "prefixscan" && d.Prefix != 0 && len(ips) > 0 {
+		ips[len(ips)-1] += "/" + strconv.FormatUint(uint64(d.Prefix), 10)
+	}
+	return b.finish(id, ips...)
+}
+
+// Sting is a scamper sting measurement of the packet loss to and from
+// Dst. Its results aren't decoded from warts, so it can be built but
+// not run on a Socket. Zero values use the scamper defaults
+type Sting struct {
+	Dst          uint32
+	Count        uint16 // -c, the number of probes
+	Dport        uint16 // -d
+	Sport        uint16 // -s
+	Distribution uint8  // -f, from 1 to 3
+	Hole         uint16 // -H, the size of the hole
+	Inter        uint16 // -i, the ms between probes
+	Mean         uint16 // -m, the mean ms between probes
+}
+
+// StingCommand builds the scamper sting command for s with the user id id
+func StingCommand(s Sting, id uint32) (Command, error) {
+	ip, err := dst("sting", s.Dst)
+	if err != nil {
+		return Command{}, err
+	}
+	b := newBuilder("sting")
+	set := func(f string, v uint16) {
+		if v != 0 {
+			b.opt(f, strconv.FormatUint(uint64(v), 10))
+		}
+	}
+	set("-c", s.Count)
+	set("-d", s.Dport)
+	if s.Distribution > 3 {
+		b.fail("distribution", fmt.Sprint(s.Distribution), "must be from 1 to 3")
+	}
+	set("-f", uint16(s.Distribution))
+	set("-H", s.Hole)
+	set("-i", s.Inter)
+	set("-m", s.Mean)
+	set("-s", s.Sport)
+	return b.finish(id, ip)
+}
+
+// buildCommand builds the command for a measurement argument
+func buildCommand(arg interface{}, id uint32) (Command, error) {
+	switch a := arg.(type) {
 	case *dm.PingMeasurement:
-		return c.issuePing(w, c.Arg.(*dm.PingMeasurement))
+		return PingCommand(a, id)
 	case *dm.TracerouteMeasurement:
-		return c.issueTraceroute(w, c.Arg.(*dm.TracerouteMeasurement))
-	default:
-		return fmt.Errorf("Unknown arg type.")
+		return TraceCommand(a, id)
+	case *dm.TracelbMeasurement:
+		return TracelbCommand(a, id)
+	case *dm.DealiasMeasurement:
+		return DealiasCommand(a, id)
 	}
+	return Command{}, fmt.Errorf("Unknown arg type.")
+}
+
+// Validate checks that arg is a measurement scamper will accept
+func Validate(arg interface{}) error {
+	_, err := buildCommand(arg, 0)
+	return err
 }
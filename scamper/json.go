@@ -0,0 +1,338 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package scamper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+)
+
+// The objects scamper writes when attached with format json.
+// Only the fields the datamodel has a place for are decoded
+
+type jsonTime struct {
+	Sec   int64  `json:"sec"`
+	Usec  int64  `json:"usec"`
+	Ftime string `json:"ftime"`
+}
+
+type jsonObject struct {
+	Type   string `json:"type"`
+	UserID uint32 `json:"userid"`
+}
+
+type jsonTsAndAddr struct {
+	IP string `json:"ip"`
+	Ts uint32 `json:"ts"`
+}
+
+type jsonPingResponse struct {
+	From       string          `json:"from"`
+	Seq        uint32          `json:"seq"`
+	ReplySize  uint32          `json:"reply_size"`
+	ReplyTTL   uint32          `json:"reply_ttl"`
+	ReplyProto string          `json:"reply_proto"`
+	Tx         jsonTime        `json:"tx"`
+	RTT        float64         `json:"rtt"`
+	ProbeIPID  uint32          `json:"probe_ipid"`
+	ReplyIPID  uint32          `json:"reply_ipid"`
+	ICMPType   uint32          `json:"icmp_type"`
+	ICMPCode   uint32          `json:"icmp_code"`
+	RR         []string        `json:"RR"`
+	TsOnly     []uint32        `json:"tsonly"`
+	TsAndAddr  []jsonTsAndAddr `json:"tsandaddr"`
+}
+
+type jsonPingStats struct {
+	Replies int32   `json:"replies"`
+	Loss    float32 `json:"loss"`
+	Min     float32 `json:"min"`
+	Max     float32 `json:"max"`
+	Avg     float32 `json:"avg"`
+	StdDev  float32 `json:"stddev"`
+}
+
+type jsonPing struct {
+	Version    string             `json:"version"`
+	Type       string             `json:"type"`
+	Method     string             `json:"method"`
+	Src        string             `json:"src"`
+	Dst        string             `json:"dst"`
+	Start      jsonTime           `json:"start"`
+	PingSent   uint32             `json:"ping_sent"`
+	ProbeSize  uint32             `json:"probe_size"`
+	UserID     uint32             `json:"userid"`
+	TTL        uint32             `json:"ttl"`
+	Wait       uint32             `json:"wait"`
+	Timeout    uint32             `json:"timeout"`
+	Flags      []string           `json:"flags"`
+	Responses  []jsonPingResponse `json:"responses"`
+	Statistics *jsonPingStats     `json:"statistics"`
+}
+
+type jsonMPLSLabel struct {
+	TTL   uint32 `json:"mpls_ttl"`
+	S     uint32 `json:"mpls_s"`
+	Exp   uint32 `json:"mpls_exp"`
+	Label uint32 `json:"mpls_label"`
+}
+
+type jsonICMPExt struct {
+	ClassNumber uint32          `json:"ie_cn"`
+	TypeNumber  uint32          `json:"ie_ct"`
+	Labels      []jsonMPLSLabel `json:"mpls_labels"`
+}
+
+type jsonHop struct {
+	Addr      string        `json:"addr"`
+	ProbeTTL  uint32        `json:"probe_ttl"`
+	ProbeID   uint32        `json:"probe_id"`
+	ProbeSize uint32        `json:"probe_size"`
+	RTT       float64       `json:"rtt"`
+	ReplyTTL  uint32        `json:"reply_ttl"`
+	ReplyTos  uint32        `json:"reply_tos"`
+	ReplySize uint32        `json:"reply_size"`
+	ReplyIPID uint32        `json:"reply_ipid"`
+	ICMPType  uint32        `json:"icmp_type"`
+	ICMPCode  uint32        `json:"icmp_code"`
+	ICMPQTTL  uint32        `json:"icmp_q_ttl"`
+	ICMPQIPL  uint32        `json:"icmp_q_ipl"`
+	ICMPQTos  uint32        `json:"icmp_q_tos"`
+	ICMPExt   []jsonICMPExt `json:"icmpext"`
+}
+
+type jsonTrace struct {
+	Version    string    `json:"version"`
+	Type       string    `json:"type"`
+	UserID     uint32    `json:"userid"`
+	Method     string    `json:"method"`
+	Src        string    `json:"src"`
+	Dst        string    `json:"dst"`
+	Sport      uint32    `json:"sport"`
+	Dport      uint32    `json:"dport"`
+	StopReason string    `json:"stop_reason"`
+	StopData   uint32    `json:"stop_data"`
+	Start      jsonTime  `json:"start"`
+	HopCount   uint32    `json:"hop_count"`
+	Attempts   uint32    `json:"attempts"`
+	HopLimit   uint32    `json:"hoplimit"`
+	FirstHop   uint32    `json:"firsthop"`
+	Wait       uint32    `json:"wait"`
+	WaitProbe  uint32    `json:"wait_probe"`
+	Tos        uint32    `json:"tos"`
+	ProbeSize  uint32    `json:"probe_size"`
+	GapLimit   uint32    `json:"gaplimit"`
+	Hops       []jsonHop `json:"hops"`
+}
+
+// parseJSON parses a scamper JSON object. Pings and traces are returned as
+// datamodel Pings and Traceroutes, other types return an error along with
+// the user id so the command can still be answered
+func parseJSON(b []byte) (interface{}, uint32, error) {
+	var obj jsonObject
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, 0, err
+	}
+	switch obj.Type {
+	case "ping":
+		var p jsonPing
+		if err := json.Unmarshal(b, &p); err != nil {
+			return nil, obj.UserID, err
+		}
+		ret, err := p.convert()
+		return ret, obj.UserID, err
+	case "trace":
+		var t jsonTrace
+		if err := json.Unmarshal(b, &t); err != nil {
+			return nil, obj.UserID, err
+		}
+		ret, err := t.convert()
+		return ret, obj.UserID, err
+	}
+	return nil, obj.UserID, fmt.Errorf("Unsupported scamper JSON object type: %s", obj.Type)
+}
+
+func jsonAddr(addr string) (uint32, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.To4() == nil {
+		return 0, fmt.Errorf("Unsupported address in scamper JSON: %q", addr)
+	}
+	return util.IPStringToInt32(addr)
+}
+
+// jsonRTT converts an rtt in milliseconds to microseconds
+func jsonRTT(ms float64) int64 {
+	return int64(math.Floor(ms*1000 + 0.5))
+}
+
+func (p jsonPing) convert() (dm.Ping, error) {
+	var err error
+	ret := dm.Ping{
+		Version:   p.Version,
+		Type:      p.Type,
+		Method:    p.Method,
+		Start:     &dm.Time{Sec: p.Start.Sec, Usec: p.Start.Usec},
+		PingSent:  p.PingSent,
+		ProbeSize: p.ProbeSize,
+		UserId:    p.UserID,
+		Ttl:       p.TTL,
+		Wait:      p.Wait,
+		Timeout:   p.Timeout,
+		Flags:     p.Flags,
+	}
+	if ret.Src, err = jsonAddr(p.Src); err != nil {
+		return dm.Ping{}, err
+	}
+	if ret.Dst, err = jsonAddr(p.Dst); err != nil {
+		return dm.Ping{}, err
+	}
+	ret.Responses = make([]*dm.PingResponse, len(p.Responses))
+	for i, r := range p.Responses {
+		rtt := jsonRTT(r.RTT)
+		rx := r.Tx.Sec*1000000 + r.Tx.Usec + rtt
+		rep := &dm.PingResponse{
+			Seq:        r.Seq,
+			ReplySize:  r.ReplySize,
+			ReplyTtl:   r.ReplyTTL,
+			ReplyProto: r.ReplyProto,
+			Tx:         &dm.Time{Sec: r.Tx.Sec, Usec: r.Tx.Usec},
+			Rx:         &dm.Time{Sec: rx / 1000000, Usec: rx % 1000000},
+			Rtt:        uint32(rtt),
+			ProbeIpid:  r.ProbeIPID,
+			ReplyIpid:  r.ReplyIPID,
+			IcmpType:   r.ICMPType,
+			IcmpCode:   r.ICMPCode,
+			Tsonly:     r.TsOnly,
+		}
+		if rep.From, err = jsonAddr(r.From); err != nil {
+			return dm.Ping{}, err
+		}
+		for _, hop := range r.RR {
+			addr, err := jsonAddr(hop)
+			if err != nil {
+				return dm.Ping{}, err
+			}
+			rep.RR = append(rep.RR, addr)
+		}
+		for _, ts := range r.TsAndAddr {
+			addr, err := jsonAddr(ts.IP)
+			if err != nil {
+				return dm.Ping{}, err
+			}
+			rep.Tsandaddr = append(rep.Tsandaddr, &dm.TsAndAddr{Ip: addr, Ts: ts.Ts})
+		}
+		ret.Responses[i] = rep
+	}
+	if s := p.Statistics; s != nil {
+		ret.Statistics = &dm.PingStats{
+			Replies: s.Replies,
+			Loss:    s.Loss,
+			Min:     s.Min,
+			Max:     s.Max,
+			Avg:     s.Avg,
+			Stddev:  s.StdDev,
+		}
+	}
+	return ret, nil
+}
+
+func (t jsonTrace) convert() (dm.Traceroute, error) {
+	var err error
+	ret := dm.Traceroute{
+		Version:    t.Version,
+		Type:       t.Type,
+		UserId:     t.UserID,
+		Method:     t.Method,
+		Sport:      t.Sport,
+		Dport:      t.Dport,
+		StopReason: t.StopReason,
+		StopData:   t.StopData,
+		Start:      &dm.TracerouteTime{Sec: t.Start.Sec, Usec: t.Start.Usec, Ftime: t.Start.Ftime},
+		HopCount:   t.HopCount,
+		Attempts:   t.Attempts,
+		Hoplimit:   t.HopLimit,
+		Firsthop:   t.FirstHop,
+		Wait:       t.Wait,
+		WaitProbe:  t.WaitProbe,
+		Tos:        t.Tos,
+		ProbeSize:  t.ProbeSize,
+		GapLimit:   t.GapLimit,
+	}
+	if ret.Src, err = jsonAddr(t.Src); err != nil {
+		return dm.Traceroute{}, err
+	}
+	if ret.Dst, err = jsonAddr(t.Dst); err != nil {
+		return dm.Traceroute{}, err
+	}
+	ret.Hops = make([]*dm.TracerouteHop, len(t.Hops))
+	for i, h := range t.Hops {
+		rtt := jsonRTT(h.RTT)
+		hop := &dm.TracerouteHop{
+			ProbeTtl:  h.ProbeTTL,
+			ProbeId:   h.ProbeID,
+			ProbeSize: h.ProbeSize,
+			Rtt:       &dm.RTT{Sec: rtt / 1000000, Usec: rtt % 1000000},
+			ReplyTtl:  h.ReplyTTL,
+			ReplyTos:  h.ReplyTos,
+			ReplySize: h.ReplySize,
+			ReplyIpid: h.ReplyIPID,
+			IcmpType:  h.ICMPType,
+			IcmpCode:  h.ICMPCode,
+			IcmpQTtl:  h.ICMPQTTL,
+			IcmpQIpl:  h.ICMPQIPL,
+			IcmpQTos:  h.ICMPQTos,
+		}
+		if hop.Addr, err = jsonAddr(h.Addr); err != nil {
+			return dm.Traceroute{}, err
+		}
+		for _, ext := range h.ICMPExt {
+			hop.IcmpExt = append(hop.IcmpExt, ext.convert())
+		}
+		ret.Hops[i] = hop
+	}
+	return ret, nil
+}
+
+// convert rebuilds the extension data from the MPLS label stack entries
+// scamper decoded, other extensions have no data in the JSON
+func (e jsonICMPExt) convert() *dm.ICMPExtension {
+	ext := &dm.ICMPExtension{
+		ClassNumber: e.ClassNumber,
+		TypeNumber:  e.TypeNumber,
+	}
+	for _, l := range e.Labels {
+		entry := l.Label<<12 | (l.Exp&0x7)<<9 | (l.S&0x1)<<8 | l.TTL&0xff
+		ext.Data = append(ext.Data, byte(entry>>24), byte(entry>>16), byte(entry>>8), byte(entry))
+	}
+	return ext
+}
@@ -0,0 +1,295 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package scamper_test
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/scamper"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+)
+
+// step is one exchange with the scripted scamper. The command read must
+// start with expect, then reply is written back followed by a DATA message
+// for each of data with %d replaced by the command's user id
+type step struct {
+	expect string
+	reply  string
+	data   []string
+}
+
+// scriptedScamper serves one connection on a unix socket following script
+func scriptedScamper(t *testing.T, script []step) (string, <-chan error, func()) {
+	dir, err := ioutil.TempDir("", "scamperjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "192.168.1.2:5000")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer c.Close()
+		r := bufio.NewReader(c)
+		for _, s := range script {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				errc <- err
+				return
+			}
+			if !strings.HasPrefix(line, s.expect) {
+				errc <- fmt.Errorf("Expected %q, got %q", s.expect, line)
+				return
+			}
+			reply := s.reply
+			for _, d := range s.data {
+				if strings.Contains(d, "%d") {
+					d = fmt.Sprintf(d, userID(line))
+				}
+				reply += fmt.Sprintf("DATA %d\n%s\n", len(d)+1, d)
+			}
+			if _, err := c.Write([]byte(reply)); err != nil {
+				errc <- err
+				return
+			}
+		}
+		errc <- nil
+		// Hold the connection open until the test is done
+		r.ReadString('\n')
+	}()
+	return path, errc, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func userID(cmd string) int {
+	fields := strings.Fields(cmd)
+	for i := 0; i < len(fields)-1; i++ {
+		if fields[i] == "-U" {
+			var id int
+			fmt.Sscan(fields[i+1], &id)
+			return id
+		}
+	}
+	return 0
+}
+
+const (
+	jsonCycle   = `{"type":"cycle-start", "list_name":"default", "id":1, "hostname":"vp", "start_time":1441298505}`
+	jsonPing    = `{"version":"0.4", "type":"ping", "method":"icmp-echo", "src":"192.168.1.2", "dst":"8.8.8.8", "start":{"sec":1441298505, "usec":541596}, "ping_sent":2, "probe_size":84, "userid":%d, "ttl":64, "wait":1, "timeout":10, "flags":["v4rr"], "responses":[{"from":"8.8.8.8", "seq":0, "reply_size":84, "reply_ttl":55, "reply_proto":"icmp", "tx":{"sec":1441298505, "usec":941596}, "rtt":12.345, "probe_ipid":1, "reply_ipid":2, "icmp_type":0, "icmp_code":0, "RR":["10.0.0.1", "10.0.0.2"]}], "statistics":{"replies":1, "loss":1, "min":12.345, "avg":12.345, "max":12.345, "stddev":0.000}}`
+	jsonTrace   = `{"type":"trace", "version":"0.1", "userid":%d, "method":"icmp-paris", "src":"192.168.1.2", "dst":"8.8.8.8", "icmp_sum":0, "stop_reason":"COMPLETED", "stop_data":0, "start":{"sec":1441298505, "usec":1, "ftime":"2015-09-03 16:41:45"}, "hop_count":2, "attempts":2, "hoplimit":0, "firsthop":1, "wait":5, "wait_probe":0, "tos":0, "probe_size":44, "hops":[{"addr":"10.0.0.1", "probe_ttl":1, "probe_id":1, "probe_size":44, "rtt":1.5, "reply_ttl":64, "reply_tos":0, "reply_ipid":7, "reply_size":56, "icmp_type":11, "icmp_code":0, "icmp_q_ttl":1, "icmp_q_ipl":44, "icmp_q_tos":0, "icmpext":[{"ie_cn":1, "ie_ct":1, "ie_dl":4, "mpls_labels":[{"mpls_ttl":1, "mpls_s":1, "mpls_exp":0, "mpls_label":16}]}]}, {"addr":"8.8.8.8", "probe_ttl":2, "probe_id":1, "probe_size":44, "rtt":2.25, "reply_ttl":55, "reply_tos":0, "reply_ipid":8, "reply_size":44, "icmp_type":0, "icmp_code":0}]}`
+	jsonTracelb = `{"type":"tracelb", "version":"0.1", "userid":%d, "method":"icmp-echo", "src":"192.168.1.2", "dst":"8.8.8.8"}`
+)
+
+func jsonSocket(t *testing.T, script []step) (*scamper.Socket, <-chan error, func()) {
+	path, errc, stop := scriptedScamper(t, script)
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	f, err := scamper.Attach(c, scamper.JSON)
+	if err != nil || f != scamper.JSON {
+		stop()
+		t.Fatalf("Attach returned %v, %v expected JSON", f, err)
+	}
+	sock, err := scamper.NewSocketFormat(path, c, f)
+	if err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	return sock, errc, func() {
+		sock.Stop()
+		stop()
+	}
+}
+
+func wait(t *testing.T, rc <-chan scamper.Response) scamper.Response {
+	select {
+	case r := <-rc:
+		return r
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for a response")
+	}
+	return scamper.Response{}
+}
+
+func TestSocketJSON(t *testing.T) {
+	defer util.LeakCheck(t)()
+	sock, errc, stop := jsonSocket(t, []step{
+		{expect: "attach format json", reply: "OK\n"},
+		{expect: "ping", reply: "OK id-1\n", data: []string{jsonCycle, jsonPing}},
+		{expect: "trace", reply: "OK id-2\n", data: []string{jsonTrace}},
+	})
+	defer stop()
+	rc, _, err := sock.DoMeasurement(&datamodel.PingMeasurement{Src: 3232235778, Dst: 134744072, RR: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := wait(t, rc)
+	p, ok := r.Ret.(datamodel.Ping)
+	if !ok || r.Err != nil {
+		t.Fatalf("Expected a datamodel.Ping, got %T %v", r.Ret, r.Err)
+	}
+	if p.Src != 3232235778 || p.Dst != 134744072 || len(p.Responses) != 1 || p.Statistics.Replies != 1 {
+		t.Fatalf("Unexpected ping %v", p.String())
+	}
+	resp := p.Responses[0]
+	if resp.Rtt != 12345 || resp.Rx.Sec != 1441298505 || resp.Rx.Usec != 953941 || len(resp.RR) != 2 || resp.RR[1] != 167772162 {
+		t.Fatalf("Unexpected ping response %v", resp.String())
+	}
+	rc, _, err = sock.DoMeasurement(&datamodel.TracerouteMeasurement{Src: 3232235778, Dst: 134744072})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = wait(t, rc)
+	tr, ok := r.Ret.(datamodel.Traceroute)
+	if !ok || r.Err != nil {
+		t.Fatalf("Expected a datamodel.Traceroute, got %T %v", r.Ret, r.Err)
+	}
+	if tr.StopReason != "COMPLETED" || len(tr.Hops) != 2 || tr.Hops[1].Addr != tr.Dst || tr.Hops[0].Rtt.Usec != 1500 {
+		t.Fatalf("Unexpected traceroute %v", tr.String())
+	}
+	ext := tr.Hops[0].IcmpExt
+	if len(ext) != 1 || fmt.Sprint(ext[0].Data) != "[0 1 1 1]" {
+		t.Fatalf("Unexpected ICMP extensions %v", ext)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSocketJSONUnsupported(t *testing.T) {
+	defer util.LeakCheck(t)()
+	sock, errc, stop := jsonSocket(t, []step{
+		{expect: "attach format json", reply: "OK\n"},
+		{expect: "tracelb", reply: "OK id-1\n", data: []string{jsonTracelb}},
+	})
+	defer stop()
+	rc, _, err := sock.DoMeasurement(&datamodel.TracelbMeasurement{Src: 3232235778, Dst: 134744072})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := wait(t, rc); r.Err == nil {
+		t.Fatalf("Expected an error for an unsupported type, got %v", r.Ret)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAttachFallback(t *testing.T) {
+	defer util.LeakCheck(t)()
+	path, errc, stop := scriptedScamper(t, []step{
+		{expect: "attach format json", reply: "ERR command not understood\n"},
+		{expect: "attach\n", reply: "OK\n"},
+	})
+	defer stop()
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	f, err := scamper.Attach(c, scamper.JSON)
+	if err != nil || f != scamper.Warts {
+		t.Fatalf("Attach returned %v, %v expected warts", f, err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegotiateRefused(t *testing.T) {
+	defer util.LeakCheck(t)()
+	// A socket that is already attached only gets the format request
+	path, errc, stop := scriptedScamper(t, []step{
+		{expect: "attach format json", reply: "ERR command not understood\n"},
+	})
+	defer stop()
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	f, err := scamper.Negotiate(c, scamper.JSON)
+	if err != nil || f != scamper.Warts {
+		t.Fatalf("Negotiate returned %v, %v expected warts", f, err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSocketStatus(t *testing.T) {
+	defer util.LeakCheck(t)()
+	sock, errc, stop := jsonSocket(t, []step{
+		{expect: "attach format json", reply: "OK\n"},
+		{expect: "ping", reply: "OK id-1\n", data: []string{jsonPing}},
+		{expect: "ping", reply: "OK id-2\n"},
+	})
+	defer stop()
+	rc, _, err := sock.DoMeasurement(&datamodel.PingMeasurement{Src: 3232235778, Dst: 134744072})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(t, rc)
+	_, id, err := sock.DoMeasurement(&datamodel.PingMeasurement{Src: 3232235778, Dst: 134744072})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	st := sock.Status()
+	if st.IP != "192.168.1.2" || st.Issued != 2 || st.Completed != 1 || st.Outstanding != 1 || st.Latency <= 0 || st.Oldest <= 0 {
+		t.Fatalf("Unexpected status %+v", st)
+	}
+	sock.RemoveMeasurement(id)
+	st = sock.Status()
+	if st.Outstanding != 0 || st.Abandoned != 1 || st.Oldest != 0 {
+		t.Fatalf("Unexpected status after remove %+v", st)
+	}
+}
@@ -146,3 +146,246 @@ func TestCmd(t *testing.T) {
 	c.IssueCommand(res)
 	t.Log(res.String())
 }
+
+func TestCmdTracelb(t *testing.T) {
+	tm := &datamodel.TracelbMeasurement{
+		Src:        1111111111,
+		Dst:        16843009,
+		Confidence: "99",
+		Method:     "udp-dport",
+	}
+	c := scamper.Cmd{ID: 5, Arg: tm}
+	res := &bytes.Buffer{}
+	if err := c.IssueCommand(res); err != nil {
+		t.Fatal(err)
+	}
+	expected := "tracelb -c 99 -P udp-dport -U 5 1.1.1.1\n"
+	if res.String() != expected {
+		t.Fatalf("TestCmdTracelb, Expected[%q], Got[%q]", expected, res.String())
+	}
+}
+
+func TestCmdDealias(t *testing.T) {
+	for _, test := range []struct {
+		desc     string
+		dm       *datamodel.DealiasMeasurement
+		expected string
+	}{
+		{
+			desc: "ally",
+			dm: &datamodel.DealiasMeasurement{
+				Addrs:     []uint32{16843009, 16843010},
+				WaitProbe: "1000",
+			},
+			expected: "dealias -m ally -p '-P icmp-echo' -W 1000 -U 5 1.1.1.1 1.1.1.2\n",
+		},
+		{
+			desc: "prefixscan",
+			dm: &datamodel.DealiasMeasurement{
+				Method:      "prefixscan",
+				ProbeMethod: "udp",
+				Addrs:       []uint32{16843009, 33686016},
+				Prefix:      30,
+			},
+			expected: "dealias -m prefixscan -p '-P udp' -U 5 1.1.1.1 2.2.2.0/30\n",
+		},
+	} {
+		c := scamper.Cmd{ID: 5, Arg: test.dm}
+		res := &bytes.Buffer{}
+		if err := c.IssueCommand(res); err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		if res.String() != test.expected {
+			t.Fatalf("TestCmdDealias %s, Expected[%q], Got[%q]", test.desc, test.expected, res.String())
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	for _, test := range []struct {
+		desc     string
+		arg      interface{}
+		expected string
+	}{
+		{
+			desc:     "ping defaults",
+			arg:      &datamodel.PingMeasurement{Dst: 16843009},
+			expected: "ping -U 7 1.1.1.1",
+		},
+		{
+			desc: "ping record route",
+			arg: &datamodel.PingMeasurement{
+				Dst:    16843009,
+				RR:     true,
+				Count:  "1",
+				Method: "icmp-echo",
+			},
+			expected: "ping -R -c 1 -P icmp-echo -U 7 1.1.1.1",
+		},
+		{
+			desc: "ping spoofed timestamp",
+			arg: &datamodel.PingMeasurement{
+				Dst:       16843009,
+				Spoof:     true,
+				SAddr:     "2.2.2.2",
+				TimeStamp: "tsprespec=1.1.1.1,3.3.3.3",
+			},
+			expected: "ping -O spoof -S 2.2.2.2 -F 61681 -d 62195 -T tsprespec=1.1.1.1,3.3.3.3 -U 7 1.1.1.1",
+		},
+		{
+			desc: "ping every option",
+			arg: &datamodel.PingMeasurement{
+				Dst:        16843009,
+				Payload:    "0a0b",
+				Count:      "3",
+				IcmpSum:    "420",
+				Wait:       "0.5",
+				Ttl:        "64",
+				Mtu:        "1500",
+				ReplyCount: "2",
+				Pattern:    "ff",
+				Method:     "udp-dport",
+				Size:       "84",
+				Tos:        "8",
+				TimeStamp:  "tsonly",
+			},
+			expected: "ping -B 0a0b -c 3 -C 420 -i 0.5 -m 64 -M 1500 -o 2 -p ff -P udp-dport -s 84 -z 8 -T tsonly -U 7 1.1.1.1",
+		},
+		{
+			desc:     "trace defaults",
+			arg:      &datamodel.TracerouteMeasurement{Dst: 16843009},
+			expected: "trace -P ICMP-paris -U 7 1.1.1.1",
+		},
+		{
+			desc: "trace every option",
+			arg: &datamodel.TracerouteMeasurement{
+				Dst:          16843009,
+				Confidence:   "95",
+				Dport:        "33435",
+				FirstHop:     "2",
+				GapLimit:     "5",
+				GapAction:    "2",
+				MaxTtl:       "30",
+				PathDiscov:   true,
+				Loops:        "3",
+				LoopAction:   "1",
+				Payload:      "00",
+				Method:       "udp-paris",
+				Attempts:     "2",
+				SendAll:      true,
+				Sport:        "1234",
+				Tos:          "4",
+				TimeExceeded: true,
+				Wait:         "2",
+				WaitProbe:    "10",
+				GssEntry:     "3.3.3.3",
+				LssName:      "lss",
+			},
+			expected: "trace -c 95 -d 33435 -f 2 -g 5 -G 2 -m 30 -M -l 3 -L 1 -p 00 -P udp-paris -q 2 -Q -s 1234 -t 4 -T -w 2 -W 10 -z 3.3.3.3 -Z lss -U 7 1.1.1.1",
+		},
+		{
+			desc: "tracelb",
+			arg: &datamodel.TracelbMeasurement{
+				Dst:         16843009,
+				Attempts:    "2",
+				MaxProbec:   "3000",
+				WaitTimeout: "5",
+				WaitProbe:   "25",
+			},
+			expected: "tracelb -P icmp-echo -q 2 -Q 3000 -w 5 -W 25 -U 7 1.1.1.1",
+		},
+		{
+			desc: "dealias mercator",
+			arg: &datamodel.DealiasMeasurement{
+				Method:      "mercator",
+				ProbeMethod: "udp",
+				Addrs:       []uint32{16843009},
+				Attempts:    "3",
+			},
+			expected: "dealias -m mercator -p '-P udp' -q 3 -U 7 1.1.1.1",
+		},
+	} {
+		c := scamper.Cmd{ID: 7, Arg: test.arg}
+		res := &bytes.Buffer{}
+		if err := c.IssueCommand(res); err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		if res.String() != test.expected+"\n" {
+			t.Fatalf("TestCommands %s, Expected[%q], Got[%q]", test.desc, test.expected+"\n", res.String())
+		}
+	}
+}
+
+func TestStingCommand(t *testing.T) {
+	cmd, err := scamper.StingCommand(scamper.Sting{Dst: 16843009, Count: 48, Dport: 80, Distribution: 2, Mean: 100}, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "sting -c 48 -d 80 -f 2 -m 100 -U 7 1.1.1.1"; cmd.String() != expected {
+		t.Fatalf("TestStingCommand, Expected[%q], Got[%q]", expected, cmd.String())
+	}
+	if _, err := scamper.StingCommand(scamper.Sting{Dst: 16843009, Distribution: 4}, 7); err == nil {
+		t.Fatal("Expected an error for a bad distribution")
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc   string
+		arg    interface{}
+		option string
+	}{
+		{"ping count", &datamodel.PingMeasurement{Dst: 16843009, Count: "many"}, "count"},
+		{"ping zero count", &datamodel.PingMeasurement{Dst: 16843009, Count: "0"}, "count"},
+		{"ping ttl", &datamodel.PingMeasurement{Dst: 16843009, Ttl: "256"}, "ttl"},
+		{"ping wait", &datamodel.PingMeasurement{Dst: 16843009, Wait: "-1"}, "wait"},
+		{"ping method", &datamodel.PingMeasurement{Dst: 16843009, Method: "icmp"}, "method"},
+		{"ping pattern", &datamodel.PingMeasurement{Dst: 16843009, Pattern: "xyz"}, "pattern"},
+		{"ping spoof without address", &datamodel.PingMeasurement{Dst: 16843009, Spoof: true}, "spoof address"},
+		{"ping spoof address", &datamodel.PingMeasurement{Dst: 16843009, Spoof: true, SAddr: "nope"}, "spoof address"},
+		{"ping rr and ts", &datamodel.PingMeasurement{Dst: 16843009, RR: true, TimeStamp: "tsonly"}, "timestamp"},
+		{"ping tsprespec", &datamodel.PingMeasurement{Dst: 16843009, TimeStamp: "tsprespec=1.1.1.1,1.1.1.2,1.1.1.3,1.1.1.4,1.1.1.5"}, "timestamp"},
+		{"ping timestamp", &datamodel.PingMeasurement{Dst: 16843009, TimeStamp: "ts"}, "timestamp"},
+		{"trace method", &datamodel.TracerouteMeasurement{Dst: 16843009, Method: "paris"}, "method"},
+		{"trace confidence", &datamodel.TracerouteMeasurement{Dst: 16843009, Confidence: "90"}, "confidence"},
+		{"trace first hop", &datamodel.TracerouteMeasurement{Dst: 16843009, FirstHop: "10", MaxTtl: "5"}, "first hop"},
+		{"trace attempts", &datamodel.TracerouteMeasurement{Dst: 16843009, Attempts: "11"}, "attempts"},
+		{"trace lss name", &datamodel.TracerouteMeasurement{Dst: 16843009, LssName: "a b"}, "lss name"},
+		{"tracelb method", &datamodel.TracelbMeasurement{Dst: 16843009, Method: "udp"}, "method"},
+		{"tracelb wait probe", &datamodel.TracelbMeasurement{Dst: 16843009, WaitProbe: "5"}, "wait probe"},
+		{"dealias method", &datamodel.DealiasMeasurement{Method: "guess", Addrs: []uint32{1}}, "method"},
+		{"dealias addresses", &datamodel.DealiasMeasurement{Addrs: []uint32{1}}, "addresses"},
+		{"dealias no addresses", &datamodel.DealiasMeasurement{Method: "radargun"}, "addresses"},
+		{"dealias probe", &datamodel.DealiasMeasurement{ProbeMethod: "icmp", Addrs: []uint32{1, 2}}, "probe method"},
+		{"dealias prefix", &datamodel.DealiasMeasurement{Addrs: []uint32{1, 2}, Prefix: 24}, "prefix"},
+	} {
+		err := scamper.Validate(test.arg)
+		oerr, ok := err.(scamper.OptionError)
+		if !ok {
+			t.Fatalf("%s: Expected an OptionError, got %v", test.desc, err)
+		}
+		if oerr.Option != test.option {
+			t.Fatalf("%s: Expected an error for %s, got %v", test.desc, test.option, err)
+		}
+	}
+}
+
+func TestDoMeasurementInvalid(t *testing.T) {
+	defer util.LeakCheck(t)()
+	s := internal.NewServer(sockPath, nil)
+	s.Start()
+	defer s.Stop()
+	c, err := net.Dial("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Failed to Dial socket: %v", err)
+	}
+	sock, err := scamper.NewSocket(sockPath, c)
+	if err != nil {
+		t.Fatalf("Failed to create a socket: %v", err)
+	}
+	defer sock.Stop()
+	_, _, err = sock.DoMeasurement(&datamodel.PingMeasurement{Dst: 16843009, Count: "lots"})
+	if _, ok := err.(scamper.OptionError); !ok {
+		t.Fatalf("Expected an OptionError, got %v", err)
+	}
+}
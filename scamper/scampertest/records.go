@@ -0,0 +1,186 @@
+package scampertest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+const (
+	wartsMagic  = 0x1205
+	headerLen   = 8
+	pingUserID  = 19
+	traceUserID = 28
+)
+
+type record struct {
+	t    warts.WartsT
+	data []byte
+}
+
+// splitRecords splits warts data into its records
+func splitRecords(data []byte) ([]record, error) {
+	var ret []record
+	for off := 0; off < len(data); {
+		if len(data)-off < headerLen {
+			return nil, fmt.Errorf("Short warts header at offset %d", off)
+		}
+		head := data[off : off+headerLen]
+		if binary.BigEndian.Uint16(head) != wartsMagic {
+			return nil, fmt.Errorf("Bad warts magic at offset %d", off)
+		}
+		t := warts.WartsT(binary.BigEndian.Uint16(head[2:]))
+		end := off + headerLen + int(binary.BigEndian.Uint32(head[4:]))
+		if end > len(data) {
+			return nil, fmt.Errorf("Warts record at offset %d overruns the data", off)
+		}
+		ret = append(ret, record{t: t, data: data[off:end]})
+		off = end
+	}
+	return ret, nil
+}
+
+// readRecords reads the records from the warts file fname
+func readRecords(fname string) ([]record, error) {
+	data, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := splitRecords(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fname, err)
+	}
+	return recs, nil
+}
+
+// paramSize returns the size of the parameter for flag in a record of type t
+// that starts at p
+func paramSize(t warts.WartsT, flag int, p []byte, dataLen int) (int, error) {
+	addr := func() (int, error) {
+		if len(p) < 1 {
+			return 0, fmt.Errorf("Short address")
+		}
+		if p[0] == 0 {
+			return 5, nil
+		}
+		return 2 + int(p[0]), nil
+	}
+	switch t {
+	case warts.PingT:
+		switch flag {
+		case 1, 2, 3, 4, 19:
+			return 4, nil
+		case 5:
+			return 8, nil
+		case 6, 7, 12, 13, 16:
+			return 1, nil
+		case 8, 10, 11, 14, 15, 17, 18:
+			return 2, nil
+		case 9:
+			return dataLen, nil
+		case 20, 21:
+			return addr()
+		}
+	case warts.TracerouteT:
+		switch flag {
+		case 1, 2, 3, 4, 28:
+			return 4, nil
+		case 5:
+			return 8, nil
+		case 6, 7, 8, 9, 10, 11, 15, 16, 17, 18, 20, 21, 22, 24, 25:
+			return 1, nil
+		case 12, 13, 14, 19, 23:
+			return 2, nil
+		case 26, 27:
+			return addr()
+		}
+	}
+	return 0, fmt.Errorf("Unknown flag %d for warts type %d", flag, t)
+}
+
+// setUserID returns a copy of the ping or traceroute record rec with its
+// user id set to id, adding the parameter if the record does not have one
+func setUserID(rec record, id uint32) ([]byte, error) {
+	target := pingUserID
+	if rec.t == warts.TracerouteT {
+		target = traceUserID
+	} else if rec.t != warts.PingT {
+		return nil, fmt.Errorf("Can't set the user id of warts type %d", rec.t)
+	}
+	body := rec.data[headerLen:]
+	// Read the flag bytes, each holds 7 flags and the high bit marks another byte
+	var flagBytes []byte
+	for i := 0; ; i++ {
+		if i >= len(body) {
+			return nil, fmt.Errorf("Short flags")
+		}
+		flagBytes = append(flagBytes, body[i])
+		if body[i]&0x80 == 0 {
+			break
+		}
+	}
+	isSet := func(flag int) bool {
+		i := (flag - 1) / 7
+		return i < len(flagBytes) && flagBytes[i]&(1<<uint((flag-1)%7)) != 0
+	}
+	hasParams := false
+	for _, b := range flagBytes {
+		if b&0x7f != 0 {
+			hasParams = true
+		}
+	}
+	paramStart := len(flagBytes)
+	if hasParams {
+		paramStart += 2
+	}
+	params := body[paramStart:]
+	// Find where the user id is or would be
+	off := 0
+	dataLen := 0
+	for flag := 1; flag < target; flag++ {
+		if !isSet(flag) {
+			continue
+		}
+		n, err := paramSize(rec.t, flag, params[off:], dataLen)
+		if err != nil {
+			return nil, err
+		}
+		if rec.t == warts.PingT && flag == 8 {
+			dataLen = int(binary.BigEndian.Uint16(params[off:]))
+		}
+		off += n
+		if off > len(params) {
+			return nil, fmt.Errorf("Parameters overrun the record")
+		}
+	}
+	var uid [4]byte
+	binary.BigEndian.PutUint32(uid[:], id)
+	if isSet(target) {
+		ret := append([]byte(nil), rec.data...)
+		copy(ret[headerLen+paramStart+off:], uid[:])
+		return ret, nil
+	}
+	// Set the flag, growing the flag bytes if needed
+	i := (target - 1) / 7
+	for len(flagBytes) <= i {
+		flagBytes[len(flagBytes)-1] |= 0x80
+		flagBytes = append(flagBytes, 0)
+	}
+	flagBytes[i] |= 1 << uint((target-1)%7)
+	var plen uint16
+	if hasParams {
+		plen = binary.BigEndian.Uint16(body[paramStart-2:])
+	}
+	plen += 4
+	var out []byte
+	out = append(out, rec.data[:headerLen]...)
+	out = append(out, flagBytes...)
+	out = append(out, byte(plen>>8), byte(plen))
+	out = append(out, params[:off]...)
+	out = append(out, uid[:]...)
+	out = append(out, body[paramStart+off:]...)
+	binary.BigEndian.PutUint32(out[4:], uint32(len(out)-headerLen))
+	return out, nil
+}
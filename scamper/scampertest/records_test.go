@@ -0,0 +1,66 @@
+package scampertest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+func TestSetUserID(t *testing.T) {
+	files, err := filepath.Glob("../../doc/*.warts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("No warts files found")
+	}
+	for _, f := range files {
+		recs, err := readRecords(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var header []byte
+		for _, r := range recs {
+			switch r.t {
+			case warts.ListT, warts.CycleStartT:
+				header = append(header, r.data...)
+				continue
+			case warts.PingT, warts.TracerouteT:
+			default:
+				continue
+			}
+			for _, id := range []uint32{0, 1, 4294967295} {
+				data, err := setUserID(r, id)
+				if err != nil {
+					t.Fatalf("%s: setUserID(%d): %v", f, id, err)
+				}
+				res, err := warts.Parse(append(append([]byte(nil), header...), data...), []warts.WartsT{r.t})
+				if err != nil {
+					t.Fatalf("%s: parsing record with user id %d: %v", f, id, err)
+				}
+				if len(res) != 1 {
+					t.Fatalf("%s: got %d records, expected 1", f, len(res))
+				}
+				var got uint32
+				switch p := res[0].(type) {
+				case warts.Ping:
+					got = p.Flags.UserID
+				case warts.Traceroute:
+					got = p.Flags.UserID
+				}
+				if got != id {
+					t.Fatalf("%s: user id %d, expected %d", f, got, id)
+				}
+				// Setting it again must overwrite rather than add
+				again, err := setUserID(record{t: r.t, data: data}, id+1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(again) != len(data) {
+					t.Fatalf("%s: setting a present user id changed the length", f)
+				}
+			}
+		}
+	}
+}
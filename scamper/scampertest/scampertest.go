@@ -0,0 +1,219 @@
+// Package scampertest provides a stand-in for a scamper control socket that
+// answers measurements with canned warts records
+package scampertest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NEU-SNS/ReverseTraceroute/uuencode"
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+// Server speaks the scamper control socket protocol. Each ping or trace
+// command is answered with the next canned record of that type with its
+// user id set to the one in the command
+type Server struct {
+	header [][]byte
+	recs   map[warts.WartsT][]record
+	mu     sync.Mutex
+	next   map[warts.WartsT]int
+	l      net.Listener
+	conns  []net.Conn
+	wg     sync.WaitGroup
+}
+
+// New creates a Server with the ping and traceroute records in the warts files.
+// The list and cycle records of the first file are used as the warts header
+func New(files ...string) (*Server, error) {
+	s := &Server{
+		recs: make(map[warts.WartsT][]record),
+		next: make(map[warts.WartsT]int),
+	}
+	for _, f := range files {
+		recs, err := readRecords(f)
+		if err != nil {
+			return nil, err
+		}
+		var header [][]byte
+		for _, r := range recs {
+			switch r.t {
+			case warts.ListT, warts.CycleStartT:
+				header = append(header, r.data)
+			case warts.PingT, warts.TracerouteT:
+				s.recs[r.t] = append(s.recs[r.t], r)
+			}
+		}
+		if s.header == nil && len(header) == 2 {
+			s.header = header
+		}
+	}
+	if s.header == nil {
+		return nil, fmt.Errorf("No list and cycle records found")
+	}
+	return s, nil
+}
+
+// Listen starts serving connections on the given network and address
+func (s *Server) Listen(network, addr string) error {
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	s.l = l
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.ServeConn(c)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr is the address the server is listening on
+func (s *Server) Addr() net.Addr {
+	return s.l.Addr()
+}
+
+// Close stops the server and closes its connections
+func (s *Server) Close() error {
+	var err error
+	if s.l != nil {
+		err = s.l.Close()
+		if s.l.Addr().Network() == "unix" {
+			os.Remove(s.l.Addr().String())
+		}
+	}
+	s.mu.Lock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func writeData(w io.Writer, data []byte) error {
+	enc, err := uuencode.UUEncode(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "DATA %d\n", len(enc)); err != nil {
+		return err
+	}
+	_, err = w.Write(enc)
+	return err
+}
+
+func (s *Server) nextRecord(t warts.WartsT) (record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recs := s.recs[t]
+	if len(recs) == 0 {
+		return record{}, false
+	}
+	r := recs[s.next[t]%len(recs)]
+	s.next[t]++
+	return r, true
+}
+
+// answer builds the record that answers the command in fields
+func (s *Server) answer(fields []string) ([]byte, error) {
+	var t warts.WartsT
+	switch fields[0] {
+	case "ping":
+		t = warts.PingT
+	case "trace":
+		t = warts.TracerouteT
+	default:
+		return nil, fmt.Errorf("command not supported")
+	}
+	var uid uint64
+	for i := 1; i < len(fields)-1; i++ {
+		if fields[i] == "-U" {
+			var err error
+			uid, err = strconv.ParseUint(fields[i+1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("bad user id")
+			}
+		}
+	}
+	r, ok := s.nextRecord(t)
+	if !ok {
+		return nil, fmt.Errorf("no canned %s", fields[0])
+	}
+	return setUserID(r, uint32(uid))
+}
+
+// ServeConn answers the commands written to c until it is closed
+func (s *Server) ServeConn(c net.Conn) {
+	s.mu.Lock()
+	s.conns = append(s.conns, c)
+	s.mu.Unlock()
+	defer c.Close()
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	var sentHeader bool
+	sendHeader := func() error {
+		if sentHeader {
+			return nil
+		}
+		sentHeader = true
+		for _, h := range s.header {
+			if err := writeData(w, h); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var cmds int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "attach" && len(fields) > 1 {
+			// Like scampers before JSON output
+			fmt.Fprint(w, "ERR command not understood\n")
+		} else if fields[0] == "attach" {
+			fmt.Fprint(w, "OK\n")
+			err = sendHeader()
+		} else {
+			cmds++
+			rec, aerr := s.answer(fields)
+			if aerr != nil {
+				fmt.Fprintf(w, "ERR %v\n", aerr)
+			} else {
+				fmt.Fprintf(w, "OK id-%d\n", cmds)
+				if err = sendHeader(); err == nil {
+					err = writeData(w, rec)
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
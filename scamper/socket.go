@@ -30,6 +30,7 @@ package scamper
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"net"
@@ -90,6 +91,7 @@ type cmdMap struct {
 type cmdResponse struct {
 	cmd  Cmd
 	done chan Response
+	sent time.Time
 }
 
 func (cm *cmdMap) forEach() <-chan cmdResponse {
@@ -115,14 +117,15 @@ func (cm *cmdMap) getCmd(id uint32) (cmdResponse, error) {
 	return cmdResponse{}, ErrorCmdNotFound
 }
 
-func (cm *cmdMap) rmCmd(id uint32) {
+func (cm *cmdMap) rmCmd(id uint32) bool {
 	cm.Lock()
 	defer cm.Unlock()
 	if _, ok := cm.cmds[id]; ok {
 		delete(cm.cmds, id)
-		return
+		return true
 	}
 	log.Errorf("rmdCmd no cmd with id: %d", id)
+	return false
 }
 
 func (cm *cmdMap) addCmd(c cmdResponse) error {
@@ -135,13 +138,180 @@ func (cm *cmdMap) addCmd(c cmdResponse) error {
 	return nil
 }
 
+// outstanding returns the number of commands waiting for a result and
+// the time the oldest of them was sent
+func (cm *cmdMap) outstanding() (int, time.Time) {
+	cm.Lock()
+	defer cm.Unlock()
+	var oldest time.Time
+	for _, c := range cm.cmds {
+		if oldest.IsZero() || c.sent.Before(oldest) {
+			oldest = c.sent
+		}
+	}
+	return len(cm.cmds), oldest
+}
+
 func newCmdMap() *cmdMap {
 	m := make(map[uint32]cmdResponse)
 	return &cmdMap{cmds: m}
 }
 
+// Format is the format scamper returns results in
+type Format int
+
+const (
+	// Warts is uuencoded warts, which every version of scamper writes
+	Warts Format = iota
+	// JSON is the scamper JSON output of newer versions
+	JSON
+)
+
+func (f Format) String() string {
+	switch f {
+	case Warts:
+		return "warts"
+	case JSON:
+		return "json"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the Format named s, warts or json
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "warts":
+		return Warts, nil
+	case "json":
+		return JSON, nil
+	}
+	return Warts, fmt.Errorf("Unknown scamper format: %s", s)
+}
+
+// readLine reads a line from c a byte at a time so nothing after it is consumed
+func readLine(c io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		if _, err := c.Read(b); err != nil {
+			return "", err
+		}
+		if b[0] == '\n' {
+			return string(line), nil
+		}
+		line = append(line, b[0])
+	}
+}
+
+func attachCmd(c io.ReadWriter, cmd string) (string, error) {
+	if _, err := io.WriteString(c, cmd+"\n"); err != nil {
+		return "", err
+	}
+	return readLine(c)
+}
+
+// Negotiate asks a socket that already returns results over itself, like
+// the sockets sc_remoted makes for vantage points, for results in f.
+// A scamper that refuses JSON keeps writing warts. The format scamper
+// agreed to is returned
+func Negotiate(c io.ReadWriter, f Format) (Format, error) {
+	if f != JSON {
+		return Warts, nil
+	}
+	reply, err := attachCmd(c, "attach format json")
+	if err != nil {
+		return Warts, err
+	}
+	if reply != string(ok) {
+		log.Infof("scamper refused JSON output, using warts: %s", reply)
+		return Warts, nil
+	}
+	return JSON, nil
+}
+
+// Attach puts a scamper control socket into the mode where results are
+// returned over it. When f is JSON it asks for JSON output and falls back
+// to warts if scamper refuses. The format scamper agreed to is returned
+func Attach(c io.ReadWriter, f Format) (Format, error) {
+	if f, err := Negotiate(c, f); err != nil || f == JSON {
+		return f, err
+	}
+	reply, err := attachCmd(c, "attach")
+	if err != nil {
+		return Warts, err
+	}
+	if reply != string(ok) {
+		return Warts, fmt.Errorf("Failed to attach to scamper: %s", reply)
+	}
+	return Warts, nil
+}
+
+// latencyWeight is the weight a new sample has in the latency average
+const latencyWeight = 0.2
+
+type sockStats struct {
+	mu        sync.Mutex
+	issued    uint64
+	completed uint64
+	errors    uint64
+	abandoned uint64
+	latency   time.Duration
+}
+
+func (ss *sockStats) issue() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.issued++
+}
+
+func (ss *sockStats) fail() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.errors++
+}
+
+func (ss *sockStats) abandon() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.abandoned++
+}
+
+func (ss *sockStats) complete(took time.Duration, err error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.completed++
+	if err != nil {
+		ss.errors++
+	}
+	if ss.completed == 1 {
+		ss.latency = took
+		return
+	}
+	ss.latency += time.Duration(latencyWeight * float64(took-ss.latency))
+}
+
+// SocketStatus is a snapshot of the load on a socket
+type SocketStatus struct {
+	IP string
+	// Outstanding is the number of commands waiting for a result
+	Outstanding int
+	// Queued is the number of commands not yet written to the socket,
+	// they are counted in Outstanding as well
+	Queued    int
+	Issued    uint64
+	Completed uint64
+	Errors    uint64
+	// Abandoned is the number of commands removed before they finished
+	Abandoned uint64
+	// Latency is a moving average of the time from issue to result
+	Latency time.Duration
+	// Oldest is the age of the oldest outstanding command
+	Oldest time.Duration
+}
+
 // Socket represents a scamper control socket
 type Socket struct {
+	format      Format
 	fname       string
 	ip          string
 	port        string
@@ -152,6 +322,7 @@ type Socket struct {
 	rw          *bufio.ReadWriter
 	done        chan struct{}
 	write       chan cmdResponse
+	stats       sockStats
 	// Access atomically
 	userID uint32
 	mu     sync.Mutex // Protect state
@@ -165,17 +336,25 @@ const (
 	closed
 )
 
-// NewSocket creates a new scamper socket
+// NewSocket creates a new scamper socket that reads warts results
 func NewSocket(fname string, con net.Conn) (*Socket, error) {
+	return NewSocketFormat(fname, con, Warts)
+}
+
+// NewSocketFormat creates a new scamper socket that reads results in the
+// format f. Results in JSON are returned as datamodel Pings and Traceroutes
+func NewSocketFormat(fname string, con net.Conn, f Format) (*Socket, error) {
 	sock := &Socket{
-		fname: fname,
-		cmds:  newCmdMap(),
-		con:   con,
-		rw:    bufio.NewReadWriter(bufio.NewReader(con), bufio.NewWriter(con)),
-		done:  make(chan struct{}),
-		write: make(chan cmdResponse, 50),
+		format: f,
+		fname:  fname,
+		cmds:   newCmdMap(),
+		con:    con,
+		rw:     bufio.NewReadWriter(bufio.NewReader(con), bufio.NewWriter(con)),
+		done:   make(chan struct{}),
+		write:  make(chan cmdResponse, 50),
 	}
-
+	// Set the ip before the goroutines that use it start
+	sock.IP()
 	go sock.readConn()
 	go sock.writeConn()
 	return sock, nil
@@ -222,7 +401,7 @@ func (s *Socket) readResponse() (Response, error) {
 	if err != nil {
 		return Response{}, parseErr{err: err, line: line}
 	}
-	if resp.RType != data {
+	if resp.RType != data || s.format != Warts {
 		return resp, nil
 	}
 	// The first two data messages received are the header of the warts format
@@ -236,7 +415,7 @@ func (s *Socket) readResponse() (Response, error) {
 
 func (s *Socket) readConn() {
 	var filter []warts.WartsT
-	filter = append(filter, warts.PingT, warts.TracerouteT)
+	filter = append(filter, warts.PingT, warts.TracerouteT, warts.MDATracerouteT, warts.AliasResolutionT)
 	count := cmdsRead.WithLabelValues(s.IP())
 	bytes := bytesRead.WithLabelValues(s.IP())
 	for {
@@ -255,8 +434,10 @@ func (s *Socket) readConn() {
 		if resp.Header {
 			continue
 		}
-		switch resp.RType {
-		case data:
+		switch {
+		case resp.RType == data && s.format == JSON:
+			go s.handleJSON(resp)
+		case resp.RType == data:
 			go func(r Response) {
 				dec := &uuencode.UUDecodingWriter{}
 				s.wartsHeader[0].WriteTo(dec)
@@ -265,12 +446,19 @@ func (s *Socket) readConn() {
 				res, err := warts.Parse(dec.Bytes(), filter)
 				r.Err = err
 				var cr cmdResponse
+				if err != nil {
+					s.stats.fail()
+				}
 				if err == nil {
 					switch t := res[0].(type) {
 					case warts.Traceroute:
 						r.UserID = t.Flags.UserID
 					case warts.Ping:
 						r.UserID = t.Flags.UserID
+					case warts.Tracelb:
+						r.UserID = t.Flags.UserID
+					case warts.Dealias:
+						r.UserID = t.Flags.UserID
 					}
 					r.Ret = res[0]
 					cr, err = s.cmds.getCmd(r.UserID)
@@ -278,6 +466,7 @@ func (s *Socket) readConn() {
 						return
 					}
 					s.cmds.rmCmd(r.UserID)
+					s.stats.complete(time.Since(cr.sent), nil)
 				}
 				cr.done <- r
 			}(resp)
@@ -287,6 +476,28 @@ func (s *Socket) readConn() {
 	}
 }
 
+// handleJSON answers the command a JSON result belongs to. Objects that
+// don't belong to a command, like cycle records, are dropped
+func (s *Socket) handleJSON(r Response) {
+	b := bytes.TrimSpace(r.Data)
+	if len(b) > 0 && b[0] != '{' {
+		dec := &uuencode.UUDecodingWriter{}
+		r.WriteTo(dec)
+		b = bytes.TrimSpace(dec.Bytes())
+	}
+	r.Ret, r.UserID, r.Err = parseJSON(b)
+	if r.UserID == 0 {
+		return
+	}
+	cr, err := s.cmds.getCmd(r.UserID)
+	if err != nil {
+		return
+	}
+	s.cmds.rmCmd(r.UserID)
+	s.stats.complete(time.Since(cr.sent), r.Err)
+	cr.done <- r
+}
+
 func (s *Socket) writeConn() {
 	tick := time.NewTicker(time.Millisecond * 10)
 	count := cmdsWritten.WithLabelValues(s.IP())
@@ -302,6 +513,7 @@ func (s *Socket) writeConn() {
 			err := w.cmd.IssueCommand(s.rw)
 			if err != nil {
 				log.Error(err)
+				s.stats.fail()
 				s.rw.Writer.Reset(s.con)
 				writes = 0
 				continue
@@ -349,10 +561,33 @@ func (s *Socket) getID() uint32 {
 
 // RemoveMeasurement remove a measurment being run with id id
 func (s *Socket) RemoveMeasurement(id uint32) error {
-	s.cmds.rmCmd(id)
+	if s.cmds.rmCmd(id) {
+		s.stats.abandon()
+	}
 	return nil
 }
 
+// Status reports the load on the socket from the commands it is running
+func (s *Socket) Status() SocketStatus {
+	out, oldest := s.cmds.outstanding()
+	st := SocketStatus{
+		IP:          s.IP(),
+		Outstanding: out,
+		Queued:      len(s.write),
+	}
+	if !oldest.IsZero() {
+		st.Oldest = time.Since(oldest)
+	}
+	s.stats.mu.Lock()
+	defer s.stats.mu.Unlock()
+	st.Issued = s.stats.issued
+	st.Completed = s.stats.completed
+	st.Errors = s.stats.errors
+	st.Abandoned = s.stats.abandoned
+	st.Latency = s.stats.latency
+	return st
+}
+
 var (
 	// ErrSocketClosed is returned when the socket is closed
 	ErrSocketClosed = fmt.Errorf("Socket closed.")
@@ -363,16 +598,21 @@ var (
 
 // DoMeasurement perform the measurement described by arg
 func (s *Socket) DoMeasurement(arg interface{}) (<-chan Response, uint32, error) {
+	// Reject bad options here, scamper's error wouldn't reach the caller
+	if err := Validate(arg); err != nil {
+		return nil, 0, err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.state == open {
 		id := s.getID()
 		cmd := Cmd{ID: id, Arg: arg}
-		cr := cmdResponse{cmd: cmd, done: make(chan Response, 1)}
+		cr := cmdResponse{cmd: cmd, done: make(chan Response, 1), sent: time.Now()}
 		err := s.cmds.addCmd(cr)
 		if err != nil {
 			return nil, 0, err
 		}
+		s.stats.issue()
 		if err != nil {
 			log.Error(err)
 			s.cmds.rmCmd(id)
@@ -9,15 +9,24 @@ type Sender struct {
 }
 
 // Send provides a mock function with given fields: _a0, _a1
-func (_m *Sender) Send(_a0 []*dm.Probe, _a1 uint32) error {
+func (_m *Sender) Send(_a0 []*dm.Probe, _a1 uint32) ([]uint32, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func([]*dm.Probe, uint32) error); ok {
+	var r0 []uint32
+	if rf, ok := ret.Get(0).(func([]*dm.Probe, uint32) []uint32); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint32)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*dm.Probe, uint32) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
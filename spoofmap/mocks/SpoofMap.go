@@ -40,3 +40,17 @@ func (_m *SpoofMap) Receive(_a0 *dm.Probe) error {
 
 	return r0
 }
+
+// Pending provides a mock function with given fields:
+func (_m *SpoofMap) Pending() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
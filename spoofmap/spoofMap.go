@@ -7,6 +7,7 @@ import (
 
 	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
 	"github.com/NEU-SNS/ReverseTraceroute/log"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
@@ -17,38 +18,112 @@ var (
 	ErrorSpoofNotFound = fmt.Errorf("Received a spoof with no matching Id")
 )
 
+var (
+	spoofStates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "spoofmap",
+		Name:      "spoofs",
+		Help:      "Count of the spoofs by delivery state: registered, answered, unanswered, delivered and undelivered",
+	}, []string{"state"})
+	spoofRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "spoofmap",
+		Name:      "retries",
+		Help:      "Count of the spoofed probes sent again because they were not acknowledged",
+	})
+	spoofsWaiting = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "spoofmap",
+		Name:      "waiting",
+		Help:      "The number of registered spoofs that have not been answered",
+	})
+	spoofsQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "spoofmap",
+		Name:      "queued",
+		Help:      "The number of spoofed probes waiting to be acknowledged",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(spoofStates)
+	prometheus.MustRegister(spoofRetries)
+	prometheus.MustRegister(spoofsWaiting)
+	prometheus.MustRegister(spoofsQueued)
+}
+
 // Sender is the interface for something that can sent a slice of SpoofedProbes
-// to an address
+// to an address. It returns the ProbeIds of the probes the receiver acknowledged
 type Sender interface {
-	Send([]*dm.Probe, uint32) error
+	Send([]*dm.Probe, uint32) ([]uint32, error)
+}
+
+// Config is the configuration of a SpoofMap
+type Config struct {
+	// Expire is how long a spoof waits for its probe and how long a
+	// received probe is retried before it is given up on
+	Expire time.Duration
+	// Interval is how often queued probes are sent
+	Interval time.Duration
+	// MaxBackoff limits how long a destination that fails is left
+	// before it is tried again
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig is the Config used by New
+var DefaultConfig = Config{
+	Expire:     time.Minute,
+	Interval:   time.Second * 2,
+	MaxBackoff: time.Minute,
 }
 
 type spoof struct {
-	probe *dm.Probe
 	t     time.Time
 	spoof dm.Spoof
 }
 
+type queued struct {
+	t     time.Time
+	sent  bool
+	probe *dm.Probe
+}
+
+// destQueue holds the probes that are waiting to be acknowledged by a destination
+type destQueue struct {
+	probes  map[uint32]*queued
+	backoff time.Duration
+	next    time.Time
+}
+
+// SpoofMap tracks spoofed measurement requests and delivers the probes
+// received for them
 type SpoofMap interface {
 	Quit()
 	Register(dm.Spoof) error
 	Receive(*dm.Probe) error
+	Pending() int
 }
 
-// SpoofMap is used to track spoofed measurement requests
 type spoofMap struct {
 	sync.Mutex
 	spoofs    map[uint32]*spoof
+	dests     map[uint32]*destQueue
 	quit      chan struct{}
 	transport Sender
+	config    Config
+	now       func() time.Time
 }
 
-// New creates a SpoofMap
+// New creates a SpoofMap with the DefaultConfig
 func New(s Sender) SpoofMap {
+	return NewWithConfig(s, DefaultConfig)
+}
+
+// NewWithConfig creates a SpoofMap with the given Config
+func NewWithConfig(s Sender, c Config) SpoofMap {
 	sm := &spoofMap{
 		spoofs:    make(map[uint32]*spoof),
+		dests:     make(map[uint32]*destQueue),
 		transport: s,
 		quit:      make(chan struct{}),
+		config:    c,
+		now:       time.Now,
 	}
 	go sm.sendSpoofs()
 	go sm.cleanOld()
@@ -64,89 +139,175 @@ func (s *spoofMap) Quit() {
 func (s *spoofMap) Register(sp dm.Spoof) error {
 	s.Lock()
 	defer s.Unlock()
+	now := s.now()
 	if spf, ok := s.spoofs[sp.Id]; ok {
-		if time.Since(spf.t) > time.Second*60 {
-			s.spoofs[sp.Id] = &spoof{
-				t:     time.Now(),
-				spoof: sp,
-			}
-			return nil
+		if now.Sub(spf.t) <= s.config.Expire {
+			return ErrorIDInUse
 		}
-		return ErrorIDInUse
+		spoofStates.WithLabelValues("unanswered").Inc()
+	} else {
+		spoofsWaiting.Inc()
 	}
 	s.spoofs[sp.Id] = &spoof{
-		t:     time.Now(),
+		t:     now,
 		spoof: sp,
 	}
+	spoofStates.WithLabelValues("registered").Inc()
 	return nil
 }
 
-// Receive is used when a probe for a spoof is gotten
+// Receive is used when a probe for a spoof is gotten. The probe is
+// queued for the address it was requested from
 func (s *spoofMap) Receive(p *dm.Probe) error {
 	s.Lock()
 	defer s.Unlock()
-	if sp, ok := s.spoofs[p.ProbeId]; ok {
-		pr := *p
-		sp.probe = &pr
-		return nil
+	if _, ok := s.spoofs[p.ProbeId]; !ok {
+		return ErrorSpoofNotFound
+	}
+	delete(s.spoofs, p.ProbeId)
+	spoofsWaiting.Dec()
+	spoofStates.WithLabelValues("answered").Inc()
+	pr := *p
+	dq, ok := s.dests[pr.SenderIp]
+	if !ok {
+		dq = &destQueue{probes: make(map[uint32]*queued)}
+		s.dests[pr.SenderIp] = dq
+	}
+	if _, ok := dq.probes[pr.ProbeId]; !ok {
+		spoofsQueued.Inc()
+	}
+	dq.probes[pr.ProbeId] = &queued{t: s.now(), probe: &pr}
+	return nil
+}
+
+// Pending is the number of registered spoofs that have not been answered
+// plus the number of received probes that have not been acknowledged
+func (s *spoofMap) Pending() int {
+	s.Lock()
+	defer s.Unlock()
+	n := len(s.spoofs)
+	for _, dq := range s.dests {
+		n += len(dq.probes)
 	}
-	return ErrorSpoofNotFound
+	return n
 }
 
 // call in a goroutine
 func (s *spoofMap) sendSpoofs() {
-	t := time.NewTicker(time.Second * 2)
-	var dests map[uint32][]*dm.Probe
+	t := time.NewTicker(s.config.Interval)
+	defer t.Stop()
 	for {
 		select {
 		case <-s.quit:
 			return
 		case <-t.C:
-			s.Lock()
-			dests = make(map[uint32][]*dm.Probe)
-			for id, spoof := range s.spoofs {
-				if spoof.probe != nil {
-					dests[spoof.probe.SenderIp] = append(dests[spoof.probe.SenderIp], spoof.probe)
-					spoof.probe = nil
-					delete(s.spoofs, id)
-				}
+			s.flush()
+		}
+	}
+}
+
+// flush sends the queued probes of every destination that is not
+// backing off from a failure
+func (s *spoofMap) flush() {
+	s.Lock()
+	now := s.now()
+	batches := make(map[uint32][]*dm.Probe)
+	for ip, dq := range s.dests {
+		if now.Before(dq.next) {
+			continue
+		}
+		for _, q := range dq.probes {
+			if q.sent {
+				spoofRetries.Inc()
 			}
-			for ip, probes := range dests {
-				if err := s.transport.Send(probes, ip); err != nil {
-					log.Error(err)
-					continue
-				}
-				delete(dests, ip)
+			q.sent = true
+			batches[ip] = append(batches[ip], q.probe)
+		}
+	}
+	s.Unlock()
+	// The lock isn't held while sending so a slow destination
+	// doesn't block Register and Receive
+	for ip, probes := range batches {
+		acked, err := s.transport.Send(probes, ip)
+		s.Lock()
+		s.ack(ip, acked, err)
+		s.Unlock()
+		if err != nil {
+			log.Errorf("Failed to send %d spoofed probes: %v", len(probes), err)
+		}
+	}
+}
+
+// ack must be called with the lock held
+func (s *spoofMap) ack(ip uint32, acked []uint32, err error) {
+	dq, ok := s.dests[ip]
+	if !ok {
+		return
+	}
+	if err != nil {
+		switch {
+		case dq.backoff == 0:
+			dq.backoff = s.config.Interval
+		case dq.backoff < s.config.MaxBackoff:
+			dq.backoff *= 2
+			if dq.backoff > s.config.MaxBackoff {
+				dq.backoff = s.config.MaxBackoff
 			}
-			s.Unlock()
 		}
+		dq.next = s.now().Add(dq.backoff)
+		return
+	}
+	dq.backoff = 0
+	dq.next = time.Time{}
+	for _, id := range acked {
+		if _, ok := dq.probes[id]; ok {
+			delete(dq.probes, id)
+			spoofsQueued.Dec()
+			spoofStates.WithLabelValues("delivered").Inc()
+		}
+	}
+	if len(dq.probes) == 0 {
+		delete(s.dests, ip)
 	}
 }
 
 // run this in a goroutine
-// spoofed probes may never get responses
-// clean out old ones so the memory doesn't grow quite as much
+// spoofed probes may never get responses and destinations may never
+// acknowledge, clean out old ones so the memory doesn't grow
 func (s *spoofMap) cleanOld() {
-	t := time.NewTicker(time.Minute)
-	var count int
+	t := time.NewTicker(s.config.Expire / 2)
+	defer t.Stop()
 	for {
 		select {
 		case <-s.quit:
 			return
 		case <-t.C:
-			s.Lock()
-			count = 0
-			for id, spoof := range s.spoofs {
-				if time.Since(spoof.t) > time.Second*60 {
-					spoof.probe = nil
-					delete(s.spoofs, id)
-				}
-				count++
-				if count == 10000 {
-					break
-				}
+			s.expire()
+		}
+	}
+}
+
+func (s *spoofMap) expire() {
+	s.Lock()
+	defer s.Unlock()
+	now := s.now()
+	for id, spoof := range s.spoofs {
+		if now.Sub(spoof.t) > s.config.Expire {
+			delete(s.spoofs, id)
+			spoofsWaiting.Dec()
+			spoofStates.WithLabelValues("unanswered").Inc()
+		}
+	}
+	for ip, dq := range s.dests {
+		for id, q := range dq.probes {
+			if now.Sub(q.t) > s.config.Expire {
+				delete(dq.probes, id)
+				spoofsQueued.Dec()
+				spoofStates.WithLabelValues("undelivered").Inc()
 			}
-			s.Unlock()
+		}
+		if len(dq.probes) == 0 {
+			delete(s.dests, ip)
 		}
 	}
 }
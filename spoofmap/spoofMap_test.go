@@ -1,7 +1,9 @@
 package spoofmap_test
 
 import (
+	"fmt"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,16 +13,45 @@ import (
 )
 
 type sender struct {
-	sent []*datamodel.Probe
+	mu    sync.Mutex
+	sent  []*datamodel.Probe
+	fails int
+	nack  map[uint32]int
+	calls int
 }
 
-func (s *sender) Send(ps []*datamodel.Probe, ip uint32) error {
-	s.sent = append(s.sent, ps...)
-	return nil
+// Send fails the first fails calls and doesn't acknowledge a probe
+// for the number of sends given in nack
+func (s *sender) Send(ps []*datamodel.Probe, ip uint32) ([]uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.fails > 0 {
+		s.fails--
+		return nil, fmt.Errorf("Controller unreachable")
+	}
+	var acked []uint32
+	for _, p := range ps {
+		if s.nack[p.ProbeId] > 0 {
+			s.nack[p.ProbeId]--
+			continue
+		}
+		s.sent = append(s.sent, p)
+		acked = append(acked, p.ProbeId)
+	}
+	return acked, nil
 }
 
 func (s *sender) getProbes() []*datamodel.Probe {
-	return s.sent
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*datamodel.Probe(nil), s.sent...)
+}
+
+func (s *sender) getCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
 }
 
 func TestQuit(t *testing.T) {
@@ -153,3 +184,92 @@ func TestSendSpoofs(t *testing.T) {
 		sm.Quit()
 	}
 }
+
+var fast = spoofmap.Config{
+	Expire:     time.Second,
+	Interval:   time.Millisecond * 20,
+	MaxBackoff: time.Millisecond * 80,
+}
+
+func waitFor(t *testing.T, what string, f func() bool) {
+	deadline := time.Now().Add(time.Second * 2)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
+
+func TestSendSpoofsRetry(t *testing.T) {
+	defer util.LeakCheck(t)()
+	s := &sender{fails: 3, nack: map[uint32]int{2: 2}}
+	sm := spoofmap.NewWithConfig(s, fast)
+	defer sm.Quit()
+	for _, id := range []uint32{1, 2, 3} {
+		if err := sm.Register(datamodel.Spoof{Id: id}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sm.Receive(&datamodel.Probe{ProbeId: id, SenderIp: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, "all probes delivered", func() bool { return len(s.getProbes()) == 3 })
+	// 3 failures, then 2 more sends before probe 2 is acknowledged
+	if calls := s.getCalls(); calls != 6 {
+		t.Fatalf("Expected 6 sends, got %d", calls)
+	}
+	<-time.After(fast.Interval * 3)
+	if calls := s.getCalls(); calls != 6 {
+		t.Fatalf("Acknowledged probes were sent again, %d sends", calls)
+	}
+}
+
+func TestPending(t *testing.T) {
+	defer util.LeakCheck(t)()
+	s := &sender{fails: 2}
+	sm := spoofmap.NewWithConfig(s, fast)
+	defer sm.Quit()
+	for _, id := range []uint32{1, 2, 3} {
+		if err := sm.Register(datamodel.Spoof{Id: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sm.Receive(&datamodel.Probe{ProbeId: 1, SenderIp: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if p := sm.Pending(); p != 3 {
+		t.Fatalf("Expected 3 pending, got %d", p)
+	}
+	waitFor(t, "probe delivered", func() bool { return sm.Pending() == 2 })
+}
+
+func TestExpire(t *testing.T) {
+	defer util.LeakCheck(t)()
+	s := &sender{fails: 1000}
+	sm := spoofmap.NewWithConfig(s, fast)
+	defer sm.Quit()
+	if err := sm.Register(datamodel.Spoof{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Register(datamodel.Spoof{Id: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Receive(&datamodel.Probe{ProbeId: 2}); err != nil {
+		t.Fatal(err)
+	}
+	<-time.After(fast.Expire * 2)
+	// The unanswered spoof is gone so the id is free and its probe is unknown
+	if err := sm.Receive(&datamodel.Probe{ProbeId: 1}); err != spoofmap.ErrorSpoofNotFound {
+		t.Fatalf("Expected[%v] got [%v]", spoofmap.ErrorSpoofNotFound, err)
+	}
+	if err := sm.Register(datamodel.Spoof{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// The undelivered probe is no longer retried
+	calls := s.getCalls()
+	<-time.After(fast.MaxBackoff * 2)
+	if s.getCalls() != calls {
+		t.Fatalf("Expired probe was still being sent")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/atlas/client"
+	"github.com/NEU-SNS/ReverseTraceroute/atlas/ingest"
+	"github.com/NEU-SNS/ReverseTraceroute/atlas/pb"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var filePath string
+var format string
+var atlasAddr string
+var rootCA string
+var src string
+var start int64
+
+const usage = `atlasingest -f <file> -t <ripe|ark|iplane> -a <atlas addr> -ca <root ca> [-src <ip> -start <unix time>]`
+
+func init() {
+	flag.StringVar(&filePath, "f", "", "The path to the file that contains the traceroutes, .gz and .bz2 files are decompressed")
+	flag.StringVar(&format, "t", "", "The format of the file: ripe, ark or iplane")
+	flag.StringVar(&atlasAddr, "a", "", "The address of the atlas")
+	flag.StringVar(&rootCA, "ca", "", "The root CA used to connect to the atlas")
+	flag.StringVar(&src, "src", "", "The source of the traceroutes, required for iplane")
+	flag.Int64Var(&start, "start", 0, "The unix time the traceroutes were run, used for iplane")
+}
+
+func makeScanner(r io.Reader) (ingest.Scanner, pb.TraceSource, error) {
+	switch format {
+	case "ripe":
+		return ingest.NewRIPEScanner(r), pb.TraceSource_RIPE_ATLAS, nil
+	case "ark":
+		return ingest.NewWartsScanner(r), pb.TraceSource_CAIDA_ARK, nil
+	case "iplane":
+		srcIP, err := util.IPStringToInt32(src)
+		if err != nil {
+			return nil, pb.TraceSource_INTERNAL, fmt.Errorf("Invalid source for iplane: %v", err)
+		}
+		var st time.Time
+		if start != 0 {
+			st = time.Unix(start, 0)
+		}
+		return ingest.NewIPlaneScanner(r, srcIP, st), pb.TraceSource_IPLANE, nil
+	default:
+		return nil, pb.TraceSource_INTERNAL, fmt.Errorf("Unknown format: %s", format)
+	}
+}
+
+func main() {
+	flag.Parse()
+	if filePath == "" || format == "" || atlasAddr == "" || rootCA == "" {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	var r io.Reader
+	switch filepath.Ext(filePath) {
+	case ".bz2":
+		r = bzip2.NewReader(f)
+	case ".gz":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer gz.Close()
+		r = gz
+	default:
+		r = f
+	}
+	scan, source, err := makeScanner(r)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	host, _, err := net.SplitHostPort(atlasAddr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	creds, err := credentials.NewClientTLSFromFile(rootCA, host)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cc, err := grpc.Dial(atlasAddr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer cc.Close()
+	ctx := context.Background()
+	st, err := client.New(ctx, cc).IngestTraceroutes(ctx)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	for scan.Scan() {
+		err := st.Send(&pb.IngestRequest{
+			Source:     source,
+			Traceroute: scan.Traceroute(),
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	if err := scan.Err(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	resp, err := st.CloseAndRecv()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Stored %d traceroutes, rejected %d\n", resp.Stored, resp.Rejected)
+}
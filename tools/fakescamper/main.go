@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/NEU-SNS/ReverseTraceroute/scamper/scampertest"
+)
+
+var listen string
+var wartsGlob string
+
+const usage = `fakescamper -l <unix socket path|host:port> [-w <warts glob>]`
+
+func init() {
+	flag.StringVar(&listen, "l", "", "The unix socket path or host:port to listen on")
+	flag.StringVar(&wartsGlob, "w", "doc/*.warts", "The warts files the canned results are taken from")
+}
+
+func main() {
+	flag.Parse()
+	if listen == "" {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	files, err := filepath.Glob(wartsGlob)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	s, err := scampertest.New(files...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	network := "unix"
+	if _, _, err := net.SplitHostPort(listen); err == nil {
+		network = "tcp"
+	}
+	if err := s.Listen(network, listen); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	<-c
+	s.Close()
+}
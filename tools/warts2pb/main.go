@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+var filePath string
+var format string
+var types string
+
+const usage = `warts2pb [-f <file>] [-o <json|pb>] [-types <ping,trace,tracelb,dealias>]`
+
+func init() {
+	flag.StringVar(&filePath, "f", "", "The warts file to convert, .gz and .bz2 files are decompressed. Reads stdin if empty")
+	flag.StringVar(&format, "o", "json", "The output: json for one JSON object per line, pb for varint length delimited protobuf")
+	flag.StringVar(&types, "types", "ping,trace", "The measurement types to convert")
+}
+
+// convert converts the warts measurements to datamodel, other objects are
+// skipped
+func convert(obj interface{}, want map[string]bool) proto.Message {
+	switch o := obj.(type) {
+	case warts.Ping:
+		if want["ping"] {
+			p := dm.ConvertPing(o)
+			return &p
+		}
+	case warts.Traceroute:
+		if want["trace"] {
+			t := dm.ConvertTraceroute(o)
+			return &t
+		}
+	case warts.Tracelb:
+		if want["tracelb"] {
+			t := dm.ConvertTracelb(o)
+			return &t
+		}
+	case warts.Dealias:
+		if want["dealias"] {
+			d := dm.ConvertDealias(o)
+			return &d
+		}
+	}
+	return nil
+}
+
+type writer func(w *bufio.Writer, m proto.Message) error
+
+func writeJSON(w *bufio.Writer, m proto.Message) error {
+	var marsh jsonpb.Marshaler
+	if err := marsh.Marshal(w, m); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+func writeDelimited(w *bufio.Writer, m proto.Message) error {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var l [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(l[:], uint64(len(data)))
+	if _, err := w.Write(l[:n]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func run(in io.Reader, out io.Writer, write writer, want map[string]bool) (int, error) {
+	r, err := warts.NewReader(in)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(out)
+	var count int
+	for r.Next() {
+		m := convert(r.Object(), want)
+		if m == nil {
+			continue
+		}
+		if err := write(w, m); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := r.Err(); err != nil {
+		w.Flush()
+		return count, err
+	}
+	return count, w.Flush()
+}
+
+func main() {
+	flag.Parse()
+	var write writer
+	switch format {
+	case "json":
+		write = writeJSON
+	case "pb":
+		write = writeDelimited
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+	want := make(map[string]bool)
+	for _, t := range strings.Split(types, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "ping", "trace", "tracelb", "dealias":
+			want[t] = true
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown type: %s\n%s\n", t, usage)
+			os.Exit(1)
+		}
+	}
+	in := io.Reader(os.Stdin)
+	if filePath != "" && filePath != "-" {
+		f, err := os.Open(filePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	count, err := run(in, os.Stdout, write, want)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Converted %d measurements before failing: %v\n", count, err)
+		os.Exit(1)
+	}
+}
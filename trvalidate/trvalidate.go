@@ -0,0 +1,288 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// Package trvalidate checks traceroutes for problems that make them unfit
+// to be stored
+package trvalidate
+
+import (
+	"fmt"
+	"net"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reason is the reason a traceroute was rejected
+type Reason string
+
+const (
+	// ReasonLoop is used when an address appears at non-adjacent ttls
+	ReasonLoop Reason = "loop"
+	// ReasonRepeatedHop is used when an address appears at adjacent ttls
+	ReasonRepeatedHop Reason = "repeated_hop"
+	// ReasonGaps is used when too many ttls had no response
+	ReasonGaps Reason = "gaps"
+	// ReasonPrivateHop is used when a hop is a private or bogon address
+	ReasonPrivateHop Reason = "private_hop"
+	// ReasonTTLOrder is used when the hop ttls are not monotonically increasing
+	ReasonTTLOrder Reason = "ttl_order"
+)
+
+var (
+	nameSpace       = "validate"
+	rejectedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: nameSpace,
+		Subsystem: "traceroute",
+		Name:      "rejected",
+		Help:      "The number of traceroutes rejected by reason",
+	}, []string{"reason"})
+	bogons []*net.IPNet
+)
+
+func init() {
+	prometheus.MustRegister(rejectedCounter)
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.0.2.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"198.51.100.0/24",
+		"203.0.113.0/24",
+		"224.0.0.0/3",
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		bogons = append(bogons, n)
+	}
+}
+
+// RejectError is returned when a traceroute fails validation
+type RejectError struct {
+	Reason Reason
+	Detail string
+}
+
+func (re *RejectError) Error() string {
+	return fmt.Sprintf("Traceroute rejected, %s: %s", re.Reason, re.Detail)
+}
+
+// Config is the configuration of the rules a Validator applies
+type Config struct {
+	Loops        *bool `flag:"validate-loops"`
+	MaxGaps      *int  `flag:"validate-max-gaps"`
+	PrivateHops  *bool `flag:"validate-private-hops"`
+	MonotonicTTL *bool `flag:"validate-ttl-order"`
+}
+
+// NewConfig returns a Config with all rules enabled
+// and a maximum of 3 gaps
+func NewConfig() Config {
+	c := Config{
+		Loops:        new(bool),
+		MaxGaps:      new(int),
+		PrivateHops:  new(bool),
+		MonotonicTTL: new(bool),
+	}
+	*c.Loops = true
+	*c.MaxGaps = 3
+	*c.PrivateHops = true
+	*c.MonotonicTTL = true
+	return c
+}
+
+// Validator checks traceroutes against a set of rules
+type Validator struct {
+	loops        bool
+	maxGaps      int
+	privateHops  bool
+	monotonicTTL bool
+}
+
+// New creates a Validator from the Config c. Nil fields of c disable
+// the rule and a negative MaxGaps disables the gap check
+func New(c Config) *Validator {
+	v := &Validator{maxGaps: -1}
+	if c.Loops != nil {
+		v.loops = *c.Loops
+	}
+	if c.MaxGaps != nil {
+		v.maxGaps = *c.MaxGaps
+	}
+	if c.PrivateHops != nil {
+		v.privateHops = *c.PrivateHops
+	}
+	if c.MonotonicTTL != nil {
+		v.monotonicTTL = *c.MonotonicTTL
+	}
+	return v
+}
+
+// Validate checks t against the rules of the Validator. If t is rejected
+// the returned error is a *RejectError and the rejection is counted.
+// A nil Validator accepts every traceroute
+func (v *Validator) Validate(t *dm.Traceroute) error {
+	if v == nil {
+		return nil
+	}
+	err := v.validate(t)
+	if err != nil {
+		rejectedCounter.WithLabelValues(string(err.Reason)).Inc()
+		return err
+	}
+	return nil
+}
+
+func (v *Validator) validate(t *dm.Traceroute) *RejectError {
+	hops := t.GetHops()
+	if v.monotonicTTL {
+		if err := checkTTLOrder(hops); err != nil {
+			return err
+		}
+	}
+	if v.privateHops {
+		if err := checkPrivate(hops); err != nil {
+			return err
+		}
+	}
+	if v.loops {
+		if err := checkLoops(hops); err != nil {
+			return err
+		}
+	}
+	if v.maxGaps >= 0 {
+		if err := checkGaps(t, v.maxGaps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ipString(ip uint32) string {
+	s, _ := util.Int32ToIPString(ip)
+	return s
+}
+
+func checkTTLOrder(hops []*dm.TracerouteHop) *RejectError {
+	for i := 1; i < len(hops); i++ {
+		if hops[i].ProbeTtl < hops[i-1].ProbeTtl {
+			return &RejectError{
+				Reason: ReasonTTLOrder,
+				Detail: fmt.Sprintf("ttl %d follows ttl %d", hops[i].ProbeTtl, hops[i-1].ProbeTtl),
+			}
+		}
+	}
+	return nil
+}
+
+func isBogon(ip uint32) bool {
+	nip := net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip))
+	for _, n := range bogons {
+		if n.Contains(nip) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPrivate(hops []*dm.TracerouteHop) *RejectError {
+	for _, hop := range hops {
+		if isBogon(hop.Addr) {
+			return &RejectError{
+				Reason: ReasonPrivateHop,
+				Detail: fmt.Sprintf("%s at ttl %d", ipString(hop.Addr), hop.ProbeTtl),
+			}
+		}
+	}
+	return nil
+}
+
+func checkLoops(hops []*dm.TracerouteHop) *RejectError {
+	// Multiple replies for the same ttl are expected, an address
+	// seen again at a later ttl is not
+	seen := make(map[uint32]uint32)
+	for _, hop := range hops {
+		ttl, ok := seen[hop.Addr]
+		if !ok {
+			seen[hop.Addr] = hop.ProbeTtl
+			continue
+		}
+		if ttl == hop.ProbeTtl {
+			continue
+		}
+		reason := ReasonLoop
+		if hop.ProbeTtl == ttl+1 {
+			reason = ReasonRepeatedHop
+		}
+		return &RejectError{
+			Reason: reason,
+			Detail: fmt.Sprintf("%s at ttl %d and %d", ipString(hop.Addr), ttl, hop.ProbeTtl),
+		}
+	}
+	return nil
+}
+
+func checkGaps(t *dm.Traceroute, max int) *RejectError {
+	hops := t.GetHops()
+	if len(hops) == 0 {
+		return nil
+	}
+	first := t.Firsthop
+	if first == 0 {
+		first = 1
+	}
+	responded := make(map[uint32]bool)
+	var last uint32
+	for _, hop := range hops {
+		responded[hop.ProbeTtl] = true
+		if hop.ProbeTtl > last {
+			last = hop.ProbeTtl
+		}
+	}
+	var gaps int
+	for ttl := first; ttl <= last; ttl++ {
+		if !responded[ttl] {
+			gaps++
+		}
+	}
+	if gaps > max {
+		return &RejectError{
+			Reason: ReasonGaps,
+			Detail: fmt.Sprintf("%d unresponsive ttls, at most %d allowed", gaps, max),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package trvalidate_test
+
+import (
+	"testing"
+
+	dm "github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/trvalidate"
+)
+
+func hop(addr, ttl uint32) *dm.TracerouteHop {
+	return &dm.TracerouteHop{Addr: addr, ProbeTtl: ttl}
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		desc   string
+		hops   []*dm.TracerouteHop
+		reason trvalidate.Reason
+	}{
+		{
+			desc: "valid",
+			hops: []*dm.TracerouteHop{hop(0x01010101, 1), hop(0x02020202, 2), hop(0x02020203, 2), hop(0x03030303, 4)},
+		},
+		{
+			desc:   "loop",
+			hops:   []*dm.TracerouteHop{hop(0x01010101, 1), hop(0x02020202, 2), hop(0x01010101, 3)},
+			reason: trvalidate.ReasonLoop,
+		},
+		{
+			desc:   "repeated hop",
+			hops:   []*dm.TracerouteHop{hop(0x01010101, 1), hop(0x01010101, 2)},
+			reason: trvalidate.ReasonRepeatedHop,
+		},
+		{
+			desc:   "gaps",
+			hops:   []*dm.TracerouteHop{hop(0x01010101, 1), hop(0x02020202, 4)},
+			reason: trvalidate.ReasonGaps,
+		},
+		{
+			desc:   "private",
+			hops:   []*dm.TracerouteHop{hop(0xc0a80101, 1), hop(0x02020202, 2)},
+			reason: trvalidate.ReasonPrivateHop,
+		},
+		{
+			desc:   "ttl order",
+			hops:   []*dm.TracerouteHop{hop(0x01010101, 2), hop(0x02020202, 1)},
+			reason: trvalidate.ReasonTTLOrder,
+		},
+	} {
+		c := trvalidate.NewConfig()
+		*c.MaxGaps = 1
+		v := trvalidate.New(c)
+		err := v.Validate(&dm.Traceroute{Hops: test.hops})
+		if test.reason == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected rejection: %v", test.desc, err)
+			}
+			continue
+		}
+		re, ok := err.(*trvalidate.RejectError)
+		if !ok {
+			t.Fatalf("%s: expected a RejectError, got %v", test.desc, err)
+		}
+		if re.Reason != test.reason {
+			t.Fatalf("%s: expected reason %s, got %s", test.desc, test.reason, re.Reason)
+		}
+	}
+}
+
+func TestValidateRulesDisabled(t *testing.T) {
+	v := trvalidate.New(trvalidate.Config{})
+	err := v.Validate(&dm.Traceroute{Hops: []*dm.TracerouteHop{
+		hop(0x0a000001, 3), hop(0x0a000001, 1), hop(0x02020202, 9),
+	}})
+	if err != nil {
+		t.Fatalf("Validate with no rules failed: %v", err)
+	}
+}
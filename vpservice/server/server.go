@@ -31,6 +31,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -94,6 +95,10 @@ var (
 const (
 	defaultLimit = 250
 	testSize     = 50
+	// selfTestAge is how long a vp's self test is trusted
+	selfTestAge = time.Hour * 48
+	// vpStatusTimeout is how long spoofer requests wait for vp loads
+	vpStatusTimeout = time.Second * 2
 )
 
 func init() {
@@ -162,7 +167,12 @@ func NewServer(opts ...Option) (VPServer, error) {
 	for _, opt := range opts {
 		opt(&so)
 	}
-	s := server{opts: so, rrf: makeRRF(so.rrf), tsf: makeTSF(so.tsf)}
+	s := server{
+		opts:      so,
+		rrf:       makeRRF(so.rrf),
+		tsf:       makeTSF(so.tsf),
+		selfTests: &selfTests{tests: make(map[uint32]*datamodel.SelfTest)},
+	}
 	s.initGuages()
 	go s.checkCapabilitiesAndUpdate()
 	go s.updateGauges()
@@ -206,9 +216,56 @@ type tsFilter func([]types.TSVantagePoint) []*pb.VantagePoint
 type rrFilter func([]types.RRVantagePoint) []*pb.VantagePoint
 
 type server struct {
-	opts serverOptions
-	tsf  tsFilter
-	rrf  rrFilter
+	opts      serverOptions
+	tsf       tsFilter
+	rrf       rrFilter
+	selfTests *selfTests
+}
+
+// selfTests are the last self tests the vps reported through the controller
+type selfTests struct {
+	mu    sync.Mutex
+	tests map[uint32]*datamodel.SelfTest
+}
+
+func (st *selfTests) set(vps []*datamodel.VantagePoint) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for _, vp := range vps {
+		if vp.SelfTest != nil {
+			st.tests[vp.Ip] = vp.SelfTest
+		}
+	}
+}
+
+func (st *selfTests) get(ip uint32) *datamodel.SelfTest {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.tests[ip]
+}
+
+// combineSelfTest removes the capabilities observed from outside that the
+// vp's own test says it can't have. A vp that can't open raw sockets can't
+// probe with options or spoof, and one that doesn't receive ICMP can't
+// receive spoofed probes. A self test never adds a capability
+func combineSelfTest(vp *pb.VantagePoint, st *datamodel.SelfTest, now time.Time) {
+	if st == nil || now.Sub(time.Unix(st.Tested, 0)) > selfTestAge {
+		return
+	}
+	if !st.RawSocket {
+		vp.RecordRoute = false
+		vp.Timestamp = false
+		vp.Spoof = false
+	}
+	if !st.SendRecordRoute {
+		vp.RecordRoute = false
+	}
+	if !st.SendTimestamp {
+		vp.Timestamp = false
+	}
+	if !st.ReceiveIcmp {
+		vp.RecSpoof = false
+	}
 }
 
 func (s server) QuarantineVPs(vps []types.Quarantine) error {
@@ -253,6 +310,9 @@ func (s server) GetRRSpoofers(rrs *pb.RRSpooferRequest) (*pb.RRSpooferResponse,
 	resp.Max = rrs.Max
 	resp.Spoofers = s.rrf(vps)
 	log.Debug("filtered rr spoofers: ", resp.Spoofers)
+	// The spoofers are in order of distance, busy ones only go last
+	// when the controller has no load for them
+	sort.Stable(byLoad{vps: resp.Spoofers, loads: s.vpLoads(resp.Spoofers), keepOrder: true})
 	if uint32(len(resp.Spoofers)) > rrs.Max {
 		resp.Spoofers = resp.Spoofers[:rrs.Max]
 	}
@@ -273,12 +333,56 @@ func (s server) GetTSSpoofers(tsr *pb.TSSpooferRequest) (*pb.TSSpooferResponse,
 	resp.Addr = tsr.Addr
 	resp.Max = tsr.Max
 	resp.Spoofers = s.tsf(vps)
+	sort.Stable(byLoad{vps: resp.Spoofers, loads: s.vpLoads(resp.Spoofers)})
 	if uint32(len(resp.Spoofers)) > tsr.Max {
 		resp.Spoofers = resp.Spoofers[:tsr.Max]
 	}
 	return &resp, nil
 }
 
+// vpLoads asks the controller how busy the scampers of vps are. vps it
+// has no status for are missing, as are all of them if it can't be asked
+func (s server) vpLoads(vps []*pb.VantagePoint) map[uint32]float64 {
+	loads := make(map[uint32]float64)
+	if s.opts.cl == nil || len(vps) == 0 {
+		return loads
+	}
+	ips := make([]uint32, 0, len(vps))
+	for _, vp := range vps {
+		ips = append(ips, vp.Ip)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vpStatusTimeout)
+	defer cancel()
+	st, err := s.opts.cl.GetVPStatus(ctx, &datamodel.VPStatusRequest{Vps: ips})
+	if err != nil {
+		log.Error(err)
+		return loads
+	}
+	for _, vs := range st.GetStatuses() {
+		loads[vs.Ip] = vs.Load()
+	}
+	return loads
+}
+
+// byLoad sorts the least loaded vps first and vps without a load last.
+// With keepOrder only those without a load are moved
+type byLoad struct {
+	vps       []*pb.VantagePoint
+	loads     map[uint32]float64
+	keepOrder bool
+}
+
+func (b byLoad) Len() int      { return len(b.vps) }
+func (b byLoad) Swap(i, j int) { b.vps[i], b.vps[j] = b.vps[j], b.vps[i] }
+func (b byLoad) Less(i, j int) bool {
+	li, iok := b.loads[b.vps[i].Ip]
+	lj, jok := b.loads[b.vps[j].Ip]
+	if iok != jok {
+		return iok
+	}
+	return !b.keepOrder && li < lj
+}
+
 func (s server) GetLastQuarantine(ip uint32) (types.Quarantine, error) {
 	return s.opts.vpp.GetLastQuarantine(ip)
 }
@@ -302,6 +406,7 @@ func (s server) checkCapabilitiesAndUpdate() {
 				cancel()
 				continue
 			} else {
+				s.selfTests.set(vps.GetVps())
 				s.addOrUpdateVPs(vps.GetVps())
 				cancel()
 			}
@@ -467,8 +572,10 @@ func (s server) checkCapabilities() {
 	s.testSpoof(tests, vpm)
 	s.testPing(tests, vpm)
 	s.testTrace(traceTests, vpm)
+	now := time.Now()
 	var quarantines []types.Quarantine
 	for _, vp := range vpm {
+		combineSelfTest(vp, s.selfTests.get(vp.Ip), now)
 		err := s.opts.vpp.UpdateVP(*vp)
 		if err != nil {
 			log.Error(err)
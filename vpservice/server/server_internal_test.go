@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package server
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/vpservice/pb"
+)
+
+func TestCombineSelfTest(t *testing.T) {
+	now := time.Now()
+	all := pb.VantagePoint{RecordRoute: true, Timestamp: true, Spoof: true, RecSpoof: true, Ping: true}
+	for _, test := range []struct {
+		name string
+		st   *datamodel.SelfTest
+		want pb.VantagePoint
+	}{
+		{name: "no self test", want: all},
+		{
+			name: "everything passed",
+			st:   &datamodel.SelfTest{Tested: now.Unix(), RawSocket: true, SendRecordRoute: true, SendTimestamp: true, ReceiveIcmp: true},
+			want: all,
+		},
+		{
+			name: "no raw socket",
+			st:   &datamodel.SelfTest{Tested: now.Unix(), ReceiveIcmp: true},
+			want: pb.VantagePoint{RecSpoof: true, Ping: true},
+		},
+		{
+			name: "no timestamp or icmp",
+			st:   &datamodel.SelfTest{Tested: now.Unix(), RawSocket: true, SendRecordRoute: true},
+			want: pb.VantagePoint{RecordRoute: true, Spoof: true, Ping: true},
+		},
+		{
+			name: "too old",
+			st:   &datamodel.SelfTest{Tested: now.Add(-selfTestAge * 2).Unix()},
+			want: all,
+		},
+	} {
+		vp := all
+		combineSelfTest(&vp, test.st, now)
+		if vp != test.want {
+			t.Errorf("%s: got %v, expected %v", test.name, vp, test.want)
+		}
+	}
+}
+
+func TestByLoad(t *testing.T) {
+	loads := map[uint32]float64{1: 5, 2: 1, 3: 3}
+	for _, test := range []struct {
+		name      string
+		keepOrder bool
+		want      []uint32
+	}{
+		{name: "least loaded first", want: []uint32{2, 3, 1, 4, 5}},
+		// 4 and 5 have no load so may be down
+		{name: "keep order", keepOrder: true, want: []uint32{1, 2, 3, 4, 5}},
+	} {
+		vps := []*pb.VantagePoint{{Ip: 1}, {Ip: 4}, {Ip: 2}, {Ip: 5}, {Ip: 3}}
+		sort.Stable(byLoad{vps: vps, loads: loads, keepOrder: test.keepOrder})
+		var got []uint32
+		for _, vp := range vps {
+			got = append(got, vp.Ip)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, expected %v", test.name, got, test.want)
+		}
+	}
+}
@@ -28,6 +28,7 @@
 package warts
 
 import (
+	"bytes"
 	"io"
 	"sync"
 
@@ -118,3 +119,23 @@ func readAddress(f io.Reader, addrs *AddressRefs) (Address, error) {
 	return a, nil
 
 }
+
+// readAddressObject reads an address object. Old warts files define
+// addresses in their own objects, an id byte and the type come first
+func readAddressObject(f io.Reader) (Address, error) {
+	a := Address{}
+	if _, err := readUint8(f); err != nil {
+		return a, err
+	}
+	t, err := readUint8(f)
+	if err != nil {
+		return a, err
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(f); err != nil {
+		return a, err
+	}
+	a.Type = t
+	a.Address = sliceToUint64(buf.Bytes())
+	return a, nil
+}
@@ -0,0 +1,561 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts
+
+import (
+	"fmt"
+	"io"
+	"syscall"
+)
+
+// DealiasMethod is the alias resolution technique a dealias used
+type DealiasMethod uint8
+
+const (
+	// DealiasMercator sends a udp probe and compares the reply source
+	DealiasMercator DealiasMethod = 0x01
+	// DealiasAlly compares the ip ids of replies from two addresses
+	DealiasAlly = 0x02
+	// DealiasRadargun infers ip id velocity across many addresses
+	DealiasRadargun = 0x03
+	// DealiasPrefixscan looks for an alias of an address in a prefix
+	DealiasPrefixscan = 0x04
+	// DealiasBump checks the ip ids of two addresses can be bumped together
+	DealiasBump = 0x05
+)
+
+func (dm DealiasMethod) String() string {
+	methods := []string{
+		"NULL",
+		"mercator",
+		"ally",
+		"radargun",
+		"prefixscan",
+		"bump",
+	}
+	if int(dm) >= len(methods) {
+		return "NULL"
+	}
+	return methods[dm]
+}
+
+// DealiasResult is the outcome of a dealias
+type DealiasResult uint8
+
+const (
+	// DealiasResultNone is a dealias that reached no conclusion
+	DealiasResultNone DealiasResult = iota
+	// DealiasResultAliases is a dealias that found aliases
+	DealiasResultAliases
+	// DealiasResultNotAliases is a dealias that found the addresses are not aliases
+	DealiasResultNotAliases
+	// DealiasResultHalted is a dealias that was stopped
+	DealiasResultHalted
+	// DealiasResultIPIDEcho is a dealias where the target echoed the probe ip id
+	DealiasResultIPIDEcho
+)
+
+func (dr DealiasResult) String() string {
+	results := []string{
+		"none",
+		"aliases",
+		"not-aliases",
+		"halted",
+		"ipid-echo",
+	}
+	if int(dr) >= len(results) {
+		return "none"
+	}
+	return results[dr]
+}
+
+// Dealias is a warts alias resolution
+type Dealias struct {
+	Flags     DealiasFlags
+	Options   DealiasOptions
+	ProbeDefs []DealiasProbeDef
+	Probes    []DealiasProbe
+}
+
+// DealiasFlags are the parameters common to every dealias method
+type DealiasFlags struct {
+	ListID     uint32
+	CycleID    uint32
+	StartTime  syscall.Timeval
+	Method     DealiasMethod
+	Result     DealiasResult
+	ProbeCount uint32
+	UserID     uint32
+}
+
+// DealiasOptions are the parameters of the dealias method.
+// Only those used by the method are set
+type DealiasOptions struct {
+	WaitProbe     uint16
+	WaitTimeout   uint8
+	WaitRound     uint32
+	Attempts      uint16
+	Fudge         uint16
+	Count         uint16
+	BumpLimit     uint16
+	ProbeDefCount uint32
+	Prefix        uint8
+	Flags         uint8
+	ReplyCount    uint8
+	A             Address
+	B             Address
+	AB            Address
+	XS            []Address
+}
+
+// DealiasProbeDef describes a kind of probe sent by a dealias
+type DealiasProbeDef struct {
+	Dst    Address
+	Src    Address
+	ID     uint32
+	Method uint8
+	TTL    uint8
+	ToS    uint8
+	// Fields holds the checksum/id for icmp or the ports for udp and tcp
+	Fields uint32
+	Size   uint16
+	MTU    uint16
+}
+
+// DealiasProbe is a probe sent by a dealias
+type DealiasProbe struct {
+	Def        uint32
+	Tx         syscall.Timeval
+	ReplyCount uint16
+	IPID       uint16
+	Seq        uint32
+	Replies    []DealiasReply
+}
+
+// DealiasReply is a reply to a dealias probe
+type DealiasReply struct {
+	Src          Address
+	Rx           syscall.Timeval
+	IPID         uint16
+	TTL          uint8
+	ICMPTypeCode uint16
+	QuotedTTL    uint8
+	ICMPExt      ICMPExtensionList
+	Proto        uint8
+	TCPFlags     uint8
+	IPID32       uint32
+	Flags        uint8
+}
+
+func readDealias(f io.Reader) (Dealias, error) {
+	var d Dealias
+	addrs := NewAddressRefs()
+	var err error
+	d.Flags, err = readDealiasFlags(f)
+	if err != nil {
+		return d, err
+	}
+	var defs uint32
+	switch d.Flags.Method {
+	case DealiasMercator:
+		d.Options, err = readDealiasMercator(f)
+		defs = 1
+	case DealiasAlly:
+		d.Options, err = readDealiasAlly(f)
+		defs = 2
+	case DealiasRadargun:
+		d.Options, err = readDealiasRadargun(f)
+		defs = d.Options.ProbeDefCount
+	case DealiasPrefixscan:
+		d.Options, err = readDealiasPrefixscan(f, addrs)
+		defs = d.Options.ProbeDefCount
+	case DealiasBump:
+		d.Options, err = readDealiasBump(f)
+		defs = 2
+	default:
+		return d, fmt.Errorf("Unsupported dealias method: %d", d.Flags.Method)
+	}
+	if err != nil {
+		return d, err
+	}
+	// The counts are 32 bits, they are only trusted as far as there are
+	// definitions and probes to read
+	for i := uint32(0); i < defs; i++ {
+		def, err := readDealiasProbeDef(f, addrs)
+		if err != nil {
+			return d, err
+		}
+		d.ProbeDefs = append(d.ProbeDefs, def)
+	}
+	for i := uint32(0); i < d.Flags.ProbeCount; i++ {
+		probe, err := readDealiasProbe(f, addrs)
+		if err != nil {
+			return d, err
+		}
+		d.Probes = append(d.Probes, probe)
+	}
+	return d, nil
+}
+
+func readDealiasFlags(f io.Reader) (DealiasFlags, error) {
+	df := DealiasFlags{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return df, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			df.ListID, err = readUint32(p)
+		case 2:
+			df.CycleID, err = readUint32(p)
+		case 3:
+			df.StartTime, err = readTimeVal(p)
+		case 4:
+			var m uint8
+			m, err = readUint8(p)
+			df.Method = DealiasMethod(m)
+		case 5:
+			var r uint8
+			r, err = readUint8(p)
+			df.Result = DealiasResult(r)
+		case 6:
+			df.ProbeCount, err = readUint32(p)
+		case 7:
+			df.UserID, err = readUint32(p)
+		default:
+			// Parameters added after these are not needed
+			return df, nil
+		}
+		if err != nil {
+			return df, err
+		}
+	}
+	return df, nil
+}
+
+func readDealiasMercator(f io.Reader) (DealiasOptions, error) {
+	do := DealiasOptions{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return do, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			var a uint8
+			a, err = readUint8(p)
+			do.Attempts = uint16(a)
+		case 2:
+			do.WaitTimeout, err = readUint8(p)
+		default:
+			return do, nil
+		}
+		if err != nil {
+			return do, err
+		}
+	}
+	return do, nil
+}
+
+func readDealiasAlly(f io.Reader) (DealiasOptions, error) {
+	do := DealiasOptions{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return do, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			do.WaitProbe, err = readUint16(p)
+		case 2:
+			do.WaitTimeout, err = readUint8(p)
+		case 3:
+			var a uint8
+			a, err = readUint8(p)
+			do.Attempts = uint16(a)
+		case 4:
+			do.Fudge, err = readUint16(p)
+		case 5:
+			do.Count, err = readUint16(p)
+		default:
+			return do, nil
+		}
+		if err != nil {
+			return do, err
+		}
+	}
+	return do, nil
+}
+
+func readDealiasRadargun(f io.Reader) (DealiasOptions, error) {
+	do := DealiasOptions{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return do, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			do.ProbeDefCount, err = readUint32(p)
+		case 2:
+			do.Attempts, err = readUint16(p)
+		case 3:
+			do.WaitProbe, err = readUint16(p)
+		case 4:
+			do.WaitRound, err = readUint32(p)
+		case 5:
+			do.WaitTimeout, err = readUint8(p)
+		case 6:
+			do.Flags, err = readUint8(p)
+		default:
+			return do, nil
+		}
+		if err != nil {
+			return do, err
+		}
+	}
+	return do, nil
+}
+
+func readAddressList(f io.Reader, addrs *AddressRefs, referenced bool) ([]Address, error) {
+	count, err := readUint16(f)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Address, count)
+	for i := range ret {
+		if referenced {
+			ret[i], err = readReferencedAddress(f, addrs)
+		} else {
+			ret[i], err = readAddress(f, addrs)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func readDealiasPrefixscan(f io.Reader, addrs *AddressRefs) (DealiasOptions, error) {
+	do := DealiasOptions{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return do, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			do.A, err = readReferencedAddress(p, addrs)
+		case 2:
+			do.B, err = readReferencedAddress(p, addrs)
+		case 3:
+			do.AB, err = readReferencedAddress(p, addrs)
+		case 4:
+			do.XS, err = readAddressList(p, addrs, true)
+		case 5:
+			do.Prefix, err = readUint8(p)
+		case 6:
+			var a uint8
+			a, err = readUint8(p)
+			do.Attempts = uint16(a)
+		case 7:
+			do.Fudge, err = readUint16(p)
+		case 8:
+			do.WaitProbe, err = readUint16(p)
+		case 9:
+			do.WaitTimeout, err = readUint8(p)
+		case 10:
+			var c uint16
+			c, err = readUint16(p)
+			do.ProbeDefCount = uint32(c)
+		case 11:
+			do.Flags, err = readUint8(p)
+		case 12:
+			do.ReplyCount, err = readUint8(p)
+		case 13:
+			do.A, err = readAddress(p, addrs)
+		case 14:
+			do.B, err = readAddress(p, addrs)
+		case 15:
+			do.AB, err = readAddress(p, addrs)
+		case 16:
+			do.XS, err = readAddressList(p, addrs, false)
+		default:
+			return do, nil
+		}
+		if err != nil {
+			return do, err
+		}
+	}
+	return do, nil
+}
+
+func readDealiasBump(f io.Reader) (DealiasOptions, error) {
+	do := DealiasOptions{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return do, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			do.WaitProbe, err = readUint16(p)
+		case 2:
+			do.BumpLimit, err = readUint16(p)
+		case 3:
+			var a uint8
+			a, err = readUint8(p)
+			do.Attempts = uint16(a)
+		default:
+			return do, nil
+		}
+		if err != nil {
+			return do, err
+		}
+	}
+	return do, nil
+}
+
+func readDealiasProbeDef(f io.Reader, addrs *AddressRefs) (DealiasProbeDef, error) {
+	pd := DealiasProbeDef{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return pd, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			pd.Dst, err = readReferencedAddress(p, addrs)
+		case 2:
+			pd.Src, err = readReferencedAddress(p, addrs)
+		case 3:
+			pd.ID, err = readUint32(p)
+		case 4:
+			pd.Method, err = readUint8(p)
+		case 5:
+			pd.TTL, err = readUint8(p)
+		case 6:
+			pd.ToS, err = readUint8(p)
+		case 7:
+			pd.Fields, err = readUint32(p)
+		case 8:
+			pd.Size, err = readUint16(p)
+		case 9:
+			pd.MTU, err = readUint16(p)
+		case 10:
+			pd.Dst, err = readAddress(p, addrs)
+		case 11:
+			pd.Src, err = readAddress(p, addrs)
+		default:
+			return pd, nil
+		}
+		if err != nil {
+			return pd, err
+		}
+	}
+	return pd, nil
+}
+
+func readDealiasProbe(f io.Reader, addrs *AddressRefs) (DealiasProbe, error) {
+	dp := DealiasProbe{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return dp, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			dp.Def, err = readUint32(p)
+		case 2:
+			dp.Tx, err = readTimeVal(p)
+		case 3:
+			dp.ReplyCount, err = readUint16(p)
+		case 4:
+			dp.IPID, err = readUint16(p)
+		case 5:
+			dp.Seq, err = readUint32(p)
+		default:
+			break params
+		}
+		if err != nil {
+			return dp, err
+		}
+	}
+	dp.Replies = make([]DealiasReply, dp.ReplyCount)
+	for i := range dp.Replies {
+		dp.Replies[i], err = readDealiasReply(f, addrs)
+		if err != nil {
+			return dp, err
+		}
+	}
+	return dp, nil
+}
+
+func readDealiasReply(f io.Reader, addrs *AddressRefs) (DealiasReply, error) {
+	dr := DealiasReply{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return dr, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			dr.Src, err = readReferencedAddress(p, addrs)
+		case 2:
+			dr.Rx, err = readTimeVal(p)
+		case 3:
+			dr.IPID, err = readUint16(p)
+		case 4:
+			dr.TTL, err = readUint8(p)
+		case 5:
+			dr.ICMPTypeCode, err = readUint16(p)
+		case 6:
+			dr.QuotedTTL, err = readUint8(p)
+		case 7:
+			dr.ICMPExt, err = readICMPExtensionList(p)
+		case 8:
+			dr.Proto, err = readUint8(p)
+		case 9:
+			dr.TCPFlags, err = readUint8(p)
+		case 10:
+			dr.Src, err = readAddress(p, addrs)
+		case 11:
+			dr.IPID32, err = readUint32(p)
+		case 12:
+			dr.Flags, err = readUint8(p)
+		default:
+			return dr, nil
+		}
+		if err != nil {
+			return dr, err
+		}
+	}
+	return dr, nil
+}
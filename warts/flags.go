@@ -28,6 +28,7 @@
 package warts
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 )
@@ -54,6 +55,34 @@ func getFlags(f io.Reader, first uint8) ([]uint8, error) {
 	}
 }
 
+// readParams reads a parameter block. The block is read whole using its
+// length so flags the reader does not know can not desynchronise the stream
+func readParams(f io.Reader) ([]uint8, *bytes.Reader, error) {
+	first, err := readOne(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	flags, err := getFlags(f, first)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(flags) == 0 {
+		return nil, bytes.NewReader(nil), nil
+	}
+	length, err := readUint16(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	if length == 0 {
+		return flags, bytes.NewReader(nil), nil
+	}
+	block, err := readBytes(f, int(length))
+	if err != nil {
+		return nil, nil, err
+	}
+	return flags, bytes.NewReader(block), nil
+}
+
 func readListFlags(f io.Reader) (ListFlags, error) {
 	first := make([]byte, 1)
 	var lf ListFlags
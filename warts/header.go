@@ -42,13 +42,13 @@ type Header struct {
 func readHeader(f io.Reader) (Header, error) {
 	head := make([]byte, 8)
 	var wh Header
-	n, err := f.Read(head)
+	_, err := io.ReadFull(f, head)
+	if err == io.ErrUnexpectedEOF {
+		return wh, fmt.Errorf("ReadHeader, short read")
+	}
 	if err != nil {
 		return wh, err
 	}
-	if n != 8 {
-		return wh, fmt.Errorf("ReadHeader, short read")
-	}
 	wh.Magic |= uint16(head[0]) << 8
 	wh.Magic |= uint16(head[1])
 	if wh.Magic != 0x1205 {
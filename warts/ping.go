@@ -767,7 +767,7 @@ func readPingFlags(f io.Reader, addrs *AddressRefs) (PingFlags, error) {
 			if err != nil {
 				return pf, err
 			}
-			pf.TS = make([]Address, num)
+			pf.TS = make([]Address, 0, num)
 			for i := uint8(0); i < num; i++ {
 				addr, err := readAddress(f, addrs)
 				if err != nil {
@@ -0,0 +1,176 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// ObjectError is an error decoding the warts object that starts at Offset
+type ObjectError struct {
+	Offset int64
+	Type   WartsT
+	Err    error
+}
+
+func (oe *ObjectError) Error() string {
+	return fmt.Sprintf("warts object of type %d at offset %d: %v", oe.Type, oe.Offset, oe.Err)
+}
+
+// Reader decodes warts objects one at a time, so files of any size can be
+// read without holding them in memory
+type Reader struct {
+	r      *bufio.Reader
+	offset int64
+	at     int64
+	head   Header
+	obj    interface{}
+	err    error
+}
+
+// NewReader creates a Reader that reads from r. Input compressed with
+// gzip or bzip2 is decompressed
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(3)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(gz)
+	case bytes.Equal(magic, []byte("BZh")):
+		br = bufio.NewReader(bzip2.NewReader(br))
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next decodes the next object. It returns false at the end of the input
+// or when an object can not be decoded, Err tells which
+func (r *Reader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	r.obj = nil
+	r.at = r.offset
+	head, err := readHeader(r.r)
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		r.err = &ObjectError{Offset: r.at, Err: err}
+		return false
+	}
+	r.head = head
+	obj, err := readObjectBody(r.r, head)
+	r.offset += 8 + int64(head.Length)
+	if err != nil {
+		r.err = &ObjectError{Offset: r.at, Type: head.Type, Err: err}
+		return false
+	}
+	r.obj = obj
+	return true
+}
+
+// Err returns the error that stopped Next, nil at the end of the input
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Offset returns where the current object starts in the decompressed input
+func (r *Reader) Offset() int64 {
+	return r.at
+}
+
+// Type returns the type of the current object
+func (r *Reader) Type() WartsT {
+	return r.head.Type
+}
+
+// Object returns the current object. Types which are not decoded are
+// returned as their Header
+func (r *Reader) Object() interface{} {
+	return r.obj
+}
+
+// Ping returns the current object if it is a Ping
+func (r *Reader) Ping() (Ping, bool) {
+	p, ok := r.obj.(Ping)
+	return p, ok
+}
+
+// Traceroute returns the current object if it is a Traceroute
+func (r *Reader) Traceroute() (Traceroute, bool) {
+	t, ok := r.obj.(Traceroute)
+	return t, ok
+}
+
+// Tracelb returns the current object if it is a Tracelb
+func (r *Reader) Tracelb() (Tracelb, bool) {
+	t, ok := r.obj.(Tracelb)
+	return t, ok
+}
+
+// Dealias returns the current object if it is a Dealias
+func (r *Reader) Dealias() (Dealias, bool) {
+	d, ok := r.obj.(Dealias)
+	return d, ok
+}
+
+// Sniff returns the current object if it is a Sniff
+func (r *Reader) Sniff() (Sniff, bool) {
+	s, ok := r.obj.(Sniff)
+	return s, ok
+}
+
+// List returns the current object if it is a List
+func (r *Reader) List() (List, bool) {
+	l, ok := r.obj.(List)
+	return l, ok
+}
+
+// CycleStart returns the current object if it is a CycleStart
+func (r *Reader) CycleStart() (CycleStart, bool) {
+	c, ok := r.obj.(CycleStart)
+	return c, ok
+}
+
+// CycleStop returns the current object if it is a CycleStop
+func (r *Reader) CycleStop() (CycleStop, bool) {
+	c, ok := r.obj.(CycleStop)
+	return c, ok
+}
@@ -0,0 +1,161 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+type readObj struct {
+	typ    warts.WartsT
+	offset int64
+}
+
+func readAll(t *testing.T, r io.Reader) ([]readObj, error) {
+	wr, err := warts.NewReader(r)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	var objs []readObj
+	for wr.Next() {
+		objs = append(objs, readObj{typ: wr.Type(), offset: wr.Offset()})
+		switch wr.Type() {
+		case warts.PingT:
+			if _, ok := wr.Ping(); !ok {
+				t.Fatalf("Ping at %d is a %T", wr.Offset(), wr.Object())
+			}
+		case warts.MDATracerouteT:
+			if _, ok := wr.Tracelb(); !ok {
+				t.Fatalf("Tracelb at %d is a %T", wr.Offset(), wr.Object())
+			}
+		case warts.SniffT:
+			if _, ok := wr.Sniff(); !ok {
+				t.Fatalf("Sniff at %d is a %T", wr.Offset(), wr.Object())
+			}
+		}
+		if _, ok := wr.Traceroute(); ok && wr.Type() != warts.TracerouteT {
+			t.Fatalf("Traceroute accessor succeeded for type %d", wr.Type())
+		}
+	}
+	return objs, wr.Err()
+}
+
+var mixedTypes = []warts.WartsT{
+	warts.ListT, warts.CycleStartT, warts.AddressT, warts.MDATracerouteT,
+	warts.TBitT, warts.AliasResolutionT, warts.SniffT, 0x40,
+	warts.PingT, warts.CycleStopT,
+}
+
+func types(objs []readObj) []warts.WartsT {
+	var ret []warts.WartsT
+	for _, o := range objs {
+		ret = append(ret, o.typ)
+	}
+	return ret
+}
+
+func TestReader(t *testing.T) {
+	content, err := ioutil.ReadFile("../doc/test_mixed.warts")
+	if err != nil {
+		t.Fatal("Reader could not read file")
+	}
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(content)
+	w.Close()
+	bz, err := os.Open("../doc/test_mixed.warts.bz2")
+	if err != nil {
+		t.Fatal("Reader could not open file")
+	}
+	defer bz.Close()
+	for _, test := range []struct {
+		desc string
+		in   io.Reader
+	}{
+		{desc: "plain", in: bytes.NewReader(content)},
+		{desc: "gzip", in: &gz},
+		{desc: "bzip2", in: bz},
+	} {
+		objs, err := readAll(t, test.in)
+		if err != nil {
+			t.Fatalf("%s: Reader failed: %v", test.desc, err)
+		}
+		if got := types(objs); !reflect.DeepEqual(got, mixedTypes) {
+			t.Fatalf("%s: got types %v, expected %v", test.desc, got, mixedTypes)
+		}
+		if objs[0].offset != 0 || objs[1].offset != 35 {
+			t.Fatalf("%s: wrong offsets %v", test.desc, objs[:2])
+		}
+	}
+}
+
+func TestReaderEmpty(t *testing.T) {
+	objs, err := readAll(t, bytes.NewReader(nil))
+	if err != nil || len(objs) != 0 {
+		t.Fatalf("Reading nothing returned %v, %v", objs, err)
+	}
+}
+
+func TestReaderErrorOffset(t *testing.T) {
+	content, err := ioutil.ReadFile("../doc/test_mixed.warts")
+	if err != nil {
+		t.Fatal("ReaderErrorOffset could not read file")
+	}
+	objs, err := readAll(t, bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Break the magic of the dealias
+	bad := objs[5].offset
+	corrupt := append([]byte(nil), content...)
+	corrupt[bad] = 0
+	got, err := readAll(t, bytes.NewReader(corrupt))
+	if len(got) != 5 {
+		t.Fatalf("Read %d objects before the error, expected 5", len(got))
+	}
+	oe, ok := err.(*warts.ObjectError)
+	if !ok {
+		t.Fatalf("Got error %v, expected an ObjectError", err)
+	}
+	if oe.Offset != bad {
+		t.Fatalf("Error at offset %d, expected %d", oe.Offset, bad)
+	}
+	// A body that ends early is reported at the start of its object
+	_, err = readAll(t, bytes.NewReader(content[:bad+12]))
+	if oe, ok := err.(*warts.ObjectError); !ok || oe.Offset != bad || oe.Type != warts.AliasResolutionT {
+		t.Fatalf("Got error %v for a truncated dealias at %d", err, bad)
+	}
+}
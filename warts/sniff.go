@@ -0,0 +1,176 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts
+
+import (
+	"io"
+	"syscall"
+)
+
+// Sniff is a warts sniff, the packets addressed to a vantage point
+// that it captured
+type Sniff struct {
+	Flags   SniffFlags
+	Packets []SniffPacket
+}
+
+// SniffFlags are the parameters of a warts sniff
+type SniffFlags struct {
+	ListID     uint32
+	CycleID    uint32
+	UserID     uint32
+	Src        Address
+	Start      syscall.Timeval
+	Finish     syscall.Timeval
+	StopReason SniffStopReason
+	LimitPktc  uint32
+	LimitTime  uint16
+	PacketC    uint32
+	ICMPID     uint16
+}
+
+// SniffStopReason is why a sniff stopped capturing
+type SniffStopReason uint8
+
+const (
+	// SniffStopNone means the sniff has not stopped
+	SniffStopNone SniffStopReason = iota
+	// SniffStopDone means the sniff reached one of its limits
+	SniffStopDone
+	// SniffStopHalted means the sniff was halted
+	SniffStopHalted
+	// SniffStopError means the sniff failed
+	SniffStopError
+)
+
+func (sr SniffStopReason) String() string {
+	reasons := []string{
+		"none",
+		"done",
+		"halted",
+		"error",
+	}
+	if int(sr) >= len(reasons) {
+		return "unknown"
+	}
+	return reasons[sr]
+}
+
+// SniffPacket is a packet captured by a sniff
+type SniffPacket struct {
+	Time syscall.Timeval
+	Data []byte
+}
+
+func readSniff(f io.Reader) (Sniff, error) {
+	var s Sniff
+	addrs := NewAddressRefs()
+	var err error
+	s.Flags, err = readSniffFlags(f, addrs)
+	if err != nil {
+		return s, err
+	}
+	for i := uint32(0); i < s.Flags.PacketC; i++ {
+		p, err := readSniffPacket(f)
+		if err != nil {
+			return s, err
+		}
+		s.Packets = append(s.Packets, p)
+	}
+	return s, nil
+}
+
+func readSniffFlags(f io.Reader, addrs *AddressRefs) (SniffFlags, error) {
+	sf := SniffFlags{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return sf, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			sf.ListID, err = readUint32(p)
+		case 2:
+			sf.CycleID, err = readUint32(p)
+		case 3:
+			sf.UserID, err = readUint32(p)
+		case 4:
+			sf.Src, err = readAddress(p, addrs)
+		case 5:
+			sf.Start, err = readTimeVal(p)
+		case 6:
+			sf.Finish, err = readTimeVal(p)
+		case 7:
+			var sr uint8
+			sr, err = readUint8(p)
+			sf.StopReason = SniffStopReason(sr)
+		case 8:
+			sf.LimitPktc, err = readUint32(p)
+		case 9:
+			sf.LimitTime, err = readUint16(p)
+		case 10:
+			sf.PacketC, err = readUint32(p)
+		case 11:
+			sf.ICMPID, err = readUint16(p)
+		default:
+			// Flags are in order, the rest of the block is unknown
+			return sf, nil
+		}
+		if err != nil {
+			return sf, err
+		}
+	}
+	return sf, nil
+}
+
+func readSniffPacket(f io.Reader) (SniffPacket, error) {
+	sp := SniffPacket{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return sp, err
+	}
+	var length uint16
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			sp.Time, err = readTimeVal(p)
+		case 2:
+			length, err = readUint16(p)
+		case 3:
+			if length > 0 {
+				sp.Data, err = readBytes(p, int(length))
+			}
+		default:
+			return sp, nil
+		}
+		if err != nil {
+			return sp, err
+		}
+	}
+	return sp, nil
+}
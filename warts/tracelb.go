@@ -0,0 +1,408 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts
+
+import (
+	"io"
+	"syscall"
+)
+
+// Tracelb is a warts tracelb, the result of an MDA traceroute
+type Tracelb struct {
+	Flags TracelbFlags
+	Nodes []TracelbNode
+	Links []TracelbLink
+}
+
+// TracelbFlags are the parameters of a warts tracelb
+type TracelbFlags struct {
+	ListID      uint32
+	CycleID     uint32
+	SrcID       Address
+	DstID       Address
+	StartTime   syscall.Timeval
+	SourcePort  uint16
+	DestPort    uint16
+	ProbeSize   uint16
+	TraceType   TracelbType
+	FirstHop    uint8
+	WaitTimeout uint8
+	WaitProbe   uint8
+	Attempts    uint8
+	Confidence  uint8
+	ToS         uint8
+	NodeCount   uint16
+	LinkCount   uint16
+	ProbeCount  uint32
+	ProbeCMax   uint32
+	GapLimit    uint8
+	Src         Address
+	Dst         Address
+	UserID      uint32
+	TraceFlags  uint8
+	Router      Address
+}
+
+// TracelbType is the probe method of a tracelb
+type TracelbType uint8
+
+func (tt TracelbType) String() string {
+	types := []string{
+		"NULL",
+		"udp-dport",
+		"icmp-echo",
+		"udp-sport",
+		"tcp-sport",
+		"tcp-ack-sport",
+	}
+	if int(tt) >= len(types) {
+		return "NULL"
+	}
+	return types[tt]
+}
+
+// TracelbNode is an interface discovered by a tracelb
+type TracelbNode struct {
+	Addr      Address
+	Flags     uint8
+	LinkCount uint16
+	QTTL      uint8
+	Name      string
+}
+
+// TracelbLink is a link between two nodes of a tracelb.
+// From and To are indexes into the Nodes of the Tracelb,
+// HasTo is false when the link leads to no response
+type TracelbLink struct {
+	From      uint16
+	To        uint16
+	HasTo     bool
+	HopCount  uint8
+	ProbeSets []TracelbProbeSet
+}
+
+// TracelbProbeSet is the set of probes sent at one hop of a link
+type TracelbProbeSet struct {
+	ProbeCount uint16
+	Probes     []TracelbProbe
+}
+
+// TracelbProbe is a single probe of a tracelb
+type TracelbProbe struct {
+	Tx         syscall.Timeval
+	FlowID     uint16
+	TTL        uint8
+	Attempt    uint8
+	ReplyCount uint16
+	Replies    []TracelbReply
+}
+
+// TracelbReply is a reply to a tracelb probe
+type TracelbReply struct {
+	Rx           syscall.Timeval
+	IPID         uint16
+	TTL          uint8
+	Flags        uint8
+	ICMPTypeCode uint16
+	TCPFlags     uint8
+	ICMPExt      ICMPExtensionList
+	QuotedTTL    uint8
+	QuotedToS    uint8
+	From         Address
+}
+
+func readTracelb(f io.Reader) (Tracelb, error) {
+	var tl Tracelb
+	addrs := NewAddressRefs()
+	var err error
+	tl.Flags, err = readTracelbFlags(f, addrs)
+	if err != nil {
+		return tl, err
+	}
+	tl.Nodes = make([]TracelbNode, tl.Flags.NodeCount)
+	for i := range tl.Nodes {
+		tl.Nodes[i], err = readTracelbNode(f, addrs)
+		if err != nil {
+			return tl, err
+		}
+	}
+	tl.Links = make([]TracelbLink, tl.Flags.LinkCount)
+	for i := range tl.Links {
+		tl.Links[i], err = readTracelbLink(f, addrs)
+		if err != nil {
+			return tl, err
+		}
+	}
+	return tl, nil
+}
+
+func readTracelbFlags(f io.Reader, addrs *AddressRefs) (TracelbFlags, error) {
+	tf := TracelbFlags{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return tf, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			tf.ListID, err = readUint32(p)
+		case 2:
+			tf.CycleID, err = readUint32(p)
+		case 3:
+			tf.SrcID, err = readReferencedAddress(p, addrs)
+		case 4:
+			tf.DstID, err = readReferencedAddress(p, addrs)
+		case 5:
+			tf.StartTime, err = readTimeVal(p)
+		case 6:
+			tf.SourcePort, err = readUint16(p)
+		case 7:
+			tf.DestPort, err = readUint16(p)
+		case 8:
+			tf.ProbeSize, err = readUint16(p)
+		case 9:
+			var t uint8
+			t, err = readUint8(p)
+			tf.TraceType = TracelbType(t)
+		case 10:
+			tf.FirstHop, err = readUint8(p)
+		case 11:
+			tf.WaitTimeout, err = readUint8(p)
+		case 12:
+			tf.WaitProbe, err = readUint8(p)
+		case 13:
+			tf.Attempts, err = readUint8(p)
+		case 14:
+			tf.Confidence, err = readUint8(p)
+		case 15:
+			tf.ToS, err = readUint8(p)
+		case 16:
+			tf.NodeCount, err = readUint16(p)
+		case 17:
+			tf.LinkCount, err = readUint16(p)
+		case 18:
+			tf.ProbeCount, err = readUint32(p)
+		case 19:
+			tf.ProbeCMax, err = readUint32(p)
+		case 20:
+			tf.GapLimit, err = readUint8(p)
+		case 21:
+			tf.Src, err = readAddress(p, addrs)
+		case 22:
+			tf.Dst, err = readAddress(p, addrs)
+		case 23:
+			tf.UserID, err = readUint32(p)
+		case 24:
+			tf.TraceFlags, err = readUint8(p)
+		case 25:
+			tf.Router, err = readAddress(p, addrs)
+		default:
+			// Parameters added after these are not needed
+			break params
+		}
+		if err != nil {
+			return tf, err
+		}
+	}
+	return tf, nil
+}
+
+func readTracelbNode(f io.Reader, addrs *AddressRefs) (TracelbNode, error) {
+	tn := TracelbNode{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return tn, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			tn.Addr, err = readReferencedAddress(p, addrs)
+		case 2:
+			tn.Flags, err = readUint8(p)
+		case 3:
+			tn.LinkCount, err = readUint16(p)
+		case 4:
+			tn.QTTL, err = readUint8(p)
+		case 5:
+			tn.Addr, err = readAddress(p, addrs)
+		case 6:
+			tn.Name, err = getString(p)
+		default:
+			// Parameters added after these are not needed
+			break params
+		}
+		if err != nil {
+			return tn, err
+		}
+	}
+	return tn, nil
+}
+
+func readTracelbLink(f io.Reader, addrs *AddressRefs) (TracelbLink, error) {
+	tl := TracelbLink{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return tl, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			tl.From, err = readUint16(p)
+		case 2:
+			tl.To, err = readUint16(p)
+			tl.HasTo = true
+		case 3:
+			tl.HopCount, err = readUint8(p)
+		default:
+			// Parameters added after these are not needed
+			break params
+		}
+		if err != nil {
+			return tl, err
+		}
+	}
+	tl.ProbeSets = make([]TracelbProbeSet, tl.HopCount)
+	for i := range tl.ProbeSets {
+		tl.ProbeSets[i], err = readTracelbProbeSet(f, addrs)
+		if err != nil {
+			return tl, err
+		}
+	}
+	return tl, nil
+}
+
+func readTracelbProbeSet(f io.Reader, addrs *AddressRefs) (TracelbProbeSet, error) {
+	ps := TracelbProbeSet{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return ps, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			ps.ProbeCount, err = readUint16(p)
+		default:
+			// Parameters added after these are not needed
+			break params
+		}
+		if err != nil {
+			return ps, err
+		}
+	}
+	ps.Probes = make([]TracelbProbe, ps.ProbeCount)
+	for i := range ps.Probes {
+		ps.Probes[i], err = readTracelbProbe(f, addrs)
+		if err != nil {
+			return ps, err
+		}
+	}
+	return ps, nil
+}
+
+func readTracelbProbe(f io.Reader, addrs *AddressRefs) (TracelbProbe, error) {
+	tp := TracelbProbe{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return tp, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			tp.Tx, err = readTimeVal(p)
+		case 2:
+			tp.FlowID, err = readUint16(p)
+		case 3:
+			tp.TTL, err = readUint8(p)
+		case 4:
+			tp.Attempt, err = readUint8(p)
+		case 5:
+			tp.ReplyCount, err = readUint16(p)
+		default:
+			// Parameters added after these are not needed
+			break params
+		}
+		if err != nil {
+			return tp, err
+		}
+	}
+	tp.Replies = make([]TracelbReply, tp.ReplyCount)
+	for i := range tp.Replies {
+		tp.Replies[i], err = readTracelbReply(f, addrs)
+		if err != nil {
+			return tp, err
+		}
+	}
+	return tp, nil
+}
+
+func readTracelbReply(f io.Reader, addrs *AddressRefs) (TracelbReply, error) {
+	tr := TracelbReply{}
+	flags, p, err := readParams(f)
+	if err != nil {
+		return tr, err
+	}
+params:
+	for _, flag := range flags {
+		switch flag {
+		case 1:
+			tr.Rx, err = readTimeVal(p)
+		case 2:
+			tr.IPID, err = readUint16(p)
+		case 3:
+			tr.TTL, err = readUint8(p)
+		case 4:
+			tr.Flags, err = readUint8(p)
+		case 5:
+			tr.ICMPTypeCode, err = readUint16(p)
+		case 6:
+			tr.TCPFlags, err = readUint8(p)
+		case 7:
+			tr.ICMPExt, err = readICMPExtensionList(p)
+		case 8:
+			tr.QuotedTTL, err = readUint8(p)
+		case 9:
+			tr.QuotedToS, err = readUint8(p)
+		case 10:
+			tr.From, err = readReferencedAddress(p, addrs)
+		case 11:
+			tr.From, err = readAddress(p, addrs)
+		default:
+			// Parameters added after these are not needed
+			break params
+		}
+		if err != nil {
+			return tr, err
+		}
+	}
+	return tr, nil
+}
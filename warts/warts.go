@@ -28,6 +28,7 @@
 package warts
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
@@ -51,7 +52,7 @@ const (
 	TracerouteT = 0x06
 	// PingT is a the ping type
 	PingT = 0x07
-	// MDATracerouteT is the mdatracerotue type
+	// MDATracerouteT is the mdatracerotue type, scamper's tracelb
 	MDATracerouteT = 0x08
 	// AliasResolutionT is the alias resolution type
 	AliasResolutionT = 0x09
@@ -66,8 +67,28 @@ const (
 	dummy  = 0x00
 )
 
-func parseNext(f io.Reader) (interface{}, error) {
-	head, err := readHeader(f)
+// readObjectBody decodes the object that follows head
+func readObjectBody(f io.Reader, head Header) (interface{}, error) {
+	// Each object is decoded from its own body so that types which are
+	// not decoded, or fields a reader does not know, are skipped and the
+	// next object starts where its header says. The length is not trusted
+	// for the allocation, the body only grows as far as the input goes
+	var body bytes.Buffer
+	n, err := body.ReadFrom(io.LimitReader(f, int64(head.Length)))
+	if err != nil {
+		return nil, err
+	}
+	if n < int64(head.Length) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	obj, err := readObject(head, &body)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return obj, err
+}
+
+func readObject(head Header, f io.Reader) (interface{}, error) {
 	switch head.Type {
 	case ListT:
 		return readList(f)
@@ -76,20 +97,19 @@ func parseNext(f io.Reader) (interface{}, error) {
 	case CycleStopT:
 		return readCycleStop(f)
 	case AddressT:
+		return readAddressObject(f)
 	case TracerouteT:
 		return readTraceroute(f)
 	case PingT:
 		return readPing(f)
 	case MDATracerouteT:
+		return readTracelb(f)
 	case AliasResolutionT:
-	case NeighborDiscoveryT:
-	case TBitT:
-	case StingT:
+		return readDealias(f)
 	case SniffT:
+		return readSniff(f)
 	}
-	if err != nil {
-		return nil, err
-	}
+	// NeighborDiscoveryT, TBitT, StingT and anything newer are skipped
 	return head, nil
 }
 
@@ -101,19 +121,17 @@ func Parse(data []byte, objs []WartsT) ([]interface{}, error) {
 		types[obj] = true
 	}
 	var ret []interface{}
-	buf := bytes.NewBuffer(data)
-	for {
-		obj, err := parseNext(buf)
-		if err == io.EOF {
-			return ret, nil
-		}
-		if err != nil {
-			return nil, fmt.Errorf("Failed to parse warts: %v, %v", err, ret)
-		}
+	r := &Reader{r: bufio.NewReader(bytes.NewReader(data))}
+	for r.Next() {
+		obj := r.Object()
 		if types[getWartsT(obj)] {
 			ret = append(ret, obj)
 		}
 	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to parse warts: %v", err)
+	}
+	return ret, nil
 }
 
 func getWartsT(obj interface{}) WartsT {
@@ -126,6 +144,14 @@ func getWartsT(obj interface{}) WartsT {
 		return CycleStopT
 	case Traceroute:
 		return TracerouteT
+	case Tracelb:
+		return MDATracerouteT
+	case Dealias:
+		return AliasResolutionT
+	case Sniff:
+		return SniffT
+	case Address:
+		return AddressT
 	case List:
 		return ListT
 	default:
@@ -28,7 +28,11 @@
 package warts_test
 
 import (
+	"bytes"
+	"encoding/binary"
 	"io/ioutil"
+	"reflect"
+	"runtime"
 	"testing"
 
 	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
@@ -135,6 +139,251 @@ func TestTraceFirstHop(t *testing.T) {
 	}
 }
 
+// params encodes a warts parameter block with the given flags set
+func params(flags []int, data ...[]byte) []byte {
+	var max int
+	for _, f := range flags {
+		if f > max {
+			max = f
+		}
+	}
+	fb := make([]byte, (max+6)/7)
+	for _, f := range flags {
+		fb[(f-1)/7] |= 1 << uint((f-1)%7)
+	}
+	for i := 0; i < len(fb)-1; i++ {
+		fb[i] |= 0x80
+	}
+	body := bytes.Join(data, nil)
+	var buf bytes.Buffer
+	buf.Write(fb)
+	binary.Write(&buf, binary.BigEndian, uint16(len(body)))
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+func addr(ip ...byte) []byte {
+	return append([]byte{4, 1}, ip...)
+}
+
+func u8(v uint8) []byte { return []byte{v} }
+
+func u16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return b
+}
+
+func u32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+// tracelbObject builds a tracelb from 10.0.0.1 that splits at 10.0.1.1
+// over 10.0.2.1 and 10.0.2.2 and joins again at 10.0.3.1
+func tracelbObject() []byte {
+	var body bytes.Buffer
+	// Flags from newer scampers are unknown and have to be skipped
+	body.Write(params([]int{9, 16, 17, 21, 22, 23, 30},
+		u8(2), u16(4), u16(4), addr(10, 0, 0, 1), addr(10, 0, 3, 1), u32(77), u32(5)))
+	for _, ip := range [][]byte{{10, 0, 1, 1}, {10, 0, 2, 1}, {10, 0, 2, 2}, {10, 0, 3, 1}} {
+		body.Write(params([]int{4, 5}, u8(1), addr(ip...)))
+	}
+	for _, l := range [][2]uint16{{0, 1}, {0, 2}, {1, 3}, {2, 3}} {
+		body.Write(params([]int{1, 2, 3}, u16(l[0]), u16(l[1]), u8(1)))
+		body.Write(params([]int{1}, u16(1)))
+		body.Write(params([]int{1, 2, 3, 5}, u32(100), u32(0), u16(l[1]), u8(2), u16(1)))
+		// Addresses are referenced in the order they were defined,
+		// src and dst come first
+		body.Write(params([]int{1, 3, 10, 12}, u32(100), u32(1500), u8(60), u32(uint32(l[1])+2), u8(0)))
+	}
+	var obj bytes.Buffer
+	obj.Write([]byte{0x12, 0x05, 0x00, 0x08})
+	binary.Write(&obj, binary.BigEndian, uint32(body.Len()))
+	obj.Write(body.Bytes())
+	return obj.Bytes()
+}
+
+func TestParseTracelb(t *testing.T) {
+	res, err := warts.Parse(tracelbObject(), []warts.WartsT{warts.MDATracerouteT})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(res) != 1 {
+		t.Fatalf("Parse returned %d objects, expected 1", len(res))
+	}
+	wt, ok := res[0].(warts.Tracelb)
+	if !ok {
+		t.Fatalf("Parse returned %T, expected warts.Tracelb", res[0])
+	}
+	if wt.Flags.UserID != 77 || wt.Flags.TraceType.String() != "icmp-echo" {
+		t.Fatalf("Wrong flags: %+v", wt.Flags)
+	}
+	tl := datamodel.ConvertTracelb(wt)
+	if len(tl.Nodes) != 4 || len(tl.Links) != 4 {
+		t.Fatalf("Got %d nodes and %d links, expected 4 and 4", len(tl.Nodes), len(tl.Links))
+	}
+	reply := tl.Links[0].Probes[0].Probes[0].Replies[0]
+	if reply.From != tl.Links[0].To || reply.Rtt.Usec != 1500 {
+		t.Fatalf("Wrong reply: %+v", reply)
+	}
+	expected := [][]uint32{
+		{0x0a000101, 0x0a000201, 0x0a000301},
+		{0x0a000101, 0x0a000202, 0x0a000301},
+	}
+	if paths := tl.Paths(0); !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Paths: got %v, expected %v", paths, expected)
+	}
+	if paths := tl.Paths(1); len(paths) != 1 {
+		t.Fatalf("Paths(1) returned %d paths", len(paths))
+	}
+}
+
+func object(t uint16, body []byte) []byte {
+	var obj bytes.Buffer
+	obj.Write([]byte{0x12, 0x05})
+	binary.Write(&obj, binary.BigEndian, t)
+	binary.Write(&obj, binary.BigEndian, uint32(len(body)))
+	obj.Write(body)
+	return obj.Bytes()
+}
+
+// dealiasObject builds an ally from 192.168.0.1 that found 10.0.0.1
+// and 10.0.0.2 are aliases
+func dealiasObject() []byte {
+	var body bytes.Buffer
+	body.Write(params([]int{3, 4, 5, 6, 7},
+		u32(100), u32(0), u8(2), u8(1), u32(2), u32(9)))
+	body.Write(params([]int{1, 2, 3, 4}, u16(5000), u8(5), u8(5), u16(200)))
+	body.Write(params([]int{3, 4, 10, 11}, u32(0), u8(2), addr(10, 0, 0, 1), addr(192, 168, 0, 1)))
+	body.Write(params([]int{2, 3, 4, 10}, u32(1), u32(1), u8(2), addr(10, 0, 0, 2)))
+	for i, dst := range []uint32{0, 2} {
+		body.Write(params([]int{1, 2, 3, 4}, u32(uint32(i)), u32(100), u32(0), u16(1), u16(7)))
+		// The last flag is unknown and has to be skipped
+		body.Write(params([]int{1, 2, 3, 4, 13}, u32(dst), u32(100), u32(2000), u16(uint16(10+i)), u8(60), u8(0)))
+	}
+	return object(0x09, body.Bytes())
+}
+
+func TestParseDealias(t *testing.T) {
+	content := append(dealiasObject(), tracelbObject()...)
+	res, err := warts.Parse(content, []warts.WartsT{warts.AliasResolutionT, warts.MDATracerouteT})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("Parse returned %d objects, expected 2", len(res))
+	}
+	wd, ok := res[0].(warts.Dealias)
+	if !ok {
+		t.Fatalf("Parse returned %T, expected warts.Dealias", res[0])
+	}
+	if _, ok := res[1].(warts.Tracelb); !ok {
+		t.Fatalf("Parse returned %T after the dealias, expected warts.Tracelb", res[1])
+	}
+	d := datamodel.ConvertDealias(wd)
+	if d.Method != "ally" || d.Result != "aliases" || d.UserId != 9 {
+		t.Fatalf("Wrong dealias: %+v", d)
+	}
+	if d.Src != 0xc0a80001 {
+		t.Fatalf("Src: got %x, expected c0a80001", d.Src)
+	}
+	expected := []uint32{0x0a000001, 0x0a000002}
+	if aliases := d.Aliases(); !reflect.DeepEqual(aliases, expected) {
+		t.Fatalf("Aliases: got %v, expected %v", aliases, expected)
+	}
+	if ipid := d.Probes[1].Replies[0].Ipid; ipid != 11 {
+		t.Fatalf("Reply ipid: got %d, expected 11", ipid)
+	}
+}
+
+func TestParseSniff(t *testing.T) {
+	content, err := ioutil.ReadFile("../doc/test_sniff.warts")
+	if err != nil {
+		t.Fatal("ParseSniff could not read file")
+	}
+	res, err := warts.Parse(content, []warts.WartsT{warts.SniffT})
+	if err != nil {
+		t.Fatalf("ParseSniff failed: %v", err)
+	}
+	if len(res) != 1 {
+		t.Fatalf("ParseSniff returned %d objects, expected 1", len(res))
+	}
+	s, ok := res[0].(warts.Sniff)
+	if !ok {
+		t.Fatalf("ParseSniff returned %T, expected warts.Sniff", res[0])
+	}
+	if s.Flags.UserID != 12 || s.Flags.ICMPID != 0x1234 || s.Flags.Src.String() != "192.168.0.1" {
+		t.Fatalf("Wrong flags: %+v", s.Flags)
+	}
+	if s.Flags.StopReason.String() != "done" {
+		t.Fatalf("Stop reason: got %s, expected done", s.Flags.StopReason)
+	}
+	if len(s.Packets) != 2 {
+		t.Fatalf("Got %d packets, expected 2", len(s.Packets))
+	}
+	for i, src := range [][]byte{{8, 8, 8, 8}, {8, 8, 4, 4}} {
+		data := s.Packets[i].Data
+		if len(data) != 28 || !bytes.Equal(data[12:16], src) {
+			t.Fatalf("Packet %d: wrong data %v", i, data)
+		}
+	}
+}
+
+func TestParseMixed(t *testing.T) {
+	content, err := ioutil.ReadFile("../doc/test_mixed.warts")
+	if err != nil {
+		t.Fatal("ParseMixed could not read file")
+	}
+	all := []warts.WartsT{
+		warts.ListT, warts.CycleStartT, warts.CycleStopT, warts.AddressT,
+		warts.TracerouteT, warts.PingT, warts.MDATracerouteT,
+		warts.AliasResolutionT, warts.SniffT,
+	}
+	res, err := warts.Parse(content, all)
+	if err != nil {
+		t.Fatalf("ParseMixed failed: %v", err)
+	}
+	// The tbit and the object of an unknown type are skipped
+	var got []string
+	for _, obj := range res {
+		got = append(got, reflect.TypeOf(obj).Name())
+	}
+	expected := []string{"List", "CycleStart", "Address", "Tracelb", "Dealias", "Sniff", "Ping", "CycleStop"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("ParseMixed: got %v, expected %v", got, expected)
+	}
+	if a := res[2].(warts.Address); a.String() != "10.0.0.1" {
+		t.Fatalf("Address: got %s, expected 10.0.0.1", a)
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	content, err := ioutil.ReadFile("../doc/test_sniff.warts")
+	if err != nil {
+		t.Fatal("ParseTruncated could not read file")
+	}
+	if _, err := warts.Parse(content[:len(content)-30], []warts.WartsT{warts.SniffT}); err == nil {
+		t.Fatal("Parse of a truncated file succeeded")
+	}
+}
+
+func TestParseBogusLength(t *testing.T) {
+	obj := object(uint16(warts.PingT), []byte{1, 2, 3, 4})
+	// The header claims a body of almost 4GB
+	binary.BigEndian.PutUint32(obj[4:], 0xfffffff0)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := warts.Parse(obj, []warts.WartsT{warts.PingT}); err == nil {
+		t.Fatal("Parse of an object longer than its input succeeded")
+	}
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+		t.Fatalf("Parse allocated %d bytes for a 12 byte input", alloc)
+	}
+}
+
 var result []interface{}
 
 func BenchmarkParse(b *testing.B) {
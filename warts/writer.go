@@ -0,0 +1,492 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"syscall"
+	"time"
+)
+
+// Writer writes pings and traceroutes as a warts file. The list and
+// cycle start are written before the first measurement and the cycle
+// stop when the Writer is closed
+type Writer struct {
+	w       io.Writer
+	list    List
+	cycle   CycleStart
+	started bool
+}
+
+// NewWriter creates a Writer that writes to w. The measurements are put
+// in list and cycle, ids that are not set default to 1
+func NewWriter(w io.Writer, list List, cycle CycleStart) *Writer {
+	if list.ListID == 0 {
+		list.ListID = 1
+	}
+	if list.CListID == 0 {
+		list.CListID = list.ListID
+	}
+	if list.ListName == "" {
+		list.ListName = "default"
+	}
+	if cycle.CycleID == 0 {
+		cycle.CycleID = 1
+	}
+	if cycle.CCycleID == 0 {
+		cycle.CCycleID = cycle.CycleID
+	}
+	cycle.ListID = list.ListID
+	return &Writer{w: w, list: list, cycle: cycle}
+}
+
+// WritePing writes p
+func (w *Writer) WritePing(p Ping) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	p.Flags.ListID = w.list.ListID
+	p.Flags.CycleID = w.cycle.CycleID
+	body, err := encodePing(p)
+	if err != nil {
+		return err
+	}
+	return w.object(PingT, body)
+}
+
+// WriteTraceroute writes t
+func (w *Writer) WriteTraceroute(t Traceroute) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	t.Flags.ListID = w.list.ListID
+	t.Flags.CycleID = w.cycle.CycleID
+	body, err := encodeTraceroute(t)
+	if err != nil {
+		return err
+	}
+	return w.object(TracerouteT, body)
+}
+
+// Close writes the cycle stop if anything was written. It does not
+// close the underlying writer
+func (w *Writer) Close() error {
+	if !w.started {
+		return nil
+	}
+	var buf bytes.Buffer
+	putUint32(&buf, w.cycle.CycleID)
+	stop := w.cycle.StopTime
+	if stop == 0 {
+		stop = uint32(time.Now().Unix())
+	}
+	putUint32(&buf, stop)
+	buf.WriteByte(0)
+	return w.object(CycleStopT, buf.Bytes())
+}
+
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	var buf bytes.Buffer
+	putUint32(&buf, w.list.ListID)
+	putUint32(&buf, w.list.CListID)
+	putString(&buf, w.list.ListName)
+	var lp params
+	if w.list.Description != "" {
+		lp.str(1, w.list.Description)
+	}
+	if w.list.MonitorName != "" {
+		lp.str(2, w.list.MonitorName)
+	}
+	lp.writeTo(&buf)
+	if err := w.object(ListT, buf.Bytes()); err != nil {
+		return err
+	}
+	buf.Reset()
+	start := w.cycle.StartTime
+	if start == 0 {
+		start = uint32(time.Now().Unix())
+	}
+	putUint32(&buf, w.cycle.CycleID)
+	putUint32(&buf, w.cycle.ListID)
+	putUint32(&buf, w.cycle.CCycleID)
+	putUint32(&buf, start)
+	var cp params
+	if w.cycle.StopTime != 0 {
+		cp.u32(1, w.cycle.StopTime)
+	}
+	if w.cycle.Hostname != "" {
+		cp.str(2, w.cycle.Hostname)
+	}
+	cp.writeTo(&buf)
+	return w.object(CycleStartT, buf.Bytes())
+}
+
+func (w *Writer) object(t WartsT, body []byte) error {
+	var head [8]byte
+	binary.BigEndian.PutUint16(head[:2], 0x1205)
+	binary.BigEndian.PutUint16(head[2:4], uint16(t))
+	binary.BigEndian.PutUint32(head[4:], uint32(len(body)))
+	if _, err := w.w.Write(head[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(body)
+	return err
+}
+
+// params builds a parameter block. Flags have to be added in
+// increasing order
+type params struct {
+	flags []uint8
+	data  bytes.Buffer
+	err   error
+}
+
+func (p *params) set(flag uint8) {
+	p.flags = append(p.flags, flag)
+}
+
+func (p *params) u8(flag, v uint8) {
+	p.set(flag)
+	p.data.WriteByte(v)
+}
+
+func (p *params) u16(flag uint8, v uint16) {
+	p.set(flag)
+	putUint16(&p.data, v)
+}
+
+func (p *params) u32(flag uint8, v uint32) {
+	p.set(flag)
+	putUint32(&p.data, v)
+}
+
+func (p *params) str(flag uint8, s string) {
+	p.set(flag)
+	putString(&p.data, s)
+}
+
+func (p *params) timeval(flag uint8, tv syscall.Timeval) {
+	p.set(flag)
+	putTimeval(&p.data, tv)
+}
+
+func (p *params) rtt(flag uint8, tv syscall.Timeval) {
+	p.u32(flag, uint32(tv.Sec)*1000000+uint32(tv.Usec))
+}
+
+func (p *params) addr(flag uint8, a Address) {
+	p.set(flag)
+	if err := putAddress(&p.data, a); err != nil && p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *params) writeTo(buf *bytes.Buffer) {
+	if len(p.flags) == 0 {
+		buf.WriteByte(0)
+		return
+	}
+	fb := make([]byte, (int(p.flags[len(p.flags)-1])+6)/7)
+	for _, f := range p.flags {
+		fb[(f-1)/7] |= 1 << ((f - 1) % 7)
+	}
+	for i := 0; i < len(fb)-1; i++ {
+		fb[i] |= 0x80
+	}
+	buf.Write(fb)
+	putUint16(buf, uint16(p.data.Len()))
+	buf.Write(p.data.Bytes())
+}
+
+func putUint16(buf *bytes.Buffer, v uint16) {
+	buf.WriteByte(byte(v >> 8))
+	buf.WriteByte(byte(v))
+}
+
+func putUint32(buf *bytes.Buffer, v uint32) {
+	putUint16(buf, uint16(v>>16))
+	putUint16(buf, uint16(v))
+}
+
+func putString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
+
+func putTimeval(buf *bytes.Buffer, tv syscall.Timeval) {
+	putUint32(buf, uint32(tv.Sec))
+	putUint32(buf, uint32(tv.Usec))
+}
+
+// addrLength is the length of each address type, IPv6 addresses do not
+// fit in an Address
+var addrLength = map[uint8]int{
+	0x01: 4,
+	0x03: 6,
+	0x04: 8,
+}
+
+// putAddress writes a in full, addresses are never referenced
+func putAddress(buf *bytes.Buffer, a Address) error {
+	t := a.Type
+	if t == 0 {
+		t = 0x01
+	}
+	l, ok := addrLength[t]
+	if !ok {
+		return fmt.Errorf("Can not write address of type %d", t)
+	}
+	buf.WriteByte(uint8(l))
+	buf.WriteByte(t)
+	for i := l - 1; i >= 0; i-- {
+		buf.WriteByte(byte(a.Address >> uint(8*i)))
+	}
+	return nil
+}
+
+func encodePing(p Ping) ([]byte, error) {
+	f := p.Flags
+	var pp params
+	pp.u32(1, f.ListID)
+	pp.u32(2, f.CycleID)
+	pp.timeval(5, f.StartTime)
+	pp.u8(6, f.StopReason)
+	pp.u8(7, f.StopData)
+	if len(f.Data) > 0 {
+		pp.u16(8, uint16(len(f.Data)))
+		pp.set(9)
+		pp.data.Write(f.Data)
+	}
+	pp.u16(10, f.ProbeCount)
+	pp.u16(11, f.ProbeSize)
+	pp.u8(12, f.ProbeWaitS)
+	pp.u8(13, f.ProbeTTL)
+	pp.u16(14, uint16(len(p.PingReplies)))
+	pp.u16(15, f.PingsSent)
+	pp.u8(16, uint8(f.PingMethod))
+	if f.ProbeSrcPort != 0 {
+		pp.u16(17, f.ProbeSrcPort)
+	}
+	if f.ProbeDstPort != 0 {
+		pp.u16(18, f.ProbeDstPort)
+	}
+	pp.u32(19, f.UserID)
+	pp.addr(20, f.Src)
+	pp.addr(21, f.Dst)
+	if f.PF != 0 {
+		pp.u8(22, uint8(f.PF))
+	}
+	if f.ProbeTOS != 0 {
+		pp.u8(23, f.ProbeTOS)
+	}
+	if len(f.TS) > 0 {
+		pp.u8(24, uint8(len(f.TS)))
+		for _, a := range f.TS {
+			if err := putAddress(&pp.data, a); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if f.ICMPChecksum != 0 {
+		pp.u16(25, f.ICMPChecksum)
+	}
+	if f.MTU != 0 {
+		pp.u16(26, f.MTU)
+	}
+	if f.ProbeTimeout != 0 {
+		pp.u8(27, f.ProbeTimeout)
+	}
+	if f.ProbeWait != 0 {
+		pp.u32(28, f.ProbeWait)
+	}
+	if pp.err != nil {
+		return nil, pp.err
+	}
+	var buf bytes.Buffer
+	pp.writeTo(&buf)
+	putUint16(&buf, uint16(len(p.PingReplies)))
+	for _, r := range p.PingReplies {
+		if err := encodePingReply(&buf, r); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePingReply(buf *bytes.Buffer, r PingReplyFlags) error {
+	var rp params
+	if r.Flags != 0 {
+		rp.u8(2, r.Flags)
+	}
+	rp.u8(3, r.ReplyTTL)
+	rp.u16(4, r.ReplySize)
+	rp.u16(5, r.ICMP)
+	rp.rtt(6, r.RTT)
+	rp.u16(7, r.ProbeID)
+	rp.u16(8, r.ReplyIPID)
+	rp.u16(9, r.ProbeIPID)
+	rp.u8(10, uint8(r.ReplyProto))
+	if r.TCPFlags != 0 {
+		rp.u8(11, r.TCPFlags)
+	}
+	rp.addr(12, r.Addr)
+	if len(r.V4RR.Addrs) > 0 {
+		rp.u8(13, uint8(len(r.V4RR.Addrs)))
+		for _, a := range r.V4RR.Addrs {
+			if err := putAddress(&rp.data, a); err != nil {
+				return err
+			}
+		}
+	}
+	if len(r.V4TS.TimeStamps) > 0 || len(r.V4TS.Addrs) > 0 {
+		rp.u8(14, uint8(len(r.V4TS.TimeStamps)))
+		rp.data.WriteByte(uint8(len(r.V4TS.Addrs)))
+		for _, ts := range r.V4TS.TimeStamps {
+			putUint32(&rp.data, ts)
+		}
+		for _, a := range r.V4TS.Addrs {
+			if err := putAddress(&rp.data, a); err != nil {
+				return err
+			}
+		}
+	}
+	if r.ReplyIPID32 != 0 {
+		rp.u32(15, r.ReplyIPID32)
+	}
+	rp.timeval(16, r.Tx)
+	if r.TSReply != (TSReply{}) {
+		rp.u32(17, r.TSReply.OTimestamp)
+		putUint32(&rp.data, r.TSReply.RTimestamp)
+		putUint32(&rp.data, r.TSReply.TTimestamp)
+	}
+	if rp.err != nil {
+		return rp.err
+	}
+	rp.writeTo(buf)
+	return nil
+}
+
+func encodeTraceroute(t Traceroute) ([]byte, error) {
+	f := t.Flags
+	var tp params
+	tp.u32(1, f.ListID)
+	tp.u32(2, f.CycleID)
+	tp.timeval(5, f.StartTime)
+	tp.u8(6, uint8(f.StopReason))
+	tp.u8(7, f.StopData)
+	tp.u8(8, f.TraceFlags)
+	tp.u8(9, f.Attempts)
+	tp.u8(10, f.HopLimit)
+	tp.u8(11, uint8(f.TraceType))
+	tp.u16(12, f.ProbeSize)
+	tp.u16(13, f.SourcePort)
+	tp.u16(14, f.DestPort)
+	tp.u8(15, f.StartTTL)
+	tp.u8(16, f.IPToS)
+	tp.u8(17, f.TimeoutS)
+	tp.u8(18, f.Loops)
+	tp.u16(19, f.HopsProbed)
+	tp.u8(20, f.GapLimit)
+	tp.u8(21, f.GapAction)
+	tp.u8(22, f.LoopAction)
+	tp.u16(23, f.ProbesSent)
+	tp.u8(24, f.MinWaitCenti)
+	tp.u8(25, f.Confidence)
+	tp.addr(26, f.Src)
+	tp.addr(27, f.Dst)
+	tp.u32(28, f.UserID)
+	if tp.err != nil {
+		return nil, tp.err
+	}
+	var buf bytes.Buffer
+	tp.writeTo(&buf)
+	putUint16(&buf, uint16(len(t.Hops)))
+	for _, h := range t.Hops {
+		if err := encodeTracerouteHop(&buf, h); err != nil {
+			return nil, err
+		}
+	}
+	// No pmtud, lastditch or doubletree data follows
+	putUint16(&buf, 0)
+	return buf.Bytes(), nil
+}
+
+func encodeTracerouteHop(buf *bytes.Buffer, h TracerouteHop) error {
+	var hp params
+	hp.u8(2, h.ProbeTTL)
+	hp.u8(3, h.ReplyTTL)
+	if h.Flags != 0 {
+		hp.u8(4, h.Flags)
+	}
+	hp.u8(5, h.ProbeID)
+	hp.rtt(6, h.RTT)
+	hp.u16(7, h.ICMPTypeCode)
+	hp.u16(8, h.ProbeSize)
+	hp.u16(9, h.ReplySize)
+	hp.u16(10, h.IPID)
+	hp.u8(11, h.ToS)
+	if h.NextHopMTU != 0 {
+		hp.u16(12, h.NextHopMTU)
+	}
+	if h.QuotedIPLength != 0 {
+		hp.u16(13, h.QuotedIPLength)
+	}
+	if h.QuotedTTL != 0 {
+		hp.u8(14, h.QuotedTTL)
+	}
+	if h.TCPFlags != 0 {
+		hp.u8(15, h.TCPFlags)
+	}
+	if h.QuotesToS != 0 {
+		hp.u8(16, h.QuotesToS)
+	}
+	if len(h.ICMPExt.Extensions) > 0 {
+		var ext bytes.Buffer
+		for _, e := range h.ICMPExt.Extensions {
+			putUint16(&ext, uint16(len(e.Data)))
+			ext.WriteByte(e.ClassNumber)
+			ext.WriteByte(e.TypeNumber)
+			ext.Write(e.Data)
+		}
+		hp.u16(17, uint16(ext.Len()))
+		hp.data.Write(ext.Bytes())
+	}
+	hp.addr(18, h.Address)
+	if hp.err != nil {
+		return hp.err
+	}
+	hp.writeTo(buf)
+	return nil
+}
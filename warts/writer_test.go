@@ -0,0 +1,189 @@
+/*
+ Copyright (c) 2015, Northeastern University
+ All rights reserved.
+
+ Redistribution and use in source and binary forms, with or without
+ modification, are permitted provided that the following conditions are met:
+     * Redistributions of source code must retain the above copyright
+       notice, this list of conditions and the following disclaimer.
+     * Redistributions in binary form must reproduce the above copyright
+       notice, this list of conditions and the following disclaimer in the
+       documentation and/or other materials provided with the distribution.
+     * Neither the name of the Northeastern University nor the
+       names of its contributors may be used to endorse or promote products
+       derived from this software without specific prior written permission.
+
+ THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ DISCLAIMED. IN NO EVENT SHALL Northeastern University BE LIABLE FOR ANY
+ DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+ (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+ LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+ ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+ (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+ SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package warts_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/NEU-SNS/ReverseTraceroute/datamodel"
+	"github.com/NEU-SNS/ReverseTraceroute/warts"
+)
+
+var all = []warts.WartsT{
+	warts.ListT, warts.CycleStartT, warts.CycleStopT,
+	warts.PingT, warts.TracerouteT,
+}
+
+// convert reads the pings and traceroutes of a warts file
+func convert(t *testing.T, data []byte) ([]*datamodel.Ping, []*datamodel.Traceroute) {
+	res, err := warts.Parse(data, all)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var pings []*datamodel.Ping
+	var traces []*datamodel.Traceroute
+	for _, obj := range res {
+		switch o := obj.(type) {
+		case warts.Ping:
+			p := datamodel.ConvertPing(o)
+			pings = append(pings, &p)
+		case warts.Traceroute:
+			tr := datamodel.ConvertTraceroute(o)
+			traces = append(traces, &tr)
+		}
+	}
+	return pings, traces
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for _, file := range []string{
+		"../doc/test_warts.warts",
+		"../doc/rr_test.warts",
+		"../doc/test_tsonly.warts",
+		"../doc/test_tsprespec2.warts",
+		"../doc/trace_test.warts",
+		"../doc/test_firsthop_trace.warts",
+	} {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Could not read %s", file)
+		}
+		pings, traces := convert(t, content)
+		if len(pings)+len(traces) == 0 {
+			t.Fatalf("%s: no measurements", file)
+		}
+		var buf bytes.Buffer
+		w := warts.NewWriter(&buf, warts.List{}, warts.CycleStart{Hostname: "test"})
+		for _, p := range pings {
+			if err := w.WritePing(datamodel.PingToWarts(p)); err != nil {
+				t.Fatalf("%s: WritePing failed: %v", file, err)
+			}
+		}
+		for _, tr := range traces {
+			if err := w.WriteTraceroute(datamodel.TracerouteToWarts(tr)); err != nil {
+				t.Fatalf("%s: WriteTraceroute failed: %v", file, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: Close failed: %v", file, err)
+		}
+		gotPings, gotTraces := convert(t, buf.Bytes())
+		if !reflect.DeepEqual(gotPings, pings) {
+			t.Errorf("%s: pings changed\ngot  %v\nwant %v", file, gotPings, pings)
+		}
+		if !reflect.DeepEqual(gotTraces, traces) {
+			t.Errorf("%s: traceroutes changed\ngot  %v\nwant %v", file, gotTraces, traces)
+		}
+	}
+}
+
+func TestWriterHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	w := warts.NewWriter(&buf, warts.List{ListName: "atlas", MonitorName: "vp1"},
+		warts.CycleStart{CycleID: 3, StartTime: 100, Hostname: "vp1.example.com"})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Close wrote %d bytes without any measurements", buf.Len())
+	}
+	if err := w.WritePing(warts.Ping{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	res, err := warts.Parse(buf.Bytes(), all)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(res) != 4 {
+		t.Fatalf("Parse returned %d objects, expected 4", len(res))
+	}
+	l, ok := res[0].(warts.List)
+	if !ok || l.ListName != "atlas" || l.MonitorName != "vp1" || l.ListID != 1 {
+		t.Fatalf("Wrong list: %+v", res[0])
+	}
+	c, ok := res[1].(warts.CycleStart)
+	if !ok || c.CycleID != 3 || c.ListID != 1 || c.StartTime != 100 || c.Hostname != "vp1.example.com" {
+		t.Fatalf("Wrong cycle start: %+v", res[1])
+	}
+	p, ok := res[2].(warts.Ping)
+	if !ok || p.Flags.ListID != 1 || p.Flags.CycleID != 3 {
+		t.Fatalf("Wrong ping: %+v", res[2])
+	}
+	if s, ok := res[3].(warts.CycleStop); !ok || s.CycleID != 3 {
+		t.Fatalf("Wrong cycle stop: %+v", res[3])
+	}
+}
+
+func TestWriterICMPExtensions(t *testing.T) {
+	tr := &datamodel.Traceroute{
+		Type:       "trace",
+		Src:        0x01020304,
+		Dst:        0x05060708,
+		Method:     "icmp-echo-paris",
+		StopReason: "COMPLETED",
+		Start:      &datamodel.TracerouteTime{Sec: 1500000000, Usec: 10},
+		Hops: []*datamodel.TracerouteHop{
+			{
+				Addr:     0x0a000001,
+				ProbeTtl: 1,
+				Rtt:      &datamodel.RTT{Usec: 900},
+				IcmpType: 11,
+				IcmpExt: []*datamodel.ICMPExtension{
+					// An MPLS label stack entry
+					{ClassNumber: 1, TypeNumber: 1, Data: []byte{0x00, 0x3e, 0x81, 0x01}},
+				},
+			},
+			{Addr: 0x05060708, ProbeTtl: 2, Rtt: &datamodel.RTT{Sec: 1, Usec: 5}},
+		},
+	}
+	var buf bytes.Buffer
+	w := warts.NewWriter(&buf, warts.List{}, warts.CycleStart{})
+	if err := w.WriteTraceroute(datamodel.TracerouteToWarts(tr)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, traces := convert(t, buf.Bytes())
+	if len(traces) != 1 {
+		t.Fatalf("Got %d traceroutes, expected 1", len(traces))
+	}
+	got := traces[0]
+	if !reflect.DeepEqual(got.Hops, tr.Hops) {
+		t.Fatalf("Hops changed\ngot  %v\nwant %v", got.Hops, tr.Hops)
+	}
+	if got.Method != tr.Method || got.StopReason != tr.StopReason || got.Src != tr.Src || got.Dst != tr.Dst {
+		t.Fatalf("Traceroute changed\ngot  %v\nwant %v", got, tr)
+	}
+}